@@ -0,0 +1,86 @@
+package acceptance
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+	"github.com/pivotal-cf/om/models"
+)
+
+var _ = Describe("audit log", func() {
+	var (
+		dir     string
+		logFile string
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "om-audit")
+		Expect(err).ToNot(HaveOccurred())
+
+		logFile = filepath.Join(dir, "audit.log")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	run := func(args ...string) *gexec.Session {
+		command := exec.Command(pathToMain, args...)
+		command.Env = []string{"OM_AUDIT_LOG=" + logFile}
+
+		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(session).Should(gexec.Exit())
+		return session
+	}
+
+	It("records the requests that are not GET requests, and lists them", func() {
+		server := testServer(true)
+
+		for _, method := range []string{"GET", "POST"} {
+			session := run(
+				"--target", server.URL,
+				"--username", "some-env-provided-username",
+				"--password", "some-env-provided-password",
+				"--skip-ssl-validation",
+				"curl",
+				"--path", "/api/v0/available_products",
+				"--request", method,
+				"--data", `{"password": "not recorded"}`,
+			)
+			Expect(session.ExitCode()).To(Equal(0))
+		}
+
+		contents, err := ioutil.ReadFile(logFile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(contents)).ToNot(ContainSubstring("some-env-provided-password"))
+		Expect(string(contents)).ToNot(ContainSubstring("not recorded"))
+
+		session := run("audit-log", "--format", "json")
+		Expect(session.ExitCode()).To(Equal(0))
+
+		var records []models.AuditRecord
+		Expect(json.Unmarshal(session.Out.Contents(), &records)).To(Succeed())
+		Expect(records).To(HaveLen(1))
+
+		Expect(records[0].Command).To(Equal("curl"))
+		Expect(records[0].Args).To(Equal([]string{
+			"--path", "/api/v0/available_products",
+			"--request", "POST",
+			"--data", "REDACTED",
+		}))
+		Expect(records[0].Target).To(Equal(server.URL))
+		Expect(records[0].User).To(Equal("some-env-provided-username"))
+		Expect(records[0].Method).To(Equal("POST"))
+		Expect(records[0].Endpoint).To(Equal("/api/v0/available_products"))
+		Expect(records[0].Status).To(Equal(200))
+	})
+})
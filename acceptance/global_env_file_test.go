@@ -117,6 +117,19 @@ bad-key: bad-value
 			Expect(string(session.Err.Contents())).To(ContainSubstring("field bad-key not found"))
 		})
 
+		It("errors on the global options that cannot be set in the env file", func() {
+			for _, key := range []string{"auditlog", "lock", "strict"} {
+				createConfigFile(fmt.Sprintf("---\ntarget: https://example.com\n%s: true\n", key))
+
+				command := exec.Command(pathToMain, "--env", configFile.Name(), "version")
+				session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred())
+
+				Eventually(session).Should(gexec.Exit(1))
+				Expect(string(session.Err.Contents())).To(ContainSubstring(fmt.Sprintf("field %s not found", key)))
+			}
+		})
+
 		When("the env file contains variables", func() {
 			BeforeEach(func() {
 				createConfigFile(fmt.Sprintf(validConfigFile, "((target_url))"))
@@ -13,6 +13,7 @@ const GLOBAL_USAGE = `ॐ
 om helps you interact with an Ops Manager
 
 Usage: om [options] <command> [<args>]
+  --audit-log, OM_AUDIT_LOG                              string  append an audit record of every request to the Ops Manager that is not a GET request to this file, or to syslog with 'syslog'
   --ca-cert, OM_CA_CERT                                  string  OpsManager CA certificate path or value
   --client-id, -c, OM_CLIENT_ID                          string  Client ID for the Ops Manager VM (not required for unauthenticated commands)
   --client-secret, -s, OM_CLIENT_SECRET                  string  Client Secret for the Ops Manager VM (not required for unauthenticated commands)
@@ -20,9 +21,11 @@ Usage: om [options] <command> [<args>]
   --decryption-passphrase, -d, OM_DECRYPTION_PASSPHRASE  string  Passphrase to decrypt the installation if the Ops Manager VM has been rebooted (optional for most commands)
   --env, -e                                              string  env file with login credentials
   --help, -h                                             bool    prints this usage information (default: false)
+  --lock, OM_LOCK                                        string  lock config file: hold a lease on the Ops Manager for the whole command, so that two om do not change it at the same time
   --password, -p, OM_PASSWORD                            string  admin password for the Ops Manager VM (not required for unauthenticated commands)
   --request-timeout, -r, OM_REQUEST_TIMEOUT              int     timeout in seconds for HTTP requests to Ops Manager (default: 1800)
   --skip-ssl-validation, -k, OM_SKIP_SSL_VALIDATION      bool    skip ssl certificate validation during http requests (default: false)
+  --strict, OM_STRICT                                    bool    fail when a variable of a config file is missing or a provided variable is not used (default: false)
   --target, -t, OM_TARGET                                string  location of the Ops Manager VM
   --trace, -tr, OM_TRACE                                 bool    prints HTTP requests and response payloads
   --username, -u, OM_USERNAME                            string  admin username for the Ops Manager VM (not required for unauthenticated commands)
@@ -37,6 +40,7 @@ const CONFIGURE_AUTHENTICATION_USAGE = `ॐ  configure-authentication
 This unauthenticated command helps setup the internal userstore authentication mechanism for your Ops Manager.
 
 Usage: om [options] configure-authentication [<args>]
+  --audit-log, OM_AUDIT_LOG                              string  append an audit record of every request to the Ops Manager that is not a GET request to this file, or to syslog with 'syslog'
   --ca-cert, OM_CA_CERT                                  string  OpsManager CA certificate path or value
   --client-id, -c, OM_CLIENT_ID                          string  Client ID for the Ops Manager VM (not required for unauthenticated commands)
   --client-secret, -s, OM_CLIENT_SECRET                  string  Client Secret for the Ops Manager VM (not required for unauthenticated commands)
@@ -44,9 +48,11 @@ Usage: om [options] configure-authentication [<args>]
   --decryption-passphrase, -d, OM_DECRYPTION_PASSPHRASE  string  Passphrase to decrypt the installation if the Ops Manager VM has been rebooted (optional for most commands)
   --env, -e                                              string  env file with login credentials
   --help, -h                                             bool    prints this usage information (default: false)
+  --lock, OM_LOCK                                        string  lock config file: hold a lease on the Ops Manager for the whole command, so that two om do not change it at the same time
   --password, -p, OM_PASSWORD                            string  admin password for the Ops Manager VM (not required for unauthenticated commands)
   --request-timeout, -r, OM_REQUEST_TIMEOUT              int     timeout in seconds for HTTP requests to Ops Manager (default: 1800)
   --skip-ssl-validation, -k, OM_SKIP_SSL_VALIDATION      bool    skip ssl certificate validation during http requests (default: false)
+  --strict, OM_STRICT                                    bool    fail when a variable of a config file is missing or a provided variable is not used (default: false)
   --target, -t, OM_TARGET                                string  location of the Ops Manager VM
   --trace, -tr, OM_TRACE                                 bool    prints HTTP requests and response payloads
   --username, -u, OM_USERNAME                            string  admin username for the Ops Manager VM (not required for unauthenticated commands)
@@ -54,14 +60,14 @@ Usage: om [options] configure-authentication [<args>]
   OM_VARS_ENV                                            string  **EXPERIMENTAL** load vars from environment variables by specifying a prefix (e.g.: 'MY' to load MY_var=value)
 
 Command Arguments:
-  --config, -c                  string             path to yml file for configuration (keys must match the following command line flags)
-  --decryption-passphrase, -dp  string (required)  passphrase used to encrypt the installation
-  --http-proxy-url              string             proxy for outbound HTTP network traffic
-  --https-proxy-url             string             proxy for outbound HTTPS network traffic
-  --no-proxy                    string             comma-separated list of hosts that do not go through the proxy
-  --password, -p, OM_PASSWORD   string (required)  admin password
-  --precreated-client-secret    string             create a UAA client on the Ops Manager vm. The client_secret will be the value provided to this option
-  --username, -u, OM_USERNAME   string (required)  admin username
+  --config, -c                   string             path to yml file for configuration (keys must match the following command line flags)
+  --decryption-passphrase, -dp   string (required)  passphrase used to encrypt the installation
+  --http-proxy-url               string             proxy for outbound HTTP network traffic
+  --https-proxy-url              string             proxy for outbound HTTPS network traffic
+  --no-proxy                     string             comma-separated list of hosts that do not go through the proxy
+  --password, -p, OM_PASSWORD    string (required)  admin password
+  --precreated-client-secret     string             create a UAA client on the Ops Manager vm. The client_secret will be the value provided to this option
+  --username, -u, OM_USERNAME    string (required)  admin username
 `
 
 var _ = Describe("help", func() {
@@ -0,0 +1,120 @@
+package acceptance
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/gexec"
+	"github.com/pivotal-cf/om/lock"
+	"github.com/pivotal-cf/om/models"
+)
+
+var _ = Describe("lock", func() {
+	var (
+		dir        string
+		lockDir    string
+		configFile string
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "om-lock")
+		Expect(err).ToNot(HaveOccurred())
+
+		lockDir = filepath.Join(dir, "locks")
+		configFile = filepath.Join(dir, "lock.yml")
+		Expect(ioutil.WriteFile(configFile, []byte("backend: file\nname: opsman\nholder: ci\npath: "+lockDir+"\n"), 0600)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	run := func(args ...string) *gexec.Session {
+		command := exec.Command(pathToMain, args...)
+		command.Env = []string{"OM_LOCK=" + configFile}
+
+		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(session).Should(gexec.Exit())
+		return session
+	}
+
+	curl := func(target string) *gexec.Session {
+		return run(
+			"--target", target,
+			"--username", "some-env-provided-username",
+			"--password", "some-env-provided-password",
+			"--skip-ssl-validation",
+			"curl",
+			"--path", "/api/v0/available_products",
+		)
+	}
+
+	It("holds the lease for the whole command, and lists and breaks the leases of other om", func() {
+		server := testServer(true)
+
+		session := curl(server.URL)
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(filepath.Join(lockDir, "opsman.lock")).ToNot(BeAnExistingFile())
+
+		backend, err := lock.NewFileBackend(lockDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backend.Write(models.Lock{
+			Name:       "opsman",
+			ID:         "other-id",
+			Holder:     "other-pipeline",
+			Command:    "apply-changes",
+			AcquiredAt: time.Now().UTC(),
+			ExpiresAt:  time.Now().UTC().Add(time.Hour),
+		})).To(Succeed())
+
+		session = curl(server.URL)
+		Expect(session.ExitCode()).To(Equal(1))
+		Expect(session.Err).To(gbytes.Say(`the lock opsman is held by other-pipeline \(apply-changes\)`))
+
+		session = run(
+			"--target", server.URL,
+			"--username", "some-env-provided-username",
+			"--password", "some-env-provided-password",
+			"--skip-ssl-validation",
+			"available-products",
+		)
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = run("locks", "--format", "json")
+		Expect(session.ExitCode()).To(Equal(0))
+
+		var locks []models.Lock
+		Expect(json.Unmarshal(session.Out.Contents(), &locks)).To(Succeed())
+		Expect(locks).To(HaveLen(1))
+		Expect(locks[0].Holder).To(Equal("other-pipeline"))
+		Expect(locks[0].Expired).To(BeFalse())
+
+		session = run("locks", "--break", "opsman")
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(session.Out).To(gbytes.Say("broke the lock opsman"))
+
+		session = curl(server.URL)
+		Expect(session.ExitCode()).To(Equal(0))
+	})
+
+	It("only loads the lock config for the commands that use it", func() {
+		Expect(ioutil.WriteFile(configFile, []byte("backend: not-a-backend\n"), 0600)).To(Succeed())
+
+		session := run("version")
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = run("locks")
+		Expect(session.ExitCode()).To(Equal(1))
+		Expect(session.Err).To(gbytes.Say(`unsupported backend "not-a-backend"`))
+	})
+})
@@ -0,0 +1,94 @@
+package acceptance
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/gexec"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("plugins", func() {
+	var (
+		pluginDir string
+		server    *ghttp.Server
+	)
+
+	BeforeEach(func() {
+		var err error
+		pluginDir, err = ioutil.TempDir("", "om-plugins")
+		Expect(err).ToNot(HaveOccurred())
+
+		stubPath, err := gexec.Build("github.com/pivotal-cf/om/stub")
+		Expect(err).ToNot(HaveOccurred())
+
+		err = os.Link(stubPath, filepath.Join(pluginDir, "om-hello"))
+		Expect(err).ToNot(HaveOccurred())
+
+		server = createTLSServer()
+	})
+
+	AfterEach(func() {
+		server.Close()
+		Expect(os.RemoveAll(pluginDir)).To(Succeed())
+	})
+
+	It("runs an om-<name> executable on the PATH with the global options", func() {
+		command := exec.Command(pathToMain,
+			"--target", server.URL(),
+			"--username", "some-username",
+			"--password", "some-password",
+			"--skip-ssl-validation",
+			"hello", "--some-flag", "some-arg",
+		)
+		command.Env = []string{"PATH=" + pluginDir}
+
+		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(session).Should(gexec.Exit(0))
+		Expect(session.Err).To(gbytes.Say(`om-hello --some-flag some-arg`))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("OM_TARGET=" + server.URL()))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("OM_SKIP_SSL_VALIDATION=true"))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("OM_ACCESS_TOKEN=some-opsman-token"))
+		Expect(string(session.Err.Contents())).ToNot(ContainSubstring("some-password"))
+	})
+
+	It("exits with the status of the plugin", func() {
+		command := exec.Command(pathToMain, "--target", server.URL(), "hello")
+		command.Env = []string{"PATH=" + pluginDir, "STUB_ERROR_CODE=3"}
+
+		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(session).Should(gexec.Exit(3))
+		Expect(session.Err).To(gbytes.Say(`om-hello error!!`))
+	})
+
+	It("passes --help to the plugin", func() {
+		command := exec.Command(pathToMain, "hello", "--help")
+		command.Env = []string{"PATH=" + pluginDir}
+
+		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(session).Should(gexec.Exit(0))
+		Expect(session.Err).To(gbytes.Say(`om-hello --help`))
+	})
+
+	It("lists the plugins in the help", func() {
+		command := exec.Command(pathToMain, "help")
+		command.Env = []string{"PATH=" + pluginDir}
+
+		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(session).Should(gexec.Exit(0))
+		Expect(session.Out).To(gbytes.Say(`Plugins:\n  hello +` + filepath.Join(pluginDir, "om-hello")))
+	})
+})
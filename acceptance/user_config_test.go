@@ -0,0 +1,159 @@
+package acceptance
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("user config file", func() {
+	var configHome string
+
+	BeforeEach(func() {
+		var err error
+		configHome, err = ioutil.TempDir("", "om-config")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(os.Mkdir(filepath.Join(configHome, "om"), 0755)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(configHome)).To(Succeed())
+	})
+
+	writeUserConfig := func(contents string) {
+		err := ioutil.WriteFile(filepath.Join(configHome, "om", "config.yml"), []byte(contents), 0644)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	run := func(env []string, args ...string) *gexec.Session {
+		command := exec.Command(pathToMain, args...)
+		command.Env = append([]string{"XDG_CONFIG_HOME=" + configHome}, env...)
+
+		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(session).Should(gexec.Exit())
+		return session
+	}
+
+	It("loads the global options from the user config file", func() {
+		server := testServer(true)
+		writeUserConfig(fmt.Sprintf("target: %s\nskip-ssl-validation: true\n", server.URL))
+
+		session := run(nil,
+			"--username", "some-env-provided-username",
+			"--password", "some-env-provided-password",
+			"curl", "-p", "/api/v0/available_products",
+		)
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(string(session.Out.Contents())).To(MatchJSON(`[ { "name": "p-bosh", "product_version": "999.99" } ]`))
+	})
+
+	It("loads the env file of the user config file", func() {
+		server := testServer(true)
+		envFile := writeFile(fmt.Sprintf(validConfigFile, server.URL))
+		writeUserConfig(fmt.Sprintf("env: %s\n", envFile))
+
+		session := run(nil, "curl", "-p", "/api/v0/available_products")
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(string(session.Out.Contents())).To(MatchJSON(`[ { "name": "p-bosh", "product_version": "999.99" } ]`))
+	})
+
+	It("gives the flags, the env vars and the env file precedence over the user config file", func() {
+		server := testServer(true)
+		writeUserConfig("target: https://does-not-exist.example.com\nskip-ssl-validation: true\n")
+
+		session := run(nil,
+			"--target", server.URL,
+			"--username", "some-env-provided-username",
+			"--password", "some-env-provided-password",
+			"curl", "-p", "/api/v0/available_products",
+		)
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = run([]string{"OM_TARGET=" + server.URL},
+			"--username", "some-env-provided-username",
+			"--password", "some-env-provided-password",
+			"curl", "-p", "/api/v0/available_products",
+		)
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = run(nil,
+			"--env", writeFile(fmt.Sprintf(validConfigFile, server.URL)),
+			"curl", "-p", "/api/v0/available_products",
+		)
+		Expect(session.ExitCode()).To(Equal(0))
+	})
+
+	It("gives precedence over the user config file to the options that are set to their default value", func() {
+		server := testServer(true)
+		writeUserConfig(fmt.Sprintf("target: %s\nskip-ssl-validation: true\n", server.URL))
+
+		session := run(nil,
+			"--skip-ssl-validation=false",
+			"--username", "some-env-provided-username",
+			"--password", "some-env-provided-password",
+			"curl", "-p", "/api/v0/available_products",
+		)
+		Expect(session.ExitCode()).To(Equal(1))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("certificate"))
+
+		session = run([]string{"OM_SKIP_SSL_VALIDATION=false"},
+			"--username", "some-env-provided-username",
+			"--password", "some-env-provided-password",
+			"curl", "-p", "/api/v0/available_products",
+		)
+		Expect(session.ExitCode()).To(Equal(1))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("certificate"))
+
+		envFile := writeFile(fmt.Sprintf("username: some-env-provided-username\npassword: some-env-provided-password\ntarget: %s\nskip-ssl-validation: false\n", server.URL))
+		session = run(nil, "--env", envFile, "curl", "-p", "/api/v0/available_products")
+		Expect(session.ExitCode()).To(Equal(1))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("certificate"))
+	})
+
+	It("passes the default flags of the command", func() {
+		configFile := writeFile("some:\n  key: from-the-user-config\nother:\n  key: from-the-command-line\n")
+		writeUserConfig(fmt.Sprintf("interpolate:\n  config: %s\n  path: /some/key\n", configFile))
+
+		session := run(nil, "interpolate")
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(string(session.Out.Contents())).To(Equal("from-the-user-config\n\n"))
+
+		session = run(nil, "interpolate", "--path", "/other/key")
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(string(session.Out.Contents())).To(Equal("from-the-command-line\n\n"))
+	})
+
+	It("leaves out the defaults of the flags that are given on the command line", func() {
+		configFile := writeFile("first: ((first))\nsecond: ((second))\n")
+		writeUserConfig(fmt.Sprintf("interpolate:\n  config: %s\n  var: [first=from-the-user-config, second=from-the-user-config]\n", configFile))
+
+		session := run(nil, "interpolate", "--skip-missing", "-v", "first=from-the-command-line")
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(string(session.Out.Contents())).To(MatchYAML("first: from-the-command-line\nsecond: ((second))\n"))
+	})
+
+	It("fails on a flag that the command does not have", func() {
+		writeUserConfig("interpolate:\n  output-directory: /tmp\n")
+
+		session := run(nil, "interpolate")
+		Expect(session.ExitCode()).To(Equal(1))
+		Expect(string(session.Err.Contents())).To(ContainSubstring(`unknown flag "output-directory" for the interpolate command`))
+	})
+
+	It("warns about a command that om does not have and ignores it", func() {
+		writeUserConfig("download-products:\n  output-directory: /tmp\n")
+
+		session := run(nil, "version")
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(string(session.Err.Contents())).To(ContainSubstring(`warning: ignoring unknown command "download-products" in the user config file`))
+	})
+})
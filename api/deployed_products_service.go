@@ -9,8 +9,9 @@ import (
 )
 
 type DeployedProductOutput struct {
-	Type string
-	GUID string
+	Type           string
+	GUID           string
+	ProductVersion string `json:"product_version,omitempty"`
 }
 
 func (a Api) GetDeployedProductManifest(guid string) (string, error) {
@@ -103,7 +103,8 @@ key-4: 2147483648
 					ghttp.VerifyRequest("GET", "/api/v0/deployed/products"),
 					ghttp.RespondWith(http.StatusOK, `[{
 						"guid":"some-product-guid",
-						"type":"some-type"
+						"type":"some-type",
+						"product_version":"1.2.3"
 					}, {
 						"guid":"some-other-product-guid",
 						"type":"some-other-type"
@@ -115,8 +116,9 @@ key-4: 2147483648
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(output).To(Equal([]api.DeployedProductOutput{{
-				GUID: "some-product-guid",
-				Type: "some-type",
+				GUID:           "some-product-guid",
+				Type:           "some-type",
+				ProductVersion: "1.2.3",
 			}, {
 				GUID: "some-other-product-guid",
 				Type: "some-other-type",
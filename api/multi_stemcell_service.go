@@ -8,7 +8,14 @@ import (
 )
 
 type ProductMultiStemcells struct {
-	Products []ProductMultiStemcell `json:"products"`
+	Products        []ProductMultiStemcell `json:"products"`
+	StemcellLibrary []StemcellLibraryEntry `json:"stemcell_library,omitempty"`
+}
+
+type StemcellLibraryEntry struct {
+	OS       string `json:"os,omitempty"`
+	Version  string `json:"version,omitempty"`
+	Filename string `json:"filename,omitempty"`
 }
 
 type StemcellObject struct {
@@ -41,6 +41,11 @@ var _ = Describe("StemcellService", func() {
 								{"os": "ubuntu-trusty", "version": "1234.6"}
 							  ],
 							  "required_stemcells": [{"os": "ubuntu-xenial", "version": "1234.5"}]
+						}],
+						"stemcell_library": [{
+							"os": "ubuntu-trusty",
+							"version": "1234.5",
+							"filename": "light-bosh-stemcell-1234.5-google-kvm-ubuntu-trusty-go_agent.tgz"
 						}]
 					}`),
 				),
@@ -66,6 +71,13 @@ var _ = Describe("StemcellService", func() {
 						},
 					},
 				},
+				StemcellLibrary: []api.StemcellLibraryEntry{
+					{
+						OS:       "ubuntu-trusty",
+						Version:  "1234.5",
+						Filename: "light-bosh-stemcell-1234.5-google-kvm-ubuntu-trusty-go_agent.tgz",
+					},
+				},
 			}))
 		})
 
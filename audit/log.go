@@ -0,0 +1,102 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pivotal-cf/om/models"
+)
+
+// Syslog is the destination that sends the audit records to syslog instead of a file.
+const Syslog = "syslog"
+
+// Open opens the destination of the audit records,
+// either Syslog or a file that the records are appended to.
+func Open(destination string) (io.WriteCloser, error) {
+	if destination == Syslog {
+		return openSyslog()
+	}
+
+	return os.OpenFile(destination, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+}
+
+// Log writes an audit record, as a line of JSON,
+// for each request that the running command makes to the Ops Manager.
+type Log struct {
+	writer io.Writer
+	record models.AuditRecord
+	mutex  sync.Mutex
+}
+
+func New(writer io.Writer, target, user string) *Log {
+	return &Log{
+		writer: writer,
+		record: models.AuditRecord{
+			Args:   []string{},
+			Target: target,
+			User:   user,
+		},
+	}
+}
+
+// SetCommand sets the command and its arguments, redacted with RedactArgs, of the records.
+func (l *Log) SetCommand(command string, args []string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.record.Command = command
+	l.record.Args = args
+}
+
+// Record writes the record of a request.
+// The status is 0 and err is set when the request has not got a response.
+func (l *Log) Record(method, endpoint string, status int, err error) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	record := l.record
+	record.Time = time.Now().UTC()
+	record.Method = method
+	record.Endpoint = endpoint
+	record.Status = status
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	_, err = l.writer.Write(append(line, '\n'))
+	return err
+}
+
+// Read returns the records of an audit log file.
+func Read(reader io.Reader) ([]models.AuditRecord, error) {
+	records := []models.AuditRecord{}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, 1024*1024)
+
+	for number := 1; scanner.Scan(); number++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var record models.AuditRecord
+		err := json.Unmarshal(scanner.Bytes(), &record)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse the audit record on line %d: %s", number, err)
+		}
+
+		records = append(records, record)
+	}
+
+	return records, scanner.Err()
+}
@@ -0,0 +1,118 @@
+package audit_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/om/audit"
+	"github.com/pivotal-cf/om/models"
+)
+
+var _ = Describe("Log", func() {
+	Describe("Record", func() {
+		It("writes a record as a line of JSON", func() {
+			output := &bytes.Buffer{}
+
+			log := audit.New(output, "https://opsman.example.com", "some-user")
+			log.SetCommand("apply-changes", []string{"--product-name", "cf"})
+
+			err := log.Record("POST", "/api/v0/installations", 200, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = log.Record("DELETE", "/api/v0/staged/products/cf-guid", 0, errors.New("connection refused"))
+			Expect(err).ToNot(HaveOccurred())
+
+			lines := strings.SplitAfter(output.String(), "\n")
+			Expect(lines).To(HaveLen(3))
+			Expect(lines[0]).To(HaveSuffix("}\n"))
+			Expect(lines[1]).To(HaveSuffix("}\n"))
+
+			records, err := audit.Read(output)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(records).To(HaveLen(2))
+
+			Expect(records[0].Time).To(BeTemporally("~", time.Now(), time.Minute))
+			Expect(records[0].Time.Location()).To(Equal(time.UTC))
+			records[0].Time, records[1].Time = time.Time{}, time.Time{}
+
+			Expect(records).To(Equal([]models.AuditRecord{
+				{
+					Command:  "apply-changes",
+					Args:     []string{"--product-name", "cf"},
+					Target:   "https://opsman.example.com",
+					User:     "some-user",
+					Method:   "POST",
+					Endpoint: "/api/v0/installations",
+					Status:   200,
+				},
+				{
+					Command:  "apply-changes",
+					Args:     []string{"--product-name", "cf"},
+					Target:   "https://opsman.example.com",
+					User:     "some-user",
+					Method:   "DELETE",
+					Endpoint: "/api/v0/staged/products/cf-guid",
+					Error:    "connection refused",
+				},
+			}))
+		})
+	})
+
+	Describe("Open", func() {
+		It("appends to the file", func() {
+			dir, err := ioutil.TempDir("", "audit")
+			Expect(err).ToNot(HaveOccurred())
+			defer os.RemoveAll(dir)
+
+			path := filepath.Join(dir, "audit.log")
+			Expect(ioutil.WriteFile(path, []byte("first\n"), 0600)).To(Succeed())
+
+			file, err := audit.Open(path)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = file.Write([]byte("second\n"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(file.Close()).To(Succeed())
+
+			contents, err := ioutil.ReadFile(path)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(contents)).To(Equal("first\nsecond\n"))
+		})
+
+		When("the file cannot be opened", func() {
+			It("returns an error", func() {
+				_, err := audit.Open("/does/not/exist/audit.log")
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("Read", func() {
+		It("skips the empty lines", func() {
+			records, err := audit.Read(strings.NewReader(`{"command":"curl"}` + "\n\n" + `{"command":"apply-changes"}` + "\n"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(records).To(HaveLen(2))
+			Expect(records[1].Command).To(Equal("apply-changes"))
+		})
+
+		It("returns no records for an empty file", func() {
+			records, err := audit.Read(strings.NewReader(""))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(records).To(Equal([]models.AuditRecord{}))
+		})
+
+		When("a line is not a record", func() {
+			It("returns an error", func() {
+				_, err := audit.Read(strings.NewReader(`{"command":"curl"}` + "\n" + "not json\n"))
+				Expect(err).To(MatchError(ContainSubstring("could not parse the audit record on line 2")))
+			})
+		})
+	})
+})
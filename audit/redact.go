@@ -0,0 +1,140 @@
+package audit
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Redacted replaces the secrets in the arguments of the audit records.
+const Redacted = "REDACTED"
+
+var secretFlagNames = []string{"password", "secret", "passphrase", "token", "private-key"}
+
+// RedactArgs returns args with the values of the secret flags of flags replaced with Redacted,
+// as well as the request payloads given with --data and the values of the variables given with --var.
+// No args are returned when the flags of the command are unknown.
+func RedactArgs(flags interface{}, args []string) []string {
+	secrets, vars, ok := classifyFlags(flags)
+	if !ok {
+		return []string{}
+	}
+
+	redacted := []string{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return append(redacted, args[i:]...)
+		}
+
+		name := strings.TrimLeft(arg, "-")
+		if name == arg || name == "" {
+			redacted = append(redacted, arg)
+			continue
+		}
+
+		value, hasValue := "", false
+		if index := strings.Index(name, "="); index >= 0 {
+			name, value, hasValue = name[:index], name[index+1:], true
+		}
+
+		var redact func(string) string
+		switch {
+		case secrets[name]:
+			redact = redactSecret
+		case vars[name]:
+			redact = redactVar
+		default:
+			redacted = append(redacted, arg)
+			continue
+		}
+
+		if hasValue {
+			redacted = append(redacted, strings.TrimSuffix(arg, value)+redact(value))
+			continue
+		}
+
+		redacted = append(redacted, arg)
+		if i+1 < len(args) {
+			i++
+			redacted = append(redacted, redact(args[i]))
+		}
+	}
+
+	return redacted
+}
+
+// classifyFlags returns the names, long, short and aliases, of the flags that have a secret value,
+// and of the flags that set variables.
+func classifyFlags(flags interface{}) (map[string]bool, map[string]bool, bool) {
+	if flags == nil {
+		return nil, nil, false
+	}
+
+	t := reflect.TypeOf(flags)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil, nil, false
+	}
+
+	secrets, vars := map[string]bool{}, map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Type.Kind() == reflect.Bool {
+			continue
+		}
+
+		long := field.Tag.Get("long")
+
+		names := []string{long}
+		if short := field.Tag.Get("short"); short != "" {
+			names = append(names, short)
+		}
+		if alias := field.Tag.Get("alias"); alias != "" {
+			names = append(names, strings.Split(alias, ",")...)
+		}
+
+		var kind map[string]bool
+		switch {
+		case long == "var":
+			kind = vars
+		case isSecret(long), long == "data": // request payloads can have secrets
+			kind = secrets
+		default:
+			continue
+		}
+
+		for _, name := range names {
+			if name != "" {
+				kind[name] = true
+			}
+		}
+	}
+
+	return secrets, vars, true
+}
+
+func redactSecret(string) string {
+	return Redacted
+}
+
+// redactVar keeps the name of a variable given as name=value.
+func redactVar(value string) string {
+	if index := strings.Index(value, "="); index >= 0 {
+		return value[:index+1] + Redacted
+	}
+
+	return Redacted
+}
+
+func isSecret(name string) bool {
+	for _, secret := range secretFlagNames {
+		if strings.Contains(name, secret) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,65 @@
+package audit_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/om/audit"
+)
+
+var _ = Describe("RedactArgs", func() {
+	var flags struct {
+		Username   string   `long:"username"              short:"u"`
+		Password   string   `long:"password"              short:"p"`
+		Passphrase string   `long:"decryption-passphrase" short:"dp"`
+		Secret     string   `long:"client-secret"         alias:"secret"`
+		Vars       []string `long:"var"                   short:"v"`
+		Force      bool     `long:"force-token"`
+		Data       string   `long:"data"                  short:"d"`
+	}
+
+	It("redacts the values of the secret flags", func() {
+		args := audit.RedactArgs(flags, []string{
+			"--username", "admin",
+			"--password", "some-password",
+			"-p=some-password",
+			"-dp", "some-passphrase",
+			"--secret=some-secret",
+			"--client-secret", "some-secret",
+			"--force-token",
+			"-d", `{"password": "some-password"}`,
+		})
+
+		Expect(args).To(Equal([]string{
+			"--username", "admin",
+			"--password", "REDACTED",
+			"-p=REDACTED",
+			"-dp", "REDACTED",
+			"--secret=REDACTED",
+			"--client-secret", "REDACTED",
+			"--force-token",
+			"-d", "REDACTED",
+		}))
+	})
+
+	It("redacts the values of the variables", func() {
+		args := audit.RedactArgs(&flags, []string{"--var", "name=value", "-v=other=value", "--var", "no-value"})
+		Expect(args).To(Equal([]string{"--var", "name=REDACTED", "-v=other=REDACTED", "--var", "REDACTED"}))
+	})
+
+	It("keeps the arguments after --", func() {
+		args := audit.RedactArgs(flags, []string{"--username", "admin", "--", "--password", "some-password"})
+		Expect(args).To(Equal([]string{"--username", "admin", "--", "--password", "some-password"}))
+	})
+
+	It("keeps a secret flag without a value", func() {
+		args := audit.RedactArgs(flags, []string{"--password"})
+		Expect(args).To(Equal([]string{"--password"}))
+	})
+
+	When("the flags of the command are unknown", func() {
+		It("returns no arguments", func() {
+			args := audit.RedactArgs(nil, []string{"--password", "some-password"})
+			Expect(args).To(BeEmpty())
+		})
+	})
+})
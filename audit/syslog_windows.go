@@ -0,0 +1,10 @@
+package audit
+
+import (
+	"errors"
+	"io"
+)
+
+func openSyslog() (io.WriteCloser, error) {
+	return nil, errors.New("syslog is not supported on windows")
+}
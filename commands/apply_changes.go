@@ -24,7 +24,7 @@ type ApplyChanges struct {
 		Reattach           bool     `long:"reattach" description:"reattach to an already running apply changes (if available)"`
 		RecreateVMs        bool     `long:"recreate-vms" description:"recreate all vms"`
 		SkipDeployProducts bool     `short:"sdp" long:"skip-deploy-products" description:"skip deploying products when applying changes - just update the director"`
-		ProductNames       []string `short:"n"   long:"product-name"         description:"name of the product(s) to deploy, cannot be used in conjunction with --skip-deploy-products (OM 2.2+)"`
+		ProductNames       []string `short:"n"   long:"product-name"         description:"name of the product(s) to deploy, cannot be used in conjunction with --skip-deploy-products (OM 2.2+)" complete:"products"`
 	}
 }
 
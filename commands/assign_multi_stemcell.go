@@ -8,11 +8,13 @@ import (
 )
 
 type AssignMultiStemcell struct {
-	logger  logger
-	service assignMultiStemcellService
-	Options struct {
+	logger       logger
+	service      assignMultiStemcellService
+	strict       bool
+	defaultFlags []string
+	Options      struct {
 		ConfigFile  string   `long:"config"   short:"c"  description:"path to yml file for configuration (keys must match the following command line flags)"`
-		ProductName string   `long:"product"  short:"p"  description:"name of Ops Manager tile to associate a stemcell to" required:"true"`
+		ProductName string   `long:"product"  short:"p"  description:"name of Ops Manager tile to associate a stemcell to" required:"true" complete:"products"`
 		Stemcells   []string `long:"stemcell" short:"s"  description:"associate a particular stemcell version to a tile (ie 'ubuntu-trusty:123.4')" required:"true"`
 	}
 }
@@ -24,10 +26,11 @@ type assignMultiStemcellService interface {
 	Info() (api.Info, error)
 }
 
-func NewAssignMultiStemcell(service assignMultiStemcellService, logger logger) AssignMultiStemcell {
+func NewAssignMultiStemcell(service assignMultiStemcellService, logger logger, strict bool) AssignMultiStemcell {
 	return AssignMultiStemcell{
 		service: service,
 		logger:  logger,
+		strict:  strict,
 	}
 }
 
@@ -40,8 +43,13 @@ func (as AssignMultiStemcell) Usage() jhanda.Usage {
 	}
 }
 
+func (as AssignMultiStemcell) WithDefaultFlags(defaultFlags []string) jhanda.Command {
+	as.defaultFlags = defaultFlags
+	return as
+}
+
 func (as AssignMultiStemcell) Execute(args []string) error {
-	err := loadConfigFile(args, &as.Options, nil)
+	err := loadConfigFile(args, &as.Options, nil, as.strict, as.defaultFlags)
 	if err != nil {
 		return fmt.Errorf("could not parse assign-stemcell flags: %s", err)
 	}
@@ -21,7 +21,7 @@ var _ = Describe("AssignMutliStemcell", func() {
 		fakeService = &fakes.AssignMultiStemcellService{}
 		fakeService.InfoReturns(api.Info{Version: "2.6.0"}, nil)
 		logger = &fakes.Logger{}
-		command = commands.NewAssignMultiStemcell(fakeService, logger)
+		command = commands.NewAssignMultiStemcell(fakeService, logger, false)
 	})
 
 	When("--stemcell exists for the specified product", func() {
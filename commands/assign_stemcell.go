@@ -9,11 +9,13 @@ import (
 )
 
 type AssignStemcell struct {
-	logger  logger
-	service assignStemcellService
-	Options struct {
+	logger       logger
+	service      assignStemcellService
+	strict       bool
+	defaultFlags []string
+	Options      struct {
 		ConfigFile      string `long:"config"   short:"c"  description:"path to yml file for configuration (keys must match the following command line flags)"`
-		ProductName     string `long:"product"  short:"p"  description:"name of Ops Manager tile to associate a stemcell to" required:"true"`
+		ProductName     string `long:"product"  short:"p"  description:"name of Ops Manager tile to associate a stemcell to" required:"true" complete:"products"`
 		StemcellVersion string `long:"stemcell" short:"s"  description:"associate a particular stemcell version to a tile." default:"latest"`
 	}
 }
@@ -24,10 +26,11 @@ type assignStemcellService interface {
 	AssignStemcell(input api.ProductStemcells) error
 }
 
-func NewAssignStemcell(service assignStemcellService, logger logger) AssignStemcell {
+func NewAssignStemcell(service assignStemcellService, logger logger, strict bool) AssignStemcell {
 	return AssignStemcell{
 		service: service,
 		logger:  logger,
+		strict:  strict,
 	}
 }
 
@@ -40,8 +43,13 @@ func (as AssignStemcell) Usage() jhanda.Usage {
 	}
 }
 
+func (as AssignStemcell) WithDefaultFlags(defaultFlags []string) jhanda.Command {
+	as.defaultFlags = defaultFlags
+	return as
+}
+
 func (as AssignStemcell) Execute(args []string) error {
-	err := loadConfigFile(args, &as.Options, nil)
+	err := loadConfigFile(args, &as.Options, nil, as.strict, as.defaultFlags)
 	if err != nil {
 		return fmt.Errorf("could not parse assign-stemcell flags: %s", err)
 	}
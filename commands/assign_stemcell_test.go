@@ -20,7 +20,7 @@ var _ = Describe("AssignStemcell", func() {
 	BeforeEach(func() {
 		fakeService = &fakes.AssignStemcellService{}
 		logger = &fakes.Logger{}
-		command = commands.NewAssignStemcell(fakeService, logger)
+		command = commands.NewAssignStemcell(fakeService, logger, false)
 	})
 
 	When("--stemcell exists for the specified product", func() {
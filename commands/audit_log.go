@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/audit"
+	"github.com/pivotal-cf/om/models"
+	"github.com/pivotal-cf/om/presenters"
+)
+
+type AuditLog struct {
+	destination string
+	presenter   presenters.FormattedPresenter
+	Options     struct {
+		File     string `long:"file" description:"audit log file to query (default: the --audit-log of om)"`
+		Command  string `long:"command" description:"only list the records of this command"`
+		Target   string `long:"target" description:"only list the records of this Ops Manager target"`
+		User     string `long:"user" description:"only list the records of this user or client ID"`
+		Method   string `long:"method" description:"only list the records of this HTTP method"`
+		Endpoint string `long:"endpoint" description:"only list the records of the endpoints starting with this path"`
+		Since    string `long:"since" description:"only list the records since this duration ago (e.g. 24h) or this time (e.g. 2006-01-02 or 2006-01-02T15:04:05Z)"`
+		Format   string `long:"format" short:"f" description:"Format to print as (options: table,json,yaml,csv)" default:"table"`
+	}
+}
+
+func NewAuditLog(destination string, presenter presenters.FormattedPresenter) AuditLog {
+	return AuditLog{
+		destination: destination,
+		presenter:   presenter,
+	}
+}
+
+func (a AuditLog) Execute(args []string) error {
+	if _, err := jhanda.Parse(&a.Options, args); err != nil {
+		return fmt.Errorf("could not parse audit-log flags: %s", err)
+	}
+
+	if err := presenters.ValidateFormat(a.Options.Format); err != nil {
+		return fmt.Errorf("could not parse audit-log flags: %s", err)
+	}
+
+	since, err := parseSince(a.Options.Since, time.Now())
+	if err != nil {
+		return fmt.Errorf("could not parse audit-log flags: %s", err)
+	}
+
+	file := a.Options.File
+	if file == "" {
+		file = a.destination
+	}
+
+	switch file {
+	case "":
+		return errors.New("there is no audit log: set it with --audit-log, OM_AUDIT_LOG or the user config file, or query a file with --file")
+	case audit.Syslog:
+		return errors.New("the audit log is sent to syslog, which om cannot query: query a file with --file")
+	}
+
+	reader, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("could not open the audit log: %s", err)
+	}
+	defer reader.Close()
+
+	records, err := audit.Read(reader)
+	if err != nil {
+		return fmt.Errorf("could not read the audit log %s: %s", file, err)
+	}
+
+	matching := []models.AuditRecord{}
+	for _, record := range records {
+		if a.matches(record, since) {
+			matching = append(matching, record)
+		}
+	}
+
+	a.presenter.SetFormat(a.Options.Format)
+	a.presenter.PresentAuditRecords(matching)
+
+	return nil
+}
+
+func (a AuditLog) matches(record models.AuditRecord, since time.Time) bool {
+	switch {
+	case a.Options.Command != "" && record.Command != a.Options.Command:
+		return false
+	case a.Options.Target != "" && record.Target != a.Options.Target:
+		return false
+	case a.Options.User != "" && record.User != a.Options.User:
+		return false
+	case a.Options.Method != "" && !strings.EqualFold(record.Method, a.Options.Method):
+		return false
+	case !strings.HasPrefix(record.Endpoint, a.Options.Endpoint):
+		return false
+	case record.Time.Before(since):
+		return false
+	}
+
+	return true
+}
+
+func parseSince(since string, now time.Time) (time.Time, error) {
+	if since == "" {
+		return time.Time{}, nil
+	}
+
+	if duration, err := time.ParseDuration(since); err == nil {
+		return now.Add(-duration), nil
+	}
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, since); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("--since %q is neither a duration (e.g. 24h) nor a time (e.g. 2006-01-02 or 2006-01-02T15:04:05Z)", since)
+}
+
+func (a AuditLog) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This command lists the records of the audit log, which om writes with the global --audit-log for every request to the Ops Manager that is not a GET request.",
+		ShortDescription: "lists the records of the audit log",
+		Flags:            a.Options,
+	}
+}
@@ -0,0 +1,171 @@
+package commands_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/models"
+	presenterfakes "github.com/pivotal-cf/om/presenters/fakes"
+)
+
+var _ = Describe("AuditLog", func() {
+	var (
+		presenter *presenterfakes.FormattedPresenter
+		dir       string
+		logFile   string
+		records   []models.AuditRecord
+	)
+
+	BeforeEach(func() {
+		presenter = &presenterfakes.FormattedPresenter{}
+
+		var err error
+		dir, err = ioutil.TempDir("", "audit-log")
+		Expect(err).ToNot(HaveOccurred())
+
+		now := time.Now().UTC().Truncate(time.Second)
+		records = []models.AuditRecord{
+			{
+				Time:     now.Add(-48 * time.Hour),
+				Command:  "configure-director",
+				Args:     []string{"--config", "director.yml"},
+				Target:   "https://opsman.example.com",
+				User:     "admin",
+				Method:   "PUT",
+				Endpoint: "/api/v0/staged/director/properties",
+				Status:   200,
+			},
+			{
+				Time:     now.Add(-time.Hour),
+				Command:  "apply-changes",
+				Args:     []string{},
+				Target:   "https://opsman.example.com",
+				User:     "ci-client",
+				Method:   "POST",
+				Endpoint: "/api/v0/installations",
+				Status:   200,
+			},
+			{
+				Time:     now,
+				Command:  "curl",
+				Args:     []string{"--path", "/api/v0/staged/products/cf-guid", "--request", "DELETE"},
+				Target:   "https://other.example.com",
+				User:     "admin",
+				Method:   "DELETE",
+				Endpoint: "/api/v0/staged/products/cf-guid",
+				Status:   500,
+			},
+		}
+
+		var contents []byte
+		for _, record := range records {
+			line, err := json.Marshal(record)
+			Expect(err).ToNot(HaveOccurred())
+			contents = append(append(contents, line...), '\n')
+		}
+
+		logFile = filepath.Join(dir, "audit.log")
+		Expect(ioutil.WriteFile(logFile, contents, 0600)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("lists the records of the audit log", func() {
+		command := commands.NewAuditLog(logFile, presenter)
+		err := command.Execute([]string{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(presenter.SetFormatArgsForCall(0)).To(Equal("table"))
+		Expect(presenter.PresentAuditRecordsArgsForCall(0)).To(Equal(records))
+	})
+
+	It("filters the records", func() {
+		command := commands.NewAuditLog(logFile, presenter)
+
+		err := command.Execute([]string{"--user", "admin", "--method", "delete"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(presenter.PresentAuditRecordsArgsForCall(0)).To(Equal(records[2:]))
+
+		err = command.Execute([]string{"--since", "24h", "--target", "https://opsman.example.com"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(presenter.PresentAuditRecordsArgsForCall(1)).To(Equal(records[1:2]))
+
+		err = command.Execute([]string{"--command", "configure-director", "--endpoint", "/api/v0/staged"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(presenter.PresentAuditRecordsArgsForCall(2)).To(Equal(records[:1]))
+
+		err = command.Execute([]string{"--since", records[2].Time.Format(time.RFC3339)})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(presenter.PresentAuditRecordsArgsForCall(3)).To(Equal(records[2:]))
+
+		err = command.Execute([]string{"--endpoint", "/api/v1"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(presenter.PresentAuditRecordsArgsForCall(4)).To(Equal([]models.AuditRecord{}))
+	})
+
+	It("queries the file given with --file", func() {
+		command := commands.NewAuditLog("", presenter)
+		err := command.Execute([]string{"--file", logFile, "--format", "json"})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(presenter.SetFormatArgsForCall(0)).To(Equal("json"))
+		Expect(presenter.PresentAuditRecordsArgsForCall(0)).To(Equal(records))
+	})
+
+	When("there is no audit log", func() {
+		It("returns an error", func() {
+			command := commands.NewAuditLog("", presenter)
+			err := command.Execute([]string{})
+			Expect(err).To(MatchError(ContainSubstring("there is no audit log")))
+		})
+	})
+
+	When("the audit log is sent to syslog", func() {
+		It("returns an error", func() {
+			command := commands.NewAuditLog("syslog", presenter)
+			err := command.Execute([]string{})
+			Expect(err).To(MatchError(ContainSubstring("the audit log is sent to syslog, which om cannot query")))
+		})
+	})
+
+	When("the audit log cannot be read", func() {
+		It("returns an error", func() {
+			command := commands.NewAuditLog(filepath.Join(dir, "missing.log"), presenter)
+			err := command.Execute([]string{})
+			Expect(err).To(MatchError(ContainSubstring("could not open the audit log")))
+
+			Expect(ioutil.WriteFile(logFile, []byte("not json\n"), 0600)).To(Succeed())
+			command = commands.NewAuditLog(logFile, presenter)
+			err = command.Execute([]string{})
+			Expect(err).To(MatchError(ContainSubstring("could not read the audit log " + logFile)))
+		})
+	})
+
+	Describe("flags", func() {
+		It("returns an error when an unknown flag is given", func() {
+			command := commands.NewAuditLog(logFile, presenter)
+			err := command.Execute([]string{"--unknown-flag"})
+			Expect(err).To(MatchError(ContainSubstring("could not parse audit-log flags")))
+		})
+
+		It("returns an error when the format is not supported", func() {
+			command := commands.NewAuditLog(logFile, presenter)
+			err := command.Execute([]string{"--format", "xml"})
+			Expect(err).To(MatchError(ContainSubstring(`unsupported format "xml"`)))
+		})
+
+		It("returns an error when --since is neither a duration nor a time", func() {
+			command := commands.NewAuditLog(logFile, presenter)
+			err := command.Execute([]string{"--since", "yesterday"})
+			Expect(err).To(MatchError(ContainSubstring(`--since "yesterday" is neither a duration`)))
+		})
+	})
+})
@@ -14,7 +14,7 @@ type AvailableProducts struct {
 	presenter presenters.FormattedPresenter
 	logger    logger
 	Options   struct {
-		Format string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json)"`
+		Format string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json,yaml,csv)"`
 	}
 }
 
@@ -33,6 +33,10 @@ func (ap AvailableProducts) Execute(args []string) error {
 		return fmt.Errorf("could not parse available-products flags: %s", err)
 	}
 
+	if err := presenters.ValidateFormat(ap.Options.Format); err != nil {
+		return fmt.Errorf("could not parse available-products flags: %s", err)
+	}
+
 	output, err := ap.service.ListAvailableProducts()
 	if err != nil {
 		return err
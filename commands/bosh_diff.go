@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/fatih/color"
 	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/presenters"
 	"sort"
 	"strings"
 
@@ -14,11 +15,24 @@ type BoshDiff struct {
 	service boshDiffService
 	logger  logger
 	Options struct {
-		Product  []string `long:"product-name" short:"p" description:"Product to get diff for. Pass repeatedly for multiple products. If excluded, all staged non-director products will be shown."`
+		Product  []string `long:"product-name" short:"p" description:"Product to get diff for. Pass repeatedly for multiple products. If excluded, all staged non-director products will be shown." complete:"products"`
 		Director bool     `long:"director" short:"d" description:"Include director diffs. Can be combined with --product-name."`
+		Format   string   `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json)"`
 	}
 }
 
+// boshDiffOutput is the document printed with --format json.
+// The director is omitted when its diff has not been asked for.
+type boshDiffOutput struct {
+	Director *api.DirectorDiff   `json:"director,omitempty"`
+	Products []productDiffOutput `json:"products"`
+}
+
+type productDiffOutput struct {
+	Name string `json:"name"`
+	api.ProductDiff
+}
+
 //counterfeiter:generate -o ./fakes/diff_service.go --fake-name BoshDiffService . boshDiffService
 type boshDiffService interface {
 	DirectorDiff() (api.DirectorDiff, error)
@@ -38,7 +52,12 @@ func (c BoshDiff) Execute(args []string) error {
 		return fmt.Errorf("could not parse bosh-diff flags: %s", err)
 	}
 
+	if err := presenters.ValidateFormat(c.Options.Format, tableFormat, jsonFormat); err != nil {
+		return fmt.Errorf("could not parse bosh-diff flags: %s", err)
+	}
+
 	var diffableProducts []string
+	output := boshDiffOutput{Products: []productDiffOutput{}}
 
 	showDirectorAndProducts := !c.Options.Director && len(c.Options.Product) == 0
 
@@ -47,15 +66,11 @@ func (c BoshDiff) Execute(args []string) error {
 		if err != nil {
 			panic(err)
 		}
-		c.logger.Println("## Director Manifest\n")
-		notInstalled := c.printManifestDiff(diff.Manifest)
-		if !notInstalled {
-			c.logger.Println("## Director Cloud Config\n")
-			c.printManifestDiff(diff.CloudConfig)
-			c.logger.Println("## Director Runtime Configs\n")
-			c.printRuntimeConfigs(diff.RuntimeConfigs)
-			c.logger.Println("## Director CPI Configs\n")
-			c.printCPIConfigs(diff.CPIConfigs)
+
+		if c.Options.Format == jsonFormat {
+			output.Director = &diff
+		} else {
+			c.printDirectorDiff(diff)
 		}
 	}
 
@@ -81,6 +96,11 @@ func (c BoshDiff) Execute(args []string) error {
 			return err
 		}
 
+		if c.Options.Format == jsonFormat {
+			output.Products = append(output.Products, productDiffOutput{Name: product, ProductDiff: diff})
+			continue
+		}
+
 		c.logger.Printf("## Product Manifest for %s\n\n", product)
 
 		notInstalled := c.printManifestDiff(diff.Manifest)
@@ -90,9 +110,26 @@ func (c BoshDiff) Execute(args []string) error {
 		c.logger.Printf("## Runtime Configs for %s\n\n", product)
 		c.printRuntimeConfigs(diff.RuntimeConfigs)
 	}
+
+	if c.Options.Format == jsonFormat {
+		return printJSON(c.logger, output)
+	}
 	return nil
 }
 
+func (c BoshDiff) printDirectorDiff(diff api.DirectorDiff) {
+	c.logger.Println("## Director Manifest\n")
+	notInstalled := c.printManifestDiff(diff.Manifest)
+	if !notInstalled {
+		c.logger.Println("## Director Cloud Config\n")
+		c.printManifestDiff(diff.CloudConfig)
+		c.logger.Println("## Director Runtime Configs\n")
+		c.printRuntimeConfigs(diff.RuntimeConfigs)
+		c.logger.Println("## Director CPI Configs\n")
+		c.printCPIConfigs(diff.CPIConfigs)
+	}
+}
+
 func (c BoshDiff) printManifestDiff(diff api.ManifestDiff) (bool) {
 	switch diff.Status {
 	case "same":
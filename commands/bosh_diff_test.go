@@ -474,4 +474,64 @@ var _ = Describe("BoshDiff", func() {
 			})
 		})
 	})
+
+	When("the format is json", func() {
+		BeforeEach(func() {
+			service.DirectorDiffReturns(api.DirectorDiff{
+				Manifest:       api.ManifestDiff{Status: "different", Diff: "+  host: example.com"},
+				CloudConfig:    api.ManifestDiff{Status: "same"},
+				RuntimeConfigs: []api.RuntimeConfigsDiff{{Name: "dns", Status: "same"}},
+				CPIConfigs:     []api.CPIConfigsDiff{},
+			}, nil)
+
+			service.ProductDiffReturns(api.ProductDiff{
+				Manifest:       api.ManifestDiff{Status: "to_be_installed"},
+				RuntimeConfigs: []api.RuntimeConfigsDiff{},
+			}, nil)
+		})
+
+		It("prints the director and each product as a section of the document", func() {
+			diff := commands.NewBoshDiff(service, logger)
+			err = diff.Execute([]string{"--format", "json", "--director", "--product-name", "cf"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(service.ProductDiffArgsForCall(0)).To(Equal("cf"))
+			Expect(logBuffer.Contents()).To(MatchJSON(`{
+				"director": {
+					"manifest": {"status": "different", "diff": "+  host: example.com"},
+					"cloud_config": {"status": "same", "diff": ""},
+					"runtime_configs": [{"name": "dns", "status": "same", "diff": ""}],
+					"cpi_configs": []
+				},
+				"products": [{
+					"name": "cf",
+					"manifest": {"status": "to_be_installed", "diff": ""},
+					"runtime_configs": []
+				}]
+			}`))
+		})
+
+		It("does not include the director when it is not asked for", func() {
+			diff := commands.NewBoshDiff(service, logger)
+			err = diff.Execute([]string{"--format", "json", "--product-name", "cf"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(service.DirectorDiffCallCount()).To(Equal(0))
+			Expect(logBuffer.Contents()).To(MatchJSON(`{
+				"products": [{
+					"name": "cf",
+					"manifest": {"status": "to_be_installed", "diff": ""},
+					"runtime_configs": []
+				}]
+			}`))
+		})
+	})
+
+	When("the format is not supported", func() {
+		It("returns an error", func() {
+			diff := commands.NewBoshDiff(service, logger)
+			err = diff.Execute([]string{"--format", "yaml"})
+			Expect(err).To(MatchError(`could not parse bosh-diff flags: unsupported format "yaml", expected one of table, json`))
+		})
+	})
 })
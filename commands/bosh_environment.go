@@ -2,6 +2,7 @@ package commands
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -20,8 +21,9 @@ type BoshEnvironment struct {
 	rendererFactory rendererFactory
 	opsmanHost      string
 	Options         struct {
-		ShellType     string `long:"shell-type" description:"Prints for the given shell (posix|powershell)"`
+		ShellType     string `long:"shell-type" description:"Prints for the given shell (posix|powershell|fish|cmd)"`
 		SSHPrivateKey string `long:"ssh-private-key" short:"i" description:"Location of ssh private key to use to tunnel through the Ops Manager VM. Only necessary if bosh director is not reachable without a tunnel."`
+		SOCKS5Proxy   string `long:"socks5-proxy" description:"Address of a SOCKS5 proxy to the director network, as served by bosh-proxy (e.g. localhost:1080). Cannot be used with --ssh-private-key."`
 	}
 }
 
@@ -63,6 +65,10 @@ func (be BoshEnvironment) Execute(args []string) error {
 		return fmt.Errorf("could not parse bosh-env flags: %s", err)
 	}
 
+	if be.Options.SSHPrivateKey != "" && be.Options.SOCKS5Proxy != "" {
+		return fmt.Errorf("could not parse bosh-env flags: --ssh-private-key cannot be used with --socks5-proxy")
+	}
+
 	renderer, err := be.rendererFactory.Create(be.Options.ShellType)
 	if err != nil {
 		return err
@@ -108,6 +114,20 @@ func (be BoshEnvironment) Execute(args []string) error {
 		variables["BOSH_ALL_PROXY"] = fmt.Sprintf("ssh+socks5://ubuntu@%s:22?private-key=%s", be.Target(), file)
 		variables["CREDHUB_PROXY"] = variables["BOSH_ALL_PROXY"]
 	}
+
+	if be.Options.SOCKS5Proxy != "" {
+		variables["BOSH_ALL_PROXY"] = fmt.Sprintf("socks5://%s", be.Options.SOCKS5Proxy)
+		variables["CREDHUB_PROXY"] = variables["BOSH_ALL_PROXY"]
+		variables["ALL_PROXY"] = variables["BOSH_ALL_PROXY"]
+	}
+
+	if renderer.Type() == renderers.ShellTypeCmd {
+		err = writeMultilineVariables(variables)
+		if err != nil {
+			return err
+		}
+	}
+
 	be.renderVariables(renderer, variables)
 
 	return nil
@@ -115,7 +135,7 @@ func (be BoshEnvironment) Execute(args []string) error {
 
 func (be BoshEnvironment) Usage() jhanda.Usage {
 	return jhanda.Usage{
-		Description:      "This prints bosh environment variables to target bosh director. You can invoke it directly to see its output, or use it directly with an evaluate-type command:\nOn posix system: eval \"$(om bosh-env)\"\nOn powershell: iex $(om bosh-env | Out-String)",
+		Description:      "This prints bosh environment variables to target bosh director. You can invoke it directly to see its output, or use it directly with an evaluate-type command:\nOn posix system: eval \"$(om bosh-env)\"\nOn powershell: iex $(om bosh-env | Out-String)\nOn fish: om bosh-env --shell-type fish | source\nOn cmd: for /f \"delims=\" %i in ('om bosh-env --shell-type cmd') do @%i",
 		ShortDescription: "prints bosh environment variables",
 		Flags:            be.Options,
 	}
@@ -127,6 +147,35 @@ func (be BoshEnvironment) renderVariables(renderer renderers.Renderer, variables
 	}
 }
 
+// cmd.exe cannot set multi-line values, so they are written to files
+// and the variables are set to their paths, which the bosh and credhub CLIs accept.
+func writeMultilineVariables(variables map[string]string) error {
+	for name, value := range variables {
+		if !strings.Contains(value, "\n") {
+			continue
+		}
+
+		// a new file is created every time, so another user of the temp dir
+		// or another om targeting another Ops Manager cannot replace it
+		file, err := ioutil.TempFile("", fmt.Sprintf("om-bosh-env-%s-*.pem", strings.ToLower(name)))
+		if err != nil {
+			return fmt.Errorf("could not write %s to a file: %s", name, err)
+		}
+
+		_, err = file.WriteString(value)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("could not write %s to a file: %s", name, err)
+		}
+
+		variables[name] = file.Name()
+	}
+
+	return nil
+}
+
 // Get the absolute path of a key file. Because we currently do no checking to see if the private key exists, if it doesn't, emulate existing behavior.
 func getKeyFilePath(s string) (string, error) {
 	f, err := os.Open(s)
@@ -159,6 +159,77 @@ var _ = Describe("bosh-env", func() {
 			})
 		})
 
+		Describe("Execute with a SOCKS5 proxy", func() {
+			It("prints the proxy variables", func() {
+				err := command.Execute([]string{"--socks5-proxy", "localhost:1080"})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				var lines []string
+				for i := 0; i < stdout.PrintlnCallCount(); i++ {
+					lines = append(lines, fmt.Sprintf("%v", stdout.PrintlnArgsForCall(i)))
+				}
+
+				Expect(lines).To(HaveLen(11))
+				Expect(lines).To(ContainElement("[export BOSH_ALL_PROXY=socks5://localhost:1080]"))
+				Expect(lines).To(ContainElement("[export CREDHUB_PROXY=socks5://localhost:1080]"))
+				Expect(lines).To(ContainElement("[export ALL_PROXY=socks5://localhost:1080]"))
+			})
+
+			It("cannot be used with an ssh key", func() {
+				err := command.Execute([]string{"--socks5-proxy", "localhost:1080", "-i", "somepath.pem"})
+				Expect(err).To(MatchError("could not parse bosh-env flags: --ssh-private-key cannot be used with --socks5-proxy"))
+			})
+		})
+
+		Describe("Execute with the cmd shell type", func() {
+			var tmpDir string
+
+			BeforeEach(func() {
+				var err error
+				tmpDir, err = ioutil.TempDir("", "")
+				Expect(err).ToNot(HaveOccurred())
+				os.Setenv("TMPDIR", tmpDir)
+
+				fakeRendererFactory.CreateReturns(renderers.NewCmd(), nil)
+			})
+
+			AfterEach(func() {
+				os.Unsetenv("TMPDIR")
+				os.RemoveAll(tmpDir)
+			})
+
+			It("sets the multi-line variables to the path of a file", func() {
+				err := command.Execute([]string{"--shell-type", "cmd"})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				Expect(fakeRendererFactory.CreateArgsForCall(0)).To(Equal("cmd"))
+
+				var lines []string
+				for i := 0; i < stdout.PrintlnCallCount(); i++ {
+					lines = append(lines, fmt.Sprintf("%v", stdout.PrintlnArgsForCall(i)))
+				}
+
+				caCertFiles, err := filepath.Glob(filepath.Join(tmpDir, "om-bosh-env-bosh_ca_cert-*.pem"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(caCertFiles).To(HaveLen(1))
+				credhubCACertFiles, err := filepath.Glob(filepath.Join(tmpDir, "om-bosh-env-credhub_ca_cert-*.pem"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(credhubCACertFiles).To(HaveLen(1))
+
+				Expect(lines).To(ContainElement(fmt.Sprintf(`[set BOSH_CA_CERT=%s]`, caCertFiles[0])))
+				Expect(lines).To(ContainElement(fmt.Sprintf(`[set CREDHUB_CA_CERT=%s]`, credhubCACertFiles[0])))
+				Expect(lines).To(ContainElement(`[set BOSH_CLIENT=opsmanager_client]`))
+
+				contents, err := ioutil.ReadFile(caCertFiles[0])
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(contents)).To(Equal("-----BEGIN CERTIFICATE-----\nMIIC+zCCAeOgAwIBAgI...."))
+
+				info, err := os.Stat(caCertFiles[0])
+				Expect(err).ToNot(HaveOccurred())
+				Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+			})
+		})
+
 		Describe("Execute without ssh key", func() {
 			It("executes the API call", func() {
 				err := command.Execute([]string{})
@@ -173,7 +244,7 @@ var _ = Describe("bosh-env", func() {
 		It("returns the usage information for the bosh-env command", func() {
 			command := commands.NewBoshEnvironment(nil, nil, "", nil)
 			Expect(command.Usage()).To(Equal(jhanda.Usage{
-				Description:      "This prints bosh environment variables to target bosh director. You can invoke it directly to see its output, or use it directly with an evaluate-type command:\nOn posix system: eval \"$(om bosh-env)\"\nOn powershell: iex $(om bosh-env | Out-String)",
+				Description:      "This prints bosh environment variables to target bosh director. You can invoke it directly to see its output, or use it directly with an evaluate-type command:\nOn posix system: eval \"$(om bosh-env)\"\nOn powershell: iex $(om bosh-env | Out-String)\nOn fish: om bosh-env --shell-type fish | source\nOn cmd: for /f \"delims=\" %i in ('om bosh-env --shell-type cmd') do @%i",
 				ShortDescription: "prints bosh environment variables",
 				Flags:            command.Options,
 			}))
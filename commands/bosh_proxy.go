@@ -0,0 +1,148 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"sync"
+
+	"github.com/pivotal-cf/jhanda"
+	"golang.org/x/crypto/ssh"
+)
+
+type BoshProxy struct {
+	opsmanHost string
+	logger     logger
+	dialSSH    func(network, addr string, config *ssh.ClientConfig) (*ssh.Client, error)
+	listen     func(network, address string) (net.Listener, error)
+	Options    struct {
+		SSHPrivateKey string `long:"ssh-private-key" short:"i" required:"true"            description:"location of the ssh private key of the Ops Manager VM"`
+		Listen        string `long:"listen"          short:"l" default:"localhost:1080" description:"address to serve the SOCKS5 proxy on"`
+		User          string `long:"user"            short:"u" default:"ubuntu"         description:"user to ssh to the Ops Manager VM as"`
+	}
+}
+
+func NewBoshProxy(opsmanHost string, logger logger, dialSSH func(network, addr string, config *ssh.ClientConfig) (*ssh.Client, error), listen func(network, address string) (net.Listener, error)) BoshProxy {
+	return BoshProxy{
+		opsmanHost: opsmanHost,
+		logger:     logger,
+		dialSSH:    dialSSH,
+		listen:     listen,
+	}
+}
+
+func (bp BoshProxy) Execute(args []string) error {
+	if _, err := jhanda.Parse(&bp.Options, args); err != nil {
+		return fmt.Errorf("could not parse bosh-proxy flags: %s", err)
+	}
+
+	host, err := targetHost(bp.opsmanHost)
+	if err != nil {
+		return err
+	}
+
+	key, err := ioutil.ReadFile(bp.Options.SSHPrivateKey)
+	if err != nil {
+		return fmt.Errorf("could not read the ssh private key: %s", err)
+	}
+
+	signer, err := ssh.ParsePrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("could not parse the ssh private key: %s", err)
+	}
+
+	tunnel := &sshTunnel{
+		dialSSH: bp.dialSSH,
+		addr:    net.JoinHostPort(host, "22"),
+		config: &ssh.ClientConfig{
+			User: bp.Options.User,
+			Auth: []ssh.AuthMethod{ssh.PublicKeys(signer)},
+			// the host key is not known ahead of time,
+			// like for the ssh+socks5 proxy of BOSH_ALL_PROXY
+			HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		},
+	}
+	defer tunnel.close()
+
+	err = tunnel.connect()
+	if err != nil {
+		return err
+	}
+
+	listener, err := bp.listen("tcp", bp.Options.Listen)
+	if err != nil {
+		return fmt.Errorf("could not listen on %s: %s", bp.Options.Listen, err)
+	}
+	defer listener.Close()
+
+	bp.logger.Printf("serving a SOCKS5 proxy on %s through %s\n", listener.Addr(), tunnel.addr)
+	bp.logger.Printf("to target the director, run: eval \"$(om bosh-env --socks5-proxy %s)\"\n", listener.Addr())
+
+	return serveSOCKS5(listener, tunnel.dial, bp.logger)
+}
+
+// sshTunnel reconnects when the ssh connection to the Ops Manager VM has been lost.
+type sshTunnel struct {
+	dialSSH func(network, addr string, config *ssh.ClientConfig) (*ssh.Client, error)
+	addr    string
+	config  *ssh.ClientConfig
+
+	mutex  sync.Mutex
+	client *ssh.Client
+}
+
+func (t *sshTunnel) connect() error {
+	client, err := t.dialSSH("tcp", t.addr, t.config)
+	if err != nil {
+		return fmt.Errorf("could not ssh to %s: %s", t.addr, err)
+	}
+
+	t.client = client
+	return nil
+}
+
+func (t *sshTunnel) dial(network, address string) (net.Conn, error) {
+	t.mutex.Lock()
+	client := t.client
+	t.mutex.Unlock()
+
+	conn, err := client.Dial(network, address)
+	if err == nil {
+		return conn, nil
+	}
+
+	if _, ok := err.(*ssh.OpenChannelError); ok {
+		return nil, err
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.client == client {
+		_ = client.Close()
+		err = t.connect()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return t.client.Dial(network, address)
+}
+
+func (t *sshTunnel) close() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.client != nil {
+		_ = t.client.Close()
+	}
+}
+
+func (bp BoshProxy) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description: "This command opens an ssh tunnel to the Ops Manager VM and serves a local SOCKS5 proxy through it, until it is interrupted." +
+			" Use it with bosh-env --socks5-proxy to reach the director network with tools that support SOCKS5 proxies, such as bosh, credhub, curl and kubectl.",
+		ShortDescription: "serves a SOCKS5 proxy to the director network through the Ops Manager VM",
+		Flags:            bp.Options,
+	}
+}
@@ -0,0 +1,407 @@
+package commands_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/om/commands"
+	"golang.org/x/crypto/ssh"
+)
+
+// testSSHServer forwards the direct-tcpip channels, like the sshd of the Ops Manager VM.
+type testSSHServer struct {
+	listener net.Listener
+	mutex    sync.Mutex
+	conns    []net.Conn
+}
+
+func newTestSSHServer(authorized ssh.PublicKey) *testSSHServer {
+	hostKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	Expect(err).ToNot(HaveOccurred())
+	hostSigner, err := ssh.NewSignerFromKey(hostKey)
+	Expect(err).ToNot(HaveOccurred())
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if conn.User() == "ubuntu" && bytes.Equal(key.Marshal(), authorized.Marshal()) {
+				return nil, nil
+			}
+			return nil, errors.New("unauthorized")
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).ToNot(HaveOccurred())
+
+	server := &testSSHServer{listener: listener}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			server.mutex.Lock()
+			server.conns = append(server.conns, conn)
+			server.mutex.Unlock()
+
+			go server.handle(conn, config)
+		}
+	}()
+
+	return server
+}
+
+func (s *testSSHServer) handle(conn net.Conn, config *ssh.ServerConfig) {
+	_, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(requests)
+
+	for newChannel := range channels {
+		var payload struct {
+			Host       string
+			Port       uint32
+			OriginHost string
+			OriginPort uint32
+		}
+		err := ssh.Unmarshal(newChannel.ExtraData(), &payload)
+		if err != nil || newChannel.ChannelType() != "direct-tcpip" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unsupported")
+			continue
+		}
+
+		target, err := net.Dial("tcp", net.JoinHostPort(payload.Host, strconv.Itoa(int(payload.Port))))
+		if err != nil {
+			_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			_ = target.Close()
+			continue
+		}
+		go ssh.DiscardRequests(channelRequests)
+
+		go func() {
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				_, _ = io.Copy(channel, target)
+				_ = channel.CloseWrite()
+			}()
+			go func() {
+				defer wg.Done()
+				_, _ = io.Copy(target, channel)
+				_ = target.(*net.TCPConn).CloseWrite()
+			}()
+			wg.Wait()
+			_ = channel.Close()
+			_ = target.Close()
+		}()
+	}
+}
+
+func (s *testSSHServer) dropConnections() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.conns = nil
+}
+
+var _ = Describe("BoshProxy", func() {
+	var (
+		stdout    *gbytes.Buffer
+		logger    *log.Logger
+		keyFile   string
+		sshServer *testSSHServer
+		echo      net.Listener
+		echoAddr  *net.TCPAddr
+
+		dialedAddrs []string
+		dialMutex   sync.Mutex
+		dialSSH     func(network, addr string, config *ssh.ClientConfig) (*ssh.Client, error)
+		listeners   chan net.Listener
+		listen      func(network, address string) (net.Listener, error)
+		executeErr  chan error
+	)
+
+	BeforeEach(func() {
+		stdout = gbytes.NewBuffer()
+		logger = log.New(stdout, "", 0)
+
+		clientKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).ToNot(HaveOccurred())
+		clientPublicKey, err := ssh.NewPublicKey(&clientKey.PublicKey)
+		Expect(err).ToNot(HaveOccurred())
+
+		file, err := ioutil.TempFile("", "opsman-key")
+		Expect(err).ToNot(HaveOccurred())
+		_, err = file.Write(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(clientKey)}))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(file.Close()).To(Succeed())
+		keyFile = file.Name()
+
+		sshServer = newTestSSHServer(clientPublicKey)
+
+		echo, err = net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+		echoAddr = echo.Addr().(*net.TCPAddr)
+		go func() {
+			for {
+				conn, err := echo.Accept()
+				if err != nil {
+					return
+				}
+				go func() {
+					_, _ = io.Copy(conn, conn)
+					_ = conn.Close()
+				}()
+			}
+		}()
+
+		dialedAddrs = nil
+		dialSSH = func(network, addr string, config *ssh.ClientConfig) (*ssh.Client, error) {
+			dialMutex.Lock()
+			dialedAddrs = append(dialedAddrs, addr)
+			dialMutex.Unlock()
+			return ssh.Dial(network, sshServer.listener.Addr().String(), config)
+		}
+
+		listeners = make(chan net.Listener, 1)
+		listen = func(network, address string) (net.Listener, error) {
+			Expect(address).To(Equal("localhost:1080"))
+			listener, err := net.Listen(network, "127.0.0.1:0")
+			listeners <- listener
+			return listener, err
+		}
+
+		executeErr = make(chan error, 1)
+	})
+
+	AfterEach(func() {
+		_ = sshServer.listener.Close()
+		_ = echo.Close()
+		_ = os.Remove(keyFile)
+	})
+
+	start := func(args ...string) net.Listener {
+		command := commands.NewBoshProxy("https://opsman.example.com", logger, dialSSH, listen)
+		go func() {
+			executeErr <- command.Execute(append([]string{"--ssh-private-key", keyFile}, args...))
+		}()
+
+		var listener net.Listener
+		Eventually(listeners).Should(Receive(&listener))
+		return listener
+	}
+
+	connect := func(proxy net.Listener, request []byte) (net.Conn, []byte) {
+		conn, err := net.Dial("tcp", proxy.Addr().String())
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = conn.Write([]byte{5, 1, 0})
+		Expect(err).ToNot(HaveOccurred())
+
+		method := make([]byte, 2)
+		_, err = io.ReadFull(conn, method)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(method).To(Equal([]byte{5, 0}))
+
+		_, err = conn.Write(request)
+		Expect(err).ToNot(HaveOccurred())
+
+		reply := make([]byte, 10)
+		_, err = io.ReadFull(conn, reply)
+		Expect(err).ToNot(HaveOccurred())
+
+		return conn, reply
+	}
+
+	connectRequest := func(addressType byte, address []byte, port int) []byte {
+		request := append([]byte{5, 1, 0, addressType}, address...)
+		portBytes := make([]byte, 2)
+		binary.BigEndian.PutUint16(portBytes, uint16(port))
+		return append(request, portBytes...)
+	}
+
+	expectEcho := func(conn net.Conn) {
+		_, err := conn.Write([]byte("hello"))
+		Expect(err).ToNot(HaveOccurred())
+
+		echoed := make([]byte, 5)
+		_, err = io.ReadFull(conn, echoed)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(echoed)).To(Equal("hello"))
+	}
+
+	It("proxies connections through the ssh tunnel to the Ops Manager VM", func() {
+		proxy := start()
+		defer proxy.Close()
+
+		Eventually(stdout).Should(gbytes.Say(`serving a SOCKS5 proxy on 127.0.0.1:\d+ through opsman.example.com:22`))
+		Eventually(stdout).Should(gbytes.Say(`eval "\$\(om bosh-env --socks5-proxy 127.0.0.1:\d+\)"`))
+
+		conn, reply := connect(proxy, connectRequest(1, echoAddr.IP.To4(), echoAddr.Port))
+		defer conn.Close()
+		Expect(reply[:2]).To(Equal([]byte{5, 0}))
+		expectEcho(conn)
+
+		dialMutex.Lock()
+		Expect(dialedAddrs).To(Equal([]string{"opsman.example.com:22"}))
+		dialMutex.Unlock()
+	})
+
+	It("proxies connections to domain names", func() {
+		proxy := start()
+		defer proxy.Close()
+
+		conn, reply := connect(proxy, connectRequest(3, append([]byte{9}, "localhost"...), echoAddr.Port))
+		defer conn.Close()
+		Expect(reply[:2]).To(Equal([]byte{5, 0}))
+		expectEcho(conn)
+	})
+
+	It("keeps reading the response after the client has stopped writing", func() {
+		proxy := start()
+		defer proxy.Close()
+
+		conn, reply := connect(proxy, connectRequest(1, echoAddr.IP.To4(), echoAddr.Port))
+		defer conn.Close()
+		Expect(reply[:2]).To(Equal([]byte{5, 0}))
+
+		_, err := conn.Write([]byte("hello"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(conn.(*net.TCPConn).CloseWrite()).To(Succeed())
+
+		echoed, err := ioutil.ReadAll(conn)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(echoed)).To(Equal("hello"))
+	})
+
+	It("reconnects when the ssh connection has been lost", func() {
+		proxy := start()
+		defer proxy.Close()
+
+		sshServer.dropConnections()
+
+		conn, reply := connect(proxy, connectRequest(1, echoAddr.IP.To4(), echoAddr.Port))
+		defer conn.Close()
+		Expect(reply[:2]).To(Equal([]byte{5, 0}))
+		expectEcho(conn)
+
+		dialMutex.Lock()
+		Expect(dialedAddrs).To(HaveLen(2))
+		dialMutex.Unlock()
+	})
+
+	It("stops when the listener is closed", func() {
+		proxy := start()
+		Expect(proxy.Close()).To(Succeed())
+
+		Eventually(executeErr).Should(Receive(HaveOccurred()))
+	})
+
+	Describe("SOCKS5 failures", func() {
+		It("replies with a failure when the address cannot be reached", func() {
+			proxy := start()
+			defer proxy.Close()
+
+			closed, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).ToNot(HaveOccurred())
+			port := closed.Addr().(*net.TCPAddr).Port
+			Expect(closed.Close()).To(Succeed())
+
+			conn, reply := connect(proxy, connectRequest(1, []byte{127, 0, 0, 1}, port))
+			defer conn.Close()
+			Expect(reply[:2]).To(Equal([]byte{5, 1}))
+			Eventually(stdout).Should(gbytes.Say("could not proxy connection from .*: could not connect to 127.0.0.1:"))
+		})
+
+		It("replies that other commands are not supported", func() {
+			proxy := start()
+			defer proxy.Close()
+
+			request := connectRequest(1, []byte{127, 0, 0, 1}, echoAddr.Port)
+			request[1] = 2
+			conn, reply := connect(proxy, request)
+			defer conn.Close()
+			Expect(reply[:2]).To(Equal([]byte{5, 7}))
+		})
+
+		It("refuses clients that require authentication", func() {
+			proxy := start()
+			defer proxy.Close()
+
+			conn, err := net.Dial("tcp", proxy.Addr().String())
+			Expect(err).ToNot(HaveOccurred())
+			defer conn.Close()
+
+			_, err = conn.Write([]byte{5, 1, 2})
+			Expect(err).ToNot(HaveOccurred())
+
+			method := make([]byte, 2)
+			_, err = io.ReadFull(conn, method)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(method).To(Equal([]byte{5, 0xff}))
+		})
+	})
+
+	Describe("failures", func() {
+		It("returns an error when the ssh connection fails", func() {
+			command := commands.NewBoshProxy("opsman.example.com", logger, dialSSH, listen)
+			err := command.Execute([]string{"--ssh-private-key", keyFile, "--user", "root"})
+			Expect(err).To(MatchError(ContainSubstring("could not ssh to opsman.example.com:22: ")))
+		})
+
+		It("returns an error when the key cannot be read", func() {
+			command := commands.NewBoshProxy("opsman.example.com", logger, dialSSH, listen)
+			err := command.Execute([]string{"--ssh-private-key", "/does/not/exist"})
+			Expect(err).To(MatchError(ContainSubstring("could not read the ssh private key: ")))
+		})
+
+		It("returns an error when the key cannot be parsed", func() {
+			Expect(ioutil.WriteFile(keyFile, []byte("not a key"), 0600)).To(Succeed())
+
+			command := commands.NewBoshProxy("opsman.example.com", logger, dialSSH, listen)
+			err := command.Execute([]string{"--ssh-private-key", keyFile})
+			Expect(err).To(MatchError(ContainSubstring("could not parse the ssh private key: ")))
+		})
+
+		It("returns an error when it cannot listen", func() {
+			command := commands.NewBoshProxy("opsman.example.com", logger, dialSSH, func(string, string) (net.Listener, error) {
+				return nil, errors.New("address already in use")
+			})
+			err := command.Execute([]string{"--ssh-private-key", keyFile})
+			Expect(err).To(MatchError("could not listen on localhost:1080: address already in use"))
+		})
+
+		It("returns an error when the ssh key is not provided", func() {
+			command := commands.NewBoshProxy("opsman.example.com", logger, dialSSH, listen)
+			err := command.Execute([]string{})
+			Expect(err).To(MatchError(`could not parse bosh-proxy flags: missing required flag "--ssh-private-key"`))
+		})
+	})
+})
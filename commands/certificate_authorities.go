@@ -12,7 +12,7 @@ type CertificateAuthorities struct {
 	service   certificateAuthoritiesService
 	presenter presenters.FormattedPresenter
 	Options   struct {
-		Format string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json)"`
+		Format string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json,yaml,csv)"`
 	}
 }
 
@@ -34,6 +34,10 @@ func (c CertificateAuthorities) Execute(args []string) error {
 		return fmt.Errorf("could not parse certificate-authorities flags: %s", err)
 	}
 
+	if err := presenters.ValidateFormat(c.Options.Format); err != nil {
+		return fmt.Errorf("could not parse certificate-authorities flags: %s", err)
+	}
+
 	casOutput, err := c.service.ListCertificateAuthorities()
 	if err != nil {
 		return err
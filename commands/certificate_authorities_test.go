@@ -77,6 +77,13 @@ var _ = Describe("Certificate Authorities", func() {
 			})
 		})
 
+		When("the format is not supported", func() {
+			It("returns an error", func() {
+				err := certificateAuthorities.Execute([]string{"--format", "xml"})
+				Expect(err).To(MatchError(`could not parse certificate-authorities flags: unsupported format "xml", expected one of table, json, yaml, csv`))
+			})
+		})
+
 		When("the flag cannot parsed", func() {
 			It("returns an error", func() {
 				err := certificateAuthorities.Execute([]string{"--bogus", "nothing"})
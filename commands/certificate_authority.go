@@ -14,7 +14,7 @@ type CertificateAuthority struct {
 	Options   struct {
 		ID      string `long:"id" required:"true" description:"ID of certificate to display"`
 		CertPEM bool   `long:"cert-pem" description:"Display the cert pem"`
-		Format  string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json)"`
+		Format  string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json,yaml,csv)"`
 	}
 }
 
@@ -31,6 +31,10 @@ func (c CertificateAuthority) Execute(args []string) error {
 		return fmt.Errorf("could not parse certificate-authority flags: %s", err)
 	}
 
+	if err := presenters.ValidateFormat(c.Options.Format); err != nil {
+		return fmt.Errorf("could not parse certificate-authority flags: %s", err)
+	}
+
 	cas, err := c.service.ListCertificateAuthorities()
 	if err != nil {
 		return err
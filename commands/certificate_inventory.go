@@ -0,0 +1,268 @@
+package commands
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/models"
+	"github.com/pivotal-cf/om/presenters"
+)
+
+//counterfeiter:generate -o ./fakes/certificate_inventory_service.go --fake-name CertificateInventoryService . certificateInventoryService
+type certificateInventoryService interface {
+	ListCertificateAuthorities() (api.CertificateAuthoritiesOutput, error)
+	GetSSLCertificate() (api.SSLCertificateOutput, error)
+	GetSecurityRootCACertificate() (string, error)
+	ListExpiringCertificates(string) ([]api.ExpiringCertificate, error)
+}
+
+type CertificateInventory struct {
+	service   certificateInventoryService
+	presenter presenters.FormattedPresenter
+	Options   struct {
+		Format        string `long:"format"         short:"f" default:"table" description:"Format to print as (options: table,json,yaml,csv)"`
+		ExpiresWithin string `long:"expires-within" short:"e" default:"10y"   description:"timeframe of the deployed certificates to include.\n\t\t\t\tdays(d), weeks(w), months(m) and years(y) supported."`
+	}
+}
+
+func NewCertificateInventory(service certificateInventoryService, presenter presenters.FormattedPresenter) CertificateInventory {
+	return CertificateInventory{
+		service:   service,
+		presenter: presenter,
+	}
+}
+
+func (c CertificateInventory) Execute(args []string) error {
+	if _, err := jhanda.Parse(&c.Options, args); err != nil {
+		return fmt.Errorf("could not parse certificate-inventory flags: %s", err)
+	}
+
+	if err := presenters.ValidateFormat(c.Options.Format); err != nil {
+		return fmt.Errorf("could not parse certificate-inventory flags: %s", err)
+	}
+
+	if err := validateExpiresWithin(c.Options.ExpiresWithin); err != nil {
+		return fmt.Errorf("could not parse certificate-inventory flags: %s", err)
+	}
+
+	cas, err := c.service.ListCertificateAuthorities()
+	if err != nil {
+		return fmt.Errorf("could not list certificate authorities: %s", err)
+	}
+
+	ssl, err := c.service.GetSSLCertificate()
+	if err != nil {
+		return fmt.Errorf("could not get the Ops Manager SSL certificate: %s", err)
+	}
+
+	rootCA, err := c.service.GetSecurityRootCACertificate()
+	if err != nil {
+		return fmt.Errorf("could not get the root CA certificate: %s", err)
+	}
+
+	deployed, err := c.service.ListExpiringCertificates(c.Options.ExpiresWithin)
+	if err != nil {
+		return fmt.Errorf("could not list deployed certificates: %s", err)
+	}
+
+	inventory := newCertificatePool()
+
+	for _, ca := range cas.CAs {
+		err = inventory.add("certificate_authority", ca.GUID, ca.CertPEM)
+		if err != nil {
+			return err
+		}
+	}
+
+	if ssl.Certificate.Certificate != "" {
+		err = inventory.add("ssl_certificate", "ops_manager", ssl.Certificate.Certificate)
+		if err != nil {
+			return err
+		}
+	}
+
+	if rootCA != "" {
+		err = inventory.add("root_ca", "root_ca", rootCA)
+		if err != nil {
+			return err
+		}
+	}
+
+	certificates := inventory.certificates()
+
+	for _, cert := range deployed {
+		name := cert.PropertyReference
+		if name == "" {
+			name = cert.VariablePath
+		}
+
+		notBefore, notAfter := cert.ValidFrom, cert.ValidUntil
+		certificates = append(certificates, models.Certificate{
+			Source:      "deployed",
+			Name:        name,
+			Product:     cert.ProductGUID,
+			Location:    cert.Location,
+			SANs:        []string{},
+			Issuer:      cert.Issuer,
+			IssuerChain: []string{cert.Issuer},
+			NotBefore:   &notBefore,
+			NotAfter:    &notAfter,
+			DaysLeft:    daysLeft(notAfter),
+		})
+	}
+
+	c.presenter.SetFormat(c.Options.Format)
+	c.presenter.PresentCertificateInventory(certificates)
+
+	return nil
+}
+
+type pooledCertificate struct {
+	source string
+	name   string
+	cert   *x509.Certificate
+}
+
+// certificatePool decodes the certificates that are exposed as PEM,
+// and keeps every certificate of a bundle to resolve the issuer chains.
+type certificatePool struct {
+	entries   []pooledCertificate
+	bySubject map[string]*x509.Certificate
+}
+
+func newCertificatePool() *certificatePool {
+	return &certificatePool{bySubject: map[string]*x509.Certificate{}}
+}
+
+func (p *certificatePool) add(source, name, pemData string) error {
+	var certs []*x509.Certificate
+
+	rest := []byte(pemData)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("could not parse the certificate of %s %s: %s", source, name, err)
+		}
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return fmt.Errorf("could not find a PEM encoded certificate for %s %s", source, name)
+	}
+
+	for _, cert := range certs {
+		if _, ok := p.bySubject[string(cert.RawSubject)]; !ok {
+			p.bySubject[string(cert.RawSubject)] = cert
+		}
+	}
+
+	p.entries = append(p.entries, pooledCertificate{source: source, name: name, cert: certs[0]})
+
+	return nil
+}
+
+func (p *certificatePool) certificates() []models.Certificate {
+	var certificates []models.Certificate
+	for _, entry := range p.entries {
+		cert := entry.cert
+		keyType, keySize := publicKeyDetails(cert)
+		notBefore, notAfter := cert.NotBefore, cert.NotAfter
+
+		certificates = append(certificates, models.Certificate{
+			Source:             entry.source,
+			Name:               entry.name,
+			Subject:            cert.Subject.String(),
+			SANs:               subjectAlternativeNames(cert),
+			KeyType:            keyType,
+			KeySize:            keySize,
+			SignatureAlgorithm: cert.SignatureAlgorithm.String(),
+			Issuer:             cert.Issuer.String(),
+			IssuerChain:        p.issuerChain(cert),
+			NotBefore:          &notBefore,
+			NotAfter:           &notAfter,
+			DaysLeft:           daysLeft(notAfter),
+		})
+	}
+
+	return certificates
+}
+
+// issuerChain follows the issuers through the known certificates,
+// until a self-signed certificate or an unknown issuer.
+func (p *certificatePool) issuerChain(cert *x509.Certificate) []string {
+	chain := []string{}
+	seen := map[*x509.Certificate]bool{}
+
+	for cert != nil && !seen[cert] && !selfSigned(cert) {
+		seen[cert] = true
+		chain = append(chain, cert.Issuer.String())
+		cert = p.bySubject[string(cert.RawIssuer)]
+	}
+
+	if len(chain) == 0 {
+		chain = append(chain, cert.Issuer.String())
+	}
+
+	return chain
+}
+
+func selfSigned(cert *x509.Certificate) bool {
+	return string(cert.RawIssuer) == string(cert.RawSubject)
+}
+
+func publicKeyDetails(cert *x509.Certificate) (string, int) {
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return "RSA", key.N.BitLen()
+	case *ecdsa.PublicKey:
+		return "ECDSA", key.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return "Ed25519", 256
+	}
+
+	return cert.PublicKeyAlgorithm.String(), 0
+}
+
+func subjectAlternativeNames(cert *x509.Certificate) []string {
+	sans := append([]string{}, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	sans = append(sans, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+
+	return sans
+}
+
+func daysLeft(notAfter time.Time) int {
+	return int(math.Floor(time.Until(notAfter).Hours() / 24))
+}
+
+func (c CertificateInventory) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description: "This authenticated command lists every certificate that Ops Manager exposes:" +
+			" the certificate authorities, the Ops Manager SSL certificate, the root CA certificate, and the deployed certificates." +
+			" The certificates that are available as PEM are decoded into their subject, SANs, key, signature algorithm and issuer chain.",
+		ShortDescription: "lists every certificate of the Ops Manager with its details",
+		Flags:            c.Options,
+	}
+}
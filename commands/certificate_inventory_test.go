@@ -0,0 +1,268 @@
+package commands_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+	"github.com/pivotal-cf/om/models"
+	presenterfakes "github.com/pivotal-cf/om/presenters/fakes"
+)
+
+type testCertificate struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+	pem  string
+}
+
+func newTestCertificate(template *x509.Certificate, key crypto.Signer, parent *testCertificate) testCertificate {
+	signer, signerCert := key, template
+	if parent != nil {
+		signer, signerCert = parent.key, parent.cert
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signerCert, key.Public(), signer)
+	Expect(err).ToNot(HaveOccurred())
+
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).ToNot(HaveOccurred())
+
+	return testCertificate{
+		cert: cert,
+		key:  key,
+		pem:  string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+	}
+}
+
+var _ = Describe("CertificateInventory", func() {
+	var (
+		service   *fakes.CertificateInventoryService
+		presenter *presenterfakes.FormattedPresenter
+		command   commands.CertificateInventory
+
+		root         testCertificate
+		intermediate testCertificate
+		leaf         testCertificate
+		notAfter     time.Time
+	)
+
+	BeforeEach(func() {
+		service = &fakes.CertificateInventoryService{}
+		presenter = &presenterfakes.FormattedPresenter{}
+		command = commands.NewCertificateInventory(service, presenter)
+
+		notAfter = time.Now().Add(30*24*time.Hour + time.Hour).UTC().Truncate(time.Second)
+
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).ToNot(HaveOccurred())
+		ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+
+		root = newTestCertificate(&x509.Certificate{
+			SerialNumber:          big.NewInt(1),
+			Subject:               pkix.Name{CommonName: "root"},
+			NotBefore:             time.Now().Add(-time.Hour),
+			NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+			IsCA:                  true,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageCertSign,
+		}, rsaKey, nil)
+
+		intermediate = newTestCertificate(&x509.Certificate{
+			SerialNumber:          big.NewInt(2),
+			Subject:               pkix.Name{CommonName: "intermediate"},
+			NotBefore:             time.Now().Add(-time.Hour),
+			NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+			IsCA:                  true,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageCertSign,
+		}, ecdsaKey, &root)
+
+		leaf = newTestCertificate(&x509.Certificate{
+			SerialNumber: big.NewInt(3),
+			Subject:      pkix.Name{CommonName: "opsman.example.com"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     notAfter,
+			DNSNames:     []string{"opsman.example.com"},
+			IPAddresses:  []net.IP{net.ParseIP("10.0.0.1")},
+		}, ecdsaKey, &intermediate)
+
+		service.ListCertificateAuthoritiesReturns(api.CertificateAuthoritiesOutput{
+			CAs: []api.CA{{GUID: "ca-guid", CertPEM: root.pem}},
+		}, nil)
+		service.GetSSLCertificateReturns(api.SSLCertificateOutput{
+			Certificate: api.SSLCertificate{Certificate: leaf.pem + intermediate.pem},
+		}, nil)
+		service.GetSecurityRootCACertificateReturns(root.pem, nil)
+		service.ListExpiringCertificatesReturns([]api.ExpiringCertificate{
+			{
+				ProductGUID:       "cf-guid",
+				PropertyReference: ".properties.networking_poe_ssl_certs",
+				Location:          "ops_manager",
+				Issuer:            "/CN=opsmgr-bosh-dns-tls-ca",
+				ValidFrom:         time.Now().Add(-time.Hour),
+				ValidUntil:        notAfter,
+			},
+			{
+				ProductGUID:  "p-bosh-guid",
+				VariablePath: "/p-bosh/cf/diego-instance-identity-intermediate-ca",
+				Location:     "credhub",
+				Issuer:       "/CN=diego-instance-identity-root-ca",
+				ValidFrom:    time.Now().Add(-time.Hour),
+				ValidUntil:   notAfter,
+			},
+		}, nil)
+	})
+
+	It("presents the decoded certificates", func() {
+		err := command.Execute([]string{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(service.ListExpiringCertificatesArgsForCall(0)).To(Equal("10y"))
+		Expect(presenter.SetFormatArgsForCall(0)).To(Equal("table"))
+		Expect(presenter.PresentCertificateInventoryCallCount()).To(Equal(1))
+
+		certificates := presenter.PresentCertificateInventoryArgsForCall(0)
+		Expect(certificates).To(HaveLen(5))
+
+		Expect(certificates[0].Source).To(Equal("certificate_authority"))
+		Expect(certificates[0].Name).To(Equal("ca-guid"))
+		Expect(certificates[0].Subject).To(Equal("CN=root"))
+		Expect(certificates[0].KeyType).To(Equal("RSA"))
+		Expect(certificates[0].KeySize).To(Equal(2048))
+		Expect(certificates[0].SignatureAlgorithm).To(Equal("SHA256-RSA"))
+		Expect(certificates[0].IssuerChain).To(Equal([]string{"CN=root"}))
+
+		Expect(certificates[1]).To(Equal(models.Certificate{
+			Source:             "ssl_certificate",
+			Name:               "ops_manager",
+			Subject:            "CN=opsman.example.com",
+			SANs:               []string{"opsman.example.com", "10.0.0.1"},
+			KeyType:            "ECDSA",
+			KeySize:            256,
+			SignatureAlgorithm: "ECDSA-SHA256",
+			Issuer:             "CN=intermediate",
+			IssuerChain:        []string{"CN=intermediate", "CN=root"},
+			NotBefore:          &leaf.cert.NotBefore,
+			NotAfter:           &leaf.cert.NotAfter,
+			DaysLeft:           30,
+		}))
+
+		Expect(certificates[2].Source).To(Equal("root_ca"))
+		Expect(certificates[2].Name).To(Equal("root_ca"))
+
+		Expect(certificates[3].Source).To(Equal("deployed"))
+		Expect(certificates[3].Name).To(Equal(".properties.networking_poe_ssl_certs"))
+		Expect(certificates[3].Product).To(Equal("cf-guid"))
+		Expect(certificates[3].Location).To(Equal("ops_manager"))
+		Expect(certificates[3].SANs).To(BeEmpty())
+		Expect(certificates[3].IssuerChain).To(Equal([]string{"/CN=opsmgr-bosh-dns-tls-ca"}))
+		Expect(*certificates[3].NotAfter).To(Equal(notAfter))
+		Expect(certificates[3].DaysLeft).To(Equal(30))
+
+		Expect(certificates[4].Name).To(Equal("/p-bosh/cf/diego-instance-identity-intermediate-ca"))
+		Expect(certificates[4].Location).To(Equal("credhub"))
+	})
+
+	It("passes the format and the timeframe of the deployed certificates", func() {
+		err := command.Execute([]string{"--format", "json", "--expires-within", "3m"})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(presenter.SetFormatArgsForCall(0)).To(Equal("json"))
+		Expect(service.ListExpiringCertificatesArgsForCall(0)).To(Equal("3m"))
+	})
+
+	It("skips the certificates that are not configured", func() {
+		service.GetSSLCertificateReturns(api.SSLCertificateOutput{}, nil)
+		service.GetSecurityRootCACertificateReturns("", nil)
+
+		err := command.Execute([]string{})
+		Expect(err).ToNot(HaveOccurred())
+
+		certificates := presenter.PresentCertificateInventoryArgsForCall(0)
+		Expect(certificates).To(HaveLen(3))
+		Expect(certificates[0].Source).To(Equal("certificate_authority"))
+		Expect(certificates[1].Source).To(Equal("deployed"))
+	})
+
+	When("a certificate cannot be decoded", func() {
+		It("returns an error when there is no PEM", func() {
+			service.GetSecurityRootCACertificateReturns("not a certificate", nil)
+
+			err := command.Execute([]string{})
+			Expect(err).To(MatchError("could not find a PEM encoded certificate for root_ca root_ca"))
+		})
+
+		It("returns an error when the PEM is not a certificate", func() {
+			service.ListCertificateAuthoritiesReturns(api.CertificateAuthoritiesOutput{
+				CAs: []api.CA{{GUID: "ca-guid", CertPEM: "-----BEGIN CERTIFICATE-----\nbm90IGRlcg==\n-----END CERTIFICATE-----\n"}},
+			}, nil)
+
+			err := command.Execute([]string{})
+			Expect(err).To(MatchError(ContainSubstring("could not parse the certificate of certificate_authority ca-guid: ")))
+		})
+	})
+
+	When("the service fails", func() {
+		It("returns an error when listing the certificate authorities fails", func() {
+			service.ListCertificateAuthoritiesReturns(api.CertificateAuthoritiesOutput{}, errors.New("boom"))
+
+			err := command.Execute([]string{})
+			Expect(err).To(MatchError("could not list certificate authorities: boom"))
+		})
+
+		It("returns an error when getting the SSL certificate fails", func() {
+			service.GetSSLCertificateReturns(api.SSLCertificateOutput{}, errors.New("boom"))
+
+			err := command.Execute([]string{})
+			Expect(err).To(MatchError("could not get the Ops Manager SSL certificate: boom"))
+		})
+
+		It("returns an error when getting the root CA certificate fails", func() {
+			service.GetSecurityRootCACertificateReturns("", errors.New("boom"))
+
+			err := command.Execute([]string{})
+			Expect(err).To(MatchError("could not get the root CA certificate: boom"))
+		})
+
+		It("returns an error when listing the deployed certificates fails", func() {
+			service.ListExpiringCertificatesReturns(nil, errors.New("boom"))
+
+			err := command.Execute([]string{})
+			Expect(err).To(MatchError("could not list deployed certificates: boom"))
+		})
+	})
+
+	Describe("flags", func() {
+		It("returns an error when the flags cannot be parsed", func() {
+			err := command.Execute([]string{"--unknown"})
+			Expect(err).To(MatchError("could not parse certificate-inventory flags: flag provided but not defined: -unknown"))
+		})
+
+		It("validates the format", func() {
+			err := command.Execute([]string{"--format", "xml"})
+			Expect(err).To(MatchError(`could not parse certificate-inventory flags: unsupported format "xml", expected one of table, json, yaml, csv`))
+			Expect(service.ListCertificateAuthoritiesCallCount()).To(Equal(0))
+		})
+
+		It("validates the timeframe of the deployed certificates", func() {
+			err := command.Execute([]string{"--expires-within", "1s"})
+			Expect(err).To(MatchError(`could not parse certificate-inventory flags: only d,w,m, or y are supported. Default is "3m"`))
+			Expect(service.ListCertificateAuthoritiesCallCount()).To(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/configtemplate/generator"
+)
+
+type CheckProductCompatibility struct {
+	logger            logger
+	service           productCompatibilityService
+	metadataExtractor metadataExtractor
+	strict            bool
+	defaultFlags      []string
+	Options           struct {
+		ConfigFile    string `long:"config"          short:"c" description:"path to yml file for configuration (keys must match the following command line flags)"`
+		Product       string `long:"product"         short:"p" description:"path to product" required:"true"`
+		FailOnMissing bool   `long:"fail-on-missing"           description:"exit with an error if required stemcells or products are missing"`
+	}
+}
+
+func NewCheckProductCompatibility(metadataExtractor metadataExtractor, service productCompatibilityService, logger logger, strict bool) CheckProductCompatibility {
+	return CheckProductCompatibility{
+		metadataExtractor: metadataExtractor,
+		service:           service,
+		logger:            logger,
+		strict:            strict,
+	}
+}
+
+func (c CheckProductCompatibility) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This command compares the stemcell_criteria, additional_stemcells_criteria, and requires_product_versions of a product file with the stemcells uploaded to and the products deployed on the targeted Ops Manager",
+		ShortDescription: "checks that the stemcells and products required by a product are available",
+		Flags:            c.Options,
+	}
+}
+
+func (c CheckProductCompatibility) WithDefaultFlags(defaultFlags []string) jhanda.Command {
+	c.defaultFlags = defaultFlags
+	return c
+}
+
+func (c CheckProductCompatibility) Execute(args []string) error {
+	err := loadConfigFile(args, &c.Options, nil, c.strict, c.defaultFlags)
+	if err != nil {
+		return fmt.Errorf("could not parse check-product-compatibility flags: %s", err)
+	}
+
+	extracted, err := c.metadataExtractor.ExtractMetadata(c.Options.Product)
+	if err != nil {
+		return fmt.Errorf("failed to extract product metadata: %s", err)
+	}
+
+	metadata, err := generator.NewMetadata(extracted.Raw)
+	if err != nil {
+		return fmt.Errorf("failed to parse product metadata: %s", err)
+	}
+
+	compatibility, err := checkProductCompatibility(c.service, metadata)
+	if err != nil {
+		return fmt.Errorf("failed to check product compatibility: %s", err)
+	}
+
+	compatibility.report(c.logger, extracted.Name, extracted.Version)
+
+	if c.Options.FailOnMissing && !compatibility.Compatible() {
+		return errors.New("required stemcells or products are missing")
+	}
+
+	return nil
+}
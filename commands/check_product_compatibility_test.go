@@ -0,0 +1,196 @@
+package commands_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+	"github.com/pivotal-cf/om/extractor"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const compatibilityMetadata = `---
+name: some-product
+product_version: 1.2.3
+stemcell_criteria:
+  os: ubuntu-xenial
+  version: "250.17"
+  enable_patch_security_updates: true
+additional_stemcells_criteria:
+- os: windows2019
+  version: "2019.7"
+requires_product_versions:
+- name: cf
+  version: "~> 2.6"
+`
+
+var _ = Describe("CheckProductCompatibility", func() {
+	var (
+		fakeService       *fakes.ProductCompatibilityService
+		metadataExtractor *fakes.MetadataExtractor
+		logger            *fakes.Logger
+		command           commands.CheckProductCompatibility
+	)
+
+	BeforeEach(func() {
+		fakeService = &fakes.ProductCompatibilityService{}
+		fakeService.InfoReturns(api.Info{Version: "2.6.0"}, nil)
+
+		metadataExtractor = &fakes.MetadataExtractor{}
+		metadataExtractor.ExtractMetadataReturns(extractor.Metadata{
+			Name:    "some-product",
+			Version: "1.2.3",
+			Raw:     []byte(compatibilityMetadata),
+		}, nil)
+
+		logger = &fakes.Logger{}
+		command = commands.NewCheckProductCompatibility(metadataExtractor, fakeService, logger, false)
+	})
+
+	When("all stemcells and products are available", func() {
+		BeforeEach(func() {
+			fakeService.ListMultiStemcellsReturns(api.ProductMultiStemcells{
+				StemcellLibrary: []api.StemcellLibraryEntry{
+					{OS: "ubuntu-xenial", Version: "250.25"},
+					{OS: "windows2019", Version: "2019.7"},
+				},
+			}, nil)
+			fakeService.ListDeployedProductsReturns([]api.DeployedProductOutput{
+				{Type: "cf", GUID: "cf-guid", ProductVersion: "2.6.3-build.21"},
+			}, nil)
+		})
+
+		It("reports that the product is compatible", func() {
+			err := command.Execute([]string{"--product", "/path/to/product.pivotal", "--fail-on-missing"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(metadataExtractor.ExtractMetadataArgsForCall(0)).To(Equal("/path/to/product.pivotal"))
+			Expect(logger.PrintfCallCount()).To(Equal(1))
+			format, v := logger.PrintfArgsForCall(0)
+			Expect(fmt.Sprintf(format, v...)).To(Equal("product some-product 1.2.3 has all required stemcells and products available"))
+		})
+	})
+
+	When("stemcells and products are missing", func() {
+		BeforeEach(func() {
+			fakeService.ListMultiStemcellsReturns(api.ProductMultiStemcells{
+				Products: []api.ProductMultiStemcell{{
+					ProductName:       "cf",
+					AvailableVersions: []api.StemcellObject{{OS: "ubuntu-xenial", Version: "170.9"}},
+				}},
+				StemcellLibrary: []api.StemcellLibraryEntry{
+					{OS: "ubuntu-xenial", Version: "170.9"},
+					{OS: "windows2019", Version: "2019.8"},
+				},
+			}, nil)
+			fakeService.ListDeployedProductsReturns([]api.DeployedProductOutput{
+				{Type: "cf", GUID: "cf-guid", ProductVersion: "2.5.4"},
+			}, nil)
+		})
+
+		It("prints the missing stemcells and products", func() {
+			err := command.Execute([]string{"--product", "/path/to/product.pivotal"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(logger.PrintfCallCount()).To(Equal(2))
+			format, v := logger.PrintfArgsForCall(0)
+			Expect(fmt.Sprintf(format, v...)).To(Equal("product some-product 1.2.3 requires stemcells that have not been uploaded:\n  ubuntu-xenial 250.17 (or a later patch)\n  windows2019 2019.7"))
+			format, v = logger.PrintfArgsForCall(1)
+			Expect(fmt.Sprintf(format, v...)).To(Equal("product some-product 1.2.3 requires products that have not been deployed:\n  cf ~> 2.6"))
+		})
+
+		It("returns an error with --fail-on-missing", func() {
+			err := command.Execute([]string{"--product", "/path/to/product.pivotal", "--fail-on-missing"})
+			Expect(err).To(MatchError("required stemcells or products are missing"))
+		})
+	})
+
+	When("the Ops Manager is older than 2.6", func() {
+		BeforeEach(func() {
+			fakeService.InfoReturns(api.Info{Version: "2.5.0"}, nil)
+			fakeService.ListStemcellsReturns(api.ProductStemcells{
+				Products: []api.ProductStemcell{{
+					ProductName:       "cf",
+					AvailableVersions: []string{"250.18", "2019.7"},
+				}},
+			}, nil)
+			fakeService.ListDeployedProductsReturns([]api.DeployedProductOutput{
+				{Type: "cf", GUID: "cf-guid", ProductVersion: "2.6.0"},
+			}, nil)
+		})
+
+		It("uses the single stemcell assignments", func() {
+			err := command.Execute([]string{"--product", "/path/to/product.pivotal", "--fail-on-missing"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeService.ListStemcellsCallCount()).To(Equal(1))
+			Expect(fakeService.ListMultiStemcellsCallCount()).To(Equal(0))
+		})
+	})
+
+	When("the patch security updates are not enabled", func() {
+		BeforeEach(func() {
+			metadataExtractor.ExtractMetadataReturns(extractor.Metadata{
+				Name:    "some-product",
+				Version: "1.2.3",
+				Raw: []byte(`---
+stemcell_criteria:
+  os: ubuntu-xenial
+  version: "250.17"
+`),
+			}, nil)
+			fakeService.ListMultiStemcellsReturns(api.ProductMultiStemcells{
+				StemcellLibrary: []api.StemcellLibraryEntry{
+					{OS: "ubuntu-xenial", Version: "250.25"},
+				},
+			}, nil)
+		})
+
+		It("requires the exact stemcell version", func() {
+			err := command.Execute([]string{"--product", "/path/to/product.pivotal", "--fail-on-missing"})
+			Expect(err).To(MatchError("required stemcells or products are missing"))
+			Expect(fakeService.ListDeployedProductsCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("failure cases", func() {
+		It("returns an error when an unknown flag is provided", func() {
+			err := command.Execute([]string{"--badflag"})
+			Expect(err).To(MatchError("could not parse check-product-compatibility flags: flag provided but not defined: -badflag"))
+		})
+
+		It("returns an error when the metadata cannot be extracted", func() {
+			metadataExtractor.ExtractMetadataReturns(extractor.Metadata{}, errors.New("some error"))
+			err := command.Execute([]string{"--product", "/path/to/product.pivotal"})
+			Expect(err).To(MatchError("failed to extract product metadata: some error"))
+		})
+
+		It("returns an error when the stemcells cannot be listed", func() {
+			fakeService.ListMultiStemcellsReturns(api.ProductMultiStemcells{}, errors.New("some error"))
+			err := command.Execute([]string{"--product", "/path/to/product.pivotal"})
+			Expect(err).To(MatchError("failed to check product compatibility: could not list stemcells: some error"))
+		})
+
+		It("returns an error when the deployed products cannot be listed", func() {
+			fakeService.ListDeployedProductsReturns(nil, errors.New("some error"))
+			err := command.Execute([]string{"--product", "/path/to/product.pivotal"})
+			Expect(err).To(MatchError("failed to check product compatibility: could not list deployed products: some error"))
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			command := commands.NewCheckProductCompatibility(nil, nil, nil, false)
+			Expect(command.Usage()).To(Equal(jhanda.Usage{
+				Description:      "This command compares the stemcell_criteria, additional_stemcells_criteria, and requires_product_versions of a product file with the stemcells uploaded to and the products deployed on the targeted Ops Manager",
+				ShortDescription: "checks that the stemcells and products required by a product are available",
+				Flags:            command.Options,
+			}))
+		})
+	})
+})
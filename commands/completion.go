@@ -0,0 +1,236 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+)
+
+const (
+	completeProducts             = "products"
+	completeInstallations        = "installations"
+	completeCredentialReferences = "credential-references"
+)
+
+//counterfeiter:generate -o ./fakes/completion_service.go --fake-name CompletionService . completionService
+type completionService interface {
+	ListStagedProducts() (api.StagedProductsOutput, error)
+	ListInstallations() ([]api.InstallationsServiceOutput, error)
+	ListDeployedProducts() ([]api.DeployedProductOutput, error)
+	ListDeployedProductCredentials(deployedGUID string) (api.CredentialReferencesOutput, error)
+}
+
+type Completion struct {
+	commands    jhanda.CommandSet
+	globalFlags interface{}
+	service     completionService
+	output      io.Writer
+	Options     struct {
+		List        string `long:"list"         description:"print the values to complete, used by the completion scripts (options: products,installations,credential-references)"`
+		ProductName string `long:"product-name" short:"p" description:"product of the credential references to print with --list"`
+	}
+}
+
+func NewCompletion(commands jhanda.CommandSet, globalFlags interface{}, service completionService, output io.Writer) Completion {
+	return Completion{
+		commands:    commands,
+		globalFlags: globalFlags,
+		service:     service,
+		output:      output,
+	}
+}
+
+func (c Completion) Execute(args []string) error {
+	args, err := jhanda.Parse(&c.Options, args)
+	if err != nil {
+		return fmt.Errorf("could not parse completion flags: %s", err)
+	}
+
+	if c.Options.List != "" {
+		return c.listValues()
+	}
+
+	if len(args) != 1 {
+		return errors.New("could not parse completion flags: expected one shell (options: bash,zsh,fish,powershell)")
+	}
+
+	script := completionScript{
+		commands:    c.completionCommands(),
+		globalFlags: completionFlags(c.globalFlags),
+	}
+
+	var contents string
+	switch args[0] {
+	case "bash":
+		contents = script.bash()
+	case "zsh":
+		contents = script.zsh()
+	case "fish":
+		contents = script.fish()
+	case "powershell":
+		contents = script.powershell()
+	default:
+		return fmt.Errorf("could not parse completion flags: unsupported shell %q (options: bash,zsh,fish,powershell)", args[0])
+	}
+
+	_, err = io.WriteString(c.output, contents)
+	return err
+}
+
+func (c Completion) completionCommands() []completionCommand {
+	var commands []completionCommand
+	for name, command := range c.commands {
+		usage := command.Usage()
+		commands = append(commands, completionCommand{
+			name:        name,
+			description: usage.ShortDescription,
+			flags:       completionFlags(usage.Flags),
+		})
+	}
+
+	sort.Slice(commands, func(i, j int) bool {
+		return commands[i].name < commands[j].name
+	})
+
+	return commands
+}
+
+// listValues prints the values that are completed from the API, one per line.
+func (c Completion) listValues() error {
+	var values []string
+
+	switch c.Options.List {
+	case completeProducts:
+		staged, err := c.service.ListStagedProducts()
+		if err != nil {
+			return fmt.Errorf("could not list staged products: %s", err)
+		}
+
+		for _, product := range staged.Products {
+			values = append(values, product.Type)
+		}
+
+	case completeInstallations:
+		installations, err := c.service.ListInstallations()
+		if err != nil {
+			return fmt.Errorf("could not list installations: %s", err)
+		}
+
+		for _, installation := range installations {
+			values = append(values, fmt.Sprintf("%d", installation.ID))
+		}
+
+	case completeCredentialReferences:
+		if c.Options.ProductName == "" {
+			return errors.New("could not parse completion flags: --product-name is required to list credential references")
+		}
+
+		deployed, err := c.service.ListDeployedProducts()
+		if err != nil {
+			return fmt.Errorf("could not list deployed products: %s", err)
+		}
+
+		for _, product := range deployed {
+			if product.Type != c.Options.ProductName {
+				continue
+			}
+
+			credentials, err := c.service.ListDeployedProductCredentials(product.GUID)
+			if err != nil {
+				return fmt.Errorf("could not list credential references: %s", err)
+			}
+
+			values = credentials.Credentials
+		}
+
+	default:
+		return fmt.Errorf("could not parse completion flags: unsupported --list %q (options: %s,%s,%s)", c.Options.List, completeProducts, completeInstallations, completeCredentialReferences)
+	}
+
+	for _, value := range values {
+		fmt.Fprintln(c.output, value)
+	}
+
+	return nil
+}
+
+type completionCommand struct {
+	name        string
+	description string
+	flags       []completionFlag
+}
+
+// completionFlag is a flag of the Options of a command.
+// The `complete` tag names the values that are completed from the API.
+type completionFlag struct {
+	long        string
+	short       string
+	description string
+	takesValue  bool
+	values      string
+}
+
+func (f completionFlag) words() []string {
+	var words []string
+	if f.long != "" {
+		words = append(words, "--"+f.long)
+	}
+	if f.short != "" {
+		words = append(words, "-"+f.short)
+	}
+	return words
+}
+
+func completionFlags(options interface{}) []completionFlag {
+	if options == nil {
+		return nil
+	}
+
+	t := reflect.TypeOf(options)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var flags []completionFlag
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		flag := completionFlag{
+			long:        field.Tag.Get("long"),
+			short:       field.Tag.Get("short"),
+			description: strings.SplitN(field.Tag.Get("description"), "\n", 2)[0],
+			takesValue:  field.Type.Kind() != reflect.Bool,
+			values:      field.Tag.Get("complete"),
+		}
+
+		if flag.long == "" && flag.short == "" {
+			continue
+		}
+
+		flags = append(flags, flag)
+	}
+
+	return flags
+}
+
+func (c Completion) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description: "This command prints a completion script for bash, zsh, fish or powershell, generated from the commands and flags of om." +
+			" When --env is given on the command line, product names, installation IDs and credential references are completed from the Ops Manager.\n" +
+			"On bash: source <(om completion bash)\n" +
+			"On zsh: source <(om completion zsh)\n" +
+			"On fish: om completion fish | source\n" +
+			"On powershell: om completion powershell | Out-String | Invoke-Expression",
+		ShortDescription: "prints a shell completion script",
+		Flags:            c.Options,
+	}
+}
@@ -0,0 +1,370 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+)
+
+// completionScript renders the completion scripts of om.
+// The values of the flags with a `complete` tag are listed by calling
+// `om --env <env file> completion --list <values>` when --env is given.
+type completionScript struct {
+	commands    []completionCommand
+	globalFlags []completionFlag
+}
+
+func (s completionScript) commandNames() []string {
+	var names []string
+	for _, command := range s.commands {
+		names = append(names, command.name)
+	}
+	return names
+}
+
+func flagWords(flags []completionFlag, include func(completionFlag) bool) []string {
+	var words []string
+	for _, flag := range flags {
+		if include(flag) {
+			words = append(words, flag.words()...)
+		}
+	}
+	return words
+}
+
+func allFlags(completionFlag) bool { return true }
+
+func valueFlags(flag completionFlag) bool { return flag.takesValue }
+
+func fileFlags(flag completionFlag) bool { return flag.takesValue && flag.values == "" }
+
+func (s completionScript) bash() string {
+	var script strings.Builder
+
+	script.WriteString(`# bash completion for om, generated by "om completion bash"
+
+# __om_values completes the values of a flag by listing them from the Ops Manager,
+# when the env file has been given with --env.
+__om_values() {
+  local kind=$1 command_index=$2 cur=${COMP_WORDS[COMP_CWORD]} env="" product="" word i
+  for ((i = 1; i < COMP_CWORD - 1; i++)); do
+    word=${COMP_WORDS[i]}
+    if ((i < command_index)); then
+      case "$word" in
+        -e|--env) env=${COMP_WORDS[i+1]} ;;
+        --env=*) env=${word#--env=} ;;
+      esac
+    else
+      case "$word" in
+        -p|--product-name|--product) product=${COMP_WORDS[i+1]} ;;
+      esac
+    fi
+  done
+
+  [[ -n "$env" ]] || return 0
+  env=${env/#\~/$HOME}
+
+  local values
+  values=$("${COMP_WORDS[0]}" --env "$env" completion --list "$kind" ${product:+--product-name "$product"} 2>/dev/null)
+
+  local IFS=$'\n'
+  COMPREPLY=($(compgen -W "$values" -- "$cur"))
+}
+
+_om() {
+  local cur=${COMP_WORDS[COMP_CWORD]} prev=${COMP_WORDS[COMP_CWORD-1]} command="" command_index=0 i
+  COMPREPLY=()
+
+  for ((i = 1; i < COMP_CWORD; i++)); do
+    case "${COMP_WORDS[i]}" in
+`)
+	fmt.Fprintf(&script, "      %s) ((i++)) ;;\n", strings.Join(flagWords(s.globalFlags, valueFlags), "|"))
+	script.WriteString(`      -*) ;;
+      *) command=${COMP_WORDS[i]}; command_index=$i; break ;;
+    esac
+  done
+
+  if [[ -z "$command" ]]; then
+    case "$prev" in
+`)
+	fmt.Fprintf(&script, "      %s) return 0 ;;\n", strings.Join(flagWords(s.globalFlags, valueFlags), "|"))
+	script.WriteString(`    esac
+
+    if [[ "$cur" == -* ]]; then
+`)
+	fmt.Fprintf(&script, "      COMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(flagWords(s.globalFlags, allFlags), " "))
+	script.WriteString("    else\n")
+	fmt.Fprintf(&script, "      COMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(s.commandNames(), " "))
+	script.WriteString(`    fi
+    return 0
+  fi
+
+  local flags=""
+  case "$command" in
+`)
+
+	for _, command := range s.commands {
+		fmt.Fprintf(&script, "    %s)\n", command.name)
+
+		var prevCases []string
+		for _, flag := range command.flags {
+			if flag.values != "" {
+				prevCases = append(prevCases, fmt.Sprintf("        %s) __om_values %s \"$command_index\"; return 0 ;;\n", strings.Join(flag.words(), "|"), flag.values))
+			}
+		}
+		if words := flagWords(command.flags, fileFlags); len(words) > 0 {
+			prevCases = append(prevCases, fmt.Sprintf("        %s) return 0 ;;\n", strings.Join(words, "|")))
+		}
+
+		if len(prevCases) > 0 {
+			script.WriteString("      case \"$prev\" in\n")
+			script.WriteString(strings.Join(prevCases, ""))
+			script.WriteString("      esac\n")
+		}
+
+		fmt.Fprintf(&script, "      flags=%q\n", strings.Join(flagWords(command.flags, allFlags), " "))
+		script.WriteString("      ;;\n")
+	}
+
+	script.WriteString(`  esac
+
+  COMPREPLY=($(compgen -W "$flags" -- "$cur"))
+}
+
+complete -o default -F _om om
+`)
+
+	return script.String()
+}
+
+func (s completionScript) zsh() string {
+	return "#compdef om\n" +
+		"# zsh completion for om, generated by \"om completion zsh\"\n" +
+		"\n" +
+		"autoload -U +X bashcompinit && bashcompinit\n" +
+		"\n" +
+		s.bash()
+}
+
+func fishQuote(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+func fishFlag(flag completionFlag) string {
+	var words []string
+	if flag.long != "" {
+		words = append(words, "-l "+flag.long)
+	}
+	if len(flag.short) == 1 {
+		words = append(words, "-s "+flag.short)
+	} else if flag.short != "" {
+		words = append(words, "-o "+flag.short)
+	}
+
+	switch {
+	case flag.values != "":
+		words = append(words, fmt.Sprintf("-x -a '(__om_values %s)'", flag.values))
+	case flag.takesValue:
+		words = append(words, "-r")
+	}
+
+	if flag.description != "" {
+		words = append(words, "-d "+fishQuote(flag.description))
+	}
+
+	return strings.Join(words, " ")
+}
+
+func (s completionScript) fish() string {
+	var script strings.Builder
+
+	script.WriteString(`# fish completion for om, generated by "om completion fish"
+
+# __om_command prints the position of the command on the command line.
+function __om_command
+    set -l tokens (commandline -opc)
+    set -l i 2
+    while test $i -le (count $tokens)
+        switch $tokens[$i]
+`)
+	fmt.Fprintf(&script, "            case %s\n", strings.Join(flagWords(s.globalFlags, valueFlags), " "))
+	script.WriteString(`                set i (math $i + 1)
+            case '-*'
+            case '*'
+                echo $i
+                return 0
+        end
+        set i (math $i + 1)
+    end
+    return 1
+end
+
+function __om_needs_command
+    not __om_command >/dev/null
+end
+
+function __om_using_command
+    set -l i (__om_command); or return 1
+    test (commandline -opc)[$i] = $argv[1]
+end
+
+# __om_values lists the values of a flag from the Ops Manager,
+# when the env file has been given with --env.
+function __om_values
+    set -l tokens (commandline -opc)
+    set -l command_index (__om_command); or return 0
+    set -l env
+    set -l product
+    for i in (seq 2 (count $tokens))
+        set -l next (math $i + 1)
+        if test $i -lt $command_index
+            switch $tokens[$i]
+                case -e --env
+                    set env $tokens[$next]
+                case '--env=*'
+                    set env (string replace -- --env= '' $tokens[$i])
+            end
+        else
+            switch $tokens[$i]
+                case -p --product-name --product
+                    set product $tokens[$next]
+            end
+        end
+    end
+
+    test -n "$env"; or return 0
+    set -l arguments --env (string replace -r '^~' $HOME -- $env) completion --list $argv[1]
+    test -n "$product"; and set -a arguments --product-name $product
+    $tokens[1] $arguments 2>/dev/null
+end
+
+`)
+
+	for _, flag := range s.globalFlags {
+		fmt.Fprintf(&script, "complete -c om -n __om_needs_command %s\n", fishFlag(flag))
+	}
+	for _, command := range s.commands {
+		fmt.Fprintf(&script, "complete -c om -n __om_needs_command -f -a %s -d %s\n", command.name, fishQuote(command.description))
+	}
+
+	for _, command := range s.commands {
+		script.WriteString("\n")
+		for _, flag := range command.flags {
+			fmt.Fprintf(&script, "complete -c om -n '__om_using_command %s' %s\n", command.name, fishFlag(flag))
+		}
+	}
+
+	return script.String()
+}
+
+func powershellQuote(value string) string {
+	return "'" + strings.Replace(value, "'", "''", -1) + "'"
+}
+
+// powershellFlags renders the flags as a hashtable of what they complete:
+// nothing for booleans, files for other flags, or the values of the `complete` tag.
+func powershellFlags(flags []completionFlag) string {
+	var entries []string
+	for _, flag := range flags {
+		kind := ""
+		switch {
+		case flag.values != "":
+			kind = flag.values
+		case flag.takesValue:
+			kind = "file"
+		}
+
+		for _, word := range flag.words() {
+			entries = append(entries, fmt.Sprintf("%s = %s", powershellQuote(word), powershellQuote(kind)))
+		}
+	}
+
+	return "@{ " + strings.Join(entries, "; ") + " }"
+}
+
+func (s completionScript) powershell() string {
+	var script strings.Builder
+
+	script.WriteString(`# powershell completion for om, generated by "om completion powershell"
+
+Register-ArgumentCompleter -Native -CommandName om -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+
+`)
+	fmt.Fprintf(&script, "    $globalFlags = %s\n", powershellFlags(s.globalFlags))
+	script.WriteString("    $commands = [ordered]@{\n")
+	for _, command := range s.commands {
+		fmt.Fprintf(&script, "        %s = %s\n", powershellQuote(command.name), powershellQuote(command.description))
+	}
+	script.WriteString("    }\n")
+	script.WriteString("    $commandFlags = @{\n")
+	for _, command := range s.commands {
+		fmt.Fprintf(&script, "        %s = %s\n", powershellQuote(command.name), powershellFlags(command.flags))
+	}
+	script.WriteString("    }\n")
+
+	script.WriteString(`
+    $words = @($commandAst.CommandElements |
+        Where-Object { $_.Extent.EndOffset -lt ($cursorPosition - $wordToComplete.Length) } |
+        ForEach-Object { $_.ToString() })
+
+    $command = ''
+    $envFile = ''
+    $product = ''
+    for ($i = 1; $i -lt $words.Count; $i++) {
+        $word = $words[$i]
+        if ($command -eq '') {
+            if ($word -eq '-e' -or $word -eq '--env') {
+                $envFile = $words[$i + 1]
+            }
+            if ($globalFlags[$word]) {
+                $i++
+            } elseif (-not $word.StartsWith('-')) {
+                $command = $word
+            }
+        } elseif ($word -eq '-p' -or $word -eq '--product-name' -or $word -eq '--product') {
+            $product = $words[$i + 1]
+        }
+    }
+
+    $flags = $globalFlags
+    if ($command -ne '') {
+        $flags = $commandFlags[$command]
+    }
+    if ($null -eq $flags) {
+        return
+    }
+
+    $previous = $words[-1]
+    $kind = $flags[$previous]
+    if ($words.Count -gt 1 -and $kind) {
+        if ($kind -eq 'file' -or $envFile -eq '') {
+            return
+        }
+
+        $arguments = @('--env', $envFile, 'completion', '--list', $kind)
+        if ($product) {
+            $arguments += @('--product-name', $product)
+        }
+
+        & $commandAst.CommandElements[0].ToString() @arguments 2>$null |
+            Where-Object { $_ -like "$wordToComplete*" } |
+            ForEach-Object { [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_) }
+        return
+    }
+
+    if ($command -eq '' -and -not $wordToComplete.StartsWith('-')) {
+        $commands.Keys |
+            Where-Object { $_ -like "$wordToComplete*" } |
+            ForEach-Object { [System.Management.Automation.CompletionResult]::new($_, $_, 'Command', $commands[$_]) }
+        return
+    }
+
+    $flags.Keys |
+        Where-Object { $_ -like "$wordToComplete*" } |
+        Sort-Object |
+        ForEach-Object { [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterName', $_) }
+}
+`)
+
+	return script.String()
+}
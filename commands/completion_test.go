@@ -0,0 +1,203 @@
+package commands_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+)
+
+var _ = Describe("Completion", func() {
+	var (
+		output      *bytes.Buffer
+		fakeService *fakes.CompletionService
+		command     commands.Completion
+	)
+
+	BeforeEach(func() {
+		output = &bytes.Buffer{}
+		fakeService = &fakes.CompletionService{}
+
+		commandSet := jhanda.CommandSet{
+			"bake": fakeCommand{usage: jhanda.Usage{
+				ShortDescription: "bakes you a cake",
+				Flags: struct {
+					Flavour string `long:"flavour" short:"f" description:"flavour of the cake" complete:"products"`
+					Recipe  string `long:"recipe"  short:"r" description:"path to the recipe\nwith more details"`
+					Sliced  bool   `long:"sliced"            description:"slices the cake"`
+					Hidden  string `env:"HIDDEN"`
+				}{},
+			}},
+			"clean": fakeCommand{usage: jhanda.Usage{ShortDescription: "cleans up after baking"}},
+		}
+
+		globalFlags := struct {
+			Env   string `long:"env"   short:"e"  description:"env file with login credentials"`
+			Trace bool   `long:"trace" short:"tr" description:"prints HTTP requests"`
+		}{}
+
+		command = commands.NewCompletion(commandSet, globalFlags, fakeService, output)
+	})
+
+	Describe("Execute", func() {
+		It("prints a bash completion script of the commands and their flags", func() {
+			err := command.Execute([]string{"bash"})
+			Expect(err).ToNot(HaveOccurred())
+
+			script := output.String()
+			Expect(script).To(ContainSubstring(`compgen -W "--env -e --trace -tr"`))
+			Expect(script).To(ContainSubstring(`compgen -W "bake clean"`))
+			Expect(script).To(ContainSubstring(`--flavour|-f) __om_values products "$command_index"; return 0 ;;`))
+			Expect(script).To(ContainSubstring(`--recipe|-r) return 0 ;;`))
+			Expect(script).To(ContainSubstring(`flags="--flavour -f --recipe -r --sliced"`))
+			Expect(script).To(ContainSubstring("complete -o default -F _om om"))
+			Expect(script).ToNot(ContainSubstring("HIDDEN"))
+		})
+
+		It("completes commands, flags and values from the api in bash", func() {
+			bash, err := exec.LookPath("bash")
+			if err != nil {
+				Skip("bash is not installed")
+			}
+
+			err = command.Execute([]string{"bash"})
+			Expect(err).ToNot(HaveOccurred())
+
+			dir, err := ioutil.TempDir("", "om-completion")
+			Expect(err).ToNot(HaveOccurred())
+
+			scriptFile := filepath.Join(dir, "om.bash")
+			err = ioutil.WriteFile(scriptFile, output.Bytes(), 0600)
+			Expect(err).ToNot(HaveOccurred())
+
+			complete := func(words string) string {
+				out, err := exec.Command(bash, "-c", `
+source "$1"
+om() { echo "$*" | tr ' ' '-'; }
+COMP_WORDS=($2)
+COMP_CWORD=$((${#COMP_WORDS[@]} - 1))
+if [[ "$2" == *" " ]]; then COMP_WORDS+=(""); COMP_CWORD=$((COMP_CWORD + 1)); fi
+_om
+echo "${COMPREPLY[*]}"
+`, "bash", scriptFile, words).CombinedOutput()
+				Expect(err).ToNot(HaveOccurred(), string(out))
+				return string(bytes.TrimSpace(out))
+			}
+
+			Expect(complete("om ba")).To(Equal("bake"))
+			Expect(complete("om -e env.yml --tr")).To(Equal("--trace"))
+			Expect(complete("om -e env.yml bake --s")).To(Equal("--sliced"))
+			Expect(complete("om -e env.yml bake -f ")).To(Equal("--env-env.yml-completion---list-products"))
+			Expect(complete("om bake -f ")).To(Equal(""))
+		})
+
+		It("prints a zsh completion script using the bash completion", func() {
+			err := command.Execute([]string{"zsh"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(output.String()).To(HavePrefix("#compdef om\n"))
+			Expect(output.String()).To(ContainSubstring("bashcompinit"))
+			Expect(output.String()).To(ContainSubstring("complete -o default -F _om om"))
+		})
+
+		It("prints a fish completion script", func() {
+			err := command.Execute([]string{"fish"})
+			Expect(err).ToNot(HaveOccurred())
+
+			script := output.String()
+			Expect(script).To(ContainSubstring("complete -c om -n __om_needs_command -l trace -o tr -d 'prints HTTP requests'\n"))
+			Expect(script).To(ContainSubstring("complete -c om -n __om_needs_command -f -a bake -d 'bakes you a cake'\n"))
+			Expect(script).To(ContainSubstring("complete -c om -n '__om_using_command bake' -l flavour -s f -x -a '(__om_values products)' -d 'flavour of the cake'\n"))
+			Expect(script).To(ContainSubstring("complete -c om -n '__om_using_command bake' -l recipe -s r -r -d 'path to the recipe'\n"))
+			Expect(script).To(ContainSubstring("complete -c om -n '__om_using_command bake' -l sliced -d 'slices the cake'\n"))
+		})
+
+		It("prints a powershell completion script", func() {
+			err := command.Execute([]string{"powershell"})
+			Expect(err).ToNot(HaveOccurred())
+
+			script := output.String()
+			Expect(script).To(ContainSubstring("Register-ArgumentCompleter -Native -CommandName om"))
+			Expect(script).To(ContainSubstring("$globalFlags = @{ '--env' = 'file'; '-e' = 'file'; '--trace' = ''; '-tr' = '' }"))
+			Expect(script).To(ContainSubstring("'bake' = @{ '--flavour' = 'products'; '-f' = 'products'; '--recipe' = 'file'; '-r' = 'file'; '--sliced' = '' }"))
+			Expect(script).To(ContainSubstring("'clean' = @{  }"))
+		})
+
+		When("--list is given", func() {
+			It("prints the staged products", func() {
+				fakeService.ListStagedProductsReturns(api.StagedProductsOutput{
+					Products: []api.StagedProduct{{Type: "p-bosh"}, {Type: "cf"}},
+				}, nil)
+
+				err := command.Execute([]string{"--list", "products"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(output.String()).To(Equal("p-bosh\ncf\n"))
+			})
+
+			It("prints the installations", func() {
+				fakeService.ListInstallationsReturns([]api.InstallationsServiceOutput{{ID: 2}, {ID: 1}}, nil)
+
+				err := command.Execute([]string{"--list", "installations"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(output.String()).To(Equal("2\n1\n"))
+			})
+
+			It("prints the credential references of the deployed product", func() {
+				fakeService.ListDeployedProductsReturns([]api.DeployedProductOutput{
+					{Type: "p-bosh", GUID: "p-bosh-guid"},
+					{Type: "cf", GUID: "cf-guid"},
+				}, nil)
+				fakeService.ListDeployedProductCredentialsReturns(api.CredentialReferencesOutput{
+					Credentials: []string{".uaa.admin_credentials", ".properties.secret"},
+				}, nil)
+
+				err := command.Execute([]string{"--list", "credential-references", "--product-name", "cf"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(output.String()).To(Equal(".uaa.admin_credentials\n.properties.secret\n"))
+
+				Expect(fakeService.ListDeployedProductCredentialsCallCount()).To(Equal(1))
+				Expect(fakeService.ListDeployedProductCredentialsArgsForCall(0)).To(Equal("cf-guid"))
+			})
+
+			It("requires the product name to print credential references", func() {
+				err := command.Execute([]string{"--list", "credential-references"})
+				Expect(err).To(MatchError(ContainSubstring("--product-name is required")))
+			})
+
+			It("returns an error when the values cannot be listed", func() {
+				fakeService.ListStagedProductsReturns(api.StagedProductsOutput{}, errors.New("some error"))
+
+				err := command.Execute([]string{"--list", "products"})
+				Expect(err).To(MatchError("could not list staged products: some error"))
+			})
+
+			It("returns an error for unknown values", func() {
+				err := command.Execute([]string{"--list", "stemcells"})
+				Expect(err).To(MatchError(ContainSubstring(`unsupported --list "stemcells"`)))
+			})
+		})
+
+		It("returns an error for an unsupported shell", func() {
+			err := command.Execute([]string{"tcsh"})
+			Expect(err).To(MatchError(ContainSubstring(`unsupported shell "tcsh"`)))
+		})
+
+		It("returns an error when no shell is given", func() {
+			err := command.Execute([]string{})
+			Expect(err).To(MatchError(ContainSubstring("expected one shell")))
+		})
+
+		It("returns an error when the flags cannot be parsed", func() {
+			err := command.Execute([]string{"--unknown-flag"})
+			Expect(err).To(MatchError(ContainSubstring("could not parse completion flags")))
+		})
+	})
+})
@@ -13,10 +13,14 @@ import (
 type ConfigTemplate struct {
 	environFunc   envProvider
 	buildProvider buildProvider
+	strict        bool
+	defaultFlags  []string
 	Options       struct {
 		ConfigFile string   `long:"config"                     short:"c" description:"path to yml file for configuration (keys must match the following command line flags)"`
 		VarsEnv    []string `long:"vars-env" env:"OM_VARS_ENV"           description:"load variables from environment variables matching the provided prefix (e.g.: 'MY' to load MY_var=value)" experimental:"true"`
 		VarsFile   []string `long:"vars-file"                  short:"l" description:"load variables from a YAML file"`
+		VarsSource []string `long:"vars-source" env:"OM_VARS_SOURCE" description:"load variables that are not otherwise provided from a credential store (e.g.: 'vault:address=https://vault.example.com,mount=secret,path=foundation' or 'credhub:address=https://credhub.example.com,path=/foundation')"`
+		VarsStore  string   `long:"vars-store" description:"load variables from a YAML file, the variables defined in the 'variables' section of the config that are missing are generated and saved to it"`
 		Vars       []string `long:"var"                                  description:"Load variable from the command line. Format: VAR=VAL"`
 
 		PivnetApiToken    string `long:"pivnet-api-token"                                                                                                              `
@@ -29,6 +33,7 @@ type ConfigTemplate struct {
 
 		OutputDirectory string `long:"output-directory"               description:"a directory to create templates under. must already exist."                       required:"true"`
 		ExcludeVersion  bool   `long:"exclude-version"                description:"if set, will not output a version-specific directory"`
+		JSONSchema      bool   `long:"json-schema"                    description:"if set, will also output a JSON Schema of the product config (product.schema.json)"`
 	}
 }
 
@@ -51,20 +56,26 @@ var DefaultProvider = func() func(c *ConfigTemplate) MetadataProvider {
 type buildProvider func(*ConfigTemplate) MetadataProvider
 type envProvider func() []string
 
-func NewConfigTemplate(bp buildProvider) *ConfigTemplate {
-	return NewConfigTemplateWithEnvironment(bp, os.Environ)
+func NewConfigTemplate(bp buildProvider, strict bool) *ConfigTemplate {
+	return NewConfigTemplateWithEnvironment(bp, os.Environ, strict)
 }
 
-func NewConfigTemplateWithEnvironment(bp buildProvider, environFunc envProvider) *ConfigTemplate {
+func NewConfigTemplateWithEnvironment(bp buildProvider, environFunc envProvider, strict bool) *ConfigTemplate {
 	return &ConfigTemplate{
 		environFunc:   environFunc,
 		buildProvider: bp,
+		strict:        strict,
 	}
 }
 
+func (c *ConfigTemplate) WithDefaultFlags(defaultFlags []string) jhanda.Command {
+	c.defaultFlags = defaultFlags
+	return c
+}
+
 //Execute - generates config template and ops files
 func (c *ConfigTemplate) Execute(args []string) error {
-	err := loadConfigFile(args, &c.Options, c.environFunc)
+	err := loadConfigFile(args, &c.Options, c.environFunc, c.strict, c.defaultFlags)
 	if err != nil {
 		return fmt.Errorf("could not parse config-template flags: %s", err.Error())
 	}
@@ -90,6 +101,7 @@ func (c *ConfigTemplate) Execute(args []string) error {
 		c.Options.OutputDirectory,
 		c.Options.ExcludeVersion,
 		true,
+		c.Options.JSONSchema,
 	).Generate()
 }
 
@@ -106,13 +118,5 @@ func (c *ConfigTemplate) Usage() jhanda.Usage {
 }
 
 func (c *ConfigTemplate) Validate() error {
-	if c.Options.PivnetApiToken != "" && c.Options.PivnetProductSlug != "" && c.Options.ProductVersion != "" && c.Options.ProductPath == "" {
-		return nil
-	}
-
-	if c.Options.PivnetApiToken == "" && c.Options.PivnetProductSlug == "" && c.Options.ProductVersion == "" && c.Options.ProductPath != "" {
-		return nil
-	}
-
-	return fmt.Errorf("cannot load tile metadata: please provide either pivnet flags OR product-path")
+	return validateMetadataSource(c.Options.PivnetApiToken, c.Options.PivnetProductSlug, c.Options.ProductVersion, c.Options.ProductPath)
 }
@@ -0,0 +1,182 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/configtemplate/generator"
+	"github.com/pivotal-cf/om/configtemplate/metadata"
+)
+
+type ConfigTemplateDiff struct {
+	environFunc   envProvider
+	buildProvider configTemplateDiffBuildProvider
+	logger        logger
+	strict        bool
+	defaultFlags  []string
+	Options       struct {
+		ConfigFile string   `long:"config"                     short:"c" description:"path to yml file for configuration (keys must match the following command line flags)"`
+		VarsEnv    []string `long:"vars-env" env:"OM_VARS_ENV"           description:"load variables from environment variables matching the provided prefix (e.g.: 'MY' to load MY_var=value)"`
+		VarsFile   []string `long:"vars-file"                  short:"l" description:"load variables from a YAML file"`
+		VarsSource []string `long:"vars-source" env:"OM_VARS_SOURCE" description:"load variables that are not otherwise provided from a credential store (e.g.: 'vault:address=https://vault.example.com,mount=secret,path=foundation' or 'credhub:address=https://credhub.example.com,path=/foundation')"`
+		VarsStore  string   `long:"vars-store" description:"load variables from a YAML file, the variables defined in the 'variables' section of the config that are missing are generated and saved to it"`
+		Vars       []string `long:"var"                                  description:"Load variable from the command line. Format: VAR=VAL"`
+
+		PivnetApiToken    string `long:"pivnet-api-token"                                                                                                   `
+		PivnetProductSlug string `long:"pivnet-product-slug"           description:"the product name in pivnet"                                             `
+		PivnetFileGlob    string `long:"pivnet-file-glob"    short:"f" description:"a glob to match exactly one file in the pivnet product slug"  default:"*.pivotal"`
+		PivnetDisableSSL  bool   `long:"pivnet-disable-ssl"            description:"whether to disable ssl validation when contacting the Pivotal Network"`
+
+		FromProductVersion string `long:"from-product-version" description:"the currently used version of the product in pivnet"`
+		ToProductVersion   string `long:"to-product-version"   description:"the version of the product in pivnet to upgrade to"`
+		FromProductPath    string `long:"from-product-path"    description:"path to the product file of the currently used version"`
+		ToProductPath      string `long:"to-product-path"      description:"path to the product file of the version to upgrade to"`
+	}
+}
+
+type configTemplateDiffBuildProvider func(c *ConfigTemplateDiff, productPath, productVersion string) MetadataProvider
+
+var DefaultConfigTemplateDiffProvider = func() func(c *ConfigTemplateDiff, productPath, productVersion string) MetadataProvider {
+	return func(c *ConfigTemplateDiff, productPath, productVersion string) MetadataProvider {
+		options := c.Options
+		if productPath != "" {
+			return metadata.NewFileProvider(productPath)
+		}
+		return metadata.NewPivnetProvider(pivnetHost, options.PivnetApiToken, options.PivnetProductSlug, productVersion, options.PivnetFileGlob, options.PivnetDisableSSL)
+	}
+}
+
+func NewConfigTemplateDiff(bp configTemplateDiffBuildProvider, environFunc envProvider, logger logger, strict bool) *ConfigTemplateDiff {
+	return &ConfigTemplateDiff{
+		environFunc:   environFunc,
+		buildProvider: bp,
+		logger:        logger,
+		strict:        strict,
+	}
+}
+
+func (c *ConfigTemplateDiff) WithDefaultFlags(defaultFlags []string) jhanda.Command {
+	c.defaultFlags = defaultFlags
+	return c
+}
+
+func (c *ConfigTemplateDiff) Execute(args []string) error {
+	err := loadConfigFile(args, &c.Options, c.environFunc, c.strict, c.defaultFlags)
+	if err != nil {
+		return fmt.Errorf("could not parse config-template-diff flags: %s", err.Error())
+	}
+
+	from, err := c.loadMetadata("from", c.Options.FromProductPath, c.Options.FromProductVersion)
+	if err != nil {
+		return err
+	}
+
+	to, err := c.loadMetadata("to", c.Options.ToProductPath, c.Options.ToProductVersion)
+	if err != nil {
+		return err
+	}
+
+	diff, err := generator.DiffMetadata(from, to)
+	if err != nil {
+		return fmt.Errorf("could not compare the product metadata: %s", err)
+	}
+
+	c.logger.Printf("comparing %s %s with %s %s", from.Name, from.Version, to.Name, to.Version)
+
+	if !diff.HasChanges() {
+		c.logger.Println("no differences found")
+		return nil
+	}
+
+	c.printNames("added properties", diff.AddedProperties)
+	c.printNames("removed properties", diff.RemovedProperties)
+	c.printChanges("changed property types", diff.TypeChanges, func(value interface{}) string {
+		return fmt.Sprintf("%v", value)
+	})
+	c.printChanges("changed required properties", diff.RequiredChanges, func(value interface{}) string {
+		if value == true {
+			return "required"
+		}
+		return "not required"
+	})
+	c.printChanges("changed defaults", diff.DefaultChanges, func(value interface{}) string {
+		if value == nil {
+			return "(none)"
+		}
+		return fmt.Sprintf("%v", value)
+	})
+	c.printNames("added selector options", diff.AddedSelectorOptions)
+	c.printNames("added jobs", diff.AddedJobs)
+	c.printNames("removed jobs", diff.RemovedJobs)
+	c.printNames("added errands", diff.AddedErrands)
+	c.printNames("removed errands", diff.RemovedErrands)
+	c.printNames("added features ops files", diff.AddedFeatures)
+	c.printNames("removed features ops files", diff.RemovedFeatures)
+	c.printNames("added optional ops files", diff.AddedOptionalOpsFiles)
+	c.printNames("removed optional ops files", diff.RemovedOptionalOpsFiles)
+
+	for _, vars := range diff.NewVars {
+		c.printNames(fmt.Sprintf("new entries in %s", vars.File), vars.Names)
+	}
+
+	return nil
+}
+
+func (c *ConfigTemplateDiff) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "**EXPERIMENTAL** this command compares the config templates of two versions of a product. It reports added and removed properties, changes to types, required properties and defaults, added selector options, added and removed jobs, errands, and features and optional ops files, and the entries that need to be added to the vars files.",
+		ShortDescription: "**EXPERIMENTAL** compares the config templates of two versions of a product",
+		Flags:            c.Options,
+	}
+}
+
+func (c *ConfigTemplateDiff) loadMetadata(side, productPath, productVersion string) (*generator.Metadata, error) {
+	if productPath == "" && productVersion == "" {
+		return nil, fmt.Errorf("cannot load tile metadata: please provide either --%[1]s-product-path OR pivnet flags with --%[1]s-product-version", side)
+	}
+
+	if productPath != "" && productVersion != "" {
+		return nil, fmt.Errorf("cannot load tile metadata: please provide either --%[1]s-product-path OR pivnet flags with --%[1]s-product-version", side)
+	}
+
+	if productVersion != "" && (c.Options.PivnetApiToken == "" || c.Options.PivnetProductSlug == "") {
+		return nil, fmt.Errorf("cannot load tile metadata: --pivnet-api-token and --pivnet-product-slug are required with --%s-product-version", side)
+	}
+
+	metadataBytes, err := c.buildProvider(c, productPath, productVersion).MetadataBytes()
+	if err != nil {
+		if productPath != "" {
+			return nil, fmt.Errorf("error getting metadata from %s: %s", productPath, err)
+		}
+		return nil, fmt.Errorf("error getting metadata for %s at version %s: %s", c.Options.PivnetProductSlug, productVersion, err)
+	}
+
+	productMetadata, err := generator.NewMetadata(metadataBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse product metadata: %s", err)
+	}
+
+	return productMetadata, nil
+}
+
+func (c *ConfigTemplateDiff) printNames(title string, names []string) {
+	if len(names) == 0 {
+		return
+	}
+
+	c.logger.Printf("%s:", title)
+	for _, name := range names {
+		c.logger.Printf("  %s", name)
+	}
+}
+
+func (c *ConfigTemplateDiff) printChanges(title string, changes []generator.PropertyChange, describe func(interface{}) string) {
+	if len(changes) == 0 {
+		return
+	}
+
+	c.logger.Printf("%s:", title)
+	for _, change := range changes {
+		c.logger.Printf("  %s: %s -> %s", change.Reference, describe(change.From), describe(change.To))
+	}
+}
@@ -0,0 +1,168 @@
+package commands_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ConfigTemplateDiff", func() {
+	var (
+		providers map[string]*fakes.MetadataProvider
+		logger    *fakes.Logger
+		command   *commands.ConfigTemplateDiff
+
+		requestedPaths    []string
+		requestedVersions []string
+	)
+
+	output := func() []string {
+		var lines []string
+		for i := 0; i < logger.PrintfCallCount(); i++ {
+			format, v := logger.PrintfArgsForCall(i)
+			lines = append(lines, fmt.Sprintf(format, v...))
+		}
+		return lines
+	}
+
+	BeforeEach(func() {
+		from := &fakes.MetadataProvider{}
+		from.MetadataBytesReturns([]byte(`---
+name: some-product
+product_version: 1.0.0
+property_blueprints:
+- name: kept
+  type: string
+  configurable: true
+- name: removed
+  type: string
+  configurable: true
+  optional: true
+post_deploy_errands:
+- name: smoke-tests
+`), nil)
+
+		to := &fakes.MetadataProvider{}
+		to.MetadataBytesReturns([]byte(`---
+name: some-product
+product_version: 1.1.0
+property_blueprints:
+- name: kept
+  type: string
+  configurable: true
+  default: some-default
+- name: added
+  type: integer
+  configurable: true
+post_deploy_errands:
+- name: smoke-tests
+`), nil)
+
+		providers = map[string]*fakes.MetadataProvider{
+			"/path/to/1.0.0.pivotal": from,
+			"1.0.0":                  from,
+			"/path/to/1.1.0.pivotal": to,
+			"1.1.0":                  to,
+		}
+		requestedPaths = nil
+		requestedVersions = nil
+
+		logger = &fakes.Logger{}
+		command = commands.NewConfigTemplateDiff(func(c *commands.ConfigTemplateDiff, productPath, productVersion string) commands.MetadataProvider {
+			requestedPaths = append(requestedPaths, productPath)
+			requestedVersions = append(requestedVersions, productVersion)
+			if productPath != "" {
+				return providers[productPath]
+			}
+			return providers[productVersion]
+		}, func() []string { return nil }, logger, false)
+	})
+
+	It("prints the differences between two local product files", func() {
+		err := command.Execute([]string{
+			"--from-product-path", "/path/to/1.0.0.pivotal",
+			"--to-product-path", "/path/to/1.1.0.pivotal",
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(requestedPaths).To(Equal([]string{"/path/to/1.0.0.pivotal", "/path/to/1.1.0.pivotal"}))
+		Expect(output()).To(Equal([]string{
+			"comparing some-product 1.0.0 with some-product 1.1.0",
+			"added properties:",
+			"  .properties.added",
+			"removed properties:",
+			"  .properties.removed",
+			"changed required properties:",
+			"  .properties.kept: required -> not required",
+			"changed defaults:",
+			"  .properties.kept: (none) -> some-default",
+		}))
+	})
+
+	It("loads versions from pivnet", func() {
+		err := command.Execute([]string{
+			"--pivnet-api-token", "token",
+			"--pivnet-product-slug", "some-product",
+			"--from-product-version", "1.0.0",
+			"--to-product-version", "1.1.0",
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(requestedVersions).To(Equal([]string{"1.0.0", "1.1.0"}))
+	})
+
+	It("prints when there are no differences", func() {
+		err := command.Execute([]string{
+			"--from-product-path", "/path/to/1.0.0.pivotal",
+			"--to-product-path", "/path/to/1.0.0.pivotal",
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(logger.PrintlnCallCount()).To(Equal(1))
+		Expect(logger.PrintlnArgsForCall(0)).To(Equal([]interface{}{"no differences found"}))
+	})
+
+	Context("failure cases", func() {
+		It("returns an error when an unknown flag is provided", func() {
+			err := command.Execute([]string{"--badflag"})
+			Expect(err).To(MatchError("could not parse config-template-diff flags: flag provided but not defined: -badflag"))
+		})
+
+		It("returns an error when a version is missing", func() {
+			err := command.Execute([]string{"--from-product-path", "/path/to/1.0.0.pivotal"})
+			Expect(err).To(MatchError("cannot load tile metadata: please provide either --to-product-path OR pivnet flags with --to-product-version"))
+		})
+
+		It("returns an error when the pivnet flags are missing", func() {
+			err := command.Execute([]string{"--from-product-version", "1.0.0", "--to-product-path", "/path/to/1.1.0.pivotal"})
+			Expect(err).To(MatchError("cannot load tile metadata: --pivnet-api-token and --pivnet-product-slug are required with --from-product-version"))
+		})
+
+		It("returns an error when the metadata cannot be loaded", func() {
+			providers["/path/to/1.1.0.pivotal"].MetadataBytesReturns(nil, errors.New("some error"))
+
+			err := command.Execute([]string{
+				"--from-product-path", "/path/to/1.0.0.pivotal",
+				"--to-product-path", "/path/to/1.1.0.pivotal",
+			})
+			Expect(err).To(MatchError("error getting metadata from /path/to/1.1.0.pivotal: some error"))
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			command := commands.NewConfigTemplateDiff(nil, nil, nil, false)
+			Expect(command.Usage()).To(Equal(jhanda.Usage{
+				Description:      "**EXPERIMENTAL** this command compares the config templates of two versions of a product. It reports added and removed properties, changes to types, required properties and defaults, added selector options, added and removed jobs, errands, and features and optional ops files, and the entries that need to be added to the vars files.",
+				ShortDescription: "**EXPERIMENTAL** compares the config templates of two versions of a product",
+				Flags:            command.Options,
+			}))
+		})
+	})
+})
@@ -38,7 +38,7 @@ var _ = Describe("ConfigTemplate", func() {
 				f := &fakes.MetadataProvider{}
 				f.MetadataBytesReturns([]byte(`{name: example-product, product_version: "1.1.1"}`), nil)
 				return f
-			})
+			}, false)
 		})
 
 		Describe("upserting an entry in the output directory with template files", func() {
@@ -114,6 +114,30 @@ var _ = Describe("ConfigTemplate", func() {
 						Expect(filepath.Join(productDir, "default-vars.yml")).To(BeAnExistingFile())
 						Expect(filepath.Join(productDir, "required-vars.yml")).To(BeAnExistingFile())
 						Expect(filepath.Join(productDir, "resource-vars.yml")).To(BeAnExistingFile())
+						Expect(filepath.Join(productDir, "product.schema.json")).ToNot(BeAnExistingFile())
+					})
+				})
+
+				When("--json-schema is set", func() {
+					BeforeEach(func() {
+						tempDir = createOutputDirectory()
+
+						args = []string{
+							"--output-directory", tempDir,
+							"--pivnet-api-token", "b",
+							"--pivnet-product-slug", "c",
+							"--product-version", "d",
+							"--json-schema",
+						}
+					})
+
+					It("creates a JSON Schema of the product config", func() {
+						err := command.Execute(args)
+						Expect(err).ToNot(HaveOccurred())
+
+						contents, err := ioutil.ReadFile(filepath.Join(tempDir, "example-product", "1.1.1", "product.schema.json"))
+						Expect(err).ToNot(HaveOccurred())
+						Expect(string(contents)).To(ContainSubstring(`"title": "example-product 1.1.1 product configuration"`))
 					})
 				})
 
@@ -181,7 +205,7 @@ var _ = Describe("ConfigTemplate", func() {
 				f := &fakes.MetadataProvider{}
 				f.MetadataBytesReturns([]byte(`{name: example-product, product_version: "1.1.1"}`), nil)
 				return f
-			})
+			}, false)
 		})
 
 		It("returns usage information for the command", func() {
@@ -200,7 +224,7 @@ var _ = Describe("ConfigTemplate", func() {
 					f := &fakes.MetadataProvider{}
 					f.MetadataBytesReturns([]byte(`{name: example-product, product_version: "1.1.1"}`), nil)
 					return f
-				})
+				}, false)
 			})
 			It("returns an error", func() {
 				err := command.Execute([]string{"--invalid"})
@@ -216,7 +240,7 @@ var _ = Describe("ConfigTemplate", func() {
 					f := &fakes.MetadataProvider{}
 					f.MetadataBytesReturns([]byte(`{name: example-product, product_version: "1.1.1"}`), nil)
 					return f
-				})
+				}, false)
 			})
 			It("returns an error", func() {
 				err := command.Execute([]string{
@@ -234,7 +258,7 @@ var _ = Describe("ConfigTemplate", func() {
 					f := &fakes.MetadataProvider{}
 					f.MetadataBytesReturns([]byte(`{name: example-product, product_version: "1.1.1"}`), nil)
 					return f
-				})
+				}, false)
 			})
 			DescribeTable("returns an error", func(required, message string) {
 				args := []string{
@@ -266,7 +290,7 @@ var _ = Describe("ConfigTemplate", func() {
 					f := &fakes.MetadataProvider{}
 					f.MetadataBytesReturns([]byte(`{name: example-product, product_version: "1.1.1"}`), nil)
 					return f
-				})
+				}, false)
 			})
 			var (
 				configFile *os.File
@@ -356,7 +380,7 @@ output-directory: %s
 							f := &fakes.MetadataProvider{}
 							f.MetadataBytesReturns([]byte(`{name: example-product, product_version: "1.1.1"}`), nil)
 							return f
-						}, environFunc)
+						}, environFunc, false)
 					})
 
 					It("can interpolate variables into the configuration", func() {
@@ -377,7 +401,7 @@ output-directory: %s
 						f := &fakes.MetadataProvider{}
 						f.MetadataBytesReturns(nil, errors.New("cannot get metadata"))
 						return f
-					})
+					}, false)
 				})
 
 				It("returns an error", func() {
@@ -400,7 +424,7 @@ output-directory: %s
 						f := &fakes.MetadataProvider{}
 						f.MetadataBytesReturns([]byte(`{name: example-product, product_version: ""}`), nil)
 						return f
-					})
+					}, false)
 				})
 				It("errors", func() {
 					tempDir := createOutputDirectory()
@@ -3,8 +3,11 @@ package commands_test
 import (
 	"errors"
 	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/ghttp"
 	"log"
+	"net/http"
 	"os"
+	"path/filepath"
 
 	"github.com/pivotal-cf/om/api"
 	"github.com/pivotal-cf/om/commands"
@@ -12,6 +15,7 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
 	"io/ioutil"
 )
 
@@ -43,7 +47,7 @@ var _ = Describe("ConfigureAuthentication.Execute", func() {
 			return eaOutputs[service.EnsureAvailabilityCallCount()-1], nil
 		}
 
-		command := commands.NewConfigureAuthentication(nil, service, logger)
+		command := commands.NewConfigureAuthentication(nil, service, logger, false)
 		err := command.Execute([]string{
 			"--username", "some-username",
 			"--password", "some-password",
@@ -80,7 +84,7 @@ It will have the username 'precreated-client' and the client secret you provided
 				Status: api.EnsureAvailabilityStatusComplete,
 			}, nil)
 
-			command := commands.NewConfigureAuthentication(nil, service, logger)
+			command := commands.NewConfigureAuthentication(nil, service, logger, false)
 			err := command.Execute([]string{
 				"--username", "some-username",
 				"--password", "some-password",
@@ -123,7 +127,7 @@ decryption-passphrase: some-passphrase
 		})
 
 		It("reads configuration from config file", func() {
-			command := commands.NewConfigureAuthentication(nil, service, logger)
+			command := commands.NewConfigureAuthentication(nil, service, logger, false)
 			err := command.Execute([]string{
 				"--config", configFile,
 			})
@@ -147,7 +151,7 @@ decryption-passphrase: some-passphrase
 		})
 
 		It("respects vars from flags over those in the config file", func() {
-			command := commands.NewConfigureAuthentication(nil, service, logger)
+			command := commands.NewConfigureAuthentication(nil, service, logger, false)
 			err := command.Execute([]string{
 				"--config", configFile,
 				"--password", "some-password-1",
@@ -190,7 +194,7 @@ decryption-passphrase: ((vars-passphrase))
 
 		Context("variables are not provided", func() {
 			It("returns an error", func() {
-				command := commands.NewConfigureAuthentication(nil, service, logger)
+				command := commands.NewConfigureAuthentication(nil, service, logger, false)
 				err := command.Execute([]string{
 					"--config", configFile,
 				})
@@ -216,7 +220,7 @@ vars-passphrase: a-vars-file-passphrase
 			})
 
 			It("uses values from the vars file", func() {
-				command := commands.NewConfigureAuthentication(nil, service, logger)
+				command := commands.NewConfigureAuthentication(nil, service, logger, false)
 				err := command.Execute([]string{
 					"--config", configFile,
 					"--vars-file", varsFile,
@@ -237,7 +241,7 @@ vars-passphrase: a-vars-file-passphrase
 
 		Context("passed in a var (--var)", func() {
 			It("uses values from the command line", func() {
-				command := commands.NewConfigureAuthentication(nil, service, logger)
+				command := commands.NewConfigureAuthentication(nil, service, logger, false)
 				err := command.Execute([]string{
 					"--config", configFile,
 					"--var", "vars-password=a-command-line-password",
@@ -264,7 +268,7 @@ vars-passphrase: a-vars-file-passphrase
 						return []string{"OM_VAR_vars-password=an-env-var-password", "OM_VAR_vars-passphrase=an-env-var-passphrase"}
 					},
 					service,
-					logger,
+					logger, false,
 				)
 
 				err := command.Execute([]string{
@@ -294,7 +298,7 @@ vars-passphrase: a-vars-file-passphrase
 						return []string{"OM_VAR_vars-password=an-env-var-password", "OM_VAR_vars-passphrase=an-env-var-passphrase"}
 					},
 					service,
-					logger,
+					logger, false,
 				)
 
 				err = command.Execute([]string{
@@ -313,6 +317,111 @@ vars-passphrase: a-vars-file-passphrase
 				}))
 			})
 		})
+
+		Context("in strict mode (--strict)", func() {
+			It("fails with every missing and unused variable", func() {
+				command := commands.NewConfigureAuthentication(nil, service, logger, true)
+				err := command.Execute([]string{
+					"--config", configFile,
+					"--var", "vars-password=a-password",
+					"--var", "vars-pasphrase=a-typo",
+				})
+				Expect(err).To(MatchError("could not parse configure-authentication flags: could not load the config file: the variables are not strictly used:\n" +
+					"- missing ((vars-passphrase))\n" +
+					`- unused "vars-pasphrase" from --var`))
+				Expect(service.SetupCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("generated in a vars store (--vars-store)", func() {
+			It("generates the passwords once and reuses them", func() {
+				configFile = writeTestConfigFile(`
+username: some-username
+password: ((vars-password))
+decryption-passphrase: ((vars-passphrase))
+variables:
+- name: vars-password
+  type: password
+- name: vars-passphrase
+  type: password
+`)
+
+				dir, err := ioutil.TempDir("", "")
+				Expect(err).ToNot(HaveOccurred())
+				defer os.RemoveAll(dir)
+				storePath := filepath.Join(dir, "creds.yml")
+
+				command := commands.NewConfigureAuthentication(nil, service, logger, false)
+				err = command.Execute([]string{
+					"--config", configFile,
+					"--vars-store", storePath,
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				contents, err := ioutil.ReadFile(storePath)
+				Expect(err).ToNot(HaveOccurred())
+
+				var store map[string]string
+				Expect(yaml.Unmarshal(contents, &store)).To(Succeed())
+
+				setup := service.SetupArgsForCall(0)
+				Expect(setup.AdminPassword).To(Equal(store["vars-password"]))
+				Expect(setup.DecryptionPassphrase).To(Equal(store["vars-passphrase"]))
+				Expect(setup.AdminPassword).To(HaveLen(20))
+
+				service.EnsureAvailabilityReturnsOnCall(2, api.EnsureAvailabilityOutput{Status: api.EnsureAvailabilityStatusUnstarted}, nil)
+				service.EnsureAvailabilityReturnsOnCall(3, api.EnsureAvailabilityOutput{Status: api.EnsureAvailabilityStatusComplete}, nil)
+
+				command = commands.NewConfigureAuthentication(nil, service, logger, false)
+				err = command.Execute([]string{
+					"--config", configFile,
+					"--vars-store", storePath,
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(service.SetupArgsForCall(1)).To(Equal(setup))
+			})
+		})
+
+		Context("passed from a credential store (--vars-source)", func() {
+			It("interpolates variables from credhub into the configuration", func() {
+				server := ghttp.NewServer()
+				defer server.Close()
+
+				server.RouteToHandler("GET", "/api/v1/data", func(w http.ResponseWriter, r *http.Request) {
+					Expect(r.Header.Get("Authorization")).To(Equal("Bearer some-token"))
+					switch r.URL.Query().Get("name") {
+					case "/opsman/vars-password":
+						w.Write([]byte(`{"data": [{"type": "password", "value": "a-credhub-password"}]}`))
+					case "/opsman/vars-passphrase":
+						w.Write([]byte(`{"data": [{"type": "password", "value": "a-credhub-passphrase"}]}`))
+					default:
+						w.WriteHeader(http.StatusNotFound)
+					}
+				})
+
+				command := commands.NewConfigureAuthentication(
+					func() []string { return []string{"CREDHUB_TOKEN=some-token"} },
+					service,
+					logger, false,
+				)
+
+				err := command.Execute([]string{
+					"--config", configFile,
+					"--vars-source", "credhub:address=" + server.URL() + ",path=/opsman",
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(service.SetupArgsForCall(0)).To(Equal(api.SetupInput{
+					IdentityProvider:                 "internal",
+					AdminUserName:                    "some-username",
+					AdminPassword:                    "a-credhub-password",
+					AdminPasswordConfirmation:        "a-credhub-password",
+					DecryptionPassphrase:             "a-credhub-passphrase",
+					DecryptionPassphraseConfirmation: "a-credhub-passphrase",
+					EULAAccepted:                     "true",
+				}))
+			})
+		})
 	})
 
 	When("OpsMan is < 2.5", func() {
@@ -332,7 +441,7 @@ vars-passphrase: a-vars-file-passphrase
 				Version: "2.4-build.1",
 			}, nil)
 
-			command := commands.NewConfigureAuthentication(nil, service, logger)
+			command := commands.NewConfigureAuthentication(nil, service, logger, false)
 			err := command.Execute([]string{
 				"--username", "some-username",
 				"--password", "some-password",
@@ -349,7 +458,7 @@ This is only supported in OpsManager 2.5 and up.
 	Context("failure cases", func() {
 		When("an unknown flag is provided", func() {
 			It("returns an error", func() {
-				command := commands.NewConfigureAuthentication(nil, service, logger)
+				command := commands.NewConfigureAuthentication(nil, service, logger, false)
 				err := command.Execute([]string{"--banana"})
 				Expect(err).To(MatchError("could not parse configure-authentication flags: flag provided but not defined: -banana"))
 			})
@@ -357,7 +466,7 @@ This is only supported in OpsManager 2.5 and up.
 
 		When("config file cannot be opened", func() {
 			It("returns an error", func() {
-				command := commands.NewConfigureAuthentication(nil, service, logger)
+				command := commands.NewConfigureAuthentication(nil, service, logger, false)
 				err := command.Execute([]string{"--config", "something"})
 				Expect(err).To(MatchError("could not parse configure-authentication flags: could not load the config file: could not read file (something): open something: no such file or directory"))
 			})
@@ -367,7 +476,7 @@ This is only supported in OpsManager 2.5 and up.
 			It("returns an error", func() {
 				service.EnsureAvailabilityReturns(api.EnsureAvailabilityOutput{}, errors.New("failed to fetch status"))
 
-				command := commands.NewConfigureAuthentication(nil, service, logger)
+				command := commands.NewConfigureAuthentication(nil, service, logger, false)
 				err := command.Execute([]string{
 					"--username", "some-username",
 					"--password", "some-password",
@@ -383,7 +492,7 @@ This is only supported in OpsManager 2.5 and up.
 					Status: api.EnsureAvailabilityStatusUnknown,
 				}, nil)
 
-				command := commands.NewConfigureAuthentication(nil, service, logger)
+				command := commands.NewConfigureAuthentication(nil, service, logger, false)
 				err := command.Execute([]string{
 					"--username", "some-username",
 					"--password", "some-password",
@@ -401,7 +510,7 @@ This is only supported in OpsManager 2.5 and up.
 
 				service.SetupReturns(api.SetupOutput{}, errors.New("could not setup"))
 
-				command := commands.NewConfigureAuthentication(nil, service, logger)
+				command := commands.NewConfigureAuthentication(nil, service, logger, false)
 				err := command.Execute([]string{
 					"--username", "some-username",
 					"--password", "some-password",
@@ -426,7 +535,7 @@ This is only supported in OpsManager 2.5 and up.
 					return eaOutputs[service.EnsureAvailabilityCallCount()-1], eaErrors[service.EnsureAvailabilityCallCount()-1]
 				}
 
-				command := commands.NewConfigureAuthentication(nil, service, logger)
+				command := commands.NewConfigureAuthentication(nil, service, logger, false)
 				err := command.Execute([]string{
 					"--username", "some-username",
 					"--password", "some-password",
@@ -438,7 +547,7 @@ This is only supported in OpsManager 2.5 and up.
 
 		When("the --username flag is missing", func() {
 			It("returns an error", func() {
-				command := commands.NewConfigureAuthentication(nil, nil, nil)
+				command := commands.NewConfigureAuthentication(nil, nil, nil, false)
 				err := command.Execute([]string{
 					"--password", "some-password",
 					"--decryption-passphrase", "some-passphrase",
@@ -449,7 +558,7 @@ This is only supported in OpsManager 2.5 and up.
 
 		When("the --password flag is missing", func() {
 			It("returns an error", func() {
-				command := commands.NewConfigureAuthentication(nil, nil, nil)
+				command := commands.NewConfigureAuthentication(nil, nil, nil, false)
 				err := command.Execute([]string{
 					"--username", "some-username",
 					"--decryption-passphrase", "some-passphrase",
@@ -460,7 +569,7 @@ This is only supported in OpsManager 2.5 and up.
 
 		When("the --decryption-passphrase flag is missing", func() {
 			It("returns an error", func() {
-				command := commands.NewConfigureAuthentication(nil, nil, nil)
+				command := commands.NewConfigureAuthentication(nil, nil, nil, false)
 				err := command.Execute([]string{
 					"--username", "some-username",
 					"--password", "some-password",
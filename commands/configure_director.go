@@ -16,10 +16,13 @@ type ConfigureDirector struct {
 	environFunc func() []string
 	service     configureDirectorService
 	logger      logger
+	strict      bool
 	Options     struct {
 		IgnoreVerifierWarnings bool     `long:"ignore-verifier-warnings"  description:"option to ignore verifier warnings. NOT RECOMMENDED UNLESS DISABLED IN OPS MANAGER"`
 		ConfigFile             string   `short:"c" long:"config"           description:"path to yml file containing all config fields (see docs/configure-director/README.md for format)" required:"true"`
 		VarsFile               []string `long:"vars-file"                  description:"Load variables from a YAML file"`
+		VarsSource             []string `long:"vars-source" env:"OM_VARS_SOURCE" description:"Load variables that are not otherwise provided from a credential store (e.g.: 'vault:address=https://vault.example.com,mount=secret,path=foundation' or 'credhub:address=https://credhub.example.com,path=/foundation')"`
+		VarsStore              string   `long:"vars-store" description:"Load variables from a YAML file, the variables defined in the 'variables' section of the config that are missing are generated and saved to it"`
 		VarsEnv                []string `long:"vars-env" env:"OM_VARS_ENV" description:"Load variables from environment variables (e.g.: 'MY' to load MY_var=value)"`
 		Vars                   []string `long:"var" short:"v"              description:"Load variable from the command line. Format: VAR=VAL"`
 		OpsFile                []string `long:"ops-file"                   description:"YAML operations file"`
@@ -63,11 +66,12 @@ type configureDirectorService interface {
 	UpdateStagedDirectorProperties(api.DirectorProperties) error
 }
 
-func NewConfigureDirector(environFunc func() []string, service configureDirectorService, logger logger) ConfigureDirector {
+func NewConfigureDirector(environFunc func() []string, service configureDirectorService, logger logger, strict bool) ConfigureDirector {
 	return ConfigureDirector{
 		environFunc: environFunc,
 		service:     service,
 		logger:      logger,
+		strict:      strict,
 	}
 }
 
@@ -149,13 +153,16 @@ func (c ConfigureDirector) Execute(args []string) error {
 
 func (c ConfigureDirector) interpolateConfig() (*directorConfig, error) {
 	configContents, err := interpolate.Execute(interpolate.Options{
-		TemplateFile:  c.Options.ConfigFile,
-		VarsFiles:     c.Options.VarsFile,
-		EnvironFunc:   c.environFunc,
-		Vars:          c.Options.Vars,
-		VarsEnvs:      c.Options.VarsEnv,
-		OpsFiles:      c.Options.OpsFile,
-		ExpectAllKeys: true,
+		TemplateFile:      c.Options.ConfigFile,
+		VarsFiles:         c.Options.VarsFile,
+		VarsSources:       c.Options.VarsSource,
+		VarsStore:         c.Options.VarsStore,
+		EnvironFunc:       c.environFunc,
+		Vars:              c.Options.Vars,
+		VarsEnvs:          c.Options.VarsEnv,
+		OpsFiles:          c.Options.OpsFile,
+		ExpectAllKeys:     true,
+		ExpectAllVarsUsed: c.strict,
 	})
 	if err != nil {
 		return nil, err
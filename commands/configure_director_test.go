@@ -56,7 +56,7 @@ var _ = Describe("ConfigureDirector", func() {
 		command = commands.NewConfigureDirector(
 			func() []string { return []string{} },
 			service,
-			logger)
+			logger, false)
 	})
 
 	JustBeforeEach(func() {
@@ -403,7 +403,7 @@ vmtypes-configuration:
 							command = commands.NewConfigureDirector(
 								func() []string { return []string{"OM_VAR_name=network"} },
 								service,
-								logger)
+								logger, false)
 
 							err = command.Execute([]string{
 								"--config", writeTestConfigFile("vmextensions-configuration: [{name: ((name))}]"),
@@ -426,7 +426,7 @@ vmtypes-configuration:
 							command = commands.NewConfigureDirector(
 								func() []string { return []string{"OM_VAR_name=network"} },
 								service,
-								logger)
+								logger, false)
 
 							err = command.Execute([]string{
 								"--config", writeTestConfigFile("vmextensions-configuration: [{name: ((name))}]"),
@@ -8,10 +8,12 @@ import (
 )
 
 type ConfigureLDAPAuthentication struct {
-	service     configureAuthenticationService
-	logger      logger
-	environFunc func() []string
-	Options     struct {
+	service      configureAuthenticationService
+	logger       logger
+	environFunc  func() []string
+	strict       bool
+	defaultFlags []string
+	Options      struct {
 		ConfigFile                string   `long:"config"                short:"c"                  description:"path to yml file for configuration (keys must match the following command line flags)"`
 		DecryptionPassphrase      string   `long:"decryption-passphrase" short:"dp" required:"true" description:"passphrase used to encrypt the installation"`
 		HTTPProxyURL              string   `long:"http-proxy-url"                                   description:"proxy for outbound HTTP network traffic"`
@@ -32,25 +34,33 @@ type ConfigureLDAPAuthentication struct {
 		PrecreatedClientSecret    string   `long:"precreated-client-secret"                         description:"create a UAA client on the Ops Manager vm. The client_secret will be the value provided to this option"`
 		VarsEnv                   []string `long:"vars-env" env:"OM_VARS_ENV" experimental:"true"   description:"load vars from environment variables by specifying a prefix (e.g.: 'MY' to load MY_var=value)"`
 		VarsFile                  []string `long:"vars-file"                                        description:"Load variables from a YAML file"`
+		VarsSource                []string `long:"vars-source" env:"OM_VARS_SOURCE" description:"Load variables that are not otherwise provided from a credential store (e.g.: 'vault:address=https://vault.example.com,mount=secret,path=foundation' or 'credhub:address=https://credhub.example.com,path=/foundation')"`
+		VarsStore                 string   `long:"vars-store" description:"Load variables from a YAML file, the variables defined in the 'variables' section of the config that are missing are generated and saved to it"`
 		Vars                      []string `long:"var"                                              description:"Load variable from the command line. Format: VAR=VAL"`
 	}
 }
 
-func NewConfigureLDAPAuthentication(environFunc func() []string, service configureAuthenticationService, logger logger) ConfigureLDAPAuthentication {
+func NewConfigureLDAPAuthentication(environFunc func() []string, service configureAuthenticationService, logger logger, strict bool) ConfigureLDAPAuthentication {
 	return ConfigureLDAPAuthentication{
 		environFunc: environFunc,
 		service:     service,
 		logger:      logger,
+		strict:      strict,
 	}
 }
 
+func (ca ConfigureLDAPAuthentication) WithDefaultFlags(defaultFlags []string) jhanda.Command {
+	ca.defaultFlags = defaultFlags
+	return ca
+}
+
 func (ca ConfigureLDAPAuthentication) Execute(args []string) error {
 	var (
 		boshAdminClientMsg string
 		opsManUaaClientMsg string
 	)
 
-	err := loadConfigFile(args, &ca.Options, ca.environFunc)
+	err := loadConfigFile(args, &ca.Options, ca.environFunc, ca.strict, ca.defaultFlags)
 	if err != nil {
 		return fmt.Errorf("could not parse configure-ldap-authentication flags: %s", err)
 	}
@@ -45,7 +45,7 @@ var _ = Describe("ConfigureLDAPAuthentication.Execute", func() {
 			return eaOutputs[service.EnsureAvailabilityCallCount()-1], nil
 		}
 
-		command = commands.NewConfigureLDAPAuthentication(nil, service, logger)
+		command = commands.NewConfigureLDAPAuthentication(nil, service, logger, false)
 
 		service.InfoReturns(api.Info{
 			Version: "2.5-build.1",
@@ -303,7 +303,7 @@ ldap-referrals: follow
 
 		Context("variables are not provided", func() {
 			It("returns an error", func() {
-				command := commands.NewConfigureLDAPAuthentication(nil, service, logger)
+				command := commands.NewConfigureLDAPAuthentication(nil, service, logger, false)
 				err := command.Execute([]string{
 					"--config", configFile,
 				})
@@ -329,7 +329,7 @@ password: a-vars-file-password
 			})
 
 			It("uses values from the vars file", func() {
-				command := commands.NewConfigureLDAPAuthentication(nil, service, logger)
+				command := commands.NewConfigureLDAPAuthentication(nil, service, logger, false)
 				err := command.Execute([]string{
 					"--config", configFile,
 					"--vars-file", varsFile,
@@ -360,7 +360,7 @@ password: a-vars-file-password
 
 		Context("passed in a var (--var)", func() {
 			It("uses values from the command line", func() {
-				command := commands.NewConfigureLDAPAuthentication(nil, service, logger)
+				command := commands.NewConfigureLDAPAuthentication(nil, service, logger, false)
 				err := command.Execute([]string{
 					"--config", configFile,
 					"--var", "password=a-command-line-password",
@@ -397,7 +397,7 @@ password: a-vars-file-password
 						return []string{"OM_VAR_password=an-env-var-password", "OM_VAR_passphrase=an-env-var-passphrase"}
 					},
 					service,
-					logger,
+					logger, false,
 				)
 
 				err := command.Execute([]string{
@@ -437,7 +437,7 @@ password: a-vars-file-password
 						return []string{"OM_VAR_password=an-env-var-password", "OM_VAR_passphrase=an-env-var-passphrase"}
 					},
 					service,
-					logger,
+					logger, false,
 				)
 
 				err = command.Execute([]string{
@@ -530,7 +530,7 @@ password: a-vars-file-password
 
 		When("missing required fields", func() {
 			It("returns an error", func() {
-				command := commands.NewConfigureLDAPAuthentication(nil, nil, nil)
+				command := commands.NewConfigureLDAPAuthentication(nil, nil, nil, false)
 				err := command.Execute(nil)
 				Expect(err).To(MatchError("could not parse configure-ldap-authentication flags: missing required flag \"--decryption-passphrase\""))
 			})
@@ -20,9 +20,12 @@ type ConfigureProduct struct {
 	service     configureProductService
 	logger      logger
 	target      string
+	strict      bool
 	Options     struct {
 		ConfigFile string   `long:"config"    short:"c"        description:"path to yml file containing all config fields (see docs/configure-product/README.md for format)" required:"true"`
 		VarsFile   []string `long:"vars-file" short:"l"        description:"Load variables from a YAML file"`
+		VarsSource []string `long:"vars-source" env:"OM_VARS_SOURCE" description:"Load variables that are not otherwise provided from a credential store (e.g.: 'vault:address=https://vault.example.com,mount=secret,path=foundation' or 'credhub:address=https://credhub.example.com,path=/foundation')"`
+		VarsStore  string   `long:"vars-store" description:"Load variables from a YAML file, the variables defined in the 'variables' section of the config that are missing are generated and saved to it"`
 		Vars       []string `long:"var" short:"v"              description:"Load variable from the command line. Format: VAR=VAL"`
 		VarsEnv    []string `long:"vars-env" env:"OM_VARS_ENV" description:"Load variables from environment variables (e.g.: 'MY' to load MY_var=value)"`
 		OpsFile    []string `long:"ops-file"  short:"o"        description:"YAML operations file"`
@@ -49,12 +52,13 @@ type configureProduct struct {
 	Field                       map[string]interface{} `yaml:",inline"`
 }
 
-func NewConfigureProduct(environFunc func() []string, service configureProductService, target string, logger logger) ConfigureProduct {
+func NewConfigureProduct(environFunc func() []string, service configureProductService, target string, logger logger, strict bool) ConfigureProduct {
 	return ConfigureProduct{
 		environFunc: environFunc,
 		service:     service,
 		target:      target,
 		logger:      logger,
+		strict:      strict,
 	}
 }
 
@@ -326,13 +330,16 @@ func (cp *ConfigureProduct) configureErrands(cfg configureProduct, productGUID s
 
 func (cp *ConfigureProduct) interpolateConfig(cfg configureProduct) (configureProduct, error) {
 	configContents, err := interpolate.Execute(interpolate.Options{
-		TemplateFile:  cp.Options.ConfigFile,
-		VarsFiles:     cp.Options.VarsFile,
-		Vars:          cp.Options.Vars,
-		EnvironFunc:   cp.environFunc,
-		VarsEnvs:      cp.Options.VarsEnv,
-		OpsFiles:      cp.Options.OpsFile,
-		ExpectAllKeys: true,
+		TemplateFile:      cp.Options.ConfigFile,
+		VarsFiles:         cp.Options.VarsFile,
+		VarsSources:       cp.Options.VarsSource,
+		VarsStore:         cp.Options.VarsStore,
+		Vars:              cp.Options.Vars,
+		EnvironFunc:       cp.environFunc,
+		VarsEnvs:          cp.Options.VarsEnv,
+		OpsFiles:          cp.Options.OpsFile,
+		ExpectAllKeys:     true,
+		ExpectAllVarsUsed: cp.strict,
 	})
 	if err != nil {
 		return configureProduct{}, err
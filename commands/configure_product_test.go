@@ -45,7 +45,7 @@ var _ = Describe("ConfigureProduct", func() {
 			})
 
 			It("configures the given product's properties", func() {
-				client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+				client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger, false)
 
 				service.ListStagedProductsReturns(api.StagedProductsOutput{
 					Products: []api.StagedProduct{
@@ -78,7 +78,7 @@ var _ = Describe("ConfigureProduct", func() {
 			})
 
 			It("check configuration is complete after configuring", func() {
-				client := commands.NewConfigureProduct(func() []string { return nil }, service, "example.com", logger)
+				client := commands.NewConfigureProduct(func() []string { return nil }, service, "example.com", logger, false)
 
 				service.ListStagedProductsReturns(api.StagedProductsOutput{
 					Products: []api.StagedProduct{
@@ -117,7 +117,7 @@ var _ = Describe("ConfigureProduct", func() {
 			})
 
 			It("returns a helpful error message if configuration completeness cannot be validated", func() {
-				client := commands.NewConfigureProduct(func() []string { return nil }, service, "example.com", logger)
+				client := commands.NewConfigureProduct(func() []string { return nil }, service, "example.com", logger, false)
 
 				service.ListStagedProductsReturns(api.StagedProductsOutput{
 					Products: []api.StagedProduct{
@@ -158,7 +158,7 @@ var _ = Describe("ConfigureProduct", func() {
 			})
 
 			It("configures a product's network", func() {
-				client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+				client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger, false)
 
 				service.ListStagedProductsReturns(api.StagedProductsOutput{
 					Products: []api.StagedProduct{
@@ -197,7 +197,7 @@ var _ = Describe("ConfigureProduct", func() {
 			})
 
 			It("configures a product's syslog", func() {
-				client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+				client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger, false)
 
 				service.ListStagedProductsReturns(api.StagedProductsOutput{
 					Products: []api.StagedProduct{
@@ -236,7 +236,7 @@ var _ = Describe("ConfigureProduct", func() {
 			})
 
 			It("configures the resource that is provided", func() {
-				client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+				client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger, false)
 				service.ListStagedProductsReturns(api.StagedProductsOutput{
 					Products: []api.StagedProduct{
 						{GUID: "some-product-guid", Type: "cf"},
@@ -289,7 +289,7 @@ var _ = Describe("ConfigureProduct", func() {
 			})
 
 			It("sets the max in flight for all jobs", func() {
-				client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+				client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger, false)
 				service.ListStagedProductsReturns(api.StagedProductsOutput{
 					Products: []api.StagedProduct{
 						{GUID: "some-product-guid", Type: "cf"},
@@ -348,7 +348,7 @@ var _ = Describe("ConfigureProduct", func() {
 			When("the config file contains variables", func() {
 				Context("passed in a vars-file", func() {
 					It("can interpolate variables into the configuration", func() {
-						client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+						client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger, false)
 
 						configFile, err = ioutil.TempFile("", "")
 						Expect(err).ToNot(HaveOccurred())
@@ -372,7 +372,7 @@ var _ = Describe("ConfigureProduct", func() {
 
 				Context("given vars", func() {
 					It("can interpolate variables into the configuration", func() {
-						client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+						client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger, false)
 
 						configFile, err = ioutil.TempFile("", "")
 						Expect(err).ToNot(HaveOccurred())
@@ -390,7 +390,7 @@ var _ = Describe("ConfigureProduct", func() {
 
 				Context("passed as environment variables", func() {
 					It("can interpolate variables into the configuration", func() {
-						client := commands.NewConfigureProduct(func() []string { return []string{"OM_VAR_password=something-secure"} }, service, "", logger)
+						client := commands.NewConfigureProduct(func() []string { return []string{"OM_VAR_password=something-secure"} }, service, "", logger, false)
 
 						configFile, err = ioutil.TempFile("", "")
 						Expect(err).ToNot(HaveOccurred())
@@ -409,7 +409,7 @@ var _ = Describe("ConfigureProduct", func() {
 						os.Setenv("OM_VARS_ENV", "OM_VAR")
 						defer os.Unsetenv("OM_VARS_ENV")
 
-						client := commands.NewConfigureProduct(func() []string { return []string{"OM_VAR_password=something-secure"} }, service, "", logger)
+						client := commands.NewConfigureProduct(func() []string { return []string{"OM_VAR_password=something-secure"} }, service, "", logger, false)
 
 						configFile, err = ioutil.TempFile("", "")
 						Expect(err).ToNot(HaveOccurred())
@@ -425,7 +425,7 @@ var _ = Describe("ConfigureProduct", func() {
 				})
 
 				It("returns an error if missing variables", func() {
-					client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+					client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger, false)
 
 					configFile, err = ioutil.TempFile("", "")
 					Expect(err).ToNot(HaveOccurred())
@@ -442,7 +442,7 @@ var _ = Describe("ConfigureProduct", func() {
 
 			When("an ops-file is provided", func() {
 				It("can interpolate ops-files into the configuration", func() {
-					client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+					client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger, false)
 
 					configFile, err = ioutil.TempFile("", "")
 					Expect(err).ToNot(HaveOccurred())
@@ -469,7 +469,7 @@ var _ = Describe("ConfigureProduct", func() {
 				})
 
 				It("returns an error if the ops file is invalid", func() {
-					client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+					client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger, false)
 
 					configFile, err = ioutil.TempFile("", "")
 					Expect(err).ToNot(HaveOccurred())
@@ -497,7 +497,7 @@ var _ = Describe("ConfigureProduct", func() {
 				config = fmt.Sprintf(`{"product-name": "cf", "resource-config": %s}`, resourceConfig)
 			})
 			It("returns an error", func() {
-				client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+				client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger, false)
 				service.ListStagedProductsReturns(api.StagedProductsOutput{
 					Products: []api.StagedProduct{
 						{GUID: "some-product-guid", Type: "cf"},
@@ -531,7 +531,7 @@ var _ = Describe("ConfigureProduct", func() {
 			})
 
 			It("logs and then does nothing if they are empty", func() {
-				command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+				command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger, false)
 
 				err := command.Execute([]string{
 					"--config", configFile.Name(),
@@ -575,7 +575,7 @@ var _ = Describe("ConfigureProduct", func() {
 			})
 
 			It("returns an error", func() {
-				client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+				client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger, false)
 				err := client.Execute([]string{"--config", configFile.Name()})
 				Expect(err).To(MatchError("OpsManager does not allow configuration or staging changes while apply changes are running to prevent data loss for configuration and/or staging changes"))
 				Expect(service.ListInstallationsCallCount()).To(Equal(1))
@@ -589,7 +589,7 @@ var _ = Describe("ConfigureProduct", func() {
 
 			When("the product does not exist", func() {
 				It("returns an error", func() {
-					command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+					command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger, false)
 
 					service.ListStagedProductsReturns(api.StagedProductsOutput{
 						Products: []api.StagedProduct{
@@ -610,7 +610,7 @@ var _ = Describe("ConfigureProduct", func() {
 				})
 
 				It("returns an error", func() {
-					command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+					command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger, false)
 					service.ListStagedProductsReturns(api.StagedProductsOutput{
 						Products: []api.StagedProduct{
 							{GUID: "some-product-guid", Type: "cf"},
@@ -628,7 +628,7 @@ var _ = Describe("ConfigureProduct", func() {
 				})
 
 				It("returns an error", func() {
-					command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+					command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger, false)
 					service.ListStagedProductsReturns(api.StagedProductsOutput{
 						Products: []api.StagedProduct{
 							{GUID: "some-product-guid", Type: "cf"},
@@ -647,7 +647,7 @@ var _ = Describe("ConfigureProduct", func() {
 
 			When("an unknown flag is provided", func() {
 				It("returns an error", func() {
-					command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+					command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger, false)
 					err := command.Execute([]string{"--badflag"})
 					Expect(err).To(MatchError("could not parse configure-product flags: flag provided but not defined: -badflag"))
 				})
@@ -659,7 +659,7 @@ var _ = Describe("ConfigureProduct", func() {
 				})
 
 				It("returns an error", func() {
-					command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+					command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger, false)
 					err := command.Execute([]string{"--config", configFile.Name()})
 					Expect(err).To(MatchError("could not parse configure-product config: \"product-name\" is required"))
 				})
@@ -668,7 +668,7 @@ var _ = Describe("ConfigureProduct", func() {
 			When("the --config flag is passed", func() {
 				When("the provided config path does not exist", func() {
 					It("returns an error", func() {
-						command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+						command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger, false)
 						service.ListStagedProductsReturns(api.StagedProductsOutput{
 							Products: []api.StagedProduct{
 								{GUID: "some-product-guid", Type: "cf"},
@@ -691,7 +691,7 @@ var _ = Describe("ConfigureProduct", func() {
 
 					It("returns an error", func() {
 						invalidConfig := "this is not a valid config"
-						client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+						client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger, false)
 						service.ListStagedProductsReturns(api.StagedProductsOutput{
 							Products: []api.StagedProduct{
 								{GUID: "some-product-guid", Type: "cf"},
@@ -718,7 +718,7 @@ var _ = Describe("ConfigureProduct", func() {
 				})
 
 				It("returns an error", func() {
-					command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+					command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger, false)
 					service.UpdateStagedProductPropertiesReturns(errors.New("some product error"))
 
 					service.ListStagedProductsReturns(api.StagedProductsOutput{
@@ -738,7 +738,7 @@ var _ = Describe("ConfigureProduct", func() {
 				})
 
 				It("returns an error", func() {
-					command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+					command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger, false)
 					service.UpdateStagedProductNetworksAndAZsReturns(errors.New("some product error"))
 
 					service.ListStagedProductsReturns(api.StagedProductsOutput{
@@ -758,7 +758,7 @@ var _ = Describe("ConfigureProduct", func() {
 				})
 
 				It("returns an error", func() {
-					command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+					command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger, false)
 					service.UpdateSyslogConfigurationReturns(errors.New("some product error"))
 
 					service.ListStagedProductsReturns(api.StagedProductsOutput{
@@ -778,7 +778,7 @@ var _ = Describe("ConfigureProduct", func() {
 				})
 
 				It("returns an error", func() {
-					command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+					command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger, false)
 					service.UpdateSyslogConfigurationReturns(errors.New("some product error"))
 
 					service.ListStagedProductsReturns(api.StagedProductsOutput{
@@ -810,7 +810,7 @@ var _ = Describe("ConfigureProduct", func() {
 				})
 				It("errors when calling api", func() {
 					service.UpdateStagedProductErrandsReturns(errors.New("error configuring errand"))
-					client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+					client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger, false)
 
 					configFile, err = ioutil.TempFile("", "")
 					Expect(err).ToNot(HaveOccurred())
@@ -834,7 +834,7 @@ var _ = Describe("ConfigureProduct", func() {
 					Expect(err).ToNot(HaveOccurred())
 					Expect(configFile.Close()).ToNot(HaveOccurred())
 
-					client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+					client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger, false)
 					err = client.Execute([]string{
 						"--config", configFile.Name(),
 					})
@@ -9,10 +9,12 @@ import (
 )
 
 type ConfigureSAMLAuthentication struct {
-	service     configureAuthenticationService
-	logger      logger
-	environFunc func() []string
-	Options     struct {
+	service      configureAuthenticationService
+	logger       logger
+	environFunc  func() []string
+	strict       bool
+	defaultFlags []string
+	Options      struct {
 		ConfigFile                string   `long:"config"                short:"c"                  description:"path to yml file for configuration (keys must match the following command line flags)"`
 		DecryptionPassphrase      string   `long:"decryption-passphrase" short:"dp" required:"true" description:"passphrase used to encrypt the installation"`
 		HTTPProxyURL              string   `long:"http-proxy-url"                                   description:"proxy for outbound HTTP network traffic"`
@@ -26,25 +28,33 @@ type ConfigureSAMLAuthentication struct {
 		PrecreatedClientSecret    string   `long:"precreated-client-secret"                         description:"create a UAA client on the Ops Manager vm, whose secret will be the value provided to this option"`
 		VarsEnv                   []string `long:"vars-env" env:"OM_VARS_ENV" experimental:"true"   description:"load vars from environment variables by specifying a prefix (e.g.: 'MY' to load MY_var=value)"`
 		VarsFile                  []string `long:"vars-file"                                        description:"Load variables from a YAML file"`
+		VarsSource                []string `long:"vars-source" env:"OM_VARS_SOURCE" description:"Load variables that are not otherwise provided from a credential store (e.g.: 'vault:address=https://vault.example.com,mount=secret,path=foundation' or 'credhub:address=https://credhub.example.com,path=/foundation')"`
+		VarsStore                 string   `long:"vars-store" description:"Load variables from a YAML file, the variables defined in the 'variables' section of the config that are missing are generated and saved to it"`
 		Vars                      []string `long:"var"                                              description:"Load variable from the command line. Format: VAR=VAL"`
 	}
 }
 
-func NewConfigureSAMLAuthentication(environFunc func() []string, service configureAuthenticationService, logger logger) ConfigureSAMLAuthentication {
+func NewConfigureSAMLAuthentication(environFunc func() []string, service configureAuthenticationService, logger logger, strict bool) ConfigureSAMLAuthentication {
 	return ConfigureSAMLAuthentication{
 		environFunc: environFunc,
 		service:     service,
 		logger:      logger,
+		strict:      strict,
 	}
 }
 
+func (ca ConfigureSAMLAuthentication) WithDefaultFlags(defaultFlags []string) jhanda.Command {
+	ca.defaultFlags = defaultFlags
+	return ca
+}
+
 func (ca ConfigureSAMLAuthentication) Execute(args []string) error {
 	var (
 		boshAdminClientMsg string
 		opsManUaaClientMsg string
 	)
 
-	err := loadConfigFile(args, &ca.Options, ca.environFunc)
+	err := loadConfigFile(args, &ca.Options, ca.environFunc, ca.strict, ca.defaultFlags)
 	if err != nil {
 		return fmt.Errorf("could not parse configure-saml-authentication flags: %s", err)
 	}
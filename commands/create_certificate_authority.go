@@ -14,7 +14,7 @@ type CreateCertificateAuthority struct {
 	Options   struct {
 		CertPem    string `long:"certificate-pem" required:"true" description:"certificate"`
 		PrivateKey string `long:"private-key-pem" required:"true" description:"private key"`
-		Format     string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json)"`
+		Format     string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json,yaml,csv)"`
 	}
 }
 
@@ -32,6 +32,10 @@ func (c CreateCertificateAuthority) Execute(args []string) error {
 		return fmt.Errorf("could not parse create-certificate-authority flags: %s", err)
 	}
 
+	if err := presenters.ValidateFormat(c.Options.Format); err != nil {
+		return fmt.Errorf("could not parse create-certificate-authority flags: %s", err)
+	}
+
 	ca, err := c.service.CreateCertificateAuthority(api.CertificateAuthorityInput{
 		CertPem:       c.Options.CertPem,
 		PrivateKeyPem: c.Options.PrivateKey,
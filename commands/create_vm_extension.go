@@ -20,10 +20,13 @@ type CreateVMExtension struct {
 	environFunc func() []string
 	service     createVMExtensionService
 	logger      logger
+	strict      bool
 	Options     struct {
 		Name            string   `long:"name"               short:"n"   description:"VM extension name"`
 		ConfigFile      string   `long:"config"             short:"c"   description:"path to yml file containing all config fields (see docs/create-vm-extension/README.md for format)"`
 		VarsFile        []string `long:"vars-file"          short:"l"   description:"Load variables from a YAML file"`
+		VarsSource      []string `long:"vars-source" env:"OM_VARS_SOURCE" description:"Load variables that are not otherwise provided from a credential store (e.g.: 'vault:address=https://vault.example.com,mount=secret,path=foundation' or 'credhub:address=https://credhub.example.com,path=/foundation')"`
+		VarsStore       string   `long:"vars-store" description:"Load variables from a YAML file, the variables defined in the 'variables' section of the config that are missing are generated and saved to it"`
 		VarsEnv         []string `long:"vars-env" env:"OM_VARS_ENV"     description:"Load variables from environment variables (e.g.: 'MY' to load MY_var=value)"`
 		Vars            []string `long:"var"                short:"v"   description:"Load variable from the command line. Format: VAR=VAL"`
 		OpsFile         []string `long:"ops-file"           short:"o"   description:"YAML operations file"`
@@ -31,11 +34,12 @@ type CreateVMExtension struct {
 	}
 }
 
-func NewCreateVMExtension(environFunc func() []string, service createVMExtensionService, logger logger) CreateVMExtension {
+func NewCreateVMExtension(environFunc func() []string, service createVMExtensionService, logger logger, strict bool) CreateVMExtension {
 	return CreateVMExtension{
 		environFunc: environFunc,
 		service:     service,
 		logger:      logger,
+		strict:      strict,
 	}
 }
 
@@ -51,13 +55,16 @@ func (c CreateVMExtension) Execute(args []string) error {
 	if c.Options.ConfigFile != "" {
 		var cfg config.VMExtensionConfig
 		configContents, err := interpolate.Execute(interpolate.Options{
-			TemplateFile:  c.Options.ConfigFile,
-			VarsFiles:     c.Options.VarsFile,
-			EnvironFunc:   c.environFunc,
-			VarsEnvs:      c.Options.VarsEnv,
-			Vars:          c.Options.Vars,
-			OpsFiles:      c.Options.OpsFile,
-			ExpectAllKeys: true,
+			TemplateFile:      c.Options.ConfigFile,
+			VarsFiles:         c.Options.VarsFile,
+			VarsSources:       c.Options.VarsSource,
+			VarsStore:         c.Options.VarsStore,
+			EnvironFunc:       c.environFunc,
+			VarsEnvs:          c.Options.VarsEnv,
+			Vars:              c.Options.Vars,
+			OpsFiles:          c.Options.OpsFile,
+			ExpectAllKeys:     true,
+			ExpectAllVarsUsed: c.strict,
 		})
 		if err != nil {
 			return err
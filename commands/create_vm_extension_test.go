@@ -43,7 +43,7 @@ var _ = Describe("CreateVMExtension", func() {
 	BeforeEach(func() {
 		fakeService = &fakes.CreateVMExtensionService{}
 		fakeLogger = &fakes.Logger{}
-		command = commands.NewCreateVMExtension(func() []string { return nil }, fakeService, fakeLogger)
+		command = commands.NewCreateVMExtension(func() []string { return nil }, fakeService, fakeLogger, false)
 	})
 
 	AfterEach(func() {
@@ -140,7 +140,7 @@ var _ = Describe("CreateVMExtension", func() {
 					command = commands.NewCreateVMExtension(
 						func() []string { return []string{"OM_VAR_vm_extension_name=some-vm-extension"} },
 						fakeService,
-						fakeLogger)
+						fakeLogger, false)
 					configFile, err = ioutil.TempFile("", "")
 					Expect(err).ToNot(HaveOccurred())
 
@@ -246,7 +246,7 @@ var _ = Describe("CreateVMExtension", func() {
 
 	Describe("Usage", func() {
 		It("returns usage information for the command", func() {
-			command := commands.NewCreateVMExtension(nil, nil, nil)
+			command := commands.NewCreateVMExtension(nil, nil, nil, false)
 			Expect(command.Usage()).To(Equal(jhanda.Usage{
 				Description:      "This creates/updates a VM extension",
 				ShortDescription: "creates/updates a VM extension",
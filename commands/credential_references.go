@@ -14,8 +14,8 @@ type CredentialReferences struct {
 	presenter presenters.FormattedPresenter
 	logger    logger
 	Options   struct {
-		Product string `long:"product-name" short:"p" required:"true" description:"name of deployed product"`
-		Format  string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json)"`
+		Product string `long:"product-name" short:"p" required:"true" description:"name of deployed product" complete:"products"`
+		Format  string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json,yaml,csv)"`
 	}
 }
 
@@ -34,6 +34,10 @@ func (cr CredentialReferences) Execute(args []string) error {
 		return fmt.Errorf("could not parse credential-references flags: %s", err)
 	}
 
+	if err := presenters.ValidateFormat(cr.Options.Format); err != nil {
+		return fmt.Errorf("could not parse credential-references flags: %s", err)
+	}
+
 	deployedProductGUID := ""
 	deployedProducts, err := cr.service.ListDeployedProducts()
 	if err != nil {
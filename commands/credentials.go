@@ -13,10 +13,10 @@ type Credentials struct {
 	presenter presenters.FormattedPresenter
 	logger    logger
 	Options   struct {
-		Product             string `long:"product-name"         short:"p" required:"true" description:"name of deployed product"`
-		CredentialReference string `long:"credential-reference" short:"c" required:"true" description:"name of credential reference"`
+		Product             string `long:"product-name"         short:"p" required:"true" description:"name of deployed product" complete:"products"`
+		CredentialReference string `long:"credential-reference" short:"c" required:"true" description:"name of credential reference" complete:"credential-references"`
 		CredentialField     string `long:"credential-field"     short:"f"                 description:"single credential field to output"`
-		Format              string `long:"format"               short:"t" default:"table" description:"Format to print as (options: table,json)"`
+		Format              string `long:"format"               short:"t" default:"table" description:"Format to print as (options: table,json,yaml,csv)"`
 	}
 }
 
@@ -35,6 +35,10 @@ func (cs Credentials) Execute(args []string) error {
 		return fmt.Errorf("could not parse credential-references flags: %s", err)
 	}
 
+	if err := presenters.ValidateFormat(cs.Options.Format); err != nil {
+		return fmt.Errorf("could not parse credentials flags: %s", err)
+	}
+
 	deployedProductGUID := ""
 	deployedProducts, err := cs.service.ListDeployedProducts()
 	if err != nil {
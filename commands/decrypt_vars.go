@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/interpolate"
+)
+
+type DecryptVars struct {
+	environFunc func() []string
+	logger      logger
+	Options     struct {
+		VarsFile string `long:"vars-file" short:"l" required:"true" description:"path to the encrypted YAML vars file"`
+	}
+}
+
+func NewDecryptVars(environFunc func() []string, logger logger) *DecryptVars {
+	return &DecryptVars{
+		environFunc: environFunc,
+		logger:      logger,
+	}
+}
+
+func (d *DecryptVars) Execute(args []string) error {
+	if _, err := jhanda.Parse(&d.Options, args); err != nil {
+		return fmt.Errorf("could not parse decrypt-vars flags: %s", err)
+	}
+
+	contents, err := ioutil.ReadFile(d.Options.VarsFile)
+	if err != nil {
+		return fmt.Errorf("could not read vars file (%s): %s", d.Options.VarsFile, err)
+	}
+
+	if !interpolate.IsEncrypted(contents) {
+		return fmt.Errorf("could not decrypt %s: the vars file is not encrypted", d.Options.VarsFile)
+	}
+
+	decrypted, err := interpolate.DecryptVars(contents, d.environFunc)
+	if err != nil {
+		return fmt.Errorf("could not decrypt %s: %s", d.Options.VarsFile, err)
+	}
+
+	d.logger.Print(string(decrypted))
+	return nil
+}
+
+func (d *DecryptVars) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This command decrypts a vars file that has been encrypted in the format of SOPS and prints it. The decrypted vars are never written to disk. The age identities are read from SOPS_AGE_KEY or SOPS_AGE_KEY_FILE (default: ~/.config/sops/age/keys.txt), the PGP private key from OM_PGP_PRIVATE_KEY or OM_PGP_PRIVATE_KEY_FILE (with OM_PGP_PASSPHRASE).",
+		ShortDescription: "prints the decrypted contents of an encrypted vars file",
+		Flags:            d.Options,
+	}
+}
@@ -0,0 +1,76 @@
+package commands_test
+
+import (
+	"os"
+
+	"filippo.io/age"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+	"github.com/pivotal-cf/om/interpolate"
+)
+
+var _ = Describe("DecryptVars", func() {
+	var (
+		command  *commands.DecryptVars
+		logger   *fakes.Logger
+		identity *age.X25519Identity
+		varsFile string
+	)
+
+	BeforeEach(func() {
+		var err error
+		identity, err = age.GenerateX25519Identity()
+		Expect(err).ToNot(HaveOccurred())
+
+		encrypted, err := interpolate.EncryptVars([]byte("password: some-password\n"), interpolate.EncryptionKeys{
+			AgeRecipients: []string{identity.Recipient().String()},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		varsFile = writeTestConfigFile(string(encrypted))
+
+		logger = &fakes.Logger{}
+		command = commands.NewDecryptVars(func() []string {
+			return []string{"SOPS_AGE_KEY=" + identity.String(), "HOME=/nonexistent"}
+		}, logger)
+	})
+
+	AfterEach(func() {
+		os.Remove(varsFile)
+	})
+
+	It("prints the decrypted vars", func() {
+		err := command.Execute([]string{"--vars-file", varsFile})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(logger.PrintCallCount()).To(Equal(1))
+		Expect(logger.PrintArgsForCall(0)).To(Equal([]interface{}{"password: some-password\n"}))
+	})
+
+	When("the vars file is not encrypted", func() {
+		It("returns an error", func() {
+			plain := writeTestConfigFile("password: some-password\n")
+			defer os.Remove(plain)
+
+			err := command.Execute([]string{"--vars-file", plain})
+			Expect(err).To(MatchError("could not decrypt " + plain + ": the vars file is not encrypted"))
+		})
+	})
+
+	When("there is no matching key", func() {
+		It("returns an error", func() {
+			command = commands.NewDecryptVars(func() []string { return []string{"HOME=/nonexistent"} }, logger)
+
+			err := command.Execute([]string{"--vars-file", varsFile})
+			Expect(err).To(MatchError(ContainSubstring("could not decrypt " + varsFile + ": could not decrypt the data key of the vars")))
+		})
+	})
+
+	When("an unknown flag is provided", func() {
+		It("returns an error", func() {
+			err := command.Execute([]string{"--badflag"})
+			Expect(err).To(MatchError("could not parse decrypt-vars flags: flag provided but not defined: -badflag"))
+		})
+	})
+})
@@ -0,0 +1,192 @@
+package commands
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pivotal-cf/jhanda"
+)
+
+// DefaultFlagsCommand is implemented by the commands that load a config file,
+// which takes precedence over the defaults of the user config file (~/.config/om/config.yml).
+// The other commands get the defaults in front of the flags of the command line.
+type DefaultFlagsCommand interface {
+	jhanda.Command
+	WithDefaultFlags(defaultFlags []string) jhanda.Command
+}
+
+// LoadDefaultFlags converts the defaults of the user config file to the flags of command.
+// A default is left out when an environment variable of its flag is set,
+// as environment variables take precedence over the user config file.
+func LoadDefaultFlags(command string, flags interface{}, defaults map[string]interface{}, envFunc func() []string) ([]string, error) {
+	if len(defaults) == 0 {
+		return nil, nil
+	}
+
+	envs := flagEnvs(flags)
+	if envs == nil {
+		return nil, fmt.Errorf("the %s command does not accept default flags", command)
+	}
+
+	environment := map[string]bool{}
+	for _, env := range envFunc() {
+		if pair := strings.SplitN(env, "=", 2); len(pair) == 2 && pair[1] != "" {
+			environment[pair[0]] = true
+		}
+	}
+
+	var names []string
+	for name := range defaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var defaultArgs []string
+	for _, name := range names {
+		env, ok := envs[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown flag %q for the %s command", name, command)
+		}
+
+		if isSet(env, environment) {
+			continue
+		}
+
+		switch value := defaults[name].(type) {
+		case []interface{}:
+			for _, v := range value {
+				defaultArgs = append(defaultArgs, fmt.Sprintf("--%s=%v", name, v))
+			}
+		case bool:
+			defaultArgs = append(defaultArgs, fmt.Sprintf("--%s=%s", name, strconv.FormatBool(value)))
+		default:
+			defaultArgs = append(defaultArgs, fmt.Sprintf("--%s=%v", name, value))
+		}
+	}
+
+	return defaultArgs, nil
+}
+
+func isSet(envTag string, environment map[string]bool) bool {
+	if envTag == "" {
+		return false
+	}
+
+	for _, env := range strings.Split(envTag, ",") {
+		if environment[env] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MergeDefaultFlags puts the default flags in front of args.
+// A default is left out when its flag is given in args,
+// by its long name, its short name or any of its aliases.
+func MergeDefaultFlags(flags interface{}, defaultFlags []string, args []string) []string {
+	fields := flagFields(flags)
+
+	given := map[int]bool{}
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+
+		if field, ok := fields[flagName(arg)]; ok {
+			given[field] = true
+		}
+	}
+
+	merged := []string{}
+	for _, defaultFlag := range defaultFlags {
+		if field, ok := fields[flagName(defaultFlag)]; ok && given[field] {
+			continue
+		}
+		merged = append(merged, defaultFlag)
+	}
+
+	return append(merged, args...)
+}
+
+// flagName returns the name of a flag argument, such as -c, --config or --config=file.yml,
+// or an empty name when arg is not a flag.
+func flagName(arg string) string {
+	if !strings.HasPrefix(arg, "-") {
+		return ""
+	}
+
+	name := strings.TrimLeft(arg, "-")
+	if i := strings.Index(name, "="); i >= 0 {
+		name = name[:i]
+	}
+
+	return name
+}
+
+// flagFields returns the index of the field of every name of the flags.
+func flagFields(flags interface{}) map[string]int {
+	t, ok := flagsType(flags)
+	if !ok {
+		return nil
+	}
+
+	fields := map[string]int{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		for _, tag := range []string{"long", "short", "alias"} {
+			for _, name := range strings.Split(field.Tag.Get(tag), ",") {
+				if name != "" {
+					fields[name] = i
+				}
+			}
+		}
+	}
+
+	return fields
+}
+
+// flagEnvs returns the long names and aliases of the flags,
+// with the environment variable that sets them, if any.
+func flagEnvs(flags interface{}) map[string]string {
+	t, ok := flagsType(flags)
+	if !ok {
+		return nil
+	}
+
+	envs := map[string]string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		var names []string
+		if long := field.Tag.Get("long"); long != "" {
+			names = append(names, long)
+		}
+		if alias := field.Tag.Get("alias"); alias != "" {
+			names = append(names, strings.Split(alias, ",")...)
+		}
+
+		for _, name := range names {
+			envs[name] = field.Tag.Get("env")
+		}
+	}
+
+	return envs
+}
+
+func flagsType(flags interface{}) (reflect.Type, bool) {
+	if flags == nil {
+		return nil, false
+	}
+
+	t := reflect.TypeOf(flags)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t, t.Kind() == reflect.Struct
+}
@@ -0,0 +1,176 @@
+package commands_test
+
+import (
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+)
+
+var _ = Describe("DefaultFlags", func() {
+	var flags struct {
+		Directory string   `long:"output-directory" short:"o" env:"SOME_DIRECTORY"`
+		Warnings  bool     `long:"ignore-warnings"  short:"i"`
+		Errands   []string `long:"errand" alias:"errands"`
+		Timeout   int      `long:"timeout" env:"SOME_TIMEOUT,OTHER_TIMEOUT"`
+	}
+
+	noEnv := func() []string { return nil }
+
+	Describe("LoadDefaultFlags", func() {
+		It("converts the defaults to the flags of the command", func() {
+			defaultFlags, err := commands.LoadDefaultFlags("some-command", flags, map[string]interface{}{
+				"output-directory": "/tmp/downloads",
+				"ignore-warnings":  true,
+				"errands":          []interface{}{"smoke-tests", "push-apps"},
+				"timeout":          30,
+			}, noEnv)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(defaultFlags).To(Equal([]string{
+				"--errands=smoke-tests",
+				"--errands=push-apps",
+				"--ignore-warnings=true",
+				"--output-directory=/tmp/downloads",
+				"--timeout=30",
+			}))
+		})
+
+		It("leaves out the defaults of flags set by an environment variable", func() {
+			defaultFlags, err := commands.LoadDefaultFlags("some-command", &flags, map[string]interface{}{
+				"output-directory": "/tmp/downloads",
+				"ignore-warnings":  true,
+				"timeout":          30,
+			}, func() []string {
+				return []string{"SOME_DIRECTORY=/tmp/elsewhere", "OTHER_TIMEOUT=10", "SOME_TIMEOUT="}
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(defaultFlags).To(Equal([]string{"--ignore-warnings=true"}))
+		})
+
+		It("returns nothing without defaults", func() {
+			defaultFlags, err := commands.LoadDefaultFlags("some-command", nil, nil, noEnv)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(defaultFlags).To(BeEmpty())
+		})
+
+		When("a default is not a flag of the command", func() {
+			It("returns an error", func() {
+				_, err := commands.LoadDefaultFlags("some-command", flags, map[string]interface{}{
+					"output-dir": "/tmp/downloads",
+				}, noEnv)
+				Expect(err).To(MatchError(`unknown flag "output-dir" for the some-command command`))
+			})
+		})
+
+		When("the command has no flags", func() {
+			It("returns an error", func() {
+				_, err := commands.LoadDefaultFlags("some-command", nil, map[string]interface{}{
+					"output-directory": "/tmp/downloads",
+				}, noEnv)
+				Expect(err).To(MatchError("the some-command command does not accept default flags"))
+			})
+		})
+	})
+
+	Describe("MergeDefaultFlags", func() {
+		defaultFlags := []string{"--errands=smoke-tests", "--output-directory=/tmp/downloads", "--timeout=30"}
+
+		It("puts the defaults in front of the flags", func() {
+			Expect(commands.MergeDefaultFlags(flags, defaultFlags, []string{"--ignore-warnings"})).To(Equal([]string{
+				"--errands=smoke-tests",
+				"--output-directory=/tmp/downloads",
+				"--timeout=30",
+				"--ignore-warnings",
+			}))
+		})
+
+		It("leaves out the defaults of the flags that are given by any of their names", func() {
+			Expect(commands.MergeDefaultFlags(&flags, defaultFlags, []string{"-o", "/tmp/elsewhere", "--errand=push-apps"})).To(Equal([]string{
+				"--timeout=30",
+				"-o",
+				"/tmp/elsewhere",
+				"--errand=push-apps",
+			}))
+
+			Expect(commands.MergeDefaultFlags(flags, defaultFlags, []string{"-timeout", "10", "--errands", "push-apps"})).To(Equal([]string{
+				"--output-directory=/tmp/downloads",
+				"-timeout",
+				"10",
+				"--errands",
+				"push-apps",
+			}))
+		})
+
+		It("only looks at the flags in front of --", func() {
+			Expect(commands.MergeDefaultFlags(flags, defaultFlags, []string{"--", "--timeout=10"})).To(Equal([]string{
+				"--errands=smoke-tests",
+				"--output-directory=/tmp/downloads",
+				"--timeout=30",
+				"--",
+				"--timeout=10",
+			}))
+		})
+	})
+
+	Describe("with a config file", func() {
+		var (
+			fakeService  *fakes.AssignStemcellService
+			configFile   string
+			defaultFlags []string
+		)
+
+		BeforeEach(func() {
+			fakeService = &fakes.AssignStemcellService{}
+			fakeService.ListStemcellsReturns(api.ProductStemcells{
+				Products: []api.ProductStemcell{{
+					GUID:              "cf-guid",
+					ProductName:       "cf",
+					AvailableVersions: []string{"1234.5", "1234.6"},
+				}},
+			}, nil)
+
+			file, err := ioutil.TempFile("", "config.yml")
+			Expect(err).ToNot(HaveOccurred())
+			_, err = file.WriteString("stemcell: \"1234.6\"\n")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(file.Close()).To(Succeed())
+			configFile = file.Name()
+
+			defaultFlags = []string{"--product=cf", "--stemcell=1234.5"}
+		})
+
+		AfterEach(func() {
+			Expect(os.Remove(configFile)).To(Succeed())
+		})
+
+		It("gives the config file precedence over the defaults", func() {
+			command := commands.NewAssignStemcell(fakeService, &fakes.Logger{}, false).WithDefaultFlags(defaultFlags)
+			err := command.Execute([]string{"--config", configFile})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeService.AssignStemcellArgsForCall(0).Products[0].StagedStemcellVersion).To(Equal("1234.6"))
+		})
+
+		It("gives the flags precedence over the config file", func() {
+			command := commands.NewAssignStemcell(fakeService, &fakes.Logger{}, false).WithDefaultFlags(defaultFlags)
+			err := command.Execute([]string{"-c", configFile, "-s", "1234.5"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeService.AssignStemcellArgsForCall(0).Products[0].StagedStemcellVersion).To(Equal("1234.5"))
+		})
+
+		It("takes the config file from the defaults", func() {
+			command := commands.NewAssignStemcell(fakeService, &fakes.Logger{}, false).WithDefaultFlags(append(defaultFlags, "--config="+configFile))
+			err := command.Execute(nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeService.AssignStemcellArgsForCall(0).Products[0].StagedStemcellVersion).To(Equal("1234.6"))
+		})
+	})
+})
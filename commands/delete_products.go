@@ -10,7 +10,7 @@ import (
 type DeleteProduct struct {
 	service deleteProductService
 	Options struct {
-		Product string `long:"product-name"    short:"p" required:"true" description:"name of product"`
+		Product string `long:"product-name"    short:"p" required:"true" description:"name of product" complete:"products"`
 		Version string `long:"product-version" short:"v" required:"true" description:"version of product"`
 	}
 }
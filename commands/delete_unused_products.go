@@ -1,13 +1,19 @@
 package commands
 
 import (
+	"fmt"
+
 	"github.com/pivotal-cf/jhanda"
 	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/presenters"
 )
 
 type DeleteUnusedProducts struct {
 	service deleteUnusedProductsService
 	logger  logger
+	Options struct {
+		Format string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json)"`
+	}
 }
 
 //counterfeiter:generate -o ./fakes/delete_unused_products_service.go --fake-name DeleteUnusedProductsService . deleteUnusedProductsService
@@ -23,7 +29,17 @@ func NewDeleteUnusedProducts(service deleteUnusedProductsService, logger logger)
 }
 
 func (dup DeleteUnusedProducts) Execute(args []string) error {
-	dup.logger.Printf("trashing unused products")
+	if _, err := jhanda.Parse(&dup.Options, args); err != nil {
+		return fmt.Errorf("could not parse delete-unused-products flags: %s", err)
+	}
+
+	if err := presenters.ValidateFormat(dup.Options.Format, tableFormat, jsonFormat); err != nil {
+		return fmt.Errorf("could not parse delete-unused-products flags: %s", err)
+	}
+
+	if dup.Options.Format == tableFormat {
+		dup.logger.Printf("trashing unused products")
+	}
 
 	err := dup.service.DeleteAvailableProducts(api.DeleteAvailableProductsInput{
 		ShouldDeleteAllProducts: true,
@@ -32,6 +48,10 @@ func (dup DeleteUnusedProducts) Execute(args []string) error {
 		return err
 	}
 
+	if dup.Options.Format == jsonFormat {
+		return printJSON(dup.logger, map[string]bool{"unused_products_deleted": true})
+	}
+
 	dup.logger.Printf("done")
 
 	return nil
@@ -41,5 +61,6 @@ func (dup DeleteUnusedProducts) Usage() jhanda.Usage {
 	return jhanda.Usage{
 		Description:      "This command deletes unused products in the targeted Ops Manager",
 		ShortDescription: "deletes unused products on the Ops Manager targeted",
+		Flags:            dup.Options,
 	}
 }
@@ -44,6 +44,18 @@ var _ = Describe("DeleteProduct", func() {
 			format, content = logger.PrintfArgsForCall(1)
 			Expect(fmt.Sprintf(format, content...)).To(Equal("done"))
 		})
+
+		When("the format is json", func() {
+			It("prints the result as json", func() {
+				err := command.Execute([]string{"--format", "json"})
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(fakeService.DeleteAvailableProductsCallCount()).To(Equal(1))
+				Expect(logger.PrintfCallCount()).To(Equal(0))
+				Expect(logger.PrintlnCallCount()).To(Equal(1))
+				Expect(logger.PrintlnArgsForCall(0)[0]).To(MatchJSON(`{"unused_products_deleted": true}`))
+			})
+		})
 	})
 
 	When("an error occurs", func() {
@@ -51,10 +63,18 @@ var _ = Describe("DeleteProduct", func() {
 			It("returns an error", func() {
 				fakeService.DeleteAvailableProductsReturns(errors.New("something bad happened"))
 
-				err := command.Execute([]string{"-p", "nah", "-v", "nope"})
+				err := command.Execute([]string{})
 				Expect(err).To(MatchError("something bad happened"))
 			})
 		})
+
+		When("the format is not supported", func() {
+			It("returns an error", func() {
+				err := command.Execute([]string{"--format", "yaml"})
+				Expect(err).To(MatchError(`could not parse delete-unused-products flags: unsupported format "yaml", expected one of table, json`))
+				Expect(fakeService.DeleteAvailableProductsCallCount()).To(Equal(0))
+			})
+		})
 	})
 
 	Describe("Usage", func() {
@@ -63,6 +83,7 @@ var _ = Describe("DeleteProduct", func() {
 			Expect(usage).To(Equal(jhanda.Usage{
 				Description:      "This command deletes unused products in the targeted Ops Manager",
 				ShortDescription: "deletes unused products on the Ops Manager targeted",
+				Flags:            command.Options,
 			}))
 		})
 	})
@@ -12,7 +12,7 @@ type DeployedManifest struct {
 	service deployedManifestService
 	logger  logger
 	Options struct {
-		ProductName string `long:"product-name" short:"p" required:"true" description:"name of product"`
+		ProductName string `long:"product-name" short:"p" required:"true" description:"name of product" complete:"products"`
 	}
 }
 
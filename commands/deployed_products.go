@@ -12,7 +12,7 @@ type DeployedProducts struct {
 	presenter presenters.FormattedPresenter
 	service   deployedProductsService
 	Options   struct {
-		Format string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json)"`
+		Format string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json,yaml,csv)"`
 	}
 }
 
@@ -33,6 +33,10 @@ func (dp DeployedProducts) Execute(args []string) error {
 		return fmt.Errorf("could not parse deployed-products flags: %s", err)
 	}
 
+	if err := presenters.ValidateFormat(dp.Options.Format); err != nil {
+		return fmt.Errorf("could not parse deployed-products flags: %s", err)
+	}
+
 	diagnosticReport, err := dp.service.GetDiagnosticReport()
 	if err != nil {
 		return fmt.Errorf("failed to retrieve deployed products %s", err)
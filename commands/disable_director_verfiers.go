@@ -9,10 +9,12 @@ import (
 )
 
 type DisableDirectorVerifiers struct {
-	service   disableDirectorVerifiersService
-	presenter presenters.FormattedPresenter
-	logger    logger
-	Options   struct {
+	service      disableDirectorVerifiersService
+	presenter    presenters.FormattedPresenter
+	logger       logger
+	strict       bool
+	defaultFlags []string
+	Options      struct {
 		ConfigFile    string   `long:"config"   short:"c"  description:"path to yml file for configuration (keys must match the following command line flags)"`
 		VerifierTypes []string `long:"type" short:"t"  description:"verifier types to disable" required:"true"`
 	}
@@ -24,16 +26,22 @@ type disableDirectorVerifiersService interface {
 	DisableDirectorVerifiers(verifierTypes []string) error
 }
 
-func NewDisableDirectorVerifiers(presenter presenters.FormattedPresenter, service disableDirectorVerifiersService, logger logger) DisableDirectorVerifiers {
+func NewDisableDirectorVerifiers(presenter presenters.FormattedPresenter, service disableDirectorVerifiersService, logger logger, strict bool) DisableDirectorVerifiers {
 	return DisableDirectorVerifiers{
 		service:   service,
 		presenter: presenter,
 		logger:    logger,
+		strict:    strict,
 	}
 }
 
+func (dv DisableDirectorVerifiers) WithDefaultFlags(defaultFlags []string) jhanda.Command {
+	dv.defaultFlags = defaultFlags
+	return dv
+}
+
 func (dv DisableDirectorVerifiers) Execute(args []string) error {
-	err := loadConfigFile(args, &dv.Options, nil)
+	err := loadConfigFile(args, &dv.Options, nil, dv.strict, dv.defaultFlags)
 	if err != nil {
 		return fmt.Errorf("could not parse disable-director-verifiers flags: %s", err)
 	}
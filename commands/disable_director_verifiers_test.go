@@ -31,7 +31,7 @@ var _ = Describe("DisableDirectorVerifiers", func() {
 		service = &fakes.DisableDirectorVerifiersService{}
 		stderr = gbytes.NewBuffer()
 		logger = log.New(stderr, "", 0)
-		command = commands.NewDisableDirectorVerifiers(presenter, service, logger)
+		command = commands.NewDisableDirectorVerifiers(presenter, service, logger, false)
 	})
 
 	When("all provided verifiers exist", func() {
@@ -168,7 +168,7 @@ var _ = Describe("DisableDirectorVerifiers", func() {
 
 	Describe("Usage", func() {
 		It("returns usage information for the command", func() {
-			command := commands.NewDisableDirectorVerifiers(nil, nil, nil)
+			command := commands.NewDisableDirectorVerifiers(nil, nil, nil, false)
 			Expect(command.Usage()).To(Equal(jhanda.Usage{
 				Description:      "This authenticated command disables director verifiers",
 				ShortDescription: "disables director verifiers",
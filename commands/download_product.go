@@ -43,6 +43,8 @@ type DownloadProductOptions struct {
 	OutputDir  string   `long:"output-directory"      short:"o"  description:"directory path to which the file will be outputted. File Name will be preserved from Pivotal Network" required:"true"`
 	VarsEnv    []string `long:"vars-env" env:"OM_VARS_ENV" experimental:"true" description:"load variables from environment variables matching the provided prefix (e.g.: 'MY' to load MY_var=value)"`
 	VarsFile   []string `long:"vars-file" short:"l"              description:"load variables from a YAML file"`
+	VarsSource []string `long:"vars-source" env:"OM_VARS_SOURCE" description:"load variables that are not otherwise provided from a credential store (e.g.: 'vault:address=https://vault.example.com,mount=secret,path=foundation' or 'credhub:address=https://credhub.example.com,path=/foundation')"`
+	VarsStore  string   `long:"vars-store" description:"load variables from a YAML file, the variables defined in the 'variables' section of the config that are missing are generated and saved to it"`
 	Vars       []string `long:"var"                              description:"Load variable from the command line. Format: VAR=VAL"`
 
 	PivnetFileGlob      string `long:"pivnet-file-glob"      short:"f"  description:"glob to match files within Pivotal Network product to be downloaded." required:"true"`
@@ -80,6 +82,8 @@ type DownloadProduct struct {
 	stderr         *log.Logger
 	stdout         *log.Logger
 	downloadClient ProductDownloader
+	strict         bool
+	defaultFlags   []string
 	Options        DownloadProductOptions
 }
 
@@ -88,12 +92,14 @@ func NewDownloadProduct(
 	stdout *log.Logger,
 	stderr *log.Logger,
 	progressWriter io.Writer,
+	strict bool,
 ) *DownloadProduct {
 	return &DownloadProduct{
 		environFunc:    environFunc,
 		stderr:         stderr,
 		stdout:         stdout,
 		progressWriter: progressWriter,
+		strict:         strict,
 	}
 }
 
@@ -105,8 +111,13 @@ func (c DownloadProduct) Usage() jhanda.Usage {
 	}
 }
 
+func (c *DownloadProduct) WithDefaultFlags(defaultFlags []string) jhanda.Command {
+	c.defaultFlags = defaultFlags
+	return c
+}
+
 func (c *DownloadProduct) Execute(args []string) error {
-	err := loadConfigFile(args, &c.Options, c.environFunc)
+	err := loadConfigFile(args, &c.Options, c.environFunc, c.strict, c.defaultFlags)
 	if err != nil {
 		return fmt.Errorf("could not parse download-product flags: %s", err)
 	}
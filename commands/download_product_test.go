@@ -46,7 +46,7 @@ var _ = Describe("DownloadProduct", func() {
 			environFunc,
 			log.New(buffer, "", 0),
 			log.New(buffer, "", 0),
-			buffer,
+			buffer, false,
 		)
 	})
 
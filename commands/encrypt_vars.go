@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/interpolate"
+)
+
+type EncryptVars struct {
+	logger  logger
+	Options struct {
+		VarsFile      string   `long:"vars-file"      short:"l" required:"true" description:"path to the YAML vars file to encrypt"`
+		AgeRecipients []string `long:"age-recipient"  short:"a"                 description:"age public key to encrypt the vars for (e.g.: age1...)"`
+		PGPPublicKeys []string `long:"pgp-public-key"                           description:"path to an armored PGP public key to encrypt the vars for"`
+		InPlace       bool     `long:"in-place"       short:"i"                 description:"replace the vars file with the encrypted vars instead of printing them"`
+	}
+}
+
+func NewEncryptVars(logger logger) *EncryptVars {
+	return &EncryptVars{
+		logger: logger,
+	}
+}
+
+func (e *EncryptVars) Execute(args []string) error {
+	if _, err := jhanda.Parse(&e.Options, args); err != nil {
+		return fmt.Errorf("could not parse encrypt-vars flags: %s", err)
+	}
+
+	if len(e.Options.AgeRecipients) == 0 && len(e.Options.PGPPublicKeys) == 0 {
+		return fmt.Errorf("at least one --age-recipient or --pgp-public-key is required")
+	}
+
+	contents, err := ioutil.ReadFile(e.Options.VarsFile)
+	if err != nil {
+		return fmt.Errorf("could not read vars file (%s): %s", e.Options.VarsFile, err)
+	}
+
+	keys := interpolate.EncryptionKeys{
+		AgeRecipients: e.Options.AgeRecipients,
+	}
+
+	for _, path := range e.Options.PGPPublicKeys {
+		publicKey, err := ioutil.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("could not read PGP public key (%s): %s", path, err)
+		}
+		keys.PGPPublicKeys = append(keys.PGPPublicKeys, publicKey)
+	}
+
+	encrypted, err := interpolate.EncryptVars(contents, keys)
+	if err != nil {
+		return fmt.Errorf("could not encrypt %s: %s", e.Options.VarsFile, err)
+	}
+
+	if !e.Options.InPlace {
+		e.logger.Print(string(encrypted))
+		return nil
+	}
+
+	info, err := os.Stat(e.Options.VarsFile)
+	if err != nil {
+		return fmt.Errorf("could not read vars file (%s): %s", e.Options.VarsFile, err)
+	}
+
+	err = ioutil.WriteFile(e.Options.VarsFile, encrypted, info.Mode())
+	if err != nil {
+		return fmt.Errorf("could not write encrypted vars file (%s): %s", e.Options.VarsFile, err)
+	}
+
+	e.logger.Printf("encrypted %s", e.Options.VarsFile)
+	return nil
+}
+
+func (e *EncryptVars) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This command encrypts the values of a YAML vars file in the format of SOPS for age recipients and/or PGP public keys. Keys that end with '_unencrypted' are not encrypted. Encrypted vars files are decrypted transparently by --vars-file, using the age identities in SOPS_AGE_KEY or SOPS_AGE_KEY_FILE, or the PGP private key in OM_PGP_PRIVATE_KEY or OM_PGP_PRIVATE_KEY_FILE (with OM_PGP_PASSPHRASE).",
+		ShortDescription: "encrypts a vars file for age recipients or PGP keys",
+		Flags:            e.Options,
+	}
+}
@@ -0,0 +1,117 @@
+package commands_test
+
+import (
+	"io/ioutil"
+	"os"
+
+	"filippo.io/age"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+	"github.com/pivotal-cf/om/interpolate"
+)
+
+var _ = Describe("EncryptVars", func() {
+	var (
+		command  *commands.EncryptVars
+		logger   *fakes.Logger
+		identity *age.X25519Identity
+		varsFile string
+	)
+
+	BeforeEach(func() {
+		var err error
+		identity, err = age.GenerateX25519Identity()
+		Expect(err).ToNot(HaveOccurred())
+
+		varsFile = writeTestConfigFile("password: some-password\n")
+		err = os.Chmod(varsFile, 0600)
+		Expect(err).ToNot(HaveOccurred())
+
+		logger = &fakes.Logger{}
+		command = commands.NewEncryptVars(logger)
+	})
+
+	AfterEach(func() {
+		os.Remove(varsFile)
+	})
+
+	It("prints the encrypted vars", func() {
+		err := command.Execute([]string{
+			"--vars-file", varsFile,
+			"--age-recipient", identity.Recipient().String(),
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(logger.PrintCallCount()).To(Equal(1))
+		encrypted := []byte(logger.PrintArgsForCall(0)[0].(string))
+		Expect(interpolate.IsEncrypted(encrypted)).To(BeTrue())
+		Expect(string(encrypted)).ToNot(ContainSubstring("some-password"))
+
+		decrypted, err := interpolate.DecryptVars(encrypted, func() []string {
+			return []string{"SOPS_AGE_KEY=" + identity.String()}
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(decrypted)).To(Equal("password: some-password\n"))
+
+		contents, err := ioutil.ReadFile(varsFile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(contents)).To(Equal("password: some-password\n"))
+	})
+
+	It("replaces the vars file with --in-place", func() {
+		err := command.Execute([]string{
+			"--vars-file", varsFile,
+			"--age-recipient", identity.Recipient().String(),
+			"--in-place",
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		contents, err := ioutil.ReadFile(varsFile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(interpolate.IsEncrypted(contents)).To(BeTrue())
+
+		info, err := os.Stat(varsFile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+		format, args := logger.PrintfArgsForCall(0)
+		Expect(format).To(Equal("encrypted %s"))
+		Expect(args).To(Equal([]interface{}{varsFile}))
+	})
+
+	When("no keys are given", func() {
+		It("returns an error", func() {
+			err := command.Execute([]string{"--vars-file", varsFile})
+			Expect(err).To(MatchError("at least one --age-recipient or --pgp-public-key is required"))
+		})
+	})
+
+	When("the vars file does not exist", func() {
+		It("returns an error", func() {
+			err := command.Execute([]string{
+				"--vars-file", "/does/not/exist.yml",
+				"--age-recipient", identity.Recipient().String(),
+			})
+			Expect(err).To(MatchError(ContainSubstring("could not read vars file (/does/not/exist.yml)")))
+		})
+	})
+
+	When("the age recipient is invalid", func() {
+		It("returns an error", func() {
+			err := command.Execute([]string{
+				"--vars-file", varsFile,
+				"--age-recipient", "not-a-recipient",
+			})
+			Expect(err).To(MatchError(ContainSubstring("could not encrypt " + varsFile + `: invalid age recipient "not-a-recipient"`)))
+		})
+	})
+
+	When("an unknown flag is provided", func() {
+		It("returns an error", func() {
+			err := command.Execute([]string{"--badflag"})
+			Expect(err).To(MatchError("could not parse encrypt-vars flags: flag provided but not defined: -badflag"))
+		})
+	})
+})
@@ -20,8 +20,8 @@ type Errands struct {
 	presenter presenters.FormattedPresenter
 	service   errandsService
 	Options   struct {
-		ProductName string `long:"product-name" short:"p" required:"true" description:"name of product"`
-		Format      string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json)"`
+		ProductName string `long:"product-name" short:"p" required:"true" description:"name of product" complete:"products"`
+		Format      string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json,yaml,csv)"`
 	}
 }
 
@@ -37,6 +37,10 @@ func (e Errands) Execute(args []string) error {
 		return fmt.Errorf("could not parse errands flags: %s", err)
 	}
 
+	if err := presenters.ValidateFormat(e.Options.Format); err != nil {
+		return fmt.Errorf("could not parse errands flags: %s", err)
+	}
+
 	findOutput, err := e.service.GetStagedProductByName(e.Options.ProductName)
 	if err != nil {
 		return fmt.Errorf("failed to find staged product %q: %s", e.Options.ProductName, err)
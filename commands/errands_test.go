@@ -83,6 +83,13 @@ var _ = Describe("Errands", func() {
 		})
 
 		Context("failure cases", func() {
+			When("the format is not supported", func() {
+				It("returns an error", func() {
+					err := command.Execute([]string{"--product-name", "some-product-name", "--format", "xml"})
+					Expect(err).To(MatchError(`could not parse errands flags: unsupported format "xml", expected one of table, json, yaml, csv`))
+				})
+			})
+
 			When("an unknown flag is passed", func() {
 				It("returns an error", func() {
 					err := command.Execute([]string{"--unknown-flag"})
@@ -6,7 +6,9 @@ import (
 	"github.com/fatih/color"
 	"github.com/pivotal-cf/jhanda"
 	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/presenters"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 )
@@ -21,9 +23,28 @@ type ExpiringCerts struct {
 	api     expiringCertsService
 	Options struct {
 		ExpiresWithin string `long:"expires-within"  short:"e"  description:"timeframe in which to check expiration. Default: \"3m\".\n\t\t\t\tdays(d), weeks(w), months(m) and years(y) supported."`
+		Format        string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json)"`
 	}
 }
 
+// expiringCertificatesOutput is the document printed with --format json.
+type expiringCertificatesOutput struct {
+	ExpiresWithin string                      `json:"expires_within"`
+	Certificates  []expiringCertificateOutput `json:"certificates"`
+}
+
+type expiringCertificateOutput struct {
+	ProductGUID       string    `json:"product_guid"`
+	PropertyReference string    `json:"property_reference"`
+	VariablePath      string    `json:"variable_path"`
+	Location          string    `json:"location"`
+	Issuer            string    `json:"issuer"`
+	Configurable      bool      `json:"configurable"`
+	ValidFrom         time.Time `json:"valid_from"`
+	ValidUntil        time.Time `json:"valid_until"`
+	Expired           bool      `json:"expired"`
+}
+
 func NewExpiringCertificates(service expiringCertsService, logger logger) *ExpiringCerts {
 	return &ExpiringCerts{
 		api:    service,
@@ -44,6 +65,10 @@ func (e *ExpiringCerts) Execute(args []string) error {
 		return fmt.Errorf("could not parse expiring-certificates flags: %s", err)
 	}
 
+	if err := presenters.ValidateFormat(e.Options.Format, tableFormat, jsonFormat); err != nil {
+		return fmt.Errorf("could not parse expiring-certificates flags: %s", err)
+	}
+
 	if e.Options.ExpiresWithin == "" {
 		e.Options.ExpiresWithin = "3m"
 	}
@@ -53,12 +78,28 @@ func (e *ExpiringCerts) Execute(args []string) error {
 		return err
 	}
 
-	e.logger.Println("Getting expiring certificates...")
+	if e.Options.Format == tableFormat {
+		e.logger.Println("Getting expiring certificates...")
+	}
+
 	expiringCerts, err := e.api.ListExpiringCertificates(e.Options.ExpiresWithin)
 	if err != nil {
 		return fmt.Errorf("could not fetch expiring certificates: %s", err)
 	}
 
+	if e.Options.Format == jsonFormat {
+		err = printJSON(e.logger, e.jsonOutput(expiringCerts))
+		if err != nil {
+			return err
+		}
+
+		if len(expiringCerts) > 0 {
+			return errors.New("found expiring certificates in the foundation")
+		}
+
+		return nil
+	}
+
 	if len(expiringCerts) == 0 {
 		e.logger.Printf(color.GreenString("[✓] No certificates are expiring in %s\n"), e.Options.ExpiresWithin)
 		return nil
@@ -88,6 +129,41 @@ func (e *ExpiringCerts) Execute(args []string) error {
 	return errors.New("found expiring certificates in the foundation")
 }
 
+func (e *ExpiringCerts) jsonOutput(certs []api.ExpiringCertificate) expiringCertificatesOutput {
+	output := expiringCertificatesOutput{
+		ExpiresWithin: e.Options.ExpiresWithin,
+		Certificates:  []expiringCertificateOutput{},
+	}
+
+	now := time.Now()
+	for _, cert := range certs {
+		output.Certificates = append(output.Certificates, expiringCertificateOutput{
+			ProductGUID:       cert.ProductGUID,
+			PropertyReference: cert.PropertyReference,
+			VariablePath:      cert.VariablePath,
+			Location:          cert.Location,
+			Issuer:            cert.Issuer,
+			Configurable:      cert.Configurable,
+			ValidFrom:         cert.ValidFrom,
+			ValidUntil:        cert.ValidUntil,
+			Expired:           now.After(cert.ValidUntil),
+		})
+	}
+
+	sort.SliceStable(output.Certificates, func(i, j int) bool {
+		a, b := output.Certificates[i], output.Certificates[j]
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+		if a.ProductGUID != b.ProductGUID {
+			return a.ProductGUID < b.ProductGUID
+		}
+		return a.PropertyReference+a.VariablePath < b.PropertyReference+b.VariablePath
+	})
+
+	return output
+}
+
 func (e *ExpiringCerts) groupByLocation(certs []api.ExpiringCertificate) (map[string][]api.ExpiringCertificate, map[string]map[string][]api.ExpiringCertificate) {
 	expiringCertsWithVariablePath := make(map[string][]api.ExpiringCertificate)
 	expiringCertsWithProductGUID := make(map[string]map[string][]api.ExpiringCertificate)
@@ -125,7 +201,11 @@ func (e *ExpiringCerts) printExpiringCertInfo(cert api.ExpiringCertificate) {
 }
 
 func (e ExpiringCerts) validateConfig() error {
-	matched, err := regexp.MatchString("^[1-9]+\\d*[dwmy]$", e.Options.ExpiresWithin)
+	return validateExpiresWithin(e.Options.ExpiresWithin)
+}
+
+func validateExpiresWithin(expiresWithin string) error {
+	matched, err := regexp.MatchString("^[1-9]+\\d*[dwmy]$", expiresWithin)
 	if err != nil {
 		return err
 	}
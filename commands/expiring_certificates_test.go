@@ -217,6 +217,76 @@ var _ = Describe("ExpiringCertificates", func() {
 		})
 	})
 
+	When("the format is json", func() {
+		It("prints the certificates without any other output", func() {
+			validUntil, err := time.Parse(time.RFC3339, "2999-01-01T01:01:01Z")
+			Expect(err).ToNot(HaveOccurred())
+			expiredUntil, err := time.Parse(time.RFC3339, "2015-12-12T12:12:12Z")
+			Expect(err).ToNot(HaveOccurred())
+
+			service.ListExpiringCertificatesReturns([]api.ExpiringCertificate{
+				{
+					ValidUntil:        validUntil,
+					PropertyReference: ".properties.networking_poe_ssl_certs",
+					ProductGUID:       "cf-guid",
+					Location:          "ops_manager",
+				},
+				{
+					Issuer:       "/opsmgr/bosh_dns/ca",
+					ValidUntil:   expiredUntil,
+					Location:     "credhub",
+					VariablePath: "/opsmgr/bosh_dns/tls_ca",
+				},
+			}, nil)
+
+			command := commands.NewExpiringCertificates(service, logger)
+			err = command.Execute([]string{"--format", "json", "--expires-within", "1y"})
+			Expect(err).To(MatchError("found expiring certificates in the foundation"))
+
+			Expect(stdout.Contents()).To(MatchJSON(`{
+				"expires_within": "1y",
+				"certificates": [{
+					"product_guid": "",
+					"property_reference": "",
+					"variable_path": "/opsmgr/bosh_dns/tls_ca",
+					"location": "credhub",
+					"issuer": "/opsmgr/bosh_dns/ca",
+					"configurable": false,
+					"valid_from": "0001-01-01T00:00:00Z",
+					"valid_until": "2015-12-12T12:12:12Z",
+					"expired": true
+				}, {
+					"product_guid": "cf-guid",
+					"property_reference": ".properties.networking_poe_ssl_certs",
+					"variable_path": "",
+					"location": "ops_manager",
+					"issuer": "",
+					"configurable": false,
+					"valid_from": "0001-01-01T00:00:00Z",
+					"valid_until": "2999-01-01T01:01:01Z",
+					"expired": false
+				}]
+			}`))
+		})
+
+		It("prints an empty list when no certificates are expiring", func() {
+			command := commands.NewExpiringCertificates(service, logger)
+			err := command.Execute([]string{"--format", "json"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(stdout.Contents()).To(MatchJSON(`{"expires_within": "3m", "certificates": []}`))
+		})
+	})
+
+	When("the format is not supported", func() {
+		It("returns an error", func() {
+			command := commands.NewExpiringCertificates(service, logger)
+			err := command.Execute([]string{"--format", "yaml"})
+			Expect(err).To(MatchError(`could not parse expiring-certificates flags: unsupported format "yaml", expected one of table, json`))
+			Expect(service.ListExpiringCertificatesCallCount()).To(Equal(0))
+		})
+	})
+
 	When("certs cannot be fetched", func() {
 		It("returns an error", func() {
 			service.ListExpiringCertificatesReturns(nil, errors.New("an api error"))
@@ -0,0 +1,324 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"github.com/pivotal-cf/om/api"
+)
+
+type CertificateInventoryService struct {
+	GetSSLCertificateStub        func() (api.SSLCertificateOutput, error)
+	getSSLCertificateMutex       sync.RWMutex
+	getSSLCertificateArgsForCall []struct {
+	}
+	getSSLCertificateReturns struct {
+		result1 api.SSLCertificateOutput
+		result2 error
+	}
+	getSSLCertificateReturnsOnCall map[int]struct {
+		result1 api.SSLCertificateOutput
+		result2 error
+	}
+	GetSecurityRootCACertificateStub        func() (string, error)
+	getSecurityRootCACertificateMutex       sync.RWMutex
+	getSecurityRootCACertificateArgsForCall []struct {
+	}
+	getSecurityRootCACertificateReturns struct {
+		result1 string
+		result2 error
+	}
+	getSecurityRootCACertificateReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	ListCertificateAuthoritiesStub        func() (api.CertificateAuthoritiesOutput, error)
+	listCertificateAuthoritiesMutex       sync.RWMutex
+	listCertificateAuthoritiesArgsForCall []struct {
+	}
+	listCertificateAuthoritiesReturns struct {
+		result1 api.CertificateAuthoritiesOutput
+		result2 error
+	}
+	listCertificateAuthoritiesReturnsOnCall map[int]struct {
+		result1 api.CertificateAuthoritiesOutput
+		result2 error
+	}
+	ListExpiringCertificatesStub        func(string) ([]api.ExpiringCertificate, error)
+	listExpiringCertificatesMutex       sync.RWMutex
+	listExpiringCertificatesArgsForCall []struct {
+		arg1 string
+	}
+	listExpiringCertificatesReturns struct {
+		result1 []api.ExpiringCertificate
+		result2 error
+	}
+	listExpiringCertificatesReturnsOnCall map[int]struct {
+		result1 []api.ExpiringCertificate
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *CertificateInventoryService) GetSSLCertificate() (api.SSLCertificateOutput, error) {
+	fake.getSSLCertificateMutex.Lock()
+	ret, specificReturn := fake.getSSLCertificateReturnsOnCall[len(fake.getSSLCertificateArgsForCall)]
+	fake.getSSLCertificateArgsForCall = append(fake.getSSLCertificateArgsForCall, struct {
+	}{})
+	stub := fake.GetSSLCertificateStub
+	fakeReturns := fake.getSSLCertificateReturns
+	fake.recordInvocation("GetSSLCertificate", []interface{}{})
+	fake.getSSLCertificateMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CertificateInventoryService) GetSSLCertificateCallCount() int {
+	fake.getSSLCertificateMutex.RLock()
+	defer fake.getSSLCertificateMutex.RUnlock()
+	return len(fake.getSSLCertificateArgsForCall)
+}
+
+func (fake *CertificateInventoryService) GetSSLCertificateCalls(stub func() (api.SSLCertificateOutput, error)) {
+	fake.getSSLCertificateMutex.Lock()
+	defer fake.getSSLCertificateMutex.Unlock()
+	fake.GetSSLCertificateStub = stub
+}
+
+func (fake *CertificateInventoryService) GetSSLCertificateReturns(result1 api.SSLCertificateOutput, result2 error) {
+	fake.getSSLCertificateMutex.Lock()
+	defer fake.getSSLCertificateMutex.Unlock()
+	fake.GetSSLCertificateStub = nil
+	fake.getSSLCertificateReturns = struct {
+		result1 api.SSLCertificateOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CertificateInventoryService) GetSSLCertificateReturnsOnCall(i int, result1 api.SSLCertificateOutput, result2 error) {
+	fake.getSSLCertificateMutex.Lock()
+	defer fake.getSSLCertificateMutex.Unlock()
+	fake.GetSSLCertificateStub = nil
+	if fake.getSSLCertificateReturnsOnCall == nil {
+		fake.getSSLCertificateReturnsOnCall = make(map[int]struct {
+			result1 api.SSLCertificateOutput
+			result2 error
+		})
+	}
+	fake.getSSLCertificateReturnsOnCall[i] = struct {
+		result1 api.SSLCertificateOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CertificateInventoryService) GetSecurityRootCACertificate() (string, error) {
+	fake.getSecurityRootCACertificateMutex.Lock()
+	ret, specificReturn := fake.getSecurityRootCACertificateReturnsOnCall[len(fake.getSecurityRootCACertificateArgsForCall)]
+	fake.getSecurityRootCACertificateArgsForCall = append(fake.getSecurityRootCACertificateArgsForCall, struct {
+	}{})
+	stub := fake.GetSecurityRootCACertificateStub
+	fakeReturns := fake.getSecurityRootCACertificateReturns
+	fake.recordInvocation("GetSecurityRootCACertificate", []interface{}{})
+	fake.getSecurityRootCACertificateMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CertificateInventoryService) GetSecurityRootCACertificateCallCount() int {
+	fake.getSecurityRootCACertificateMutex.RLock()
+	defer fake.getSecurityRootCACertificateMutex.RUnlock()
+	return len(fake.getSecurityRootCACertificateArgsForCall)
+}
+
+func (fake *CertificateInventoryService) GetSecurityRootCACertificateCalls(stub func() (string, error)) {
+	fake.getSecurityRootCACertificateMutex.Lock()
+	defer fake.getSecurityRootCACertificateMutex.Unlock()
+	fake.GetSecurityRootCACertificateStub = stub
+}
+
+func (fake *CertificateInventoryService) GetSecurityRootCACertificateReturns(result1 string, result2 error) {
+	fake.getSecurityRootCACertificateMutex.Lock()
+	defer fake.getSecurityRootCACertificateMutex.Unlock()
+	fake.GetSecurityRootCACertificateStub = nil
+	fake.getSecurityRootCACertificateReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CertificateInventoryService) GetSecurityRootCACertificateReturnsOnCall(i int, result1 string, result2 error) {
+	fake.getSecurityRootCACertificateMutex.Lock()
+	defer fake.getSecurityRootCACertificateMutex.Unlock()
+	fake.GetSecurityRootCACertificateStub = nil
+	if fake.getSecurityRootCACertificateReturnsOnCall == nil {
+		fake.getSecurityRootCACertificateReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.getSecurityRootCACertificateReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CertificateInventoryService) ListCertificateAuthorities() (api.CertificateAuthoritiesOutput, error) {
+	fake.listCertificateAuthoritiesMutex.Lock()
+	ret, specificReturn := fake.listCertificateAuthoritiesReturnsOnCall[len(fake.listCertificateAuthoritiesArgsForCall)]
+	fake.listCertificateAuthoritiesArgsForCall = append(fake.listCertificateAuthoritiesArgsForCall, struct {
+	}{})
+	stub := fake.ListCertificateAuthoritiesStub
+	fakeReturns := fake.listCertificateAuthoritiesReturns
+	fake.recordInvocation("ListCertificateAuthorities", []interface{}{})
+	fake.listCertificateAuthoritiesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CertificateInventoryService) ListCertificateAuthoritiesCallCount() int {
+	fake.listCertificateAuthoritiesMutex.RLock()
+	defer fake.listCertificateAuthoritiesMutex.RUnlock()
+	return len(fake.listCertificateAuthoritiesArgsForCall)
+}
+
+func (fake *CertificateInventoryService) ListCertificateAuthoritiesCalls(stub func() (api.CertificateAuthoritiesOutput, error)) {
+	fake.listCertificateAuthoritiesMutex.Lock()
+	defer fake.listCertificateAuthoritiesMutex.Unlock()
+	fake.ListCertificateAuthoritiesStub = stub
+}
+
+func (fake *CertificateInventoryService) ListCertificateAuthoritiesReturns(result1 api.CertificateAuthoritiesOutput, result2 error) {
+	fake.listCertificateAuthoritiesMutex.Lock()
+	defer fake.listCertificateAuthoritiesMutex.Unlock()
+	fake.ListCertificateAuthoritiesStub = nil
+	fake.listCertificateAuthoritiesReturns = struct {
+		result1 api.CertificateAuthoritiesOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CertificateInventoryService) ListCertificateAuthoritiesReturnsOnCall(i int, result1 api.CertificateAuthoritiesOutput, result2 error) {
+	fake.listCertificateAuthoritiesMutex.Lock()
+	defer fake.listCertificateAuthoritiesMutex.Unlock()
+	fake.ListCertificateAuthoritiesStub = nil
+	if fake.listCertificateAuthoritiesReturnsOnCall == nil {
+		fake.listCertificateAuthoritiesReturnsOnCall = make(map[int]struct {
+			result1 api.CertificateAuthoritiesOutput
+			result2 error
+		})
+	}
+	fake.listCertificateAuthoritiesReturnsOnCall[i] = struct {
+		result1 api.CertificateAuthoritiesOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CertificateInventoryService) ListExpiringCertificates(arg1 string) ([]api.ExpiringCertificate, error) {
+	fake.listExpiringCertificatesMutex.Lock()
+	ret, specificReturn := fake.listExpiringCertificatesReturnsOnCall[len(fake.listExpiringCertificatesArgsForCall)]
+	fake.listExpiringCertificatesArgsForCall = append(fake.listExpiringCertificatesArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ListExpiringCertificatesStub
+	fakeReturns := fake.listExpiringCertificatesReturns
+	fake.recordInvocation("ListExpiringCertificates", []interface{}{arg1})
+	fake.listExpiringCertificatesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CertificateInventoryService) ListExpiringCertificatesCallCount() int {
+	fake.listExpiringCertificatesMutex.RLock()
+	defer fake.listExpiringCertificatesMutex.RUnlock()
+	return len(fake.listExpiringCertificatesArgsForCall)
+}
+
+func (fake *CertificateInventoryService) ListExpiringCertificatesCalls(stub func(string) ([]api.ExpiringCertificate, error)) {
+	fake.listExpiringCertificatesMutex.Lock()
+	defer fake.listExpiringCertificatesMutex.Unlock()
+	fake.ListExpiringCertificatesStub = stub
+}
+
+func (fake *CertificateInventoryService) ListExpiringCertificatesArgsForCall(i int) string {
+	fake.listExpiringCertificatesMutex.RLock()
+	defer fake.listExpiringCertificatesMutex.RUnlock()
+	argsForCall := fake.listExpiringCertificatesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *CertificateInventoryService) ListExpiringCertificatesReturns(result1 []api.ExpiringCertificate, result2 error) {
+	fake.listExpiringCertificatesMutex.Lock()
+	defer fake.listExpiringCertificatesMutex.Unlock()
+	fake.ListExpiringCertificatesStub = nil
+	fake.listExpiringCertificatesReturns = struct {
+		result1 []api.ExpiringCertificate
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CertificateInventoryService) ListExpiringCertificatesReturnsOnCall(i int, result1 []api.ExpiringCertificate, result2 error) {
+	fake.listExpiringCertificatesMutex.Lock()
+	defer fake.listExpiringCertificatesMutex.Unlock()
+	fake.ListExpiringCertificatesStub = nil
+	if fake.listExpiringCertificatesReturnsOnCall == nil {
+		fake.listExpiringCertificatesReturnsOnCall = make(map[int]struct {
+			result1 []api.ExpiringCertificate
+			result2 error
+		})
+	}
+	fake.listExpiringCertificatesReturnsOnCall[i] = struct {
+		result1 []api.ExpiringCertificate
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CertificateInventoryService) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getSSLCertificateMutex.RLock()
+	defer fake.getSSLCertificateMutex.RUnlock()
+	fake.getSecurityRootCACertificateMutex.RLock()
+	defer fake.getSecurityRootCACertificateMutex.RUnlock()
+	fake.listCertificateAuthoritiesMutex.RLock()
+	defer fake.listCertificateAuthoritiesMutex.RUnlock()
+	fake.listExpiringCertificatesMutex.RLock()
+	defer fake.listExpiringCertificatesMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *CertificateInventoryService) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
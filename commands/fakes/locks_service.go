@@ -0,0 +1,179 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"github.com/pivotal-cf/om/models"
+)
+
+type LocksService struct {
+	BreakStub        func(string) error
+	breakMutex       sync.RWMutex
+	breakArgsForCall []struct {
+		arg1 string
+	}
+	breakReturns struct {
+		result1 error
+	}
+	breakReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ListStub        func() ([]models.Lock, error)
+	listMutex       sync.RWMutex
+	listArgsForCall []struct {
+	}
+	listReturns struct {
+		result1 []models.Lock
+		result2 error
+	}
+	listReturnsOnCall map[int]struct {
+		result1 []models.Lock
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *LocksService) Break(arg1 string) error {
+	fake.breakMutex.Lock()
+	ret, specificReturn := fake.breakReturnsOnCall[len(fake.breakArgsForCall)]
+	fake.breakArgsForCall = append(fake.breakArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.BreakStub
+	fakeReturns := fake.breakReturns
+	fake.recordInvocation("Break", []interface{}{arg1})
+	fake.breakMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *LocksService) BreakCallCount() int {
+	fake.breakMutex.RLock()
+	defer fake.breakMutex.RUnlock()
+	return len(fake.breakArgsForCall)
+}
+
+func (fake *LocksService) BreakCalls(stub func(string) error) {
+	fake.breakMutex.Lock()
+	defer fake.breakMutex.Unlock()
+	fake.BreakStub = stub
+}
+
+func (fake *LocksService) BreakArgsForCall(i int) string {
+	fake.breakMutex.RLock()
+	defer fake.breakMutex.RUnlock()
+	argsForCall := fake.breakArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *LocksService) BreakReturns(result1 error) {
+	fake.breakMutex.Lock()
+	defer fake.breakMutex.Unlock()
+	fake.BreakStub = nil
+	fake.breakReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *LocksService) BreakReturnsOnCall(i int, result1 error) {
+	fake.breakMutex.Lock()
+	defer fake.breakMutex.Unlock()
+	fake.BreakStub = nil
+	if fake.breakReturnsOnCall == nil {
+		fake.breakReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.breakReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *LocksService) List() ([]models.Lock, error) {
+	fake.listMutex.Lock()
+	ret, specificReturn := fake.listReturnsOnCall[len(fake.listArgsForCall)]
+	fake.listArgsForCall = append(fake.listArgsForCall, struct {
+	}{})
+	stub := fake.ListStub
+	fakeReturns := fake.listReturns
+	fake.recordInvocation("List", []interface{}{})
+	fake.listMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *LocksService) ListCallCount() int {
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	return len(fake.listArgsForCall)
+}
+
+func (fake *LocksService) ListCalls(stub func() ([]models.Lock, error)) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = stub
+}
+
+func (fake *LocksService) ListReturns(result1 []models.Lock, result2 error) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = nil
+	fake.listReturns = struct {
+		result1 []models.Lock
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *LocksService) ListReturnsOnCall(i int, result1 []models.Lock, result2 error) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = nil
+	if fake.listReturnsOnCall == nil {
+		fake.listReturnsOnCall = make(map[int]struct {
+			result1 []models.Lock
+			result2 error
+		})
+	}
+	fake.listReturnsOnCall[i] = struct {
+		result1 []models.Lock
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *LocksService) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.breakMutex.RLock()
+	defer fake.breakMutex.RUnlock()
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *LocksService) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -0,0 +1,394 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"github.com/pivotal-cf/om/api"
+)
+
+type MetricsService struct {
+	GetDiagnosticReportStub        func() (api.DiagnosticReport, error)
+	getDiagnosticReportMutex       sync.RWMutex
+	getDiagnosticReportArgsForCall []struct {
+	}
+	getDiagnosticReportReturns struct {
+		result1 api.DiagnosticReport
+		result2 error
+	}
+	getDiagnosticReportReturnsOnCall map[int]struct {
+		result1 api.DiagnosticReport
+		result2 error
+	}
+	InfoStub        func() (api.Info, error)
+	infoMutex       sync.RWMutex
+	infoArgsForCall []struct {
+	}
+	infoReturns struct {
+		result1 api.Info
+		result2 error
+	}
+	infoReturnsOnCall map[int]struct {
+		result1 api.Info
+		result2 error
+	}
+	ListExpiringCertificatesStub        func(string) ([]api.ExpiringCertificate, error)
+	listExpiringCertificatesMutex       sync.RWMutex
+	listExpiringCertificatesArgsForCall []struct {
+		arg1 string
+	}
+	listExpiringCertificatesReturns struct {
+		result1 []api.ExpiringCertificate
+		result2 error
+	}
+	listExpiringCertificatesReturnsOnCall map[int]struct {
+		result1 []api.ExpiringCertificate
+		result2 error
+	}
+	ListInstallationsStub        func() ([]api.InstallationsServiceOutput, error)
+	listInstallationsMutex       sync.RWMutex
+	listInstallationsArgsForCall []struct {
+	}
+	listInstallationsReturns struct {
+		result1 []api.InstallationsServiceOutput
+		result2 error
+	}
+	listInstallationsReturnsOnCall map[int]struct {
+		result1 []api.InstallationsServiceOutput
+		result2 error
+	}
+	ListStagedPendingChangesStub        func() (api.PendingChangesOutput, error)
+	listStagedPendingChangesMutex       sync.RWMutex
+	listStagedPendingChangesArgsForCall []struct {
+	}
+	listStagedPendingChangesReturns struct {
+		result1 api.PendingChangesOutput
+		result2 error
+	}
+	listStagedPendingChangesReturnsOnCall map[int]struct {
+		result1 api.PendingChangesOutput
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *MetricsService) GetDiagnosticReport() (api.DiagnosticReport, error) {
+	fake.getDiagnosticReportMutex.Lock()
+	ret, specificReturn := fake.getDiagnosticReportReturnsOnCall[len(fake.getDiagnosticReportArgsForCall)]
+	fake.getDiagnosticReportArgsForCall = append(fake.getDiagnosticReportArgsForCall, struct {
+	}{})
+	stub := fake.GetDiagnosticReportStub
+	fakeReturns := fake.getDiagnosticReportReturns
+	fake.recordInvocation("GetDiagnosticReport", []interface{}{})
+	fake.getDiagnosticReportMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *MetricsService) GetDiagnosticReportCallCount() int {
+	fake.getDiagnosticReportMutex.RLock()
+	defer fake.getDiagnosticReportMutex.RUnlock()
+	return len(fake.getDiagnosticReportArgsForCall)
+}
+
+func (fake *MetricsService) GetDiagnosticReportCalls(stub func() (api.DiagnosticReport, error)) {
+	fake.getDiagnosticReportMutex.Lock()
+	defer fake.getDiagnosticReportMutex.Unlock()
+	fake.GetDiagnosticReportStub = stub
+}
+
+func (fake *MetricsService) GetDiagnosticReportReturns(result1 api.DiagnosticReport, result2 error) {
+	fake.getDiagnosticReportMutex.Lock()
+	defer fake.getDiagnosticReportMutex.Unlock()
+	fake.GetDiagnosticReportStub = nil
+	fake.getDiagnosticReportReturns = struct {
+		result1 api.DiagnosticReport
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *MetricsService) GetDiagnosticReportReturnsOnCall(i int, result1 api.DiagnosticReport, result2 error) {
+	fake.getDiagnosticReportMutex.Lock()
+	defer fake.getDiagnosticReportMutex.Unlock()
+	fake.GetDiagnosticReportStub = nil
+	if fake.getDiagnosticReportReturnsOnCall == nil {
+		fake.getDiagnosticReportReturnsOnCall = make(map[int]struct {
+			result1 api.DiagnosticReport
+			result2 error
+		})
+	}
+	fake.getDiagnosticReportReturnsOnCall[i] = struct {
+		result1 api.DiagnosticReport
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *MetricsService) Info() (api.Info, error) {
+	fake.infoMutex.Lock()
+	ret, specificReturn := fake.infoReturnsOnCall[len(fake.infoArgsForCall)]
+	fake.infoArgsForCall = append(fake.infoArgsForCall, struct {
+	}{})
+	stub := fake.InfoStub
+	fakeReturns := fake.infoReturns
+	fake.recordInvocation("Info", []interface{}{})
+	fake.infoMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *MetricsService) InfoCallCount() int {
+	fake.infoMutex.RLock()
+	defer fake.infoMutex.RUnlock()
+	return len(fake.infoArgsForCall)
+}
+
+func (fake *MetricsService) InfoCalls(stub func() (api.Info, error)) {
+	fake.infoMutex.Lock()
+	defer fake.infoMutex.Unlock()
+	fake.InfoStub = stub
+}
+
+func (fake *MetricsService) InfoReturns(result1 api.Info, result2 error) {
+	fake.infoMutex.Lock()
+	defer fake.infoMutex.Unlock()
+	fake.InfoStub = nil
+	fake.infoReturns = struct {
+		result1 api.Info
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *MetricsService) InfoReturnsOnCall(i int, result1 api.Info, result2 error) {
+	fake.infoMutex.Lock()
+	defer fake.infoMutex.Unlock()
+	fake.InfoStub = nil
+	if fake.infoReturnsOnCall == nil {
+		fake.infoReturnsOnCall = make(map[int]struct {
+			result1 api.Info
+			result2 error
+		})
+	}
+	fake.infoReturnsOnCall[i] = struct {
+		result1 api.Info
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *MetricsService) ListExpiringCertificates(arg1 string) ([]api.ExpiringCertificate, error) {
+	fake.listExpiringCertificatesMutex.Lock()
+	ret, specificReturn := fake.listExpiringCertificatesReturnsOnCall[len(fake.listExpiringCertificatesArgsForCall)]
+	fake.listExpiringCertificatesArgsForCall = append(fake.listExpiringCertificatesArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ListExpiringCertificatesStub
+	fakeReturns := fake.listExpiringCertificatesReturns
+	fake.recordInvocation("ListExpiringCertificates", []interface{}{arg1})
+	fake.listExpiringCertificatesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *MetricsService) ListExpiringCertificatesCallCount() int {
+	fake.listExpiringCertificatesMutex.RLock()
+	defer fake.listExpiringCertificatesMutex.RUnlock()
+	return len(fake.listExpiringCertificatesArgsForCall)
+}
+
+func (fake *MetricsService) ListExpiringCertificatesCalls(stub func(string) ([]api.ExpiringCertificate, error)) {
+	fake.listExpiringCertificatesMutex.Lock()
+	defer fake.listExpiringCertificatesMutex.Unlock()
+	fake.ListExpiringCertificatesStub = stub
+}
+
+func (fake *MetricsService) ListExpiringCertificatesArgsForCall(i int) string {
+	fake.listExpiringCertificatesMutex.RLock()
+	defer fake.listExpiringCertificatesMutex.RUnlock()
+	argsForCall := fake.listExpiringCertificatesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *MetricsService) ListExpiringCertificatesReturns(result1 []api.ExpiringCertificate, result2 error) {
+	fake.listExpiringCertificatesMutex.Lock()
+	defer fake.listExpiringCertificatesMutex.Unlock()
+	fake.ListExpiringCertificatesStub = nil
+	fake.listExpiringCertificatesReturns = struct {
+		result1 []api.ExpiringCertificate
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *MetricsService) ListExpiringCertificatesReturnsOnCall(i int, result1 []api.ExpiringCertificate, result2 error) {
+	fake.listExpiringCertificatesMutex.Lock()
+	defer fake.listExpiringCertificatesMutex.Unlock()
+	fake.ListExpiringCertificatesStub = nil
+	if fake.listExpiringCertificatesReturnsOnCall == nil {
+		fake.listExpiringCertificatesReturnsOnCall = make(map[int]struct {
+			result1 []api.ExpiringCertificate
+			result2 error
+		})
+	}
+	fake.listExpiringCertificatesReturnsOnCall[i] = struct {
+		result1 []api.ExpiringCertificate
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *MetricsService) ListInstallations() ([]api.InstallationsServiceOutput, error) {
+	fake.listInstallationsMutex.Lock()
+	ret, specificReturn := fake.listInstallationsReturnsOnCall[len(fake.listInstallationsArgsForCall)]
+	fake.listInstallationsArgsForCall = append(fake.listInstallationsArgsForCall, struct {
+	}{})
+	stub := fake.ListInstallationsStub
+	fakeReturns := fake.listInstallationsReturns
+	fake.recordInvocation("ListInstallations", []interface{}{})
+	fake.listInstallationsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *MetricsService) ListInstallationsCallCount() int {
+	fake.listInstallationsMutex.RLock()
+	defer fake.listInstallationsMutex.RUnlock()
+	return len(fake.listInstallationsArgsForCall)
+}
+
+func (fake *MetricsService) ListInstallationsCalls(stub func() ([]api.InstallationsServiceOutput, error)) {
+	fake.listInstallationsMutex.Lock()
+	defer fake.listInstallationsMutex.Unlock()
+	fake.ListInstallationsStub = stub
+}
+
+func (fake *MetricsService) ListInstallationsReturns(result1 []api.InstallationsServiceOutput, result2 error) {
+	fake.listInstallationsMutex.Lock()
+	defer fake.listInstallationsMutex.Unlock()
+	fake.ListInstallationsStub = nil
+	fake.listInstallationsReturns = struct {
+		result1 []api.InstallationsServiceOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *MetricsService) ListInstallationsReturnsOnCall(i int, result1 []api.InstallationsServiceOutput, result2 error) {
+	fake.listInstallationsMutex.Lock()
+	defer fake.listInstallationsMutex.Unlock()
+	fake.ListInstallationsStub = nil
+	if fake.listInstallationsReturnsOnCall == nil {
+		fake.listInstallationsReturnsOnCall = make(map[int]struct {
+			result1 []api.InstallationsServiceOutput
+			result2 error
+		})
+	}
+	fake.listInstallationsReturnsOnCall[i] = struct {
+		result1 []api.InstallationsServiceOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *MetricsService) ListStagedPendingChanges() (api.PendingChangesOutput, error) {
+	fake.listStagedPendingChangesMutex.Lock()
+	ret, specificReturn := fake.listStagedPendingChangesReturnsOnCall[len(fake.listStagedPendingChangesArgsForCall)]
+	fake.listStagedPendingChangesArgsForCall = append(fake.listStagedPendingChangesArgsForCall, struct {
+	}{})
+	stub := fake.ListStagedPendingChangesStub
+	fakeReturns := fake.listStagedPendingChangesReturns
+	fake.recordInvocation("ListStagedPendingChanges", []interface{}{})
+	fake.listStagedPendingChangesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *MetricsService) ListStagedPendingChangesCallCount() int {
+	fake.listStagedPendingChangesMutex.RLock()
+	defer fake.listStagedPendingChangesMutex.RUnlock()
+	return len(fake.listStagedPendingChangesArgsForCall)
+}
+
+func (fake *MetricsService) ListStagedPendingChangesCalls(stub func() (api.PendingChangesOutput, error)) {
+	fake.listStagedPendingChangesMutex.Lock()
+	defer fake.listStagedPendingChangesMutex.Unlock()
+	fake.ListStagedPendingChangesStub = stub
+}
+
+func (fake *MetricsService) ListStagedPendingChangesReturns(result1 api.PendingChangesOutput, result2 error) {
+	fake.listStagedPendingChangesMutex.Lock()
+	defer fake.listStagedPendingChangesMutex.Unlock()
+	fake.ListStagedPendingChangesStub = nil
+	fake.listStagedPendingChangesReturns = struct {
+		result1 api.PendingChangesOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *MetricsService) ListStagedPendingChangesReturnsOnCall(i int, result1 api.PendingChangesOutput, result2 error) {
+	fake.listStagedPendingChangesMutex.Lock()
+	defer fake.listStagedPendingChangesMutex.Unlock()
+	fake.ListStagedPendingChangesStub = nil
+	if fake.listStagedPendingChangesReturnsOnCall == nil {
+		fake.listStagedPendingChangesReturnsOnCall = make(map[int]struct {
+			result1 api.PendingChangesOutput
+			result2 error
+		})
+	}
+	fake.listStagedPendingChangesReturnsOnCall[i] = struct {
+		result1 api.PendingChangesOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *MetricsService) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getDiagnosticReportMutex.RLock()
+	defer fake.getDiagnosticReportMutex.RUnlock()
+	fake.infoMutex.RLock()
+	defer fake.infoMutex.RUnlock()
+	fake.listExpiringCertificatesMutex.RLock()
+	defer fake.listExpiringCertificatesMutex.RUnlock()
+	fake.listInstallationsMutex.RLock()
+	defer fake.listInstallationsMutex.RUnlock()
+	fake.listStagedPendingChangesMutex.RLock()
+	defer fake.listStagedPendingChangesMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *MetricsService) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
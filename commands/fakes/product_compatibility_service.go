@@ -0,0 +1,315 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"github.com/pivotal-cf/om/api"
+)
+
+type ProductCompatibilityService struct {
+	InfoStub        func() (api.Info, error)
+	infoMutex       sync.RWMutex
+	infoArgsForCall []struct {
+	}
+	infoReturns struct {
+		result1 api.Info
+		result2 error
+	}
+	infoReturnsOnCall map[int]struct {
+		result1 api.Info
+		result2 error
+	}
+	ListDeployedProductsStub        func() ([]api.DeployedProductOutput, error)
+	listDeployedProductsMutex       sync.RWMutex
+	listDeployedProductsArgsForCall []struct {
+	}
+	listDeployedProductsReturns struct {
+		result1 []api.DeployedProductOutput
+		result2 error
+	}
+	listDeployedProductsReturnsOnCall map[int]struct {
+		result1 []api.DeployedProductOutput
+		result2 error
+	}
+	ListMultiStemcellsStub        func() (api.ProductMultiStemcells, error)
+	listMultiStemcellsMutex       sync.RWMutex
+	listMultiStemcellsArgsForCall []struct {
+	}
+	listMultiStemcellsReturns struct {
+		result1 api.ProductMultiStemcells
+		result2 error
+	}
+	listMultiStemcellsReturnsOnCall map[int]struct {
+		result1 api.ProductMultiStemcells
+		result2 error
+	}
+	ListStemcellsStub        func() (api.ProductStemcells, error)
+	listStemcellsMutex       sync.RWMutex
+	listStemcellsArgsForCall []struct {
+	}
+	listStemcellsReturns struct {
+		result1 api.ProductStemcells
+		result2 error
+	}
+	listStemcellsReturnsOnCall map[int]struct {
+		result1 api.ProductStemcells
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *ProductCompatibilityService) Info() (api.Info, error) {
+	fake.infoMutex.Lock()
+	ret, specificReturn := fake.infoReturnsOnCall[len(fake.infoArgsForCall)]
+	fake.infoArgsForCall = append(fake.infoArgsForCall, struct {
+	}{})
+	stub := fake.InfoStub
+	fakeReturns := fake.infoReturns
+	fake.recordInvocation("Info", []interface{}{})
+	fake.infoMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ProductCompatibilityService) InfoCallCount() int {
+	fake.infoMutex.RLock()
+	defer fake.infoMutex.RUnlock()
+	return len(fake.infoArgsForCall)
+}
+
+func (fake *ProductCompatibilityService) InfoCalls(stub func() (api.Info, error)) {
+	fake.infoMutex.Lock()
+	defer fake.infoMutex.Unlock()
+	fake.InfoStub = stub
+}
+
+func (fake *ProductCompatibilityService) InfoReturns(result1 api.Info, result2 error) {
+	fake.infoMutex.Lock()
+	defer fake.infoMutex.Unlock()
+	fake.InfoStub = nil
+	fake.infoReturns = struct {
+		result1 api.Info
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ProductCompatibilityService) InfoReturnsOnCall(i int, result1 api.Info, result2 error) {
+	fake.infoMutex.Lock()
+	defer fake.infoMutex.Unlock()
+	fake.InfoStub = nil
+	if fake.infoReturnsOnCall == nil {
+		fake.infoReturnsOnCall = make(map[int]struct {
+			result1 api.Info
+			result2 error
+		})
+	}
+	fake.infoReturnsOnCall[i] = struct {
+		result1 api.Info
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ProductCompatibilityService) ListDeployedProducts() ([]api.DeployedProductOutput, error) {
+	fake.listDeployedProductsMutex.Lock()
+	ret, specificReturn := fake.listDeployedProductsReturnsOnCall[len(fake.listDeployedProductsArgsForCall)]
+	fake.listDeployedProductsArgsForCall = append(fake.listDeployedProductsArgsForCall, struct {
+	}{})
+	stub := fake.ListDeployedProductsStub
+	fakeReturns := fake.listDeployedProductsReturns
+	fake.recordInvocation("ListDeployedProducts", []interface{}{})
+	fake.listDeployedProductsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ProductCompatibilityService) ListDeployedProductsCallCount() int {
+	fake.listDeployedProductsMutex.RLock()
+	defer fake.listDeployedProductsMutex.RUnlock()
+	return len(fake.listDeployedProductsArgsForCall)
+}
+
+func (fake *ProductCompatibilityService) ListDeployedProductsCalls(stub func() ([]api.DeployedProductOutput, error)) {
+	fake.listDeployedProductsMutex.Lock()
+	defer fake.listDeployedProductsMutex.Unlock()
+	fake.ListDeployedProductsStub = stub
+}
+
+func (fake *ProductCompatibilityService) ListDeployedProductsReturns(result1 []api.DeployedProductOutput, result2 error) {
+	fake.listDeployedProductsMutex.Lock()
+	defer fake.listDeployedProductsMutex.Unlock()
+	fake.ListDeployedProductsStub = nil
+	fake.listDeployedProductsReturns = struct {
+		result1 []api.DeployedProductOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ProductCompatibilityService) ListDeployedProductsReturnsOnCall(i int, result1 []api.DeployedProductOutput, result2 error) {
+	fake.listDeployedProductsMutex.Lock()
+	defer fake.listDeployedProductsMutex.Unlock()
+	fake.ListDeployedProductsStub = nil
+	if fake.listDeployedProductsReturnsOnCall == nil {
+		fake.listDeployedProductsReturnsOnCall = make(map[int]struct {
+			result1 []api.DeployedProductOutput
+			result2 error
+		})
+	}
+	fake.listDeployedProductsReturnsOnCall[i] = struct {
+		result1 []api.DeployedProductOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ProductCompatibilityService) ListMultiStemcells() (api.ProductMultiStemcells, error) {
+	fake.listMultiStemcellsMutex.Lock()
+	ret, specificReturn := fake.listMultiStemcellsReturnsOnCall[len(fake.listMultiStemcellsArgsForCall)]
+	fake.listMultiStemcellsArgsForCall = append(fake.listMultiStemcellsArgsForCall, struct {
+	}{})
+	stub := fake.ListMultiStemcellsStub
+	fakeReturns := fake.listMultiStemcellsReturns
+	fake.recordInvocation("ListMultiStemcells", []interface{}{})
+	fake.listMultiStemcellsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ProductCompatibilityService) ListMultiStemcellsCallCount() int {
+	fake.listMultiStemcellsMutex.RLock()
+	defer fake.listMultiStemcellsMutex.RUnlock()
+	return len(fake.listMultiStemcellsArgsForCall)
+}
+
+func (fake *ProductCompatibilityService) ListMultiStemcellsCalls(stub func() (api.ProductMultiStemcells, error)) {
+	fake.listMultiStemcellsMutex.Lock()
+	defer fake.listMultiStemcellsMutex.Unlock()
+	fake.ListMultiStemcellsStub = stub
+}
+
+func (fake *ProductCompatibilityService) ListMultiStemcellsReturns(result1 api.ProductMultiStemcells, result2 error) {
+	fake.listMultiStemcellsMutex.Lock()
+	defer fake.listMultiStemcellsMutex.Unlock()
+	fake.ListMultiStemcellsStub = nil
+	fake.listMultiStemcellsReturns = struct {
+		result1 api.ProductMultiStemcells
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ProductCompatibilityService) ListMultiStemcellsReturnsOnCall(i int, result1 api.ProductMultiStemcells, result2 error) {
+	fake.listMultiStemcellsMutex.Lock()
+	defer fake.listMultiStemcellsMutex.Unlock()
+	fake.ListMultiStemcellsStub = nil
+	if fake.listMultiStemcellsReturnsOnCall == nil {
+		fake.listMultiStemcellsReturnsOnCall = make(map[int]struct {
+			result1 api.ProductMultiStemcells
+			result2 error
+		})
+	}
+	fake.listMultiStemcellsReturnsOnCall[i] = struct {
+		result1 api.ProductMultiStemcells
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ProductCompatibilityService) ListStemcells() (api.ProductStemcells, error) {
+	fake.listStemcellsMutex.Lock()
+	ret, specificReturn := fake.listStemcellsReturnsOnCall[len(fake.listStemcellsArgsForCall)]
+	fake.listStemcellsArgsForCall = append(fake.listStemcellsArgsForCall, struct {
+	}{})
+	stub := fake.ListStemcellsStub
+	fakeReturns := fake.listStemcellsReturns
+	fake.recordInvocation("ListStemcells", []interface{}{})
+	fake.listStemcellsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ProductCompatibilityService) ListStemcellsCallCount() int {
+	fake.listStemcellsMutex.RLock()
+	defer fake.listStemcellsMutex.RUnlock()
+	return len(fake.listStemcellsArgsForCall)
+}
+
+func (fake *ProductCompatibilityService) ListStemcellsCalls(stub func() (api.ProductStemcells, error)) {
+	fake.listStemcellsMutex.Lock()
+	defer fake.listStemcellsMutex.Unlock()
+	fake.ListStemcellsStub = stub
+}
+
+func (fake *ProductCompatibilityService) ListStemcellsReturns(result1 api.ProductStemcells, result2 error) {
+	fake.listStemcellsMutex.Lock()
+	defer fake.listStemcellsMutex.Unlock()
+	fake.ListStemcellsStub = nil
+	fake.listStemcellsReturns = struct {
+		result1 api.ProductStemcells
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ProductCompatibilityService) ListStemcellsReturnsOnCall(i int, result1 api.ProductStemcells, result2 error) {
+	fake.listStemcellsMutex.Lock()
+	defer fake.listStemcellsMutex.Unlock()
+	fake.ListStemcellsStub = nil
+	if fake.listStemcellsReturnsOnCall == nil {
+		fake.listStemcellsReturnsOnCall = make(map[int]struct {
+			result1 api.ProductStemcells
+			result2 error
+		})
+	}
+	fake.listStemcellsReturnsOnCall[i] = struct {
+		result1 api.ProductStemcells
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ProductCompatibilityService) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.infoMutex.RLock()
+	defer fake.infoMutex.RUnlock()
+	fake.listDeployedProductsMutex.RLock()
+	defer fake.listDeployedProductsMutex.RUnlock()
+	fake.listMultiStemcellsMutex.RLock()
+	defer fake.listMultiStemcellsMutex.RUnlock()
+	fake.listStemcellsMutex.RLock()
+	defer fake.listStemcellsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *ProductCompatibilityService) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
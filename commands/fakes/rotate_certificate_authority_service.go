@@ -0,0 +1,920 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"github.com/pivotal-cf/om/api"
+)
+
+type RotateCertificateAuthorityService struct {
+	ActivateCertificateAuthorityStub        func(api.ActivateCertificateAuthorityInput) error
+	activateCertificateAuthorityMutex       sync.RWMutex
+	activateCertificateAuthorityArgsForCall []struct {
+		arg1 api.ActivateCertificateAuthorityInput
+	}
+	activateCertificateAuthorityReturns struct {
+		result1 error
+	}
+	activateCertificateAuthorityReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreateInstallationStub        func(bool, bool, []string, api.ApplyErrandChanges) (api.InstallationsServiceOutput, error)
+	createInstallationMutex       sync.RWMutex
+	createInstallationArgsForCall []struct {
+		arg1 bool
+		arg2 bool
+		arg3 []string
+		arg4 api.ApplyErrandChanges
+	}
+	createInstallationReturns struct {
+		result1 api.InstallationsServiceOutput
+		result2 error
+	}
+	createInstallationReturnsOnCall map[int]struct {
+		result1 api.InstallationsServiceOutput
+		result2 error
+	}
+	DeleteCertificateAuthorityStub        func(api.DeleteCertificateAuthorityInput) error
+	deleteCertificateAuthorityMutex       sync.RWMutex
+	deleteCertificateAuthorityArgsForCall []struct {
+		arg1 api.DeleteCertificateAuthorityInput
+	}
+	deleteCertificateAuthorityReturns struct {
+		result1 error
+	}
+	deleteCertificateAuthorityReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GenerateCertificateAuthorityStub        func() (api.CA, error)
+	generateCertificateAuthorityMutex       sync.RWMutex
+	generateCertificateAuthorityArgsForCall []struct {
+	}
+	generateCertificateAuthorityReturns struct {
+		result1 api.CA
+		result2 error
+	}
+	generateCertificateAuthorityReturnsOnCall map[int]struct {
+		result1 api.CA
+		result2 error
+	}
+	GetInstallationStub        func(int) (api.InstallationsServiceOutput, error)
+	getInstallationMutex       sync.RWMutex
+	getInstallationArgsForCall []struct {
+		arg1 int
+	}
+	getInstallationReturns struct {
+		result1 api.InstallationsServiceOutput
+		result2 error
+	}
+	getInstallationReturnsOnCall map[int]struct {
+		result1 api.InstallationsServiceOutput
+		result2 error
+	}
+	GetInstallationLogsStub        func(int) (api.InstallationsServiceOutput, error)
+	getInstallationLogsMutex       sync.RWMutex
+	getInstallationLogsArgsForCall []struct {
+		arg1 int
+	}
+	getInstallationLogsReturns struct {
+		result1 api.InstallationsServiceOutput
+		result2 error
+	}
+	getInstallationLogsReturnsOnCall map[int]struct {
+		result1 api.InstallationsServiceOutput
+		result2 error
+	}
+	InfoStub        func() (api.Info, error)
+	infoMutex       sync.RWMutex
+	infoArgsForCall []struct {
+	}
+	infoReturns struct {
+		result1 api.Info
+		result2 error
+	}
+	infoReturnsOnCall map[int]struct {
+		result1 api.Info
+		result2 error
+	}
+	ListCertificateAuthoritiesStub        func() (api.CertificateAuthoritiesOutput, error)
+	listCertificateAuthoritiesMutex       sync.RWMutex
+	listCertificateAuthoritiesArgsForCall []struct {
+	}
+	listCertificateAuthoritiesReturns struct {
+		result1 api.CertificateAuthoritiesOutput
+		result2 error
+	}
+	listCertificateAuthoritiesReturnsOnCall map[int]struct {
+		result1 api.CertificateAuthoritiesOutput
+		result2 error
+	}
+	ListInstallationsStub        func() ([]api.InstallationsServiceOutput, error)
+	listInstallationsMutex       sync.RWMutex
+	listInstallationsArgsForCall []struct {
+	}
+	listInstallationsReturns struct {
+		result1 []api.InstallationsServiceOutput
+		result2 error
+	}
+	listInstallationsReturnsOnCall map[int]struct {
+		result1 []api.InstallationsServiceOutput
+		result2 error
+	}
+	RegenerateCertificatesStub        func() error
+	regenerateCertificatesMutex       sync.RWMutex
+	regenerateCertificatesArgsForCall []struct {
+	}
+	regenerateCertificatesReturns struct {
+		result1 error
+	}
+	regenerateCertificatesReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RunningInstallationStub        func() (api.InstallationsServiceOutput, error)
+	runningInstallationMutex       sync.RWMutex
+	runningInstallationArgsForCall []struct {
+	}
+	runningInstallationReturns struct {
+		result1 api.InstallationsServiceOutput
+		result2 error
+	}
+	runningInstallationReturnsOnCall map[int]struct {
+		result1 api.InstallationsServiceOutput
+		result2 error
+	}
+	UpdateStagedDirectorPropertiesStub        func(api.DirectorProperties) error
+	updateStagedDirectorPropertiesMutex       sync.RWMutex
+	updateStagedDirectorPropertiesArgsForCall []struct {
+		arg1 api.DirectorProperties
+	}
+	updateStagedDirectorPropertiesReturns struct {
+		result1 error
+	}
+	updateStagedDirectorPropertiesReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *RotateCertificateAuthorityService) ActivateCertificateAuthority(arg1 api.ActivateCertificateAuthorityInput) error {
+	fake.activateCertificateAuthorityMutex.Lock()
+	ret, specificReturn := fake.activateCertificateAuthorityReturnsOnCall[len(fake.activateCertificateAuthorityArgsForCall)]
+	fake.activateCertificateAuthorityArgsForCall = append(fake.activateCertificateAuthorityArgsForCall, struct {
+		arg1 api.ActivateCertificateAuthorityInput
+	}{arg1})
+	stub := fake.ActivateCertificateAuthorityStub
+	fakeReturns := fake.activateCertificateAuthorityReturns
+	fake.recordInvocation("ActivateCertificateAuthority", []interface{}{arg1})
+	fake.activateCertificateAuthorityMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *RotateCertificateAuthorityService) ActivateCertificateAuthorityCallCount() int {
+	fake.activateCertificateAuthorityMutex.RLock()
+	defer fake.activateCertificateAuthorityMutex.RUnlock()
+	return len(fake.activateCertificateAuthorityArgsForCall)
+}
+
+func (fake *RotateCertificateAuthorityService) ActivateCertificateAuthorityCalls(stub func(api.ActivateCertificateAuthorityInput) error) {
+	fake.activateCertificateAuthorityMutex.Lock()
+	defer fake.activateCertificateAuthorityMutex.Unlock()
+	fake.ActivateCertificateAuthorityStub = stub
+}
+
+func (fake *RotateCertificateAuthorityService) ActivateCertificateAuthorityArgsForCall(i int) api.ActivateCertificateAuthorityInput {
+	fake.activateCertificateAuthorityMutex.RLock()
+	defer fake.activateCertificateAuthorityMutex.RUnlock()
+	argsForCall := fake.activateCertificateAuthorityArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *RotateCertificateAuthorityService) ActivateCertificateAuthorityReturns(result1 error) {
+	fake.activateCertificateAuthorityMutex.Lock()
+	defer fake.activateCertificateAuthorityMutex.Unlock()
+	fake.ActivateCertificateAuthorityStub = nil
+	fake.activateCertificateAuthorityReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *RotateCertificateAuthorityService) ActivateCertificateAuthorityReturnsOnCall(i int, result1 error) {
+	fake.activateCertificateAuthorityMutex.Lock()
+	defer fake.activateCertificateAuthorityMutex.Unlock()
+	fake.ActivateCertificateAuthorityStub = nil
+	if fake.activateCertificateAuthorityReturnsOnCall == nil {
+		fake.activateCertificateAuthorityReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.activateCertificateAuthorityReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *RotateCertificateAuthorityService) CreateInstallation(arg1 bool, arg2 bool, arg3 []string, arg4 api.ApplyErrandChanges) (api.InstallationsServiceOutput, error) {
+	var arg3Copy []string
+	if arg3 != nil {
+		arg3Copy = make([]string, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.createInstallationMutex.Lock()
+	ret, specificReturn := fake.createInstallationReturnsOnCall[len(fake.createInstallationArgsForCall)]
+	fake.createInstallationArgsForCall = append(fake.createInstallationArgsForCall, struct {
+		arg1 bool
+		arg2 bool
+		arg3 []string
+		arg4 api.ApplyErrandChanges
+	}{arg1, arg2, arg3Copy, arg4})
+	stub := fake.CreateInstallationStub
+	fakeReturns := fake.createInstallationReturns
+	fake.recordInvocation("CreateInstallation", []interface{}{arg1, arg2, arg3Copy, arg4})
+	fake.createInstallationMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *RotateCertificateAuthorityService) CreateInstallationCallCount() int {
+	fake.createInstallationMutex.RLock()
+	defer fake.createInstallationMutex.RUnlock()
+	return len(fake.createInstallationArgsForCall)
+}
+
+func (fake *RotateCertificateAuthorityService) CreateInstallationCalls(stub func(bool, bool, []string, api.ApplyErrandChanges) (api.InstallationsServiceOutput, error)) {
+	fake.createInstallationMutex.Lock()
+	defer fake.createInstallationMutex.Unlock()
+	fake.CreateInstallationStub = stub
+}
+
+func (fake *RotateCertificateAuthorityService) CreateInstallationArgsForCall(i int) (bool, bool, []string, api.ApplyErrandChanges) {
+	fake.createInstallationMutex.RLock()
+	defer fake.createInstallationMutex.RUnlock()
+	argsForCall := fake.createInstallationArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *RotateCertificateAuthorityService) CreateInstallationReturns(result1 api.InstallationsServiceOutput, result2 error) {
+	fake.createInstallationMutex.Lock()
+	defer fake.createInstallationMutex.Unlock()
+	fake.CreateInstallationStub = nil
+	fake.createInstallationReturns = struct {
+		result1 api.InstallationsServiceOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RotateCertificateAuthorityService) CreateInstallationReturnsOnCall(i int, result1 api.InstallationsServiceOutput, result2 error) {
+	fake.createInstallationMutex.Lock()
+	defer fake.createInstallationMutex.Unlock()
+	fake.CreateInstallationStub = nil
+	if fake.createInstallationReturnsOnCall == nil {
+		fake.createInstallationReturnsOnCall = make(map[int]struct {
+			result1 api.InstallationsServiceOutput
+			result2 error
+		})
+	}
+	fake.createInstallationReturnsOnCall[i] = struct {
+		result1 api.InstallationsServiceOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RotateCertificateAuthorityService) DeleteCertificateAuthority(arg1 api.DeleteCertificateAuthorityInput) error {
+	fake.deleteCertificateAuthorityMutex.Lock()
+	ret, specificReturn := fake.deleteCertificateAuthorityReturnsOnCall[len(fake.deleteCertificateAuthorityArgsForCall)]
+	fake.deleteCertificateAuthorityArgsForCall = append(fake.deleteCertificateAuthorityArgsForCall, struct {
+		arg1 api.DeleteCertificateAuthorityInput
+	}{arg1})
+	stub := fake.DeleteCertificateAuthorityStub
+	fakeReturns := fake.deleteCertificateAuthorityReturns
+	fake.recordInvocation("DeleteCertificateAuthority", []interface{}{arg1})
+	fake.deleteCertificateAuthorityMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *RotateCertificateAuthorityService) DeleteCertificateAuthorityCallCount() int {
+	fake.deleteCertificateAuthorityMutex.RLock()
+	defer fake.deleteCertificateAuthorityMutex.RUnlock()
+	return len(fake.deleteCertificateAuthorityArgsForCall)
+}
+
+func (fake *RotateCertificateAuthorityService) DeleteCertificateAuthorityCalls(stub func(api.DeleteCertificateAuthorityInput) error) {
+	fake.deleteCertificateAuthorityMutex.Lock()
+	defer fake.deleteCertificateAuthorityMutex.Unlock()
+	fake.DeleteCertificateAuthorityStub = stub
+}
+
+func (fake *RotateCertificateAuthorityService) DeleteCertificateAuthorityArgsForCall(i int) api.DeleteCertificateAuthorityInput {
+	fake.deleteCertificateAuthorityMutex.RLock()
+	defer fake.deleteCertificateAuthorityMutex.RUnlock()
+	argsForCall := fake.deleteCertificateAuthorityArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *RotateCertificateAuthorityService) DeleteCertificateAuthorityReturns(result1 error) {
+	fake.deleteCertificateAuthorityMutex.Lock()
+	defer fake.deleteCertificateAuthorityMutex.Unlock()
+	fake.DeleteCertificateAuthorityStub = nil
+	fake.deleteCertificateAuthorityReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *RotateCertificateAuthorityService) DeleteCertificateAuthorityReturnsOnCall(i int, result1 error) {
+	fake.deleteCertificateAuthorityMutex.Lock()
+	defer fake.deleteCertificateAuthorityMutex.Unlock()
+	fake.DeleteCertificateAuthorityStub = nil
+	if fake.deleteCertificateAuthorityReturnsOnCall == nil {
+		fake.deleteCertificateAuthorityReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteCertificateAuthorityReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *RotateCertificateAuthorityService) GenerateCertificateAuthority() (api.CA, error) {
+	fake.generateCertificateAuthorityMutex.Lock()
+	ret, specificReturn := fake.generateCertificateAuthorityReturnsOnCall[len(fake.generateCertificateAuthorityArgsForCall)]
+	fake.generateCertificateAuthorityArgsForCall = append(fake.generateCertificateAuthorityArgsForCall, struct {
+	}{})
+	stub := fake.GenerateCertificateAuthorityStub
+	fakeReturns := fake.generateCertificateAuthorityReturns
+	fake.recordInvocation("GenerateCertificateAuthority", []interface{}{})
+	fake.generateCertificateAuthorityMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *RotateCertificateAuthorityService) GenerateCertificateAuthorityCallCount() int {
+	fake.generateCertificateAuthorityMutex.RLock()
+	defer fake.generateCertificateAuthorityMutex.RUnlock()
+	return len(fake.generateCertificateAuthorityArgsForCall)
+}
+
+func (fake *RotateCertificateAuthorityService) GenerateCertificateAuthorityCalls(stub func() (api.CA, error)) {
+	fake.generateCertificateAuthorityMutex.Lock()
+	defer fake.generateCertificateAuthorityMutex.Unlock()
+	fake.GenerateCertificateAuthorityStub = stub
+}
+
+func (fake *RotateCertificateAuthorityService) GenerateCertificateAuthorityReturns(result1 api.CA, result2 error) {
+	fake.generateCertificateAuthorityMutex.Lock()
+	defer fake.generateCertificateAuthorityMutex.Unlock()
+	fake.GenerateCertificateAuthorityStub = nil
+	fake.generateCertificateAuthorityReturns = struct {
+		result1 api.CA
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RotateCertificateAuthorityService) GenerateCertificateAuthorityReturnsOnCall(i int, result1 api.CA, result2 error) {
+	fake.generateCertificateAuthorityMutex.Lock()
+	defer fake.generateCertificateAuthorityMutex.Unlock()
+	fake.GenerateCertificateAuthorityStub = nil
+	if fake.generateCertificateAuthorityReturnsOnCall == nil {
+		fake.generateCertificateAuthorityReturnsOnCall = make(map[int]struct {
+			result1 api.CA
+			result2 error
+		})
+	}
+	fake.generateCertificateAuthorityReturnsOnCall[i] = struct {
+		result1 api.CA
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RotateCertificateAuthorityService) GetInstallation(arg1 int) (api.InstallationsServiceOutput, error) {
+	fake.getInstallationMutex.Lock()
+	ret, specificReturn := fake.getInstallationReturnsOnCall[len(fake.getInstallationArgsForCall)]
+	fake.getInstallationArgsForCall = append(fake.getInstallationArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.GetInstallationStub
+	fakeReturns := fake.getInstallationReturns
+	fake.recordInvocation("GetInstallation", []interface{}{arg1})
+	fake.getInstallationMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *RotateCertificateAuthorityService) GetInstallationCallCount() int {
+	fake.getInstallationMutex.RLock()
+	defer fake.getInstallationMutex.RUnlock()
+	return len(fake.getInstallationArgsForCall)
+}
+
+func (fake *RotateCertificateAuthorityService) GetInstallationCalls(stub func(int) (api.InstallationsServiceOutput, error)) {
+	fake.getInstallationMutex.Lock()
+	defer fake.getInstallationMutex.Unlock()
+	fake.GetInstallationStub = stub
+}
+
+func (fake *RotateCertificateAuthorityService) GetInstallationArgsForCall(i int) int {
+	fake.getInstallationMutex.RLock()
+	defer fake.getInstallationMutex.RUnlock()
+	argsForCall := fake.getInstallationArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *RotateCertificateAuthorityService) GetInstallationReturns(result1 api.InstallationsServiceOutput, result2 error) {
+	fake.getInstallationMutex.Lock()
+	defer fake.getInstallationMutex.Unlock()
+	fake.GetInstallationStub = nil
+	fake.getInstallationReturns = struct {
+		result1 api.InstallationsServiceOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RotateCertificateAuthorityService) GetInstallationReturnsOnCall(i int, result1 api.InstallationsServiceOutput, result2 error) {
+	fake.getInstallationMutex.Lock()
+	defer fake.getInstallationMutex.Unlock()
+	fake.GetInstallationStub = nil
+	if fake.getInstallationReturnsOnCall == nil {
+		fake.getInstallationReturnsOnCall = make(map[int]struct {
+			result1 api.InstallationsServiceOutput
+			result2 error
+		})
+	}
+	fake.getInstallationReturnsOnCall[i] = struct {
+		result1 api.InstallationsServiceOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RotateCertificateAuthorityService) GetInstallationLogs(arg1 int) (api.InstallationsServiceOutput, error) {
+	fake.getInstallationLogsMutex.Lock()
+	ret, specificReturn := fake.getInstallationLogsReturnsOnCall[len(fake.getInstallationLogsArgsForCall)]
+	fake.getInstallationLogsArgsForCall = append(fake.getInstallationLogsArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.GetInstallationLogsStub
+	fakeReturns := fake.getInstallationLogsReturns
+	fake.recordInvocation("GetInstallationLogs", []interface{}{arg1})
+	fake.getInstallationLogsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *RotateCertificateAuthorityService) GetInstallationLogsCallCount() int {
+	fake.getInstallationLogsMutex.RLock()
+	defer fake.getInstallationLogsMutex.RUnlock()
+	return len(fake.getInstallationLogsArgsForCall)
+}
+
+func (fake *RotateCertificateAuthorityService) GetInstallationLogsCalls(stub func(int) (api.InstallationsServiceOutput, error)) {
+	fake.getInstallationLogsMutex.Lock()
+	defer fake.getInstallationLogsMutex.Unlock()
+	fake.GetInstallationLogsStub = stub
+}
+
+func (fake *RotateCertificateAuthorityService) GetInstallationLogsArgsForCall(i int) int {
+	fake.getInstallationLogsMutex.RLock()
+	defer fake.getInstallationLogsMutex.RUnlock()
+	argsForCall := fake.getInstallationLogsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *RotateCertificateAuthorityService) GetInstallationLogsReturns(result1 api.InstallationsServiceOutput, result2 error) {
+	fake.getInstallationLogsMutex.Lock()
+	defer fake.getInstallationLogsMutex.Unlock()
+	fake.GetInstallationLogsStub = nil
+	fake.getInstallationLogsReturns = struct {
+		result1 api.InstallationsServiceOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RotateCertificateAuthorityService) GetInstallationLogsReturnsOnCall(i int, result1 api.InstallationsServiceOutput, result2 error) {
+	fake.getInstallationLogsMutex.Lock()
+	defer fake.getInstallationLogsMutex.Unlock()
+	fake.GetInstallationLogsStub = nil
+	if fake.getInstallationLogsReturnsOnCall == nil {
+		fake.getInstallationLogsReturnsOnCall = make(map[int]struct {
+			result1 api.InstallationsServiceOutput
+			result2 error
+		})
+	}
+	fake.getInstallationLogsReturnsOnCall[i] = struct {
+		result1 api.InstallationsServiceOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RotateCertificateAuthorityService) Info() (api.Info, error) {
+	fake.infoMutex.Lock()
+	ret, specificReturn := fake.infoReturnsOnCall[len(fake.infoArgsForCall)]
+	fake.infoArgsForCall = append(fake.infoArgsForCall, struct {
+	}{})
+	stub := fake.InfoStub
+	fakeReturns := fake.infoReturns
+	fake.recordInvocation("Info", []interface{}{})
+	fake.infoMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *RotateCertificateAuthorityService) InfoCallCount() int {
+	fake.infoMutex.RLock()
+	defer fake.infoMutex.RUnlock()
+	return len(fake.infoArgsForCall)
+}
+
+func (fake *RotateCertificateAuthorityService) InfoCalls(stub func() (api.Info, error)) {
+	fake.infoMutex.Lock()
+	defer fake.infoMutex.Unlock()
+	fake.InfoStub = stub
+}
+
+func (fake *RotateCertificateAuthorityService) InfoReturns(result1 api.Info, result2 error) {
+	fake.infoMutex.Lock()
+	defer fake.infoMutex.Unlock()
+	fake.InfoStub = nil
+	fake.infoReturns = struct {
+		result1 api.Info
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RotateCertificateAuthorityService) InfoReturnsOnCall(i int, result1 api.Info, result2 error) {
+	fake.infoMutex.Lock()
+	defer fake.infoMutex.Unlock()
+	fake.InfoStub = nil
+	if fake.infoReturnsOnCall == nil {
+		fake.infoReturnsOnCall = make(map[int]struct {
+			result1 api.Info
+			result2 error
+		})
+	}
+	fake.infoReturnsOnCall[i] = struct {
+		result1 api.Info
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RotateCertificateAuthorityService) ListCertificateAuthorities() (api.CertificateAuthoritiesOutput, error) {
+	fake.listCertificateAuthoritiesMutex.Lock()
+	ret, specificReturn := fake.listCertificateAuthoritiesReturnsOnCall[len(fake.listCertificateAuthoritiesArgsForCall)]
+	fake.listCertificateAuthoritiesArgsForCall = append(fake.listCertificateAuthoritiesArgsForCall, struct {
+	}{})
+	stub := fake.ListCertificateAuthoritiesStub
+	fakeReturns := fake.listCertificateAuthoritiesReturns
+	fake.recordInvocation("ListCertificateAuthorities", []interface{}{})
+	fake.listCertificateAuthoritiesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *RotateCertificateAuthorityService) ListCertificateAuthoritiesCallCount() int {
+	fake.listCertificateAuthoritiesMutex.RLock()
+	defer fake.listCertificateAuthoritiesMutex.RUnlock()
+	return len(fake.listCertificateAuthoritiesArgsForCall)
+}
+
+func (fake *RotateCertificateAuthorityService) ListCertificateAuthoritiesCalls(stub func() (api.CertificateAuthoritiesOutput, error)) {
+	fake.listCertificateAuthoritiesMutex.Lock()
+	defer fake.listCertificateAuthoritiesMutex.Unlock()
+	fake.ListCertificateAuthoritiesStub = stub
+}
+
+func (fake *RotateCertificateAuthorityService) ListCertificateAuthoritiesReturns(result1 api.CertificateAuthoritiesOutput, result2 error) {
+	fake.listCertificateAuthoritiesMutex.Lock()
+	defer fake.listCertificateAuthoritiesMutex.Unlock()
+	fake.ListCertificateAuthoritiesStub = nil
+	fake.listCertificateAuthoritiesReturns = struct {
+		result1 api.CertificateAuthoritiesOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RotateCertificateAuthorityService) ListCertificateAuthoritiesReturnsOnCall(i int, result1 api.CertificateAuthoritiesOutput, result2 error) {
+	fake.listCertificateAuthoritiesMutex.Lock()
+	defer fake.listCertificateAuthoritiesMutex.Unlock()
+	fake.ListCertificateAuthoritiesStub = nil
+	if fake.listCertificateAuthoritiesReturnsOnCall == nil {
+		fake.listCertificateAuthoritiesReturnsOnCall = make(map[int]struct {
+			result1 api.CertificateAuthoritiesOutput
+			result2 error
+		})
+	}
+	fake.listCertificateAuthoritiesReturnsOnCall[i] = struct {
+		result1 api.CertificateAuthoritiesOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RotateCertificateAuthorityService) ListInstallations() ([]api.InstallationsServiceOutput, error) {
+	fake.listInstallationsMutex.Lock()
+	ret, specificReturn := fake.listInstallationsReturnsOnCall[len(fake.listInstallationsArgsForCall)]
+	fake.listInstallationsArgsForCall = append(fake.listInstallationsArgsForCall, struct {
+	}{})
+	stub := fake.ListInstallationsStub
+	fakeReturns := fake.listInstallationsReturns
+	fake.recordInvocation("ListInstallations", []interface{}{})
+	fake.listInstallationsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *RotateCertificateAuthorityService) ListInstallationsCallCount() int {
+	fake.listInstallationsMutex.RLock()
+	defer fake.listInstallationsMutex.RUnlock()
+	return len(fake.listInstallationsArgsForCall)
+}
+
+func (fake *RotateCertificateAuthorityService) ListInstallationsCalls(stub func() ([]api.InstallationsServiceOutput, error)) {
+	fake.listInstallationsMutex.Lock()
+	defer fake.listInstallationsMutex.Unlock()
+	fake.ListInstallationsStub = stub
+}
+
+func (fake *RotateCertificateAuthorityService) ListInstallationsReturns(result1 []api.InstallationsServiceOutput, result2 error) {
+	fake.listInstallationsMutex.Lock()
+	defer fake.listInstallationsMutex.Unlock()
+	fake.ListInstallationsStub = nil
+	fake.listInstallationsReturns = struct {
+		result1 []api.InstallationsServiceOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RotateCertificateAuthorityService) ListInstallationsReturnsOnCall(i int, result1 []api.InstallationsServiceOutput, result2 error) {
+	fake.listInstallationsMutex.Lock()
+	defer fake.listInstallationsMutex.Unlock()
+	fake.ListInstallationsStub = nil
+	if fake.listInstallationsReturnsOnCall == nil {
+		fake.listInstallationsReturnsOnCall = make(map[int]struct {
+			result1 []api.InstallationsServiceOutput
+			result2 error
+		})
+	}
+	fake.listInstallationsReturnsOnCall[i] = struct {
+		result1 []api.InstallationsServiceOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RotateCertificateAuthorityService) RegenerateCertificates() error {
+	fake.regenerateCertificatesMutex.Lock()
+	ret, specificReturn := fake.regenerateCertificatesReturnsOnCall[len(fake.regenerateCertificatesArgsForCall)]
+	fake.regenerateCertificatesArgsForCall = append(fake.regenerateCertificatesArgsForCall, struct {
+	}{})
+	stub := fake.RegenerateCertificatesStub
+	fakeReturns := fake.regenerateCertificatesReturns
+	fake.recordInvocation("RegenerateCertificates", []interface{}{})
+	fake.regenerateCertificatesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *RotateCertificateAuthorityService) RegenerateCertificatesCallCount() int {
+	fake.regenerateCertificatesMutex.RLock()
+	defer fake.regenerateCertificatesMutex.RUnlock()
+	return len(fake.regenerateCertificatesArgsForCall)
+}
+
+func (fake *RotateCertificateAuthorityService) RegenerateCertificatesCalls(stub func() error) {
+	fake.regenerateCertificatesMutex.Lock()
+	defer fake.regenerateCertificatesMutex.Unlock()
+	fake.RegenerateCertificatesStub = stub
+}
+
+func (fake *RotateCertificateAuthorityService) RegenerateCertificatesReturns(result1 error) {
+	fake.regenerateCertificatesMutex.Lock()
+	defer fake.regenerateCertificatesMutex.Unlock()
+	fake.RegenerateCertificatesStub = nil
+	fake.regenerateCertificatesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *RotateCertificateAuthorityService) RegenerateCertificatesReturnsOnCall(i int, result1 error) {
+	fake.regenerateCertificatesMutex.Lock()
+	defer fake.regenerateCertificatesMutex.Unlock()
+	fake.RegenerateCertificatesStub = nil
+	if fake.regenerateCertificatesReturnsOnCall == nil {
+		fake.regenerateCertificatesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.regenerateCertificatesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *RotateCertificateAuthorityService) RunningInstallation() (api.InstallationsServiceOutput, error) {
+	fake.runningInstallationMutex.Lock()
+	ret, specificReturn := fake.runningInstallationReturnsOnCall[len(fake.runningInstallationArgsForCall)]
+	fake.runningInstallationArgsForCall = append(fake.runningInstallationArgsForCall, struct {
+	}{})
+	stub := fake.RunningInstallationStub
+	fakeReturns := fake.runningInstallationReturns
+	fake.recordInvocation("RunningInstallation", []interface{}{})
+	fake.runningInstallationMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *RotateCertificateAuthorityService) RunningInstallationCallCount() int {
+	fake.runningInstallationMutex.RLock()
+	defer fake.runningInstallationMutex.RUnlock()
+	return len(fake.runningInstallationArgsForCall)
+}
+
+func (fake *RotateCertificateAuthorityService) RunningInstallationCalls(stub func() (api.InstallationsServiceOutput, error)) {
+	fake.runningInstallationMutex.Lock()
+	defer fake.runningInstallationMutex.Unlock()
+	fake.RunningInstallationStub = stub
+}
+
+func (fake *RotateCertificateAuthorityService) RunningInstallationReturns(result1 api.InstallationsServiceOutput, result2 error) {
+	fake.runningInstallationMutex.Lock()
+	defer fake.runningInstallationMutex.Unlock()
+	fake.RunningInstallationStub = nil
+	fake.runningInstallationReturns = struct {
+		result1 api.InstallationsServiceOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RotateCertificateAuthorityService) RunningInstallationReturnsOnCall(i int, result1 api.InstallationsServiceOutput, result2 error) {
+	fake.runningInstallationMutex.Lock()
+	defer fake.runningInstallationMutex.Unlock()
+	fake.RunningInstallationStub = nil
+	if fake.runningInstallationReturnsOnCall == nil {
+		fake.runningInstallationReturnsOnCall = make(map[int]struct {
+			result1 api.InstallationsServiceOutput
+			result2 error
+		})
+	}
+	fake.runningInstallationReturnsOnCall[i] = struct {
+		result1 api.InstallationsServiceOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RotateCertificateAuthorityService) UpdateStagedDirectorProperties(arg1 api.DirectorProperties) error {
+	fake.updateStagedDirectorPropertiesMutex.Lock()
+	ret, specificReturn := fake.updateStagedDirectorPropertiesReturnsOnCall[len(fake.updateStagedDirectorPropertiesArgsForCall)]
+	fake.updateStagedDirectorPropertiesArgsForCall = append(fake.updateStagedDirectorPropertiesArgsForCall, struct {
+		arg1 api.DirectorProperties
+	}{arg1})
+	stub := fake.UpdateStagedDirectorPropertiesStub
+	fakeReturns := fake.updateStagedDirectorPropertiesReturns
+	fake.recordInvocation("UpdateStagedDirectorProperties", []interface{}{arg1})
+	fake.updateStagedDirectorPropertiesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *RotateCertificateAuthorityService) UpdateStagedDirectorPropertiesCallCount() int {
+	fake.updateStagedDirectorPropertiesMutex.RLock()
+	defer fake.updateStagedDirectorPropertiesMutex.RUnlock()
+	return len(fake.updateStagedDirectorPropertiesArgsForCall)
+}
+
+func (fake *RotateCertificateAuthorityService) UpdateStagedDirectorPropertiesCalls(stub func(api.DirectorProperties) error) {
+	fake.updateStagedDirectorPropertiesMutex.Lock()
+	defer fake.updateStagedDirectorPropertiesMutex.Unlock()
+	fake.UpdateStagedDirectorPropertiesStub = stub
+}
+
+func (fake *RotateCertificateAuthorityService) UpdateStagedDirectorPropertiesArgsForCall(i int) api.DirectorProperties {
+	fake.updateStagedDirectorPropertiesMutex.RLock()
+	defer fake.updateStagedDirectorPropertiesMutex.RUnlock()
+	argsForCall := fake.updateStagedDirectorPropertiesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *RotateCertificateAuthorityService) UpdateStagedDirectorPropertiesReturns(result1 error) {
+	fake.updateStagedDirectorPropertiesMutex.Lock()
+	defer fake.updateStagedDirectorPropertiesMutex.Unlock()
+	fake.UpdateStagedDirectorPropertiesStub = nil
+	fake.updateStagedDirectorPropertiesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *RotateCertificateAuthorityService) UpdateStagedDirectorPropertiesReturnsOnCall(i int, result1 error) {
+	fake.updateStagedDirectorPropertiesMutex.Lock()
+	defer fake.updateStagedDirectorPropertiesMutex.Unlock()
+	fake.UpdateStagedDirectorPropertiesStub = nil
+	if fake.updateStagedDirectorPropertiesReturnsOnCall == nil {
+		fake.updateStagedDirectorPropertiesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateStagedDirectorPropertiesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *RotateCertificateAuthorityService) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.activateCertificateAuthorityMutex.RLock()
+	defer fake.activateCertificateAuthorityMutex.RUnlock()
+	fake.createInstallationMutex.RLock()
+	defer fake.createInstallationMutex.RUnlock()
+	fake.deleteCertificateAuthorityMutex.RLock()
+	defer fake.deleteCertificateAuthorityMutex.RUnlock()
+	fake.generateCertificateAuthorityMutex.RLock()
+	defer fake.generateCertificateAuthorityMutex.RUnlock()
+	fake.getInstallationMutex.RLock()
+	defer fake.getInstallationMutex.RUnlock()
+	fake.getInstallationLogsMutex.RLock()
+	defer fake.getInstallationLogsMutex.RUnlock()
+	fake.infoMutex.RLock()
+	defer fake.infoMutex.RUnlock()
+	fake.listCertificateAuthoritiesMutex.RLock()
+	defer fake.listCertificateAuthoritiesMutex.RUnlock()
+	fake.listInstallationsMutex.RLock()
+	defer fake.listInstallationsMutex.RUnlock()
+	fake.regenerateCertificatesMutex.RLock()
+	defer fake.regenerateCertificatesMutex.RUnlock()
+	fake.runningInstallationMutex.RLock()
+	defer fake.runningInstallationMutex.RUnlock()
+	fake.updateStagedDirectorPropertiesMutex.RLock()
+	defer fake.updateStagedDirectorPropertiesMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *RotateCertificateAuthorityService) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
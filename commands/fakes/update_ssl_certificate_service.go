@@ -8,6 +8,41 @@ import (
 )
 
 type UpdateSSLCertificateService struct {
+	DeleteSSLCertificateStub        func() error
+	deleteSSLCertificateMutex       sync.RWMutex
+	deleteSSLCertificateArgsForCall []struct {
+	}
+	deleteSSLCertificateReturns struct {
+		result1 error
+	}
+	deleteSSLCertificateReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GenerateCertificateStub        func(api.DomainsInput) (string, error)
+	generateCertificateMutex       sync.RWMutex
+	generateCertificateArgsForCall []struct {
+		arg1 api.DomainsInput
+	}
+	generateCertificateReturns struct {
+		result1 string
+		result2 error
+	}
+	generateCertificateReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	GetSecurityRootCACertificateStub        func() (string, error)
+	getSecurityRootCACertificateMutex       sync.RWMutex
+	getSecurityRootCACertificateArgsForCall []struct {
+	}
+	getSecurityRootCACertificateReturns struct {
+		result1 string
+		result2 error
+	}
+	getSecurityRootCACertificateReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	UpdateSSLCertificateStub        func(api.SSLCertificateInput) error
 	updateSSLCertificateMutex       sync.RWMutex
 	updateSSLCertificateArgsForCall []struct {
@@ -23,21 +58,195 @@ type UpdateSSLCertificateService struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *UpdateSSLCertificateService) DeleteSSLCertificate() error {
+	fake.deleteSSLCertificateMutex.Lock()
+	ret, specificReturn := fake.deleteSSLCertificateReturnsOnCall[len(fake.deleteSSLCertificateArgsForCall)]
+	fake.deleteSSLCertificateArgsForCall = append(fake.deleteSSLCertificateArgsForCall, struct {
+	}{})
+	stub := fake.DeleteSSLCertificateStub
+	fakeReturns := fake.deleteSSLCertificateReturns
+	fake.recordInvocation("DeleteSSLCertificate", []interface{}{})
+	fake.deleteSSLCertificateMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *UpdateSSLCertificateService) DeleteSSLCertificateCallCount() int {
+	fake.deleteSSLCertificateMutex.RLock()
+	defer fake.deleteSSLCertificateMutex.RUnlock()
+	return len(fake.deleteSSLCertificateArgsForCall)
+}
+
+func (fake *UpdateSSLCertificateService) DeleteSSLCertificateCalls(stub func() error) {
+	fake.deleteSSLCertificateMutex.Lock()
+	defer fake.deleteSSLCertificateMutex.Unlock()
+	fake.DeleteSSLCertificateStub = stub
+}
+
+func (fake *UpdateSSLCertificateService) DeleteSSLCertificateReturns(result1 error) {
+	fake.deleteSSLCertificateMutex.Lock()
+	defer fake.deleteSSLCertificateMutex.Unlock()
+	fake.DeleteSSLCertificateStub = nil
+	fake.deleteSSLCertificateReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *UpdateSSLCertificateService) DeleteSSLCertificateReturnsOnCall(i int, result1 error) {
+	fake.deleteSSLCertificateMutex.Lock()
+	defer fake.deleteSSLCertificateMutex.Unlock()
+	fake.DeleteSSLCertificateStub = nil
+	if fake.deleteSSLCertificateReturnsOnCall == nil {
+		fake.deleteSSLCertificateReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteSSLCertificateReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *UpdateSSLCertificateService) GenerateCertificate(arg1 api.DomainsInput) (string, error) {
+	fake.generateCertificateMutex.Lock()
+	ret, specificReturn := fake.generateCertificateReturnsOnCall[len(fake.generateCertificateArgsForCall)]
+	fake.generateCertificateArgsForCall = append(fake.generateCertificateArgsForCall, struct {
+		arg1 api.DomainsInput
+	}{arg1})
+	stub := fake.GenerateCertificateStub
+	fakeReturns := fake.generateCertificateReturns
+	fake.recordInvocation("GenerateCertificate", []interface{}{arg1})
+	fake.generateCertificateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *UpdateSSLCertificateService) GenerateCertificateCallCount() int {
+	fake.generateCertificateMutex.RLock()
+	defer fake.generateCertificateMutex.RUnlock()
+	return len(fake.generateCertificateArgsForCall)
+}
+
+func (fake *UpdateSSLCertificateService) GenerateCertificateCalls(stub func(api.DomainsInput) (string, error)) {
+	fake.generateCertificateMutex.Lock()
+	defer fake.generateCertificateMutex.Unlock()
+	fake.GenerateCertificateStub = stub
+}
+
+func (fake *UpdateSSLCertificateService) GenerateCertificateArgsForCall(i int) api.DomainsInput {
+	fake.generateCertificateMutex.RLock()
+	defer fake.generateCertificateMutex.RUnlock()
+	argsForCall := fake.generateCertificateArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *UpdateSSLCertificateService) GenerateCertificateReturns(result1 string, result2 error) {
+	fake.generateCertificateMutex.Lock()
+	defer fake.generateCertificateMutex.Unlock()
+	fake.GenerateCertificateStub = nil
+	fake.generateCertificateReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *UpdateSSLCertificateService) GenerateCertificateReturnsOnCall(i int, result1 string, result2 error) {
+	fake.generateCertificateMutex.Lock()
+	defer fake.generateCertificateMutex.Unlock()
+	fake.GenerateCertificateStub = nil
+	if fake.generateCertificateReturnsOnCall == nil {
+		fake.generateCertificateReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.generateCertificateReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *UpdateSSLCertificateService) GetSecurityRootCACertificate() (string, error) {
+	fake.getSecurityRootCACertificateMutex.Lock()
+	ret, specificReturn := fake.getSecurityRootCACertificateReturnsOnCall[len(fake.getSecurityRootCACertificateArgsForCall)]
+	fake.getSecurityRootCACertificateArgsForCall = append(fake.getSecurityRootCACertificateArgsForCall, struct {
+	}{})
+	stub := fake.GetSecurityRootCACertificateStub
+	fakeReturns := fake.getSecurityRootCACertificateReturns
+	fake.recordInvocation("GetSecurityRootCACertificate", []interface{}{})
+	fake.getSecurityRootCACertificateMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *UpdateSSLCertificateService) GetSecurityRootCACertificateCallCount() int {
+	fake.getSecurityRootCACertificateMutex.RLock()
+	defer fake.getSecurityRootCACertificateMutex.RUnlock()
+	return len(fake.getSecurityRootCACertificateArgsForCall)
+}
+
+func (fake *UpdateSSLCertificateService) GetSecurityRootCACertificateCalls(stub func() (string, error)) {
+	fake.getSecurityRootCACertificateMutex.Lock()
+	defer fake.getSecurityRootCACertificateMutex.Unlock()
+	fake.GetSecurityRootCACertificateStub = stub
+}
+
+func (fake *UpdateSSLCertificateService) GetSecurityRootCACertificateReturns(result1 string, result2 error) {
+	fake.getSecurityRootCACertificateMutex.Lock()
+	defer fake.getSecurityRootCACertificateMutex.Unlock()
+	fake.GetSecurityRootCACertificateStub = nil
+	fake.getSecurityRootCACertificateReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *UpdateSSLCertificateService) GetSecurityRootCACertificateReturnsOnCall(i int, result1 string, result2 error) {
+	fake.getSecurityRootCACertificateMutex.Lock()
+	defer fake.getSecurityRootCACertificateMutex.Unlock()
+	fake.GetSecurityRootCACertificateStub = nil
+	if fake.getSecurityRootCACertificateReturnsOnCall == nil {
+		fake.getSecurityRootCACertificateReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.getSecurityRootCACertificateReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *UpdateSSLCertificateService) UpdateSSLCertificate(arg1 api.SSLCertificateInput) error {
 	fake.updateSSLCertificateMutex.Lock()
 	ret, specificReturn := fake.updateSSLCertificateReturnsOnCall[len(fake.updateSSLCertificateArgsForCall)]
 	fake.updateSSLCertificateArgsForCall = append(fake.updateSSLCertificateArgsForCall, struct {
 		arg1 api.SSLCertificateInput
 	}{arg1})
+	stub := fake.UpdateSSLCertificateStub
+	fakeReturns := fake.updateSSLCertificateReturns
 	fake.recordInvocation("UpdateSSLCertificate", []interface{}{arg1})
 	fake.updateSSLCertificateMutex.Unlock()
-	if fake.UpdateSSLCertificateStub != nil {
-		return fake.UpdateSSLCertificateStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.updateSSLCertificateReturns
 	return fakeReturns.result1
 }
 
@@ -86,6 +295,12 @@ func (fake *UpdateSSLCertificateService) UpdateSSLCertificateReturnsOnCall(i int
 func (fake *UpdateSSLCertificateService) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.deleteSSLCertificateMutex.RLock()
+	defer fake.deleteSSLCertificateMutex.RUnlock()
+	fake.generateCertificateMutex.RLock()
+	defer fake.generateCertificateMutex.RUnlock()
+	fake.getSecurityRootCACertificateMutex.RLock()
+	defer fake.getSecurityRootCACertificateMutex.RUnlock()
 	fake.updateSSLCertificateMutex.RLock()
 	defer fake.updateSSLCertificateMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
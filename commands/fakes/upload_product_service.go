@@ -22,6 +22,54 @@ type UploadProductService struct {
 		result1 bool
 		result2 error
 	}
+	InfoStub        func() (api.Info, error)
+	infoMutex       sync.RWMutex
+	infoArgsForCall []struct {
+	}
+	infoReturns struct {
+		result1 api.Info
+		result2 error
+	}
+	infoReturnsOnCall map[int]struct {
+		result1 api.Info
+		result2 error
+	}
+	ListDeployedProductsStub        func() ([]api.DeployedProductOutput, error)
+	listDeployedProductsMutex       sync.RWMutex
+	listDeployedProductsArgsForCall []struct {
+	}
+	listDeployedProductsReturns struct {
+		result1 []api.DeployedProductOutput
+		result2 error
+	}
+	listDeployedProductsReturnsOnCall map[int]struct {
+		result1 []api.DeployedProductOutput
+		result2 error
+	}
+	ListMultiStemcellsStub        func() (api.ProductMultiStemcells, error)
+	listMultiStemcellsMutex       sync.RWMutex
+	listMultiStemcellsArgsForCall []struct {
+	}
+	listMultiStemcellsReturns struct {
+		result1 api.ProductMultiStemcells
+		result2 error
+	}
+	listMultiStemcellsReturnsOnCall map[int]struct {
+		result1 api.ProductMultiStemcells
+		result2 error
+	}
+	ListStemcellsStub        func() (api.ProductStemcells, error)
+	listStemcellsMutex       sync.RWMutex
+	listStemcellsArgsForCall []struct {
+	}
+	listStemcellsReturns struct {
+		result1 api.ProductStemcells
+		result2 error
+	}
+	listStemcellsReturnsOnCall map[int]struct {
+		result1 api.ProductStemcells
+		result2 error
+	}
 	UploadAvailableProductStub        func(api.UploadAvailableProductInput) (api.UploadAvailableProductOutput, error)
 	uploadAvailableProductMutex       sync.RWMutex
 	uploadAvailableProductArgsForCall []struct {
@@ -46,15 +94,16 @@ func (fake *UploadProductService) CheckProductAvailability(arg1 string, arg2 str
 		arg1 string
 		arg2 string
 	}{arg1, arg2})
+	stub := fake.CheckProductAvailabilityStub
+	fakeReturns := fake.checkProductAvailabilityReturns
 	fake.recordInvocation("CheckProductAvailability", []interface{}{arg1, arg2})
 	fake.checkProductAvailabilityMutex.Unlock()
-	if fake.CheckProductAvailabilityStub != nil {
-		return fake.CheckProductAvailabilityStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.checkProductAvailabilityReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -103,21 +152,246 @@ func (fake *UploadProductService) CheckProductAvailabilityReturnsOnCall(i int, r
 	}{result1, result2}
 }
 
+func (fake *UploadProductService) Info() (api.Info, error) {
+	fake.infoMutex.Lock()
+	ret, specificReturn := fake.infoReturnsOnCall[len(fake.infoArgsForCall)]
+	fake.infoArgsForCall = append(fake.infoArgsForCall, struct {
+	}{})
+	stub := fake.InfoStub
+	fakeReturns := fake.infoReturns
+	fake.recordInvocation("Info", []interface{}{})
+	fake.infoMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *UploadProductService) InfoCallCount() int {
+	fake.infoMutex.RLock()
+	defer fake.infoMutex.RUnlock()
+	return len(fake.infoArgsForCall)
+}
+
+func (fake *UploadProductService) InfoCalls(stub func() (api.Info, error)) {
+	fake.infoMutex.Lock()
+	defer fake.infoMutex.Unlock()
+	fake.InfoStub = stub
+}
+
+func (fake *UploadProductService) InfoReturns(result1 api.Info, result2 error) {
+	fake.infoMutex.Lock()
+	defer fake.infoMutex.Unlock()
+	fake.InfoStub = nil
+	fake.infoReturns = struct {
+		result1 api.Info
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *UploadProductService) InfoReturnsOnCall(i int, result1 api.Info, result2 error) {
+	fake.infoMutex.Lock()
+	defer fake.infoMutex.Unlock()
+	fake.InfoStub = nil
+	if fake.infoReturnsOnCall == nil {
+		fake.infoReturnsOnCall = make(map[int]struct {
+			result1 api.Info
+			result2 error
+		})
+	}
+	fake.infoReturnsOnCall[i] = struct {
+		result1 api.Info
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *UploadProductService) ListDeployedProducts() ([]api.DeployedProductOutput, error) {
+	fake.listDeployedProductsMutex.Lock()
+	ret, specificReturn := fake.listDeployedProductsReturnsOnCall[len(fake.listDeployedProductsArgsForCall)]
+	fake.listDeployedProductsArgsForCall = append(fake.listDeployedProductsArgsForCall, struct {
+	}{})
+	stub := fake.ListDeployedProductsStub
+	fakeReturns := fake.listDeployedProductsReturns
+	fake.recordInvocation("ListDeployedProducts", []interface{}{})
+	fake.listDeployedProductsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *UploadProductService) ListDeployedProductsCallCount() int {
+	fake.listDeployedProductsMutex.RLock()
+	defer fake.listDeployedProductsMutex.RUnlock()
+	return len(fake.listDeployedProductsArgsForCall)
+}
+
+func (fake *UploadProductService) ListDeployedProductsCalls(stub func() ([]api.DeployedProductOutput, error)) {
+	fake.listDeployedProductsMutex.Lock()
+	defer fake.listDeployedProductsMutex.Unlock()
+	fake.ListDeployedProductsStub = stub
+}
+
+func (fake *UploadProductService) ListDeployedProductsReturns(result1 []api.DeployedProductOutput, result2 error) {
+	fake.listDeployedProductsMutex.Lock()
+	defer fake.listDeployedProductsMutex.Unlock()
+	fake.ListDeployedProductsStub = nil
+	fake.listDeployedProductsReturns = struct {
+		result1 []api.DeployedProductOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *UploadProductService) ListDeployedProductsReturnsOnCall(i int, result1 []api.DeployedProductOutput, result2 error) {
+	fake.listDeployedProductsMutex.Lock()
+	defer fake.listDeployedProductsMutex.Unlock()
+	fake.ListDeployedProductsStub = nil
+	if fake.listDeployedProductsReturnsOnCall == nil {
+		fake.listDeployedProductsReturnsOnCall = make(map[int]struct {
+			result1 []api.DeployedProductOutput
+			result2 error
+		})
+	}
+	fake.listDeployedProductsReturnsOnCall[i] = struct {
+		result1 []api.DeployedProductOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *UploadProductService) ListMultiStemcells() (api.ProductMultiStemcells, error) {
+	fake.listMultiStemcellsMutex.Lock()
+	ret, specificReturn := fake.listMultiStemcellsReturnsOnCall[len(fake.listMultiStemcellsArgsForCall)]
+	fake.listMultiStemcellsArgsForCall = append(fake.listMultiStemcellsArgsForCall, struct {
+	}{})
+	stub := fake.ListMultiStemcellsStub
+	fakeReturns := fake.listMultiStemcellsReturns
+	fake.recordInvocation("ListMultiStemcells", []interface{}{})
+	fake.listMultiStemcellsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *UploadProductService) ListMultiStemcellsCallCount() int {
+	fake.listMultiStemcellsMutex.RLock()
+	defer fake.listMultiStemcellsMutex.RUnlock()
+	return len(fake.listMultiStemcellsArgsForCall)
+}
+
+func (fake *UploadProductService) ListMultiStemcellsCalls(stub func() (api.ProductMultiStemcells, error)) {
+	fake.listMultiStemcellsMutex.Lock()
+	defer fake.listMultiStemcellsMutex.Unlock()
+	fake.ListMultiStemcellsStub = stub
+}
+
+func (fake *UploadProductService) ListMultiStemcellsReturns(result1 api.ProductMultiStemcells, result2 error) {
+	fake.listMultiStemcellsMutex.Lock()
+	defer fake.listMultiStemcellsMutex.Unlock()
+	fake.ListMultiStemcellsStub = nil
+	fake.listMultiStemcellsReturns = struct {
+		result1 api.ProductMultiStemcells
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *UploadProductService) ListMultiStemcellsReturnsOnCall(i int, result1 api.ProductMultiStemcells, result2 error) {
+	fake.listMultiStemcellsMutex.Lock()
+	defer fake.listMultiStemcellsMutex.Unlock()
+	fake.ListMultiStemcellsStub = nil
+	if fake.listMultiStemcellsReturnsOnCall == nil {
+		fake.listMultiStemcellsReturnsOnCall = make(map[int]struct {
+			result1 api.ProductMultiStemcells
+			result2 error
+		})
+	}
+	fake.listMultiStemcellsReturnsOnCall[i] = struct {
+		result1 api.ProductMultiStemcells
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *UploadProductService) ListStemcells() (api.ProductStemcells, error) {
+	fake.listStemcellsMutex.Lock()
+	ret, specificReturn := fake.listStemcellsReturnsOnCall[len(fake.listStemcellsArgsForCall)]
+	fake.listStemcellsArgsForCall = append(fake.listStemcellsArgsForCall, struct {
+	}{})
+	stub := fake.ListStemcellsStub
+	fakeReturns := fake.listStemcellsReturns
+	fake.recordInvocation("ListStemcells", []interface{}{})
+	fake.listStemcellsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *UploadProductService) ListStemcellsCallCount() int {
+	fake.listStemcellsMutex.RLock()
+	defer fake.listStemcellsMutex.RUnlock()
+	return len(fake.listStemcellsArgsForCall)
+}
+
+func (fake *UploadProductService) ListStemcellsCalls(stub func() (api.ProductStemcells, error)) {
+	fake.listStemcellsMutex.Lock()
+	defer fake.listStemcellsMutex.Unlock()
+	fake.ListStemcellsStub = stub
+}
+
+func (fake *UploadProductService) ListStemcellsReturns(result1 api.ProductStemcells, result2 error) {
+	fake.listStemcellsMutex.Lock()
+	defer fake.listStemcellsMutex.Unlock()
+	fake.ListStemcellsStub = nil
+	fake.listStemcellsReturns = struct {
+		result1 api.ProductStemcells
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *UploadProductService) ListStemcellsReturnsOnCall(i int, result1 api.ProductStemcells, result2 error) {
+	fake.listStemcellsMutex.Lock()
+	defer fake.listStemcellsMutex.Unlock()
+	fake.ListStemcellsStub = nil
+	if fake.listStemcellsReturnsOnCall == nil {
+		fake.listStemcellsReturnsOnCall = make(map[int]struct {
+			result1 api.ProductStemcells
+			result2 error
+		})
+	}
+	fake.listStemcellsReturnsOnCall[i] = struct {
+		result1 api.ProductStemcells
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *UploadProductService) UploadAvailableProduct(arg1 api.UploadAvailableProductInput) (api.UploadAvailableProductOutput, error) {
 	fake.uploadAvailableProductMutex.Lock()
 	ret, specificReturn := fake.uploadAvailableProductReturnsOnCall[len(fake.uploadAvailableProductArgsForCall)]
 	fake.uploadAvailableProductArgsForCall = append(fake.uploadAvailableProductArgsForCall, struct {
 		arg1 api.UploadAvailableProductInput
 	}{arg1})
+	stub := fake.UploadAvailableProductStub
+	fakeReturns := fake.uploadAvailableProductReturns
 	fake.recordInvocation("UploadAvailableProduct", []interface{}{arg1})
 	fake.uploadAvailableProductMutex.Unlock()
-	if fake.UploadAvailableProductStub != nil {
-		return fake.UploadAvailableProductStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.uploadAvailableProductReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -171,6 +445,14 @@ func (fake *UploadProductService) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.checkProductAvailabilityMutex.RLock()
 	defer fake.checkProductAvailabilityMutex.RUnlock()
+	fake.infoMutex.RLock()
+	defer fake.infoMutex.RUnlock()
+	fake.listDeployedProductsMutex.RLock()
+	defer fake.listDeployedProductsMutex.RUnlock()
+	fake.listMultiStemcellsMutex.RLock()
+	defer fake.listMultiStemcellsMutex.RUnlock()
+	fake.listStemcellsMutex.RLock()
+	defer fake.listStemcellsMutex.RUnlock()
 	fake.uploadAvailableProductMutex.RLock()
 	defer fake.uploadAvailableProductMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -12,7 +12,7 @@ type GenerateCertificateAuthority struct {
 	service   generateCertificateAuthorityService
 	presenter presenters.FormattedPresenter
 	Options   struct {
-		Format string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json)"`
+		Format string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json,yaml,csv)"`
 	}
 }
 
@@ -30,6 +30,10 @@ func (g GenerateCertificateAuthority) Execute(args []string) error {
 		return fmt.Errorf("could not parse generate-certificate-authority flags: %s", err)
 	}
 
+	if err := presenters.ValidateFormat(g.Options.Format); err != nil {
+		return fmt.Errorf("could not parse generate-certificate-authority flags: %s", err)
+	}
+
 	certificateAuthority, err := g.service.GenerateCertificateAuthority()
 	if err != nil {
 		return err
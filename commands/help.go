@@ -18,6 +18,9 @@ Usage: {{.Usage}}
 {{end}}
 {{if .Arguments}}{{.ArgumentsName}}:
 {{range .Arguments}}  {{.}}
+{{end}}{{end}}{{if .Plugins}}
+Plugins:
+{{range .Plugins}}  {{.}}
 {{end}}{{end}}
 `
 
@@ -28,6 +31,7 @@ type TemplateContext struct {
 	GlobalFlags   []string
 	ArgumentsName string
 	Arguments     []string
+	Plugins       []string
 }
 
 type Help struct {
@@ -92,11 +96,17 @@ func (h Help) buildGlobalContext() TemplateContext {
 
 	sort.Strings(names)
 
-	var commands []string
+	var commands, plugins []string
 	for _, name := range names {
 		command := h.commands[name]
-		name = h.pad(name, " ", length)
-		commands = append(commands, fmt.Sprintf("%s  %s", name, command.Usage().ShortDescription))
+		line := fmt.Sprintf("%s  %s", h.pad(name, " ", length), command.Usage().ShortDescription)
+
+		if _, ok := command.(Plugin); ok {
+			plugins = append(plugins, line)
+			continue
+		}
+
+		commands = append(commands, line)
 	}
 
 	return TemplateContext{
@@ -105,6 +115,7 @@ func (h Help) buildGlobalContext() TemplateContext {
 		Usage:         "om [options] <command> [<args>]",
 		ArgumentsName: "Commands",
 		Arguments:     commands,
+		Plugins:       plugins,
 	}
 }
 
@@ -79,6 +79,28 @@ var _ = Describe("Help", func() {
 				Expect(err).ToNot(HaveOccurred())
 
 				Expect(output.String()).To(ContainSubstring(GLOBAL_USAGE))
+				Expect(output.String()).ToNot(ContainSubstring("Plugins:"))
+			})
+
+			It("lists the plugins after the commands", func() {
+				bake := &fakeCommand{
+					usage: jhanda.Usage{ShortDescription: "bakes you a cake"},
+				}
+
+				help := commands.NewHelp(output, strings.TrimSpace(flags), jhanda.CommandSet{
+					"bake":     bake,
+					"decorate": commands.NewPlugin("decorate", "/usr/local/bin/om-decorate", commands.PluginGlobals{}, nil, nil, nil, nil),
+				})
+				err := help.Execute([]string{})
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(output.String()).To(HaveSuffix(`Commands:
+  bake      bakes you a cake
+
+Plugins:
+  decorate  /usr/local/bin/om-decorate
+
+`))
 			})
 		})
 
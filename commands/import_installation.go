@@ -13,11 +13,13 @@ import (
 const maxRetries = 3
 
 type ImportInstallation struct {
-	multipart  multipart
-	logger     logger
-	service    importInstallationService
-	passphrase string
-	Options    struct {
+	multipart    multipart
+	logger       logger
+	service      importInstallationService
+	passphrase   string
+	strict       bool
+	defaultFlags []string
+	Options      struct {
 		ConfigFile      string `long:"config"                short:"c"                  description:"path to yml file for configuration (keys must match the following command line flags)"`
 		Installation    string `long:"installation"          short:"i"  required:"true" description:"path to installation."`
 		PollingInterval int    `long:"polling-interval"      short:"pi"                 description:"interval (in seconds) to check OpsManager availability" default:"10"`
@@ -30,12 +32,13 @@ type importInstallationService interface {
 	EnsureAvailability(input api.EnsureAvailabilityInput) (api.EnsureAvailabilityOutput, error)
 }
 
-func NewImportInstallation(multipart multipart, service importInstallationService, passphrase string, logger logger) *ImportInstallation {
+func NewImportInstallation(multipart multipart, service importInstallationService, passphrase string, logger logger, strict bool) *ImportInstallation {
 	return &ImportInstallation{
 		multipart:  multipart,
 		logger:     logger,
 		service:    service,
 		passphrase: passphrase,
+		strict:     strict,
 	}
 }
 
@@ -47,6 +50,11 @@ func (ii ImportInstallation) Usage() jhanda.Usage {
 	}
 }
 
+func (ii *ImportInstallation) WithDefaultFlags(defaultFlags []string) jhanda.Command {
+	ii.defaultFlags = defaultFlags
+	return ii
+}
+
 func (ii *ImportInstallation) Execute(args []string) error {
 	err := ii.validate(args)
 	if err != nil {
@@ -129,7 +137,7 @@ func (ii *ImportInstallation) validate(args []string) error {
 		return fmt.Errorf("the global decryption-passphrase argument is required for this command")
 	}
 
-	err := loadConfigFile(args, &ii.Options, nil)
+	err := loadConfigFile(args, &ii.Options, nil, ii.strict, ii.defaultFlags)
 	if err != nil {
 		return fmt.Errorf("could not parse import-installation flags: %s", err)
 	}
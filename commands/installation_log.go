@@ -11,7 +11,7 @@ type InstallationLog struct {
 	service installationLogService
 	logger  logger
 	Options struct {
-		Id int `long:"id" required:"true" description:"id of the installation to retrieve logs for"`
+		Id int `long:"id" required:"true" description:"id of the installation to retrieve logs for" complete:"installations"`
 	}
 }
 
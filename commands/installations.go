@@ -13,7 +13,7 @@ type Installations struct {
 	service   installationsService
 	presenter presenters.FormattedPresenter
 	Options   struct {
-		Format string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json)"`
+		Format string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json,yaml,csv)"`
 	}
 }
 
@@ -34,6 +34,10 @@ func (i Installations) Execute(args []string) error {
 		return fmt.Errorf("could not parse installations flags: %s", err)
 	}
 
+	if err := presenters.ValidateFormat(i.Options.Format); err != nil {
+		return fmt.Errorf("could not parse installations flags: %s", err)
+	}
+
 	installationsOutput, err := i.service.ListInstallations()
 	if err != nil {
 		return err
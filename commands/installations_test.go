@@ -89,6 +89,13 @@ var _ = Describe("Installations", func() {
 		})
 
 		Context("Failure cases", func() {
+			When("the format is not supported", func() {
+				It("returns an error", func() {
+					err := command.Execute([]string{"--format", "xml"})
+					Expect(err).To(MatchError(`could not parse installations flags: unsupported format "xml", expected one of table, json, yaml, csv`))
+				})
+			})
+
 			When("an unknown flag is passed", func() {
 				It("returns an error", func() {
 					err := command.Execute([]string{"--unknown-flag"})
@@ -8,28 +8,37 @@ import (
 
 	"github.com/pivotal-cf/jhanda"
 	"github.com/pivotal-cf/om/interpolate"
+	"github.com/pivotal-cf/om/presenters"
 )
 
 type Interpolate struct {
 	environFunc func() []string
+	presenter   presenters.FormattedPresenter
 	logger      logger
 	input       *os.File
+	strict      bool
 	Options     struct {
 		ConfigFile        string   `long:"config"       short:"c"     description:"path for file to be interpolated"`
 		Path              string   `long:"path"                       description:"Extract specified value out of the interpolated file (e.g.: /private_key). The rest of the file will not be printed."`
 		VarsEnv           []string `long:"vars-env" env:"OM_VARS_ENV" description:"Load variables from environment variables (e.g.: 'MY' to load MY_var=value)"`
 		VarsFile          []string `long:"vars-file"    short:"l"     description:"Load variables from a YAML file"`
+		VarsSource        []string `long:"vars-source" env:"OM_VARS_SOURCE" description:"Load variables that are not otherwise provided from a credential store (e.g.: 'vault:address=https://vault.example.com,mount=secret,path=foundation' or 'credhub:address=https://credhub.example.com,path=/foundation')"`
+		VarsStore         string   `long:"vars-store" description:"Load variables from a YAML file, the variables defined in the 'variables' section of the config that are missing are generated and saved to it"`
 		Vars              []string `long:"var"          short:"v"     description:"Load variable from the command line. Format: VAR=VAL"`
 		OpsFile           []string `long:"ops-file"     short:"o"     description:"YAML operations files"`
 		SkipMissingParams bool     `long:"skip-missing" short:"s"     description:"Allow skipping missing params"`
+		Check             bool     `long:"check"                      description:"Report every missing variable, the unused variables of each vars file, and which source is used for variables that are provided more than once, instead of printing the interpolated file"`
+		Format            string   `long:"format"       short:"f"     default:"table" description:"Format to print the --check report as (options: table,json,yaml,csv)"`
 	}
 }
 
-func NewInterpolate(environFunc func() []string, logger logger, input *os.File) Interpolate {
+func NewInterpolate(environFunc func() []string, presenter presenters.FormattedPresenter, logger logger, input *os.File, strict bool) Interpolate {
 	return Interpolate{
 		environFunc: environFunc,
+		presenter:   presenter,
 		logger:      logger,
 		input:       input,
+		strict:      strict,
 	}
 }
 
@@ -38,6 +47,10 @@ func (c Interpolate) Execute(args []string) error {
 		return fmt.Errorf("could not parse interpolate flags: %s", err)
 	}
 
+	if err := presenters.ValidateFormat(c.Options.Format); err != nil {
+		return fmt.Errorf("could not parse interpolate flags: %s", err)
+	}
+
 	info, err := c.input.Stat()
 	if err != nil {
 		return fmt.Errorf("error in STDIN: %s", err)
@@ -74,18 +87,30 @@ func (c Interpolate) Execute(args []string) error {
 		expectAllKeys = false
 	}
 
-	bytes, err := interpolate.Execute(interpolate.Options{
-		TemplateFile:  c.Options.ConfigFile,
-		VarsFiles:     c.Options.VarsFile,
-		Vars:          c.Options.Vars,
-		EnvironFunc:   c.environFunc,
-		VarsEnvs:      c.Options.VarsEnv,
-		OpsFiles:      c.Options.OpsFile,
-		ExpectAllKeys: expectAllKeys,
-		Path:          c.Options.Path,
-	})
+	options := interpolate.Options{
+		TemplateFile:      c.Options.ConfigFile,
+		VarsFiles:         c.Options.VarsFile,
+		VarsSources:       c.Options.VarsSource,
+		VarsStore:         c.Options.VarsStore,
+		Vars:              c.Options.Vars,
+		EnvironFunc:       c.environFunc,
+		VarsEnvs:          c.Options.VarsEnv,
+		OpsFiles:          c.Options.OpsFile,
+		ExpectAllKeys:     expectAllKeys,
+		ExpectAllVarsUsed: c.strict,
+		Path:              c.Options.Path,
+	}
+
+	if c.Options.Check {
+		return c.check(options)
+	}
+
+	bytes, err := interpolate.Execute(options)
 	if err != nil {
 		splitErr := strings.Split(err.Error(), ": ")
+		if len(splitErr) < 2 {
+			return err
+		}
 		return fmt.Errorf("%s:\n%s", splitErr[0], splitErr[1])
 	}
 
@@ -94,6 +119,30 @@ func (c Interpolate) Execute(args []string) error {
 	return nil
 }
 
+func (c Interpolate) check(options interpolate.Options) error {
+	report, err := interpolate.Check(options)
+	if err != nil {
+		return fmt.Errorf("could not check the variables: %s", err)
+	}
+
+	if !options.ExpectAllKeys {
+		report.Missing = []string{}
+	}
+
+	c.presenter.SetFormat(c.Options.Format)
+	c.presenter.PresentInterpolationReport(report)
+
+	if report.HasProblems() {
+		unused := 0
+		for _, variables := range report.Unused {
+			unused += len(variables.Names)
+		}
+		return fmt.Errorf("found %d missing and %d unused variables", len(report.Missing), unused)
+	}
+
+	return nil
+}
+
 func (c Interpolate) Usage() jhanda.Usage {
 	return jhanda.Usage{
 		Description:      "interpolates variables into a manifest",
@@ -2,13 +2,17 @@ package commands_test
 
 import (
 	"io/ioutil"
+	"net/http"
 	"os"
 	"strings"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
 	"github.com/pivotal-cf/om/commands"
 	"github.com/pivotal-cf/om/commands/fakes"
+	"github.com/pivotal-cf/om/models"
+	presenterfakes "github.com/pivotal-cf/om/presenters/fakes"
 )
 
 var templateNoParameters = `hello: world`
@@ -27,9 +31,10 @@ var opsFileParameter = `- type: replace
 
 var _ = Describe("Interpolate", func() {
 	var (
-		command commands.Interpolate
-		logger  *fakes.Logger
-		stdin   *os.File
+		command   commands.Interpolate
+		presenter *presenterfakes.FormattedPresenter
+		logger    *fakes.Logger
+		stdin     *os.File
 	)
 
 	BeforeEach(func() {
@@ -38,7 +43,8 @@ var _ = Describe("Interpolate", func() {
 		Expect(err).ToNot(HaveOccurred())
 		ioutil.WriteFile(stdin.Name(), []byte(templateNoParametersOverStdin), os.ModeCharDevice|0755) // mimic a character device so it'll be picked up in the conditional
 		logger = &fakes.Logger{}
-		command = commands.NewInterpolate(func() []string { return nil }, logger, stdin)
+		presenter = &presenterfakes.FormattedPresenter{}
+		command = commands.NewInterpolate(func() []string { return nil }, presenter, logger, stdin, false)
 	})
 
 	AfterEach(func() {
@@ -191,6 +197,35 @@ var _ = Describe("Interpolate", func() {
 			})
 		})
 
+		Context("with vars source input", func() {
+			It("resolves the missing vars from the source", func() {
+				server := ghttp.NewServer()
+				defer server.Close()
+
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/v1/secret/data/foundation/hello"),
+						ghttp.VerifyHeaderKV("X-Vault-Token", "some-token"),
+						ghttp.RespondWith(http.StatusOK, `{"data": {"data": {"value": "vault"}}}`),
+					),
+				)
+
+				command = commands.NewInterpolate(func() []string { return []string{"VAULT_TOKEN=some-token"} }, presenter, logger, stdin, false)
+
+				err := ioutil.WriteFile(inputFile, []byte(templateWithMultipleParameters), 0755)
+				Expect(err).ToNot(HaveOccurred())
+				err = command.Execute([]string{
+					"--config", inputFile,
+					"--var", "world=hello",
+					"--vars-source", "vault:address=" + server.URL() + ",path=foundation",
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				content := logger.PrintlnArgsForCall(0)
+				Expect(content[0].(string)).To(MatchYAML("hello: vault\nworld: hello"))
+			})
+		})
+
 		Context("with ops file input", func() {
 			It("succeeds", func() {
 				err := ioutil.WriteFile(inputFile, []byte(templateNoParameters), 0755)
@@ -244,9 +279,91 @@ hello: world`))
 			})
 		})
 
+		When("the check flag is set", func() {
+			BeforeEach(func() {
+				err := ioutil.WriteFile(inputFile, []byte(templateWithMultipleParameters), 0755)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("presents how the variables are resolved", func() {
+				err := ioutil.WriteFile(varsFile, []byte(varsFileParameter), 0755)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = command.Execute([]string{
+					"--config", inputFile,
+					"--vars-file", varsFile,
+					"--var", "hello=override",
+					"--var", "world=earth",
+					"--check",
+					"--format", "json",
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(presenter.SetFormatArgsForCall(0)).To(Equal("json"))
+				Expect(presenter.PresentInterpolationReportCallCount()).To(Equal(1))
+				Expect(presenter.PresentInterpolationReportArgsForCall(0)).To(Equal(models.InterpolationReport{
+					Missing: []string{},
+					Unused:  []models.UnusedVariables{},
+					Overridden: []models.OverriddenVariable{{
+						Name:       "hello",
+						Source:     "--var",
+						Overridden: []string{"--vars-file " + varsFile},
+					}},
+				}))
+				Expect(logger.PrintlnCallCount()).To(Equal(0))
+			})
+
+			It("fails when variables are missing or unused", func() {
+				err := ioutil.WriteFile(varsFile, []byte("{hello: world, wrold: typo}"), 0755)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = command.Execute([]string{
+					"--config", inputFile,
+					"--vars-file", varsFile,
+					"--check",
+				})
+				Expect(err).To(MatchError("found 1 missing and 1 unused variables"))
+
+				Expect(presenter.SetFormatArgsForCall(0)).To(Equal("table"))
+				report := presenter.PresentInterpolationReportArgsForCall(0)
+				Expect(report.Missing).To(Equal([]string{"world"}))
+				Expect(report.Unused).To(Equal([]models.UnusedVariables{
+					{Source: "--vars-file " + varsFile, Names: []string{"wrold"}},
+				}))
+			})
+
+			It("does not report missing variables with --skip-missing", func() {
+				err := command.Execute([]string{
+					"--config", inputFile,
+					"--check",
+					"--skip-missing",
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(presenter.PresentInterpolationReportArgsForCall(0).Missing).To(BeEmpty())
+			})
+		})
+
+		When("strict interpolation is enabled", func() {
+			BeforeEach(func() {
+				command = commands.NewInterpolate(func() []string { return nil }, presenter, logger, stdin, true)
+			})
+
+			It("fails when a variable is not used", func() {
+				err := ioutil.WriteFile(inputFile, []byte(templateWithParameters), 0755)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = command.Execute([]string{
+					"--config", inputFile,
+					"--var", "hello=world",
+					"--var", "unused=value",
+				})
+				Expect(err).To(MatchError(ContainSubstring(`- unused "unused" from --var`)))
+			})
+		})
+
 		When("no flags are set and no stdin provided", func() {
 			It("errors", func() {
-				command = commands.NewInterpolate(func() []string { return nil }, logger, os.Stdin)
+				command = commands.NewInterpolate(func() []string { return nil }, presenter, logger, os.Stdin, false)
 				err := command.Execute([]string{})
 				Expect(err).To(MatchError(ContainSubstring("no file or STDIN input provided.")))
 			})
@@ -254,7 +371,7 @@ hello: world`))
 
 		When("no stdin provided and --config -", func() {
 			It("errors", func() {
-				command = commands.NewInterpolate(func() []string { return nil }, logger, os.Stdin)
+				command = commands.NewInterpolate(func() []string { return nil }, presenter, logger, os.Stdin, false)
 				err := command.Execute([]string{"--config", "-"})
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("no file or STDIN input provided."))
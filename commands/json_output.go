@@ -0,0 +1,22 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	tableFormat = "table"
+	yamlFormat  = "yaml"
+	jsonFormat  = "json"
+)
+
+func printJSON(logger logger, document interface{}) error {
+	output, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal json output: %s", err)
+	}
+
+	logger.Println(string(output))
+	return nil
+}
@@ -11,10 +11,12 @@ import (
 
 // Load the config file, (optionally) load the vars file, vars env as well
 // To use this function, `Config` field must be defined in the command struct being passed in.
-// To load vars, VarsFile and/or VarsEnv must exist in the command struct being passed in.
+// To load vars, VarsFile, VarsEnv, VarsSource and/or VarsStore must exist in the command struct being passed in.
 // If VarsEnv is used, envFunc must be defined instead of nil
-func loadConfigFile(args []string, command interface{}, envFunc func() []string) error {
-	_, err := jhanda.Parse(command, args)
+// With strict (the global --strict flag), it fails when a variable is missing or a provided variable is not used.
+// The defaultFlags of the user config file are only used for the flags that are neither in args nor in the config file.
+func loadConfigFile(args []string, command interface{}, envFunc func() []string, strict bool, defaultFlags []string) error {
+	_, err := jhanda.Parse(command, MergeDefaultFlags(command, defaultFlags, args))
 	commandValue := reflect.ValueOf(command).Elem()
 	configFile := commandValue.FieldByName("ConfigFile").String()
 	if configFile == "" {
@@ -24,11 +26,15 @@ func loadConfigFile(args []string, command interface{}, envFunc func() []string)
 	varsFileField := commandValue.FieldByName("VarsFile")
 	varsEnvField := commandValue.FieldByName("VarsEnv")
 	cmdVarsField := commandValue.FieldByName("Vars")
+	varsSourceField := commandValue.FieldByName("VarsSource")
+	varsStoreField := commandValue.FieldByName("VarsStore")
 
 	var (
 		varsField []string
 		varsEnv   []string
 		cmdVars   []string
+		sources   []string
+		store     string
 		ok        bool
 		options   map[string]interface{}
 		contents  []byte
@@ -52,14 +58,29 @@ func loadConfigFile(args []string, command interface{}, envFunc func() []string)
 		}
 	}
 
+	if varsSourceField.IsValid() {
+		if sources, ok = varsSourceField.Interface().([]string); !ok {
+			return fmt.Errorf("expect VarsSource field to be a `[]string`, found %s", varsSourceField.Type())
+		}
+	}
+
+	if varsStoreField.IsValid() {
+		if store, ok = varsStoreField.Interface().(string); !ok {
+			return fmt.Errorf("expect VarsStore field to be a `string`, found %s", varsStoreField.Type())
+		}
+	}
+
 	contents, err = interpolate.Execute(interpolate.Options{
-		TemplateFile:  configFile,
-		VarsEnvs:      varsEnv,
-		VarsFiles:     varsField,
-		Vars:          cmdVars,
-		EnvironFunc:   envFunc,
-		OpsFiles:      nil,
-		ExpectAllKeys: true,
+		TemplateFile:      configFile,
+		VarsEnvs:          varsEnv,
+		VarsFiles:         varsField,
+		Vars:              cmdVars,
+		VarsSources:       sources,
+		VarsStore:         store,
+		EnvironFunc:       envFunc,
+		OpsFiles:          nil,
+		ExpectAllKeys:     true,
+		ExpectAllVarsUsed: strict,
 	})
 	if err != nil {
 		return fmt.Errorf("could not load the config file: %s", err)
@@ -84,7 +105,8 @@ func loadConfigFile(args []string, command interface{}, envFunc func() []string)
 		}
 
 	}
-	fileArgs = append(fileArgs, args...)
+
+	fileArgs = MergeDefaultFlags(command, defaultFlags, append(fileArgs, args...))
 	_, err = jhanda.Parse(command, fileArgs)
 	return err
 }
@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/models"
+	"github.com/pivotal-cf/om/presenters"
+)
+
+type Locks struct {
+	service   locksService
+	presenter presenters.FormattedPresenter
+	logger    logger
+	Options   struct {
+		Break  string `long:"break" description:"name of the lock to break, whoever holds it"`
+		Format string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json,yaml,csv)"`
+	}
+}
+
+//counterfeiter:generate -o ./fakes/locks_service.go --fake-name LocksService . locksService
+type locksService interface {
+	List() ([]models.Lock, error)
+	Break(name string) error
+}
+
+// NewLocks lists and breaks the locks of the service, which is nil when om has no --lock.
+func NewLocks(service locksService, presenter presenters.FormattedPresenter, logger logger) Locks {
+	return Locks{
+		service:   service,
+		presenter: presenter,
+		logger:    logger,
+	}
+}
+
+func (l Locks) Execute(args []string) error {
+	if _, err := jhanda.Parse(&l.Options, args); err != nil {
+		return fmt.Errorf("could not parse locks flags: %s", err)
+	}
+
+	if err := presenters.ValidateFormat(l.Options.Format); err != nil {
+		return fmt.Errorf("could not parse locks flags: %s", err)
+	}
+
+	if l.service == nil {
+		return errors.New("there is no lock: set it with --lock, OM_LOCK or the user config file")
+	}
+
+	if l.Options.Break != "" {
+		err := l.service.Break(l.Options.Break)
+		if err != nil {
+			return err
+		}
+
+		l.logger.Printf("broke the lock %s\n", l.Options.Break)
+		return nil
+	}
+
+	locks, err := l.service.List()
+	if err != nil {
+		return err
+	}
+
+	l.presenter.SetFormat(l.Options.Format)
+	l.presenter.PresentLocks(locks)
+
+	return nil
+}
+
+func (l Locks) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This command lists the locks of the global --lock, which om holds on an Ops Manager for the whole command, or breaks one of them.",
+		ShortDescription: "lists or breaks the locks on the Ops Managers",
+		Flags:            l.Options,
+	}
+}
@@ -0,0 +1,91 @@
+package commands_test
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+	"github.com/pivotal-cf/om/models"
+	presenterfakes "github.com/pivotal-cf/om/presenters/fakes"
+)
+
+var _ = Describe("Locks", func() {
+	var (
+		fakeService   *fakes.LocksService
+		fakePresenter *presenterfakes.FormattedPresenter
+		fakeLogger    *fakes.Logger
+		command       commands.Locks
+	)
+
+	BeforeEach(func() {
+		fakeService = &fakes.LocksService{}
+		fakePresenter = &presenterfakes.FormattedPresenter{}
+		fakeLogger = &fakes.Logger{}
+		command = commands.NewLocks(fakeService, fakePresenter, fakeLogger)
+	})
+
+	It("lists the locks", func() {
+		locks := []models.Lock{{
+			Name:       "opsman.example.com",
+			Holder:     "ci@worker (pid 42)",
+			Command:    "apply-changes",
+			AcquiredAt: time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC),
+			ExpiresAt:  time.Date(2030, 1, 2, 4, 4, 5, 0, time.UTC),
+		}}
+		fakeService.ListReturns(locks, nil)
+
+		err := command.Execute([]string{"--format", "json"})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(fakePresenter.SetFormatArgsForCall(0)).To(Equal("json"))
+		Expect(fakePresenter.PresentLocksArgsForCall(0)).To(Equal(locks))
+		Expect(fakeService.BreakCallCount()).To(Equal(0))
+	})
+
+	It("breaks the lock given with --break", func() {
+		err := command.Execute([]string{"--break", "opsman.example.com"})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(fakeService.BreakArgsForCall(0)).To(Equal("opsman.example.com"))
+		Expect(fakePresenter.PresentLocksCallCount()).To(Equal(0))
+
+		format, content := fakeLogger.PrintfArgsForCall(0)
+		Expect(fmt.Sprintf(format, content...)).To(Equal("broke the lock opsman.example.com\n"))
+	})
+
+	When("the service fails", func() {
+		It("returns an error", func() {
+			fakeService.ListReturns(nil, errors.New("could not list the locks"))
+			err := command.Execute([]string{})
+			Expect(err).To(MatchError("could not list the locks"))
+
+			fakeService.BreakReturns(errors.New("could not break the lock"))
+			err = command.Execute([]string{"--break", "opsman.example.com"})
+			Expect(err).To(MatchError("could not break the lock"))
+		})
+	})
+
+	When("there is no lock", func() {
+		It("returns an error", func() {
+			command = commands.NewLocks(nil, fakePresenter, fakeLogger)
+			err := command.Execute([]string{})
+			Expect(err).To(MatchError("there is no lock: set it with --lock, OM_LOCK or the user config file"))
+		})
+	})
+
+	Describe("flags", func() {
+		It("returns an error when an unknown flag is given", func() {
+			err := command.Execute([]string{"--unknown-flag"})
+			Expect(err).To(MatchError(ContainSubstring("could not parse locks flags")))
+		})
+
+		It("returns an error when the format is not supported", func() {
+			err := command.Execute([]string{"--format", "xml"})
+			Expect(err).To(MatchError(ContainSubstring(`unsupported format "xml"`)))
+		})
+	})
+})
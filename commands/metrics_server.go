@@ -0,0 +1,261 @@
+package commands
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+)
+
+//counterfeiter:generate -o ./fakes/metrics_service.go --fake-name MetricsService . metricsService
+type metricsService interface {
+	Info() (api.Info, error)
+	ListExpiringCertificates(string) ([]api.ExpiringCertificate, error)
+	ListStagedPendingChanges() (api.PendingChangesOutput, error)
+	ListInstallations() ([]api.InstallationsServiceOutput, error)
+	GetDiagnosticReport() (api.DiagnosticReport, error)
+}
+
+type MetricsServer struct {
+	service metricsService
+	logger  logger
+	serve   func(addr string, handler http.Handler) error
+	Options struct {
+		Listen          string `long:"listen"           short:"l"  default:":9225" description:"address to serve the metrics on"`
+		PollingInterval int    `long:"polling-interval" short:"pi" default:"60"    description:"interval (in seconds) at which Ops Manager is polled"`
+		ExpiresWithin   string `long:"expires-within"   short:"e"  default:"3m"    description:"timeframe of the certificates to report the expiry of.\n\t\t\t\tdays(d), weeks(w), months(m) and years(y) supported."`
+	}
+}
+
+func NewMetricsServer(service metricsService, logger logger, serve func(addr string, handler http.Handler) error) MetricsServer {
+	return MetricsServer{
+		service: service,
+		logger:  logger,
+		serve:   serve,
+	}
+}
+
+func (ms MetricsServer) Execute(args []string) error {
+	if _, err := jhanda.Parse(&ms.Options, args); err != nil {
+		return fmt.Errorf("could not parse metrics-server flags: %s", err)
+	}
+
+	if ms.Options.PollingInterval < 1 {
+		return fmt.Errorf("could not parse metrics-server flags: --polling-interval must be at least 1 second")
+	}
+
+	if err := validateExpiresWithin(ms.Options.ExpiresWithin); err != nil {
+		return fmt.Errorf("could not parse metrics-server flags: %s", err)
+	}
+
+	snapshot := &metricsSnapshot{}
+	ms.poll(snapshot)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		ticker := time.NewTicker(time.Duration(ms.Options.PollingInterval) * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				ms.poll(snapshot)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", snapshot)
+
+	ms.logger.Printf("serving metrics on %s/metrics every %ds\n", ms.Options.Listen, ms.Options.PollingInterval)
+	return ms.serve(ms.Options.Listen, mux)
+}
+
+func (ms MetricsServer) poll(snapshot *metricsSnapshot) {
+	families, err := ms.collect()
+	if err != nil {
+		ms.logger.Printf("could not poll Ops Manager: %s\n", err)
+	}
+
+	snapshot.update(families, err == nil, time.Now())
+}
+
+func (ms MetricsServer) collect() ([]metricFamily, error) {
+	info, err := ms.service.Info()
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch the Ops Manager version: %s", err)
+	}
+
+	certificates, err := ms.service.ListExpiringCertificates(ms.Options.ExpiresWithin)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch expiring certificates: %s", err)
+	}
+
+	pendingChanges, err := ms.service.ListStagedPendingChanges()
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch pending changes: %s", err)
+	}
+
+	installations, err := ms.service.ListInstallations()
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch installations: %s", err)
+	}
+
+	report, err := ms.service.GetDiagnosticReport()
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch the diagnostic report: %s", err)
+	}
+
+	families := []metricFamily{
+		gauge("om_ops_manager_info", "Version of Ops Manager.", sample(1, "version", info.Version)),
+		certificateMetrics(certificates),
+	}
+	families = append(families, pendingChangesMetrics(pendingChanges)...)
+	families = append(families, installationMetrics(installations)...)
+	families = append(families, productVersionMetrics(report)...)
+
+	return families, nil
+}
+
+func certificateMetrics(certificates []api.ExpiringCertificate) metricFamily {
+	var samples []metricSample
+	for _, cert := range certificates {
+		samples = append(samples, sample(float64(cert.ValidUntil.Unix()),
+			"location", cert.Location,
+			"product_guid", cert.ProductGUID,
+			"property_reference", cert.PropertyReference,
+			"variable_path", cert.VariablePath,
+		))
+	}
+
+	return gauge("om_certificate_expiry_timestamp_seconds", "Time at which a certificate expires, for the certificates expiring within --expires-within.", samples...)
+}
+
+func pendingChangesMetrics(pendingChanges api.PendingChangesOutput) []metricFamily {
+	var pending int
+	var samples []metricSample
+	for _, change := range pendingChanges.ChangeList {
+		changed := change.Action != "unchanged"
+		if changed {
+			pending++
+		}
+
+		samples = append(samples, sample(boolValue(changed), "product_guid", change.GUID, "action", change.Action))
+	}
+
+	return []metricFamily{
+		gauge("om_product_pending_changes", "Whether a product has pending changes, by the action of the next apply-changes.", samples...),
+		gauge("om_pending_changes", "Number of products with pending changes.", sample(float64(pending))),
+	}
+}
+
+func installationMetrics(installations []api.InstallationsServiceOutput) []metricFamily {
+	if len(installations) == 0 {
+		return nil
+	}
+
+	last := installations[0]
+	for _, installation := range installations {
+		if installation.ID > last.ID {
+			last = installation
+		}
+	}
+
+	families := []metricFamily{
+		gauge("om_last_installation_info", "Status of the last installation.", sample(1, "id", fmt.Sprintf("%d", last.ID), "status", last.Status, "user", last.UserName)),
+		gauge("om_last_installation_succeeded", "Whether the last installation has succeeded.", sample(boolValue(last.Status == "succeeded"))),
+	}
+
+	if last.StartedAt != nil {
+		families = append(families, gauge("om_last_installation_started_timestamp_seconds", "Time at which the last installation has started.", sample(float64(last.StartedAt.Unix()))))
+	}
+
+	if last.StartedAt != nil && last.FinishedAt != nil {
+		families = append(families,
+			gauge("om_last_installation_finished_timestamp_seconds", "Time at which the last installation has finished.", sample(float64(last.FinishedAt.Unix()))),
+			gauge("om_last_installation_duration_seconds", "Duration of the last installation.", sample(last.FinishedAt.Sub(*last.StartedAt).Seconds())),
+		)
+	}
+
+	return families
+}
+
+func productVersionMetrics(report api.DiagnosticReport) []metricFamily {
+	deployed := map[string]string{}
+	for _, product := range report.DeployedProducts {
+		deployed[product.Name] = product.Version
+	}
+
+	var mismatches int
+	var samples []metricSample
+	for _, product := range report.StagedProducts {
+		mismatch := product.Version != deployed[product.Name]
+		if mismatch {
+			mismatches++
+		}
+
+		samples = append(samples, sample(boolValue(mismatch),
+			"product", product.Name,
+			"staged_version", product.Version,
+			"deployed_version", deployed[product.Name],
+		))
+	}
+
+	sort.SliceStable(samples, func(i, j int) bool {
+		return samples[i].labels[0].value < samples[j].labels[0].value
+	})
+
+	return []metricFamily{
+		gauge("om_product_version_mismatch", "Whether the staged version of a product is not deployed.", samples...),
+		gauge("om_product_version_mismatches", "Number of products whose staged version is not deployed.", sample(float64(mismatches))),
+	}
+}
+
+// metricsSnapshot serves the metrics of the last successful poll.
+type metricsSnapshot struct {
+	mutex    sync.RWMutex
+	families []metricFamily
+	up       bool
+	polledAt time.Time
+}
+
+func (s *metricsSnapshot) update(families []metricFamily, up bool, polledAt time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if up {
+		s.families = families
+	}
+	s.up = up
+	s.polledAt = polledAt
+}
+
+func (s *metricsSnapshot) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	families := []metricFamily{
+		gauge("om_up", "Whether the last poll of Ops Manager has succeeded.", sample(boolValue(s.up))),
+		gauge("om_last_poll_timestamp_seconds", "Time of the last poll of Ops Manager.", sample(float64(s.polledAt.Unix()))),
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = writeMetrics(w, append(families, s.families...))
+}
+
+func (ms MetricsServer) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description: "This authenticated command polls Ops Manager and serves Prometheus metrics about the health of the foundation." +
+			" This includes certificate expiry, pending changes, the last installation, staged versions that are not deployed, and the Ops Manager version.",
+		ShortDescription: "serves Prometheus metrics about the foundation",
+		Flags:            ms.Options,
+	}
+}
@@ -0,0 +1,204 @@
+package commands_test
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+)
+
+var _ = Describe("MetricsServer", func() {
+	var (
+		service *fakes.MetricsService
+		stdout  *gbytes.Buffer
+		logger  *log.Logger
+
+		servedAddr string
+		scrape     func(handler http.Handler) string
+		scraped    string
+	)
+
+	serve := func(addr string, handler http.Handler) error {
+		servedAddr = addr
+		scraped = scrape(handler)
+		return nil
+	}
+
+	get := func(handler http.Handler) string {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("text/plain; version=0.0.4; charset=utf-8"))
+		return recorder.Body.String()
+	}
+
+	BeforeEach(func() {
+		service = &fakes.MetricsService{}
+		stdout = gbytes.NewBuffer()
+		logger = log.New(stdout, "", 0)
+		scrape = get
+
+		startedAt := time.Unix(1577836800, 0)
+		finishedAt := startedAt.Add(90 * time.Minute)
+
+		service.InfoReturns(api.Info{Version: "2.8.0-build.1"}, nil)
+		service.ListExpiringCertificatesReturns([]api.ExpiringCertificate{
+			{
+				ProductGUID:       "cf-guid",
+				PropertyReference: ".properties.networking_poe_ssl_certs",
+				Location:          "ops_manager",
+				ValidUntil:        time.Unix(1609459200, 0),
+			},
+		}, nil)
+		service.ListStagedPendingChangesReturns(api.PendingChangesOutput{
+			ChangeList: []api.ProductChange{
+				{GUID: "p-bosh-guid", Action: "unchanged"},
+				{GUID: "cf-guid", Action: "update"},
+			},
+		}, nil)
+		service.ListInstallationsReturns([]api.InstallationsServiceOutput{
+			{ID: 2, Status: "failed", UserName: "admin", StartedAt: &startedAt, FinishedAt: &finishedAt},
+			{ID: 1, Status: "succeeded", UserName: "admin", StartedAt: &startedAt, FinishedAt: &startedAt},
+		}, nil)
+		service.GetDiagnosticReportReturns(api.DiagnosticReport{
+			StagedProducts: []api.DiagnosticProduct{
+				{Name: "p-bosh", Version: "2.8.0"},
+				{Name: "cf", Version: "2.8.1"},
+			},
+			DeployedProducts: []api.DiagnosticProduct{
+				{Name: "p-bosh", Version: "2.8.0"},
+				{Name: "cf", Version: "2.8.0"},
+			},
+		}, nil)
+	})
+
+	It("serves the metrics of the foundation", func() {
+		command := commands.NewMetricsServer(service, logger, serve)
+		err := command.Execute([]string{"--listen", "127.0.0.1:9999", "--expires-within", "1y"})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(servedAddr).To(Equal("127.0.0.1:9999"))
+		Expect(service.ListExpiringCertificatesArgsForCall(0)).To(Equal("1y"))
+		Expect(stdout).To(gbytes.Say("serving metrics on 127.0.0.1:9999/metrics every 60s"))
+
+		Expect(scraped).To(ContainSubstring("# HELP om_up Whether the last poll of Ops Manager has succeeded.\n# TYPE om_up gauge\nom_up 1\n"))
+		Expect(scraped).To(ContainSubstring("\nom_ops_manager_info{version=\"2.8.0-build.1\"} 1\n"))
+		Expect(scraped).To(ContainSubstring("\nom_certificate_expiry_timestamp_seconds{location=\"ops_manager\",product_guid=\"cf-guid\",property_reference=\".properties.networking_poe_ssl_certs\",variable_path=\"\"} 1609459200\n"))
+		Expect(scraped).To(ContainSubstring("\nom_product_pending_changes{product_guid=\"p-bosh-guid\",action=\"unchanged\"} 0\n"))
+		Expect(scraped).To(ContainSubstring("\nom_product_pending_changes{product_guid=\"cf-guid\",action=\"update\"} 1\n"))
+		Expect(scraped).To(ContainSubstring("\nom_pending_changes 1\n"))
+		Expect(scraped).To(ContainSubstring("\nom_last_installation_info{id=\"2\",status=\"failed\",user=\"admin\"} 1\n"))
+		Expect(scraped).To(ContainSubstring("\nom_last_installation_succeeded 0\n"))
+		Expect(scraped).To(ContainSubstring("\nom_last_installation_started_timestamp_seconds 1577836800\n"))
+		Expect(scraped).To(ContainSubstring("\nom_last_installation_finished_timestamp_seconds 1577842200\n"))
+		Expect(scraped).To(ContainSubstring("\nom_last_installation_duration_seconds 5400\n"))
+		Expect(scraped).To(ContainSubstring("\nom_product_version_mismatch{product=\"cf\",staged_version=\"2.8.1\",deployed_version=\"2.8.0\"} 1\n"))
+		Expect(scraped).To(ContainSubstring("\nom_product_version_mismatch{product=\"p-bosh\",staged_version=\"2.8.0\",deployed_version=\"2.8.0\"} 0\n"))
+		Expect(scraped).To(ContainSubstring("\nom_product_version_mismatches 1\n"))
+	})
+
+	It("reports products that have never been deployed as a mismatch", func() {
+		service.GetDiagnosticReportReturns(api.DiagnosticReport{
+			StagedProducts: []api.DiagnosticProduct{{Name: "p-healthwatch", Version: "1.8.0"}},
+		}, nil)
+
+		command := commands.NewMetricsServer(service, logger, serve)
+		err := command.Execute([]string{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(servedAddr).To(Equal(":9225"))
+		Expect(service.ListExpiringCertificatesArgsForCall(0)).To(Equal("3m"))
+		Expect(scraped).To(ContainSubstring("\nom_product_version_mismatch{product=\"p-healthwatch\",staged_version=\"1.8.0\",deployed_version=\"\"} 1\n"))
+	})
+
+	It("polls Ops Manager at the polling interval", func() {
+		scrape = func(handler http.Handler) string {
+			Eventually(service.InfoCallCount, 3*time.Second).Should(BeNumerically(">=", 2))
+			return get(handler)
+		}
+
+		command := commands.NewMetricsServer(service, logger, serve)
+		err := command.Execute([]string{"--polling-interval", "1"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(scraped).To(ContainSubstring("om_up 1\n"))
+	})
+
+	When("polling Ops Manager fails", func() {
+		It("serves the metrics of the last successful poll and reports that it is down", func() {
+			service.ListInstallationsReturnsOnCall(1, nil, errors.New("connection refused"))
+
+			scrape = func(handler http.Handler) string {
+				Eventually(service.ListInstallationsCallCount, 3*time.Second).Should(BeNumerically(">=", 2))
+				Eventually(func() string { return get(handler) }).Should(ContainSubstring("om_up 0\n"))
+				return get(handler)
+			}
+
+			command := commands.NewMetricsServer(service, logger, serve)
+			err := command.Execute([]string{"--polling-interval", "1"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(scraped).To(ContainSubstring("\nom_pending_changes 1\n"))
+			Expect(stdout).To(gbytes.Say("could not poll Ops Manager: could not fetch installations: connection refused"))
+		})
+
+		It("only serves whether it is up when the first poll fails", func() {
+			service.InfoReturns(api.Info{}, errors.New("connection refused"))
+
+			command := commands.NewMetricsServer(service, logger, serve)
+			err := command.Execute([]string{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(scraped).To(ContainSubstring("om_up 0\n"))
+			Expect(scraped).ToNot(ContainSubstring("om_ops_manager_info"))
+		})
+	})
+
+	It("returns the error of the server", func() {
+		command := commands.NewMetricsServer(service, logger, func(string, http.Handler) error {
+			return fmt.Errorf("address already in use")
+		})
+
+		err := command.Execute([]string{})
+		Expect(err).To(MatchError("address already in use"))
+	})
+
+	It("escapes the label values", func() {
+		service.InfoReturns(api.Info{Version: "a \"quoted\\\" \n version"}, nil)
+
+		command := commands.NewMetricsServer(service, logger, serve)
+		err := command.Execute([]string{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(scraped).To(ContainSubstring(`om_ops_manager_info{version="a \"quoted\\\" \n version"} 1`))
+	})
+
+	Describe("flags", func() {
+		It("returns an error when the flags cannot be parsed", func() {
+			command := commands.NewMetricsServer(service, logger, serve)
+			err := command.Execute([]string{"--unknown"})
+			Expect(err).To(MatchError("could not parse metrics-server flags: flag provided but not defined: -unknown"))
+		})
+
+		It("validates the polling interval", func() {
+			command := commands.NewMetricsServer(service, logger, serve)
+			err := command.Execute([]string{"--polling-interval", "0"})
+			Expect(err).To(MatchError("could not parse metrics-server flags: --polling-interval must be at least 1 second"))
+		})
+
+		It("validates the timeframe of the certificates", func() {
+			command := commands.NewMetricsServer(service, logger, serve)
+			err := command.Execute([]string{"--expires-within", "1s"})
+			Expect(err).To(MatchError(`could not parse metrics-server flags: only d,w,m, or y are supported. Default is "3m"`))
+			Expect(service.InfoCallCount()).To(Equal(0))
+		})
+	})
+})
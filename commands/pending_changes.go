@@ -14,7 +14,7 @@ type PendingChanges struct {
 	presenter presenters.FormattedPresenter
 	Options   struct {
 		Check  bool   `long:"check" description:"Exit 1 if there are any pending changes. Useful for validating that Ops Manager is in a clean state."`
-		Format string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json)"`
+		Format string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json,yaml,csv)"`
 	}
 }
 
@@ -35,6 +35,10 @@ func (pc PendingChanges) Execute(args []string) error {
 		return fmt.Errorf("could not parse pending-changes flags: %s", err)
 	}
 
+	if err := presenters.ValidateFormat(pc.Options.Format); err != nil {
+		return fmt.Errorf("could not parse pending-changes flags: %s", err)
+	}
+
 	output, err := pc.service.ListStagedPendingChanges()
 	if err != nil {
 		return fmt.Errorf("failed to retrieve pending changes %s", err)
@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/pivotal-cf/jhanda"
+)
+
+const pluginPrefix = "om-"
+
+// PluginGlobals are the global options of om, resolved from the flags,
+// the environment variables and the env file, which are passed to plugins.
+type PluginGlobals struct {
+	Target            string
+	CACert            string
+	SkipSSLValidation bool
+	ConnectTimeout    int
+	RequestTimeout    int
+
+	// Token is nil when no credentials have been given.
+	Token func() (string, error)
+}
+
+// Plugin runs an om-<name> executable found on the PATH
+// for a command that om does not have.
+type Plugin struct {
+	name       string
+	executable string
+	globals    PluginGlobals
+	environ    func() []string
+	stdin      io.Reader
+	stdout     io.Writer
+	stderr     io.Writer
+}
+
+func NewPlugin(name, executable string, globals PluginGlobals, environ func() []string, stdin io.Reader, stdout, stderr io.Writer) Plugin {
+	return Plugin{
+		name:       name,
+		executable: executable,
+		globals:    globals,
+		environ:    environ,
+		stdin:      stdin,
+		stdout:     stdout,
+		stderr:     stderr,
+	}
+}
+
+// PluginExecutable is the name of the executable on the PATH that provides the name command.
+func PluginExecutable(name string) string {
+	return pluginPrefix + name
+}
+
+// FindPlugins returns the executables named om-<name> in the directories of path, by name.
+// Like the shell, the first directory of path containing a plugin wins.
+func FindPlugins(path string) map[string]string {
+	plugins := map[string]string{}
+
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			continue
+		}
+
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, file := range files {
+			name := file.Name()
+			if !strings.HasPrefix(name, pluginPrefix) || file.IsDir() {
+				continue
+			}
+
+			if runtime.GOOS == "windows" {
+				if !strings.EqualFold(filepath.Ext(name), ".exe") {
+					continue
+				}
+				name = strings.TrimSuffix(name, filepath.Ext(name))
+			} else if file.Mode()&0111 == 0 {
+				continue
+			}
+
+			name = strings.TrimPrefix(name, pluginPrefix)
+			if _, ok := plugins[name]; name == "" || ok {
+				continue
+			}
+
+			plugins[name] = filepath.Join(dir, file.Name())
+		}
+	}
+
+	return plugins
+}
+
+func (p Plugin) Execute(args []string) error {
+	env := append(p.environ(),
+		"OM_TARGET="+p.globals.Target,
+		"OM_CA_CERT="+p.globals.CACert,
+		"OM_SKIP_SSL_VALIDATION="+strconv.FormatBool(p.globals.SkipSSLValidation),
+		"OM_CONNECT_TIMEOUT="+strconv.Itoa(p.globals.ConnectTimeout),
+		"OM_REQUEST_TIMEOUT="+strconv.Itoa(p.globals.RequestTimeout),
+	)
+
+	if p.globals.Token != nil {
+		token, err := p.globals.Token()
+		if err != nil {
+			return fmt.Errorf("could not authenticate the %s plugin: %s", p.name, err)
+		}
+
+		env = append(env, "OM_ACCESS_TOKEN="+token)
+	}
+
+	command := exec.Command(p.executable, args...)
+	command.Env = env
+	command.Stdin = p.stdin
+	command.Stdout = p.stdout
+	command.Stderr = p.stderr
+
+	err := command.Run()
+	if _, ok := err.(*exec.ExitError); ok {
+		// the plugin has reported its own error,
+		// and om exits with the same status
+		return err
+	}
+
+	if err != nil {
+		return fmt.Errorf("could not run the %s plugin: %s", p.name, err)
+	}
+
+	return nil
+}
+
+func (p Plugin) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description: fmt.Sprintf("This command runs the plugin %s.", p.executable) +
+			" The plugin receives the target, the CA certificate, the skip-ssl-validation and the timeouts of om" +
+			" as OM_TARGET, OM_CA_CERT, OM_SKIP_SSL_VALIDATION, OM_CONNECT_TIMEOUT and OM_REQUEST_TIMEOUT," +
+			" and an access token to the Ops Manager as OM_ACCESS_TOKEN when credentials have been given." +
+			fmt.Sprintf(" Run `om %s --help` for the usage of the plugin.", p.name),
+		ShortDescription: p.executable,
+	}
+}
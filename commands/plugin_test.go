@@ -0,0 +1,156 @@
+package commands_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+	"github.com/pivotal-cf/om/commands"
+)
+
+var _ = Describe("Plugin", func() {
+	var (
+		pluginDir string
+		stdout    *bytes.Buffer
+		stderr    *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		var err error
+		pluginDir, err = ioutil.TempDir("", "om-plugins")
+		Expect(err).ToNot(HaveOccurred())
+
+		stdout = &bytes.Buffer{}
+		stderr = &bytes.Buffer{}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(pluginDir)).To(Succeed())
+	})
+
+	writePlugin := func(dir, name string, mode os.FileMode) string {
+		path := filepath.Join(dir, name)
+		Expect(ioutil.WriteFile(path, []byte("#!/bin/sh\n"), mode)).To(Succeed())
+		return path
+	}
+
+	Describe("FindPlugins", func() {
+		It("finds the om-<name> executables on the path", func() {
+			otherDir, err := ioutil.TempDir("", "om-plugins")
+			Expect(err).ToNot(HaveOccurred())
+			defer os.RemoveAll(otherDir)
+
+			hello := writePlugin(pluginDir, "om-hello", 0755)
+			writePlugin(otherDir, "om-hello", 0755)
+			world := writePlugin(otherDir, "om-world", 0755)
+			writePlugin(pluginDir, "om-not-executable", 0644)
+			writePlugin(pluginDir, "not-a-plugin", 0755)
+			Expect(os.Mkdir(filepath.Join(pluginDir, "om-directory"), 0755)).To(Succeed())
+
+			plugins := commands.FindPlugins(strings.Join([]string{pluginDir, "", "/does/not/exist", otherDir}, string(os.PathListSeparator)))
+			Expect(plugins).To(Equal(map[string]string{
+				"hello": hello,
+				"world": world,
+			}))
+		})
+	})
+
+	Describe("Execute", func() {
+		var stub string
+
+		BeforeEach(func() {
+			stubPath, err := gexec.Build("github.com/pivotal-cf/om/stub")
+			Expect(err).ToNot(HaveOccurred())
+
+			stub = filepath.Join(pluginDir, "om-hello")
+			Expect(os.Link(stubPath, stub)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			gexec.CleanupBuildArtifacts()
+		})
+
+		environ := func() []string {
+			return []string{"PATH=/usr/bin", "OM_TARGET=overridden"}
+		}
+
+		It("runs the plugin with the arguments and the global options of om", func() {
+			plugin := commands.NewPlugin("hello", stub, commands.PluginGlobals{
+				Target:            "https://opsman.example.com",
+				CACert:            "some-ca-cert",
+				SkipSSLValidation: true,
+				ConnectTimeout:    10,
+				RequestTimeout:    1800,
+				Token: func() (string, error) {
+					return "some-token", nil
+				},
+			}, environ, strings.NewReader(""), stdout, stderr)
+
+			err := plugin.Execute([]string{"--some-flag", "some-arg"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(stderr.String()).To(ContainSubstring("om-hello --some-flag some-arg\n"))
+			Expect(stderr.String()).To(ContainSubstring("PATH=/usr/bin "))
+			Expect(stderr.String()).To(ContainSubstring(" OM_TARGET=https://opsman.example.com "))
+			Expect(stderr.String()).To(ContainSubstring(" OM_CA_CERT=some-ca-cert "))
+			Expect(stderr.String()).To(ContainSubstring(" OM_SKIP_SSL_VALIDATION=true "))
+			Expect(stderr.String()).To(ContainSubstring(" OM_CONNECT_TIMEOUT=10 "))
+			Expect(stderr.String()).To(ContainSubstring(" OM_REQUEST_TIMEOUT=1800 "))
+			Expect(stderr.String()).To(ContainSubstring(" OM_ACCESS_TOKEN=some-token"))
+			Expect(stderr.String()).ToNot(ContainSubstring("OM_TARGET=overridden"))
+		})
+
+		It("does not pass a token when no credentials have been given", func() {
+			plugin := commands.NewPlugin("hello", stub, commands.PluginGlobals{}, environ, strings.NewReader(""), stdout, stderr)
+
+			err := plugin.Execute([]string{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(stderr.String()).ToNot(ContainSubstring("OM_ACCESS_TOKEN"))
+		})
+
+		It("returns the exit error of the plugin", func() {
+			plugin := commands.NewPlugin("hello", stub, commands.PluginGlobals{}, func() []string {
+				return []string{"STUB_ERROR_CODE=3"}
+			}, strings.NewReader(""), stdout, stderr)
+
+			err := plugin.Execute([]string{})
+			Expect(err).To(BeAssignableToTypeOf(&exec.ExitError{}))
+			Expect(err.(*exec.ExitError).ExitCode()).To(Equal(3))
+		})
+
+		It("returns an error when the token cannot be retrieved", func() {
+			plugin := commands.NewPlugin("hello", stub, commands.PluginGlobals{
+				Token: func() (string, error) {
+					return "", errors.New("some error")
+				},
+			}, environ, strings.NewReader(""), stdout, stderr)
+
+			err := plugin.Execute([]string{})
+			Expect(err).To(MatchError("could not authenticate the hello plugin: some error"))
+			Expect(stderr.String()).To(BeEmpty())
+		})
+
+		It("returns an error when the plugin cannot be run", func() {
+			plugin := commands.NewPlugin("hello", filepath.Join(pluginDir, "om-missing"), commands.PluginGlobals{}, environ, strings.NewReader(""), stdout, stderr)
+
+			err := plugin.Execute([]string{})
+			Expect(err).To(MatchError(ContainSubstring("could not run the hello plugin")))
+		})
+	})
+
+	Describe("Usage", func() {
+		It("describes the plugin with its executable", func() {
+			plugin := commands.NewPlugin("hello", "/usr/local/bin/om-hello", commands.PluginGlobals{}, os.Environ, nil, nil, nil)
+			Expect(plugin.Usage().ShortDescription).To(Equal("/usr/local/bin/om-hello"))
+			Expect(plugin.Usage().Description).To(ContainSubstring("This command runs the plugin /usr/local/bin/om-hello."))
+		})
+	})
+})
@@ -18,6 +18,13 @@ type PreDeployCheck struct {
 	}
 }
 
+// preDeployCheckOutput is the document printed with --format json.
+type preDeployCheckOutput struct {
+	Complete bool                 `json:"complete"`
+	Director api.PreDeployCheck   `json:"director"`
+	Products []api.PreDeployCheck `json:"products"`
+}
+
 //counterfeiter:generate -o ./fakes/pre_deploy_check_service.go --fake-name PreDeployCheckService . preDeployCheckService
 type preDeployCheckService interface {
 	Info() (api.Info, error)
@@ -37,10 +44,16 @@ func (pc PreDeployCheck) Execute(args []string) error {
 	var errorBuffer []string
 
 	if _, err := jhanda.Parse(&pc.Options, args); err != nil {
-		return fmt.Errorf("could not parse pending-changes flags: %s", err)
+		return fmt.Errorf("could not parse pre-deploy-check flags: %s", err)
 	}
 
-	pc.logger.Println("Scanning OpsManager now ...\n")
+	if err := presenters.ValidateFormat(pc.Options.Format, tableFormat, jsonFormat); err != nil {
+		return fmt.Errorf("could not parse pre-deploy-check flags: %s", err)
+	}
+
+	if pc.Options.Format != jsonFormat {
+		pc.logger.Println("Scanning OpsManager now ...\n")
+	}
 
 	info, err := pc.service.Info()
 	if err != nil {
@@ -55,6 +68,15 @@ func (pc PreDeployCheck) Execute(args []string) error {
 		return fmt.Errorf("while getting director: %s", err)
 	}
 
+	pendingProductChanges, err := pc.service.ListAllPendingProductChanges()
+	if err != nil {
+		return fmt.Errorf("while getting products: %s", err)
+	}
+
+	if pc.Options.Format == jsonFormat {
+		return pc.printJSON(pendingDirectorChanges, pendingProductChanges)
+	}
+
 	directorOk := pendingDirectorChanges.EndpointResults.Complete
 	if !directorOk {
 		errs := pc.determineDirectorErrors(pendingDirectorChanges)
@@ -63,11 +85,6 @@ func (pc PreDeployCheck) Execute(args []string) error {
 		pc.logger.Printf(color.GreenString("[✓] director: %s", pendingDirectorChanges.EndpointResults.Identifier))
 	}
 
-	pendingProductChanges, err := pc.service.ListAllPendingProductChanges()
-	if err != nil {
-		return fmt.Errorf("while getting products: %s", err)
-	}
-
 	for _, change := range pendingProductChanges {
 		if change.EndpointResults.Identifier == pendingDirectorChanges.EndpointResults.Identifier {
 			continue
@@ -91,6 +108,34 @@ func (pc PreDeployCheck) Execute(args []string) error {
 	return nil
 }
 
+func (pc PreDeployCheck) printJSON(director api.PendingDirectorChangesOutput, products []api.PendingProductChangesOutput) error {
+	output := preDeployCheckOutput{
+		Complete: director.EndpointResults.Complete,
+		Director: director.EndpointResults,
+		Products: []api.PreDeployCheck{},
+	}
+
+	for _, change := range products {
+		if change.EndpointResults.Identifier == director.EndpointResults.Identifier {
+			continue
+		}
+
+		output.Complete = output.Complete && change.EndpointResults.Complete
+		output.Products = append(output.Products, change.EndpointResults)
+	}
+
+	err := printJSON(pc.logger, output)
+	if err != nil {
+		return err
+	}
+
+	if !output.Complete {
+		return fmt.Errorf("OpsManager is not fully configured")
+	}
+
+	return nil
+}
+
 var boldError = color.New(color.Bold)
 
 func (pc PreDeployCheck) determineDirectorErrors(directorOutput api.PendingDirectorChangesOutput) []string {
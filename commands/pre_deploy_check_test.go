@@ -404,4 +404,70 @@ var _ = Describe("PreDeployCheck.Execute", func() {
 			Expect(err).To(HaveOccurred())
 		}
 	})
+
+	When("the format is json", func() {
+		It("prints the checks of the director and products without any other output", func() {
+			service.ListPendingDirectorChangesReturns(api.PendingDirectorChangesOutput{
+				EndpointResults: api.PreDeployCheck{
+					Identifier: "p-bosh-guid",
+					Complete:   true,
+					Network:    api.PreDeployNetwork{Assigned: true},
+				},
+			}, nil)
+			service.ListAllPendingProductChangesReturns([]api.PendingProductChangesOutput{
+				{EndpointResults: api.PreDeployCheck{Identifier: "p-bosh-guid", Complete: true}},
+				{EndpointResults: api.PreDeployCheck{
+					Identifier: "cf-guid",
+					Complete:   false,
+					Verifiers: []api.PreDeployVerifier{{
+						Type:   "WildcardDomainVerifier",
+						Errors: []string{"dns is bad"},
+					}},
+				}},
+			}, nil)
+
+			command := commands.NewPreDeployCheck(presenter, service, logger)
+			err := command.Execute([]string{"--format", "json"})
+			Expect(err).To(MatchError("OpsManager is not fully configured"))
+
+			Expect(stdout.Contents()).To(MatchJSON(`{
+				"complete": false,
+				"director": {
+					"identifier": "p-bosh-guid",
+					"complete": true,
+					"network": {"assigned": true},
+					"availability_zone": {"assigned": false},
+					"stemcells": null,
+					"properties": null,
+					"resources": {"jobs": null},
+					"verifiers": null
+				},
+				"products": [{
+					"identifier": "cf-guid",
+					"complete": false,
+					"network": {"assigned": false},
+					"availability_zone": {"assigned": false},
+					"stemcells": null,
+					"properties": null,
+					"resources": {"jobs": null},
+					"verifiers": [{"type": "WildcardDomainVerifier", "errors": ["dns is bad"], "ignorable": false}]
+				}]
+			}`))
+		})
+
+		It("does not return an error when everything is complete", func() {
+			command := commands.NewPreDeployCheck(presenter, service, logger)
+			err := command.Execute([]string{"--format", "json"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(stdout.Contents())).ToNot(ContainSubstring("Scanning OpsManager"))
+		})
+	})
+
+	When("the format is not supported", func() {
+		It("returns an error", func() {
+			command := commands.NewPreDeployCheck(presenter, service, logger)
+			err := command.Execute([]string{"--format", "yaml"})
+			Expect(err).To(MatchError(`could not parse pre-deploy-check flags: unsupported format "yaml", expected one of table, json`))
+		})
+	})
 })
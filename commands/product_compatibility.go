@@ -0,0 +1,188 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/configtemplate/generator"
+)
+
+//counterfeiter:generate -o ./fakes/product_compatibility_service.go --fake-name ProductCompatibilityService . productCompatibilityService
+type productCompatibilityService interface {
+	Info() (api.Info, error)
+	ListStemcells() (api.ProductStemcells, error)
+	ListMultiStemcells() (api.ProductMultiStemcells, error)
+	ListDeployedProducts() ([]api.DeployedProductOutput, error)
+}
+
+type productCompatibility struct {
+	MissingStemcells []string
+	MissingProducts  []string
+}
+
+func (pc productCompatibility) Compatible() bool {
+	return len(pc.MissingStemcells) == 0 && len(pc.MissingProducts) == 0
+}
+
+func (pc productCompatibility) report(logger logger, productName, productVersion string) {
+	if pc.Compatible() {
+		logger.Printf("product %s %s has all required stemcells and products available", productName, productVersion)
+		return
+	}
+
+	if len(pc.MissingStemcells) > 0 {
+		logger.Printf("product %s %s requires stemcells that have not been uploaded:\n  %s", productName, productVersion, strings.Join(pc.MissingStemcells, "\n  "))
+	}
+
+	if len(pc.MissingProducts) > 0 {
+		logger.Printf("product %s %s requires products that have not been deployed:\n  %s", productName, productVersion, strings.Join(pc.MissingProducts, "\n  "))
+	}
+}
+
+type uploadedStemcell struct {
+	os      string
+	version string
+}
+
+func checkProductCompatibility(service productCompatibilityService, metadata *generator.Metadata) (productCompatibility, error) {
+	var result productCompatibility
+
+	stemcells, err := listUploadedStemcells(service)
+	if err != nil {
+		return result, err
+	}
+
+	for _, criterion := range metadata.StemcellsCriteria() {
+		var found bool
+		for _, stemcell := range stemcells {
+			if stemcellSatisfiesCriteria(criterion, stemcell) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			missing := fmt.Sprintf("%s %s", criterion.OS, criterion.Version)
+			if criterion.EnablePatchSecurityUpdates {
+				missing = fmt.Sprintf("%s (or a later patch)", missing)
+			}
+			result.MissingStemcells = append(result.MissingStemcells, missing)
+		}
+	}
+
+	if len(metadata.RequiresProductVersions) == 0 {
+		return result, nil
+	}
+
+	deployedProducts, err := service.ListDeployedProducts()
+	if err != nil {
+		return result, fmt.Errorf("could not list deployed products: %s", err)
+	}
+
+	for _, requirement := range metadata.RequiresProductVersions {
+		constraint, err := version.NewConstraint(requirement.Version)
+		if err != nil {
+			return result, fmt.Errorf("could not parse version requirement '%s' for product %s: %s", requirement.Version, requirement.Name, err)
+		}
+
+		var found bool
+		for _, deployed := range deployedProducts {
+			if deployed.Type != requirement.Name {
+				continue
+			}
+
+			deployedVersion, err := version.NewVersion(strings.SplitN(deployed.ProductVersion, "-", 2)[0])
+			if err != nil {
+				continue
+			}
+
+			if constraint.Check(deployedVersion) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			result.MissingProducts = append(result.MissingProducts, fmt.Sprintf("%s %s", requirement.Name, requirement.Version))
+		}
+	}
+
+	return result, nil
+}
+
+func listUploadedStemcells(service productCompatibilityService) ([]uploadedStemcell, error) {
+	info, err := service.Info()
+	if err != nil {
+		return nil, fmt.Errorf("cannot retrieve version of Ops Manager: %s", err)
+	}
+
+	multiStemcellSupport, err := info.VersionAtLeast(2, 6)
+	if err != nil {
+		return nil, fmt.Errorf("could not determine Ops Manager version: %s", err)
+	}
+
+	var stemcells []uploadedStemcell
+
+	if !multiStemcellSupport {
+		productStemcells, err := service.ListStemcells()
+		if err != nil {
+			return nil, fmt.Errorf("could not list stemcells: %s", err)
+		}
+
+		for _, product := range productStemcells.Products {
+			for _, available := range product.AvailableVersions {
+				stemcells = append(stemcells, uploadedStemcell{version: available})
+			}
+		}
+
+		return stemcells, nil
+	}
+
+	productStemcells, err := service.ListMultiStemcells()
+	if err != nil {
+		return nil, fmt.Errorf("could not list stemcells: %s", err)
+	}
+
+	for _, entry := range productStemcells.StemcellLibrary {
+		stemcells = append(stemcells, uploadedStemcell{os: entry.OS, version: entry.Version})
+	}
+
+	for _, product := range productStemcells.Products {
+		for _, available := range product.AvailableVersions {
+			stemcells = append(stemcells, uploadedStemcell{os: available.OS, version: available.Version})
+		}
+	}
+
+	return stemcells, nil
+}
+
+// stemcellSatisfiesCriteria follows the Ops Manager rules for stemcell_criteria:
+// the OS must match, and the version must match exactly unless patch security updates
+// are enabled, in which case any later version on the same major line is accepted.
+func stemcellSatisfiesCriteria(criteria generator.StemcellCriteria, stemcell uploadedStemcell) bool {
+	if stemcell.os != "" && criteria.OS != "" && stemcell.os != criteria.OS {
+		return false
+	}
+
+	if stemcell.version == criteria.Version {
+		return true
+	}
+
+	if !criteria.EnablePatchSecurityUpdates {
+		return false
+	}
+
+	required, err := version.NewVersion(criteria.Version)
+	if err != nil {
+		return false
+	}
+
+	available, err := version.NewVersion(stemcell.version)
+	if err != nil {
+		return false
+	}
+
+	return required.Segments()[0] == available.Segments()[0] && available.GreaterThanOrEqual(required)
+}
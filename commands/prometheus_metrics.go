@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// metricFamily is a metric in the Prometheus text exposition format.
+// See https://prometheus.io/docs/instrumenting/exposition_formats/
+type metricFamily struct {
+	name    string
+	help    string
+	samples []metricSample
+}
+
+type metricSample struct {
+	labels []metricLabel
+	value  float64
+}
+
+type metricLabel struct {
+	name  string
+	value string
+}
+
+func gauge(name, help string, samples ...metricSample) metricFamily {
+	return metricFamily{
+		name:    name,
+		help:    help,
+		samples: samples,
+	}
+}
+
+func sample(value float64, labels ...string) metricSample {
+	s := metricSample{value: value}
+	for i := 0; i+1 < len(labels); i += 2 {
+		s.labels = append(s.labels, metricLabel{name: labels[i], value: labels[i+1]})
+	}
+
+	return s
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func writeMetrics(w io.Writer, families []metricFamily) error {
+	for _, family := range families {
+		_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", family.name, family.help, family.name)
+		if err != nil {
+			return err
+		}
+
+		for _, s := range family.samples {
+			var labels []string
+			for _, label := range s.labels {
+				labels = append(labels, fmt.Sprintf(`%s="%s"`, label.name, labelValueEscaper.Replace(label.value)))
+			}
+
+			name := family.name
+			if len(labels) > 0 {
+				name = fmt.Sprintf("%s{%s}", name, strings.Join(labels, ","))
+			}
+
+			_, err = fmt.Fprintf(w, "%s %s\n", name, strconv.FormatFloat(s.value, 'f', -1, 64))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
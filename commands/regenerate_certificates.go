@@ -1,10 +1,18 @@
 package commands
 
-import "github.com/pivotal-cf/jhanda"
+import (
+	"fmt"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/presenters"
+)
 
 type RegenerateCertificates struct {
 	service regenerateCertificatesService
 	logger  logger
+	Options struct {
+		Format string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json)"`
+	}
 }
 
 //counterfeiter:generate -o ./fakes/regenerate_certificates_service.go --fake-name RegenerateCertificatesService . regenerateCertificatesService
@@ -16,12 +24,24 @@ func NewRegenerateCertificates(service regenerateCertificatesService, logger log
 	return RegenerateCertificates{service: service, logger: logger}
 }
 
-func (r RegenerateCertificates) Execute(_ []string) error {
+func (r RegenerateCertificates) Execute(args []string) error {
+	if _, err := jhanda.Parse(&r.Options, args); err != nil {
+		return fmt.Errorf("could not parse regenerate-certificates flags: %s", err)
+	}
+
+	if err := presenters.ValidateFormat(r.Options.Format, tableFormat, jsonFormat); err != nil {
+		return fmt.Errorf("could not parse regenerate-certificates flags: %s", err)
+	}
+
 	err := r.service.RegenerateCertificates()
 	if err != nil {
 		return err
 	}
 
+	if r.Options.Format == jsonFormat {
+		return printJSON(r.logger, map[string]bool{"certificates_regenerated": true})
+	}
+
 	r.logger.Printf("Certificates regenerated.\n")
 
 	return nil
@@ -31,5 +51,6 @@ func (r RegenerateCertificates) Usage() jhanda.Usage {
 	return jhanda.Usage{
 		Description:      "This authenticated command deletes all non-configurable certificates in Ops Manager so they will automatically be regenerated on the next apply-changes",
 		ShortDescription: "deletes all non-configurable certificates in Ops Manager so they will automatically be regenerated on the next apply-changes",
+		Flags:            r.Options,
 	}
 }
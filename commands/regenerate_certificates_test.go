@@ -33,6 +33,25 @@ var _ = Describe("RegenerateCertificates", func() {
 			format, content := fakeLogger.PrintfArgsForCall(0)
 			Expect(fmt.Sprintf(format, content...)).To(Equal("Certificates regenerated.\n"))
 		})
+
+		When("the format is json", func() {
+			It("prints the result as json", func() {
+				err := command.Execute([]string{"--format", "json"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeService.RegenerateCertificatesCallCount()).To(Equal(1))
+
+				Expect(fakeLogger.PrintfCallCount()).To(Equal(0))
+				Expect(fakeLogger.PrintlnArgsForCall(0)[0]).To(MatchJSON(`{"certificates_regenerated": true}`))
+			})
+		})
+
+		When("the format is not supported", func() {
+			It("returns an error", func() {
+				err := command.Execute([]string{"--format", "yaml"})
+				Expect(err).To(MatchError(`could not parse regenerate-certificates flags: unsupported format "yaml", expected one of table, json`))
+				Expect(fakeService.RegenerateCertificatesCallCount()).To(Equal(0))
+			})
+		})
 	})
 
 	Describe("Usage", func() {
@@ -41,6 +60,7 @@ var _ = Describe("RegenerateCertificates", func() {
 			Expect(usage).To(Equal(jhanda.Usage{
 				Description:      "This authenticated command deletes all non-configurable certificates in Ops Manager so they will automatically be regenerated on the next apply-changes",
 				ShortDescription: "deletes all non-configurable certificates in Ops Manager so they will automatically be regenerated on the next apply-changes",
+				Flags:            command.Options,
 			}))
 		})
 	})
@@ -0,0 +1,452 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	rotateStepGenerate         = "generate-certificate-authority"
+	rotateStepApplyNewCA       = "apply-changes-with-new-certificate-authority"
+	rotateStepActivate         = "activate-certificate-authority"
+	rotateStepRegenerate       = "regenerate-certificates"
+	rotateStepApplyRegenerated = "apply-changes-with-regenerated-certificates"
+	rotateStepDeleteOldCA      = "delete-certificate-authority"
+	rotateStepDone             = "done"
+)
+
+var rotateSteps = []string{
+	rotateStepGenerate,
+	rotateStepApplyNewCA,
+	rotateStepActivate,
+	rotateStepRegenerate,
+	rotateStepApplyRegenerated,
+	rotateStepDeleteOldCA,
+}
+
+//counterfeiter:generate -o ./fakes/rotate_certificate_authority_service.go --fake-name RotateCertificateAuthorityService . rotateCertificateAuthorityService
+type rotateCertificateAuthorityService interface {
+	applyChangesService
+	ListCertificateAuthorities() (api.CertificateAuthoritiesOutput, error)
+	GenerateCertificateAuthority() (api.CA, error)
+	ActivateCertificateAuthority(api.ActivateCertificateAuthorityInput) error
+	RegenerateCertificates() error
+	DeleteCertificateAuthority(api.DeleteCertificateAuthorityInput) error
+}
+
+// rotationState is the progress of a rotation.
+// It is written to the state file after every step,
+// so an interrupted rotation can be resumed.
+type rotationState struct {
+	Step           string    `yaml:"step"`
+	OldCAGUID      string    `yaml:"old_certificate_authority_guid,omitempty"`
+	NewCAGUID      string    `yaml:"new_certificate_authority_guid,omitempty"`
+	InstallationID int       `yaml:"installation_id,omitempty"`
+	StartedAt      time.Time `yaml:"started_at,omitempty"`
+}
+
+type RotateCertificateAuthority struct {
+	service      rotateCertificateAuthorityService
+	logOutput    io.Writer
+	logger       logger
+	waitDuration time.Duration
+	Options      struct {
+		StateFile      string `long:"state-file"      short:"s" default:"rotate-certificate-authority-state.yml" description:"path to the file that records the progress of the rotation"`
+		IgnoreWarnings bool   `long:"ignore-warnings" short:"i" description:"ignore issues reported by Ops Manager when applying changes"`
+	}
+}
+
+func NewRotateCertificateAuthority(service rotateCertificateAuthorityService, logOutput io.Writer, logger logger, waitDuration time.Duration) RotateCertificateAuthority {
+	return RotateCertificateAuthority{
+		service:      service,
+		logOutput:    logOutput,
+		logger:       logger,
+		waitDuration: waitDuration,
+	}
+}
+
+func (r RotateCertificateAuthority) Execute(args []string) error {
+	if _, err := jhanda.Parse(&r.Options, args); err != nil {
+		return fmt.Errorf("could not parse rotate-certificate-authority flags: %s", err)
+	}
+
+	state, err := r.loadState()
+	if err != nil {
+		return err
+	}
+
+	if state.OldCAGUID != "" {
+		r.logger.Printf("resuming the rotation from %s at step %s\n", r.Options.StateFile, state.Step)
+	}
+
+	for state.Step != rotateStepDone {
+		r.logger.Printf("[%d/%d] %s\n", stepNumber(state.Step), len(rotateSteps), state.Step)
+
+		cas, err := r.service.ListCertificateAuthorities()
+		if err != nil {
+			return fmt.Errorf("could not list certificate authorities: %s", err)
+		}
+
+		err = r.verify(state, cas.CAs)
+		if err != nil {
+			return fmt.Errorf("cannot %s: %s", state.Step, err)
+		}
+
+		next, err := r.run(state, cas.CAs)
+		if err != nil {
+			return fmt.Errorf("could not %s: %s", state.Step, err)
+		}
+
+		err = r.saveState(next)
+		if err != nil {
+			return err
+		}
+
+		state = next
+	}
+
+	err = os.Remove(r.Options.StateFile)
+	if err != nil {
+		return fmt.Errorf("could not remove the state file: %s", err)
+	}
+
+	r.logger.Printf("certificate authority '%s' has been rotated to '%s'\n", state.OldCAGUID, state.NewCAGUID)
+
+	return nil
+}
+
+// verify checks that the certificate authorities are in the state
+// that the step expects, before the step changes anything.
+func (r RotateCertificateAuthority) verify(state rotationState, cas []api.CA) error {
+	var active []api.CA
+	var inactive []api.CA
+	for _, ca := range cas {
+		if ca.Active {
+			active = append(active, ca)
+		} else {
+			inactive = append(inactive, ca)
+		}
+	}
+
+	if len(active) != 1 {
+		return fmt.Errorf("expected exactly one active certificate authority, found %d", len(active))
+	}
+
+	switch state.Step {
+	case rotateStepGenerate:
+		if state.OldCAGUID == "" {
+			if len(inactive) > 0 {
+				return fmt.Errorf("found inactive certificate authority '%s', delete it with delete-certificate-authority before rotating", inactive[0].GUID)
+			}
+
+			return nil
+		}
+
+		if active[0].GUID != state.OldCAGUID {
+			return fmt.Errorf("expected certificate authority '%s' to be active, found '%s'", state.OldCAGUID, active[0].GUID)
+		}
+
+		generated := createdSince(inactive, state.StartedAt)
+		if len(generated) != len(inactive) || len(generated) > 1 {
+			return fmt.Errorf("found %d inactive certificate authorities, expected at most the one generated since %s, delete the others with delete-certificate-authority before rotating", len(inactive), state.StartedAt.Format(time.RFC3339))
+		}
+
+		return nil
+
+	case rotateStepActivate:
+		if active[0].GUID == state.NewCAGUID {
+			return nil
+		}
+
+		fallthrough
+
+	case rotateStepApplyNewCA:
+		if active[0].GUID != state.OldCAGUID {
+			return fmt.Errorf("expected certificate authority '%s' to be active, found '%s'", state.OldCAGUID, active[0].GUID)
+		}
+
+		if !containsCA(inactive, state.NewCAGUID) {
+			return fmt.Errorf("expected certificate authority '%s' to exist and be inactive", state.NewCAGUID)
+		}
+
+		return nil
+
+	case rotateStepRegenerate, rotateStepApplyRegenerated:
+		if active[0].GUID != state.NewCAGUID {
+			return fmt.Errorf("expected certificate authority '%s' to be active, found '%s'", state.NewCAGUID, active[0].GUID)
+		}
+
+		return nil
+
+	case rotateStepDeleteOldCA:
+		if active[0].GUID != state.NewCAGUID {
+			return fmt.Errorf("expected certificate authority '%s' to be active, found '%s'", state.NewCAGUID, active[0].GUID)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("unknown step %q", state.Step)
+}
+
+// run executes the step and returns the state of the next step.
+// Activating and deleting are skipped when they have already happened,
+// in case the rotation was interrupted before the state file was written.
+func (r RotateCertificateAuthority) run(state rotationState, cas []api.CA) (rotationState, error) {
+	next := state
+	next.Step = nextStep(state.Step)
+
+	switch state.Step {
+	case rotateStepGenerate:
+		// the state is recorded before generating, so that a resumed rotation
+		// adopts the generated certificate authority instead of generating another one
+		if state.OldCAGUID == "" {
+			state.OldCAGUID = activeCA(cas)
+			state.StartedAt = time.Now().UTC()
+
+			err := r.saveState(state)
+			if err != nil {
+				return state, err
+			}
+
+			next.OldCAGUID = state.OldCAGUID
+			next.StartedAt = state.StartedAt
+		}
+
+		var inactive []api.CA
+		for _, ca := range cas {
+			if !ca.Active {
+				inactive = append(inactive, ca)
+			}
+		}
+
+		if generated := createdSince(inactive, state.StartedAt); len(generated) == 1 {
+			next.NewCAGUID = generated[0].GUID
+			r.logger.Printf("Certificate authority '%s' is already generated\n", generated[0].GUID)
+			break
+		}
+
+		ca, err := r.service.GenerateCertificateAuthority()
+		if err != nil {
+			return state, err
+		}
+
+		next.NewCAGUID = ca.GUID
+		r.logger.Printf("Certificate authority '%s' generated\n", ca.GUID)
+
+	case rotateStepApplyNewCA, rotateStepApplyRegenerated:
+		err := r.applyChanges(state)
+		if err != nil {
+			return state, err
+		}
+
+		next.InstallationID = 0
+
+	case rotateStepActivate:
+		if activeCA(cas) == state.NewCAGUID {
+			r.logger.Printf("Certificate authority '%s' is already active\n", state.NewCAGUID)
+			break
+		}
+
+		err := r.service.ActivateCertificateAuthority(api.ActivateCertificateAuthorityInput{GUID: state.NewCAGUID})
+		if err != nil {
+			return state, err
+		}
+
+		r.logger.Printf("Certificate authority '%s' activated\n", state.NewCAGUID)
+
+	case rotateStepRegenerate:
+		err := r.service.RegenerateCertificates()
+		if err != nil {
+			return state, err
+		}
+
+		r.logger.Printf("Certificates regenerated.\n")
+
+	case rotateStepDeleteOldCA:
+		if !containsCA(cas, state.OldCAGUID) {
+			r.logger.Printf("Certificate authority '%s' is already deleted\n", state.OldCAGUID)
+			break
+		}
+
+		err := r.service.DeleteCertificateAuthority(api.DeleteCertificateAuthorityInput{GUID: state.OldCAGUID})
+		if err != nil {
+			return state, err
+		}
+
+		r.logger.Printf("Certificate authority '%s' deleted\n", state.OldCAGUID)
+	}
+
+	return next, nil
+}
+
+// applyChanges deploys all products and waits for the installation.
+// The installation is recorded in the state file first,
+// so a resumed rotation re-attaches to it instead of starting another one.
+func (r RotateCertificateAuthority) applyChanges(state rotationState) error {
+	applyChanges := NewApplyChanges(r.service, nil, NewLogWriter(r.logOutput), r.logger, r.waitDuration)
+
+	if state.InstallationID != 0 {
+		installation, err := r.service.GetInstallation(state.InstallationID)
+		if err != nil {
+			return fmt.Errorf("installation failed to get status: %s", err)
+		}
+
+		if installation.Status != api.StatusFailed {
+			r.logger.Printf("re-attaching to installation %d\n", state.InstallationID)
+			return r.waitForInstallation(applyChanges, state, installation)
+		}
+	}
+
+	running, err := r.service.RunningInstallation()
+	if err != nil {
+		return fmt.Errorf("could not check for any already running installation: %s", err)
+	}
+
+	if running != (api.InstallationsServiceOutput{}) {
+		return fmt.Errorf("installation %d is already running, wait for it to finish and rerun this command", running.ID)
+	}
+
+	r.logger.Printf("attempting to apply changes to the targeted Ops Manager")
+	installation, err := r.service.CreateInstallation(r.Options.IgnoreWarnings, true, nil, api.ApplyErrandChanges{})
+	if err != nil {
+		return fmt.Errorf("installation failed to trigger: %s", err)
+	}
+
+	state.InstallationID = installation.ID
+	err = r.saveState(state)
+	if err != nil {
+		return err
+	}
+
+	return r.waitForInstallation(applyChanges, state, installation)
+}
+
+func (r RotateCertificateAuthority) waitForInstallation(applyChanges ApplyChanges, state rotationState, installation api.InstallationsServiceOutput) error {
+	err := applyChanges.waitForApplyChangesCompletion(installation)
+	if err != nil {
+		state.InstallationID = 0
+		if saveErr := r.saveState(state); saveErr != nil {
+			return saveErr
+		}
+
+		return fmt.Errorf("%s, fix the installation and rerun this command to apply changes again", err)
+	}
+
+	return nil
+}
+
+func (r RotateCertificateAuthority) loadState() (rotationState, error) {
+	contents, err := ioutil.ReadFile(r.Options.StateFile)
+	if os.IsNotExist(err) {
+		return rotationState{Step: rotateStepGenerate}, nil
+	}
+	if err != nil {
+		return rotationState{}, fmt.Errorf("could not read the state file: %s", err)
+	}
+
+	var state rotationState
+	err = yaml.UnmarshalStrict(contents, &state)
+	if err != nil {
+		return rotationState{}, fmt.Errorf("could not parse the state file %s: %s", r.Options.StateFile, err)
+	}
+
+	if stepNumber(state.Step) == 0 {
+		return rotationState{}, fmt.Errorf("could not parse the state file %s: unknown step %q", r.Options.StateFile, state.Step)
+	}
+
+	return state, nil
+}
+
+func (r RotateCertificateAuthority) saveState(state rotationState) error {
+	contents, err := yaml.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(r.Options.StateFile, contents, 0600)
+	if err != nil {
+		return fmt.Errorf("could not write the state file: %s", err)
+	}
+
+	return nil
+}
+
+func stepNumber(step string) int {
+	for i, s := range rotateSteps {
+		if s == step {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+func nextStep(step string) string {
+	number := stepNumber(step)
+	if number == len(rotateSteps) {
+		return rotateStepDone
+	}
+
+	return rotateSteps[number]
+}
+
+func activeCA(cas []api.CA) string {
+	for _, ca := range cas {
+		if ca.Active {
+			return ca.GUID
+		}
+	}
+
+	return ""
+}
+
+// createdSince returns the certificate authorities created at or after the time.
+// Ops Manager may only give the date of created_on, which is then compared with the date of the time.
+func createdSince(cas []api.CA, since time.Time) []api.CA {
+	var created []api.CA
+	for _, ca := range cas {
+		if createdOn, err := time.Parse(time.RFC3339, ca.CreatedOn); err == nil {
+			if !createdOn.Before(since) {
+				created = append(created, ca)
+			}
+			continue
+		}
+
+		if createdOn, err := time.Parse("2006-01-02", ca.CreatedOn); err == nil {
+			if !createdOn.Before(since.UTC().Truncate(24 * time.Hour)) {
+				created = append(created, ca)
+			}
+		}
+	}
+
+	return created
+}
+
+func containsCA(cas []api.CA, guid string) bool {
+	for _, ca := range cas {
+		if ca.GUID == guid {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r RotateCertificateAuthority) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description: "This authenticated command rotates the certificate authority of the Ops Manager." +
+			" It generates a new certificate authority, applies changes, activates it, regenerates the certificates," +
+			" applies changes again, and deletes the old certificate authority." +
+			" The progress is recorded in --state-file, so the rotation can be resumed by running the command again.",
+		ShortDescription: "rotates the certificate authority of the Ops Manager",
+		Flags:            r.Options,
+	}
+}
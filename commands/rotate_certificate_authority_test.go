@@ -0,0 +1,389 @@
+package commands_test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+	"gopkg.in/yaml.v2"
+)
+
+var _ = Describe("RotateCertificateAuthority", func() {
+	var (
+		service   *fakes.RotateCertificateAuthorityService
+		logger    *fakes.Logger
+		logOutput *gbytes.Buffer
+		stateDir  string
+		stateFile string
+		cas       []api.CA
+		command   commands.RotateCertificateAuthority
+	)
+
+	logs := func() string {
+		var lines string
+		for i := 0; i < logger.PrintfCallCount(); i++ {
+			format, args := logger.PrintfArgsForCall(i)
+			lines += fmt.Sprintf(format, args...)
+		}
+		return lines
+	}
+
+	writeState := func(contents string) {
+		Expect(ioutil.WriteFile(stateFile, []byte(contents), 0600)).To(Succeed())
+	}
+
+	readState := func() string {
+		contents, err := ioutil.ReadFile(stateFile)
+		Expect(err).ToNot(HaveOccurred())
+		return string(contents)
+	}
+
+	BeforeEach(func() {
+		var err error
+		stateDir, err = ioutil.TempDir("", "rotate-ca")
+		Expect(err).ToNot(HaveOccurred())
+		stateFile = filepath.Join(stateDir, "state.yml")
+
+		logger = &fakes.Logger{}
+		logOutput = gbytes.NewBuffer()
+		service = &fakes.RotateCertificateAuthorityService{}
+
+		cas = []api.CA{{GUID: "old-guid", Active: true}}
+
+		service.ListCertificateAuthoritiesStub = func() (api.CertificateAuthoritiesOutput, error) {
+			return api.CertificateAuthoritiesOutput{CAs: append([]api.CA{}, cas...)}, nil
+		}
+		service.GenerateCertificateAuthorityStub = func() (api.CA, error) {
+			ca := api.CA{GUID: "new-guid"}
+			cas = append(cas, ca)
+			return ca, nil
+		}
+		service.ActivateCertificateAuthorityStub = func(input api.ActivateCertificateAuthorityInput) error {
+			for i := range cas {
+				cas[i].Active = cas[i].GUID == input.GUID
+			}
+			return nil
+		}
+		service.DeleteCertificateAuthorityStub = func(input api.DeleteCertificateAuthorityInput) error {
+			var remaining []api.CA
+			for _, ca := range cas {
+				if ca.GUID != input.GUID {
+					remaining = append(remaining, ca)
+				}
+			}
+			cas = remaining
+			return nil
+		}
+		service.CreateInstallationStub = func(bool, bool, []string, api.ApplyErrandChanges) (api.InstallationsServiceOutput, error) {
+			return api.InstallationsServiceOutput{ID: 10 + service.CreateInstallationCallCount()}, nil
+		}
+		service.GetInstallationStub = func(id int) (api.InstallationsServiceOutput, error) {
+			return api.InstallationsServiceOutput{ID: id, Status: api.StatusSucceeded}, nil
+		}
+		service.GetInstallationLogsStub = func(id int) (api.InstallationsServiceOutput, error) {
+			return api.InstallationsServiceOutput{ID: id, Logs: fmt.Sprintf("logs of installation %d\n", id)}, nil
+		}
+
+		command = commands.NewRotateCertificateAuthority(service, logOutput, logger, 0)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(stateDir)).To(Succeed())
+	})
+
+	It("runs every step of the rotation in order", func() {
+		err := command.Execute([]string{"--state-file", stateFile})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(service.GenerateCertificateAuthorityCallCount()).To(Equal(1))
+		Expect(service.CreateInstallationCallCount()).To(Equal(2))
+		ignoreWarnings, deployProducts, productNames, _ := service.CreateInstallationArgsForCall(0)
+		Expect(ignoreWarnings).To(BeFalse())
+		Expect(deployProducts).To(BeTrue())
+		Expect(productNames).To(BeEmpty())
+
+		Expect(service.ActivateCertificateAuthorityArgsForCall(0)).To(Equal(api.ActivateCertificateAuthorityInput{GUID: "new-guid"}))
+		Expect(service.RegenerateCertificatesCallCount()).To(Equal(1))
+		Expect(service.DeleteCertificateAuthorityArgsForCall(0)).To(Equal(api.DeleteCertificateAuthorityInput{GUID: "old-guid"}))
+		Expect(cas).To(Equal([]api.CA{{GUID: "new-guid", Active: true}}))
+
+		Expect(service.ListCertificateAuthoritiesCallCount()).To(Equal(6))
+
+		Expect(logs()).To(Equal(
+			"[1/6] generate-certificate-authority\n" +
+				"Certificate authority 'new-guid' generated\n" +
+				"[2/6] apply-changes-with-new-certificate-authority\n" +
+				"attempting to apply changes to the targeted Ops Manager" +
+				"[3/6] activate-certificate-authority\n" +
+				"Certificate authority 'new-guid' activated\n" +
+				"[4/6] regenerate-certificates\n" +
+				"Certificates regenerated.\n" +
+				"[5/6] apply-changes-with-regenerated-certificates\n" +
+				"attempting to apply changes to the targeted Ops Manager" +
+				"[6/6] delete-certificate-authority\n" +
+				"Certificate authority 'old-guid' deleted\n" +
+				"certificate authority 'old-guid' has been rotated to 'new-guid'\n",
+		))
+		Expect(logOutput).To(gbytes.Say("logs of installation 11"))
+		Expect(logOutput).To(gbytes.Say("logs of installation 12"))
+
+		Expect(stateFile).ToNot(BeAnExistingFile())
+	})
+
+	It("passes --ignore-warnings to apply changes", func() {
+		err := command.Execute([]string{"--state-file", stateFile, "--ignore-warnings"})
+		Expect(err).ToNot(HaveOccurred())
+
+		ignoreWarnings, _, _, _ := service.CreateInstallationArgsForCall(1)
+		Expect(ignoreWarnings).To(BeTrue())
+	})
+
+	When("a step fails", func() {
+		It("records the progress so the rotation can be resumed", func() {
+			service.RegenerateCertificatesReturns(errors.New("server error"))
+
+			err := command.Execute([]string{"--state-file", stateFile})
+			Expect(err).To(MatchError("could not regenerate-certificates: server error"))
+
+			var state map[string]interface{}
+			Expect(yaml.Unmarshal([]byte(readState()), &state)).To(Succeed())
+			Expect(state).To(HaveKeyWithValue("step", "regenerate-certificates"))
+			Expect(state).To(HaveKeyWithValue("old_certificate_authority_guid", "old-guid"))
+			Expect(state).To(HaveKeyWithValue("new_certificate_authority_guid", "new-guid"))
+			Expect(state).To(HaveKey("started_at"))
+
+			info, err := os.Stat(stateFile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+			service.RegenerateCertificatesReturns(nil)
+			command = commands.NewRotateCertificateAuthority(service, logOutput, logger, 0)
+			err = command.Execute([]string{"--state-file", stateFile})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(service.GenerateCertificateAuthorityCallCount()).To(Equal(1))
+			Expect(service.ActivateCertificateAuthorityCallCount()).To(Equal(1))
+			Expect(service.RegenerateCertificatesCallCount()).To(Equal(2))
+			Expect(service.CreateInstallationCallCount()).To(Equal(2))
+			Expect(service.DeleteCertificateAuthorityCallCount()).To(Equal(1))
+			Expect(logs()).To(ContainSubstring("resuming the rotation from " + stateFile + " at step regenerate-certificates\n"))
+			Expect(stateFile).ToNot(BeAnExistingFile())
+		})
+	})
+
+	When("resuming while an installation is recorded", func() {
+		BeforeEach(func() {
+			cas = []api.CA{{GUID: "old-guid", Active: true}, {GUID: "new-guid"}}
+			writeState(`{step: apply-changes-with-new-certificate-authority, old_certificate_authority_guid: old-guid, new_certificate_authority_guid: new-guid, installation_id: 5}`)
+		})
+
+		It("re-attaches to the installation instead of applying changes again", func() {
+			service.GetInstallationStub = nil
+			service.GetInstallationReturns(api.InstallationsServiceOutput{Status: api.StatusSucceeded}, nil)
+			service.GetInstallationReturnsOnCall(0, api.InstallationsServiceOutput{ID: 5, Status: api.StatusRunning}, nil)
+			service.GetInstallationReturnsOnCall(1, api.InstallationsServiceOutput{ID: 5, Status: api.StatusRunning}, nil)
+
+			err := command.Execute([]string{"--state-file", stateFile})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(service.GetInstallationArgsForCall(0)).To(Equal(5))
+			Expect(service.GetInstallationArgsForCall(1)).To(Equal(5))
+			Expect(service.GetInstallationArgsForCall(2)).To(Equal(5))
+			Expect(logs()).To(ContainSubstring("re-attaching to installation 5\n"))
+
+			Expect(service.GenerateCertificateAuthorityCallCount()).To(Equal(0))
+			Expect(service.CreateInstallationCallCount()).To(Equal(1))
+		})
+
+		It("applies changes again when the installation has failed", func() {
+			service.GetInstallationStub = nil
+			service.GetInstallationReturns(api.InstallationsServiceOutput{Status: api.StatusSucceeded}, nil)
+			service.GetInstallationReturnsOnCall(0, api.InstallationsServiceOutput{ID: 5, Status: api.StatusFailed}, nil)
+
+			err := command.Execute([]string{"--state-file", stateFile})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(service.CreateInstallationCallCount()).To(Equal(2))
+		})
+	})
+
+	When("the installation fails", func() {
+		It("forgets the installation so that the next run applies changes again", func() {
+			service.GetInstallationStub = nil
+			service.GetInstallationReturns(api.InstallationsServiceOutput{Status: api.StatusFailed}, nil)
+
+			err := command.Execute([]string{"--state-file", stateFile})
+			Expect(err).To(MatchError("could not apply-changes-with-new-certificate-authority: installation was unsuccessful, fix the installation and rerun this command to apply changes again"))
+
+			var state map[string]interface{}
+			Expect(yaml.Unmarshal([]byte(readState()), &state)).To(Succeed())
+			Expect(state).To(HaveKeyWithValue("step", "apply-changes-with-new-certificate-authority"))
+			Expect(state).ToNot(HaveKey("installation_id"))
+		})
+	})
+
+	When("an installation is already running", func() {
+		It("does not start another one", func() {
+			service.RunningInstallationReturns(api.InstallationsServiceOutput{ID: 3, Status: api.StatusRunning}, nil)
+
+			err := command.Execute([]string{"--state-file", stateFile})
+			Expect(err).To(MatchError("could not apply-changes-with-new-certificate-authority: installation 3 is already running, wait for it to finish and rerun this command"))
+			Expect(service.CreateInstallationCallCount()).To(Equal(0))
+		})
+	})
+
+	When("the rotation was interrupted after a step but before the state was recorded", func() {
+		It("does not activate the certificate authority again", func() {
+			cas = []api.CA{{GUID: "old-guid"}, {GUID: "new-guid", Active: true}}
+			writeState(`{step: activate-certificate-authority, old_certificate_authority_guid: old-guid, new_certificate_authority_guid: new-guid}`)
+
+			err := command.Execute([]string{"--state-file", stateFile})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(service.ActivateCertificateAuthorityCallCount()).To(Equal(0))
+			Expect(logs()).To(ContainSubstring("Certificate authority 'new-guid' is already active\n"))
+		})
+
+		It("does not delete the old certificate authority again", func() {
+			cas = []api.CA{{GUID: "new-guid", Active: true}}
+			writeState(`{step: delete-certificate-authority, old_certificate_authority_guid: old-guid, new_certificate_authority_guid: new-guid}`)
+
+			err := command.Execute([]string{"--state-file", stateFile})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(service.DeleteCertificateAuthorityCallCount()).To(Equal(0))
+			Expect(logs()).To(ContainSubstring("Certificate authority 'old-guid' is already deleted\n"))
+		})
+	})
+
+	When("the rotation was interrupted while generating the certificate authority", func() {
+		It("records the old certificate authority before generating", func() {
+			service.GenerateCertificateAuthorityReturns(api.CA{}, errors.New("server error"))
+
+			err := command.Execute([]string{"--state-file", stateFile})
+			Expect(err).To(MatchError("could not generate-certificate-authority: server error"))
+
+			var state map[string]interface{}
+			Expect(yaml.Unmarshal([]byte(readState()), &state)).To(Succeed())
+			Expect(state).To(HaveKeyWithValue("step", "generate-certificate-authority"))
+			Expect(state).To(HaveKeyWithValue("old_certificate_authority_guid", "old-guid"))
+			Expect(state).To(HaveKey("started_at"))
+		})
+
+		It("adopts the certificate authority generated since the rotation started", func() {
+			cas = append(cas, api.CA{GUID: "generated-guid", CreatedOn: "2030-01-02"})
+			writeState(`{step: generate-certificate-authority, old_certificate_authority_guid: old-guid, started_at: 2030-01-02T03:04:05Z}`)
+
+			err := command.Execute([]string{"--state-file", stateFile})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(service.GenerateCertificateAuthorityCallCount()).To(Equal(0))
+			Expect(service.ActivateCertificateAuthorityArgsForCall(0)).To(Equal(api.ActivateCertificateAuthorityInput{GUID: "generated-guid"}))
+			Expect(logs()).To(ContainSubstring("resuming the rotation from " + stateFile + " at step generate-certificate-authority\n"))
+			Expect(logs()).To(ContainSubstring("Certificate authority 'generated-guid' is already generated\n"))
+		})
+
+		It("generates the certificate authority when none has been generated", func() {
+			writeState(`{step: generate-certificate-authority, old_certificate_authority_guid: old-guid, started_at: 2030-01-02T03:04:05Z}`)
+
+			err := command.Execute([]string{"--state-file", stateFile})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(service.GenerateCertificateAuthorityCallCount()).To(Equal(1))
+			Expect(service.ActivateCertificateAuthorityArgsForCall(0)).To(Equal(api.ActivateCertificateAuthorityInput{GUID: "new-guid"}))
+		})
+
+		It("does not adopt a certificate authority created before the rotation started", func() {
+			cas = append(cas, api.CA{GUID: "other-guid", CreatedOn: "2030-01-01"})
+			writeState(`{step: generate-certificate-authority, old_certificate_authority_guid: old-guid, started_at: 2030-01-02T03:04:05Z}`)
+
+			err := command.Execute([]string{"--state-file", stateFile})
+			Expect(err).To(MatchError("cannot generate-certificate-authority: found 1 inactive certificate authorities, expected at most the one generated since 2030-01-02T03:04:05Z, delete the others with delete-certificate-authority before rotating"))
+			Expect(service.GenerateCertificateAuthorityCallCount()).To(Equal(0))
+		})
+
+		It("does not choose between certificate authorities generated since the rotation started", func() {
+			cas = append(cas,
+				api.CA{GUID: "generated-guid", CreatedOn: "2030-01-02T04:00:00Z"},
+				api.CA{GUID: "other-guid", CreatedOn: "2030-01-03"},
+			)
+			writeState(`{step: generate-certificate-authority, old_certificate_authority_guid: old-guid, started_at: 2030-01-02T03:04:05Z}`)
+
+			err := command.Execute([]string{"--state-file", stateFile})
+			Expect(err).To(MatchError(ContainSubstring("found 2 inactive certificate authorities")))
+			Expect(service.GenerateCertificateAuthorityCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("verifying the certificate authorities before each step", func() {
+		It("does not generate a certificate authority when there is an inactive one", func() {
+			cas = append(cas, api.CA{GUID: "other-guid"})
+
+			err := command.Execute([]string{"--state-file", stateFile})
+			Expect(err).To(MatchError("cannot generate-certificate-authority: found inactive certificate authority 'other-guid', delete it with delete-certificate-authority before rotating"))
+			Expect(service.GenerateCertificateAuthorityCallCount()).To(Equal(0))
+			Expect(stateFile).ToNot(BeAnExistingFile())
+		})
+
+		It("does not regenerate the certificates when the new certificate authority is not active", func() {
+			cas = []api.CA{{GUID: "old-guid", Active: true}, {GUID: "new-guid"}}
+			writeState(`{step: regenerate-certificates, old_certificate_authority_guid: old-guid, new_certificate_authority_guid: new-guid}`)
+
+			err := command.Execute([]string{"--state-file", stateFile})
+			Expect(err).To(MatchError("cannot regenerate-certificates: expected certificate authority 'new-guid' to be active, found 'old-guid'"))
+			Expect(service.RegenerateCertificatesCallCount()).To(Equal(0))
+		})
+
+		It("does not apply changes when the new certificate authority is missing", func() {
+			writeState(`{step: apply-changes-with-new-certificate-authority, old_certificate_authority_guid: old-guid, new_certificate_authority_guid: new-guid}`)
+
+			err := command.Execute([]string{"--state-file", stateFile})
+			Expect(err).To(MatchError("cannot apply-changes-with-new-certificate-authority: expected certificate authority 'new-guid' to exist and be inactive"))
+			Expect(service.CreateInstallationCallCount()).To(Equal(0))
+		})
+
+		It("requires exactly one active certificate authority", func() {
+			cas = []api.CA{{GUID: "old-guid"}}
+
+			err := command.Execute([]string{"--state-file", stateFile})
+			Expect(err).To(MatchError("cannot generate-certificate-authority: expected exactly one active certificate authority, found 0"))
+		})
+
+		It("returns an error when the certificate authorities cannot be listed", func() {
+			service.ListCertificateAuthoritiesStub = nil
+			service.ListCertificateAuthoritiesReturns(api.CertificateAuthoritiesOutput{}, errors.New("server error"))
+
+			err := command.Execute([]string{"--state-file", stateFile})
+			Expect(err).To(MatchError("could not list certificate authorities: server error"))
+		})
+	})
+
+	When("the state file is invalid", func() {
+		It("returns an error for an unknown step", func() {
+			writeState(`{step: unknown}`)
+
+			err := command.Execute([]string{"--state-file", stateFile})
+			Expect(err).To(MatchError(fmt.Sprintf(`could not parse the state file %s: unknown step "unknown"`, stateFile)))
+		})
+
+		It("returns an error for unknown keys", func() {
+			writeState(`{step: regenerate-certificates, unknown: key}`)
+
+			err := command.Execute([]string{"--state-file", stateFile})
+			Expect(err).To(MatchError(ContainSubstring("could not parse the state file " + stateFile)))
+		})
+	})
+
+	It("returns an error when the flags cannot be parsed", func() {
+		err := command.Execute([]string{"--unknown"})
+		Expect(err).To(MatchError("could not parse rotate-certificate-authority flags: flag provided but not defined: -unknown"))
+	})
+})
@@ -0,0 +1,215 @@
+package commands
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// socks5 implements the CONNECT command of a SOCKS5 server without authentication.
+// See https://tools.ietf.org/html/rfc1928
+const (
+	socks5Version = 0x05
+
+	socks5NoAuthentication    = 0x00
+	socks5NoAcceptableMethods = 0xff
+
+	socks5CommandConnect = 0x01
+
+	socks5AddressIPv4   = 0x01
+	socks5AddressDomain = 0x03
+	socks5AddressIPv6   = 0x04
+
+	socks5Succeeded               = 0x00
+	socks5GeneralFailure          = 0x01
+	socks5CommandNotSupported     = 0x07
+	socks5AddressTypeNotSupported = 0x08
+)
+
+type socks5Dialer func(network, address string) (net.Conn, error)
+
+// halfCloser is implemented by *net.TCPConn and the ssh channels,
+// which can stop writing while still reading the rest of the response.
+type halfCloser interface {
+	CloseWrite() error
+}
+
+// serveSOCKS5 accepts connections until the listener is closed,
+// and dials every requested address with dial.
+// Temporary accept errors, such as running out of file descriptors, are retried with a backoff.
+func serveSOCKS5(listener net.Listener, dial socks5Dialer, logger logger) error {
+	var backoff time.Duration
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				if backoff == 0 {
+					backoff = 5 * time.Millisecond
+				} else {
+					backoff *= 2
+				}
+				if backoff > time.Second {
+					backoff = time.Second
+				}
+
+				logger.Printf("could not accept connection, retrying in %s: %s\n", backoff, err)
+				time.Sleep(backoff)
+				continue
+			}
+
+			return err
+		}
+		backoff = 0
+
+		go func() {
+			err := handleSOCKS5(conn, dial)
+			if err != nil {
+				logger.Printf("could not proxy connection from %s: %s\n", conn.RemoteAddr(), err)
+			}
+		}()
+	}
+}
+
+func handleSOCKS5(conn net.Conn, dial socks5Dialer) error {
+	defer conn.Close()
+
+	err := socks5Negotiate(conn)
+	if err != nil {
+		return err
+	}
+
+	address, reply, err := socks5ReadRequest(conn)
+	if err != nil {
+		if reply != socks5Succeeded {
+			_ = socks5Reply(conn, reply)
+		}
+		return err
+	}
+
+	target, err := dial("tcp", address)
+	if err != nil {
+		_ = socks5Reply(conn, socks5GeneralFailure)
+		return fmt.Errorf("could not connect to %s: %s", address, err)
+	}
+	defer target.Close()
+
+	err = socks5Reply(conn, socks5Succeeded)
+	if err != nil {
+		return err
+	}
+
+	// each direction only closes the writing side of its destination when its source is done,
+	// so a response can still be read after the client has sent everything;
+	// both connections are closed once both directions are done
+	var wg sync.WaitGroup
+	wg.Add(2)
+	pipe := func(dst, src net.Conn) {
+		defer wg.Done()
+		_, _ = io.Copy(dst, src)
+		if hc, ok := dst.(halfCloser); ok {
+			_ = hc.CloseWrite()
+		} else {
+			_ = dst.Close()
+		}
+	}
+	go pipe(target, conn)
+	go pipe(conn, target)
+	wg.Wait()
+
+	return nil
+}
+
+func socks5Negotiate(conn net.Conn) error {
+	header := make([]byte, 2)
+	_, err := io.ReadFull(conn, header)
+	if err != nil {
+		return err
+	}
+
+	if header[0] != socks5Version {
+		return fmt.Errorf("unsupported SOCKS version %d", header[0])
+	}
+
+	methods := make([]byte, header[1])
+	_, err = io.ReadFull(conn, methods)
+	if err != nil {
+		return err
+	}
+
+	for _, method := range methods {
+		if method == socks5NoAuthentication {
+			_, err = conn.Write([]byte{socks5Version, socks5NoAuthentication})
+			return err
+		}
+	}
+
+	_, _ = conn.Write([]byte{socks5Version, socks5NoAcceptableMethods})
+	return errors.New("the client does not support connecting without authentication")
+}
+
+// socks5ReadRequest returns the address to connect to,
+// or the reply to send when the request is not supported.
+func socks5ReadRequest(conn net.Conn) (string, byte, error) {
+	header := make([]byte, 4)
+	_, err := io.ReadFull(conn, header)
+	if err != nil {
+		return "", socks5Succeeded, err
+	}
+
+	if header[1] != socks5CommandConnect {
+		return "", socks5CommandNotSupported, fmt.Errorf("unsupported SOCKS command %d", header[1])
+	}
+
+	var host string
+	switch header[3] {
+	case socks5AddressIPv4, socks5AddressIPv6:
+		ip := make([]byte, net.IPv4len)
+		if header[3] == socks5AddressIPv6 {
+			ip = make([]byte, net.IPv6len)
+		}
+
+		_, err = io.ReadFull(conn, ip)
+		if err != nil {
+			return "", socks5Succeeded, err
+		}
+
+		host = net.IP(ip).String()
+
+	case socks5AddressDomain:
+		length := make([]byte, 1)
+		_, err = io.ReadFull(conn, length)
+		if err != nil {
+			return "", socks5Succeeded, err
+		}
+
+		domain := make([]byte, length[0])
+		_, err = io.ReadFull(conn, domain)
+		if err != nil {
+			return "", socks5Succeeded, err
+		}
+
+		host = string(domain)
+
+	default:
+		return "", socks5AddressTypeNotSupported, fmt.Errorf("unsupported SOCKS address type %d", header[3])
+	}
+
+	port := make([]byte, 2)
+	_, err = io.ReadFull(conn, port)
+	if err != nil {
+		return "", socks5Succeeded, err
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))), socks5Succeeded, nil
+}
+
+// socks5Reply does not report the bound address, as the connections are tunneled.
+func socks5Reply(conn net.Conn, reply byte) error {
+	_, err := conn.Write([]byte{socks5Version, reply, 0x00, socks5AddressIPv4, 0, 0, 0, 0, 0, 0})
+	return err
+}
@@ -12,7 +12,7 @@ type SSLCertificate struct {
 	service   sslCertificateService
 	presenter presenters.FormattedPresenter
 	Options   struct {
-		Format string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json)"`
+		Format string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json,yaml,csv)"`
 	}
 }
 
@@ -34,6 +34,10 @@ func (c SSLCertificate) Execute(args []string) error {
 		return fmt.Errorf("could not parse ssl-certificate flags: %s", err)
 	}
 
+	if err := presenters.ValidateFormat(c.Options.Format); err != nil {
+		return fmt.Errorf("could not parse ssl-certificate flags: %s", err)
+	}
+
 	certOutput, err := c.service.GetSSLCertificate()
 	if err != nil {
 		return err
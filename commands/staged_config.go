@@ -16,7 +16,7 @@ type StagedConfig struct {
 	service stagedConfigService
 	logger  logger
 	Options struct {
-		Product             string `long:"product-name" short:"p" required:"true" description:"name of product"`
+		Product             string `long:"product-name" short:"p" required:"true" description:"name of product" complete:"products"`
 		IncludeCredentials  bool   `long:"include-credentials" short:"c" description:"include credentials. note: requires product to have been deployed"`
 		IncludePlaceholders bool   `long:"include-placeholders" short:"r" description:"replace obscured credentials with interpolatable placeholders"`
 	}
@@ -49,15 +49,30 @@ func (ec StagedConfig) Execute(args []string) error {
 		return fmt.Errorf("could not parse staged-config flags: %s", err)
 	}
 
-	info, err := ec.service.Info()
+	productConfig, err := ec.productConfiguration()
 	if err != nil {
 		return err
 	}
 
+	output, err := yaml.Marshal(productConfig)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal config: %s", err) // un-tested
+	}
+
+	ec.logger.Println(string(output))
+	return nil
+}
+
+func (ec StagedConfig) productConfiguration() (config.ProductConfiguration, error) {
+	info, err := ec.service.Info()
+	if err != nil {
+		return config.ProductConfiguration{}, err
+	}
+
 	if ec.Options.IncludeCredentials {
 		deployedProducts, err := ec.service.ListDeployedProducts()
 		if err != nil {
-			return err
+			return config.ProductConfiguration{}, err
 		}
 		var productDeployed bool
 		for _, p := range deployedProducts {
@@ -67,19 +82,19 @@ func (ec StagedConfig) Execute(args []string) error {
 			}
 		}
 		if !productDeployed {
-			return fmt.Errorf("cannot retrieve credentials for product '%s': deploy the product and retry", ec.Options.Product)
+			return config.ProductConfiguration{}, fmt.Errorf("cannot retrieve credentials for product '%s': deploy the product and retry", ec.Options.Product)
 		}
 	}
 
 	findOutput, err := ec.service.GetStagedProductByName(ec.Options.Product)
 	if err != nil {
-		return err
+		return config.ProductConfiguration{}, err
 	}
 	productGUID := findOutput.Product.GUID
 
 	properties, err := ec.service.GetStagedProductProperties(productGUID)
 	if err != nil {
-		return err
+		return config.ProductConfiguration{}, err
 	}
 
 	configurableProperties := map[string]interface{}{}
@@ -103,7 +118,7 @@ func (ec StagedConfig) Execute(args []string) error {
 		output, err = parser.ParseProperties(propertyName, property, ec.chooseCredentialHandler(productGUID))
 
 		if err != nil {
-			return err
+			return config.ProductConfiguration{}, err
 		}
 		if output != nil && len(output) > 0 {
 			configurableProperties[name] = output
@@ -123,24 +138,24 @@ func (ec StagedConfig) Execute(args []string) error {
 
 	networks, err := ec.service.GetStagedProductNetworksAndAZs(productGUID)
 	if err != nil {
-		return err
+		return config.ProductConfiguration{}, err
 	}
 
 	jobs, err := ec.service.ListStagedProductJobs(productGUID)
 	if err != nil {
-		return err
+		return config.ProductConfiguration{}, err
 	}
 
 	jobsToMaxInFlight, err := ec.service.GetStagedProductJobMaxInFlight(productGUID)
 	if err != nil {
-		return err
+		return config.ProductConfiguration{}, err
 	}
 
 	var syslogProperties map[string]interface{}
 	if ok, _ := info.VersionAtLeast(2, 4); ok {
 		syslogProperties, err = ec.service.GetStagedProductSyslogConfiguration(productGUID)
 		if err != nil {
-			return err
+			return config.ProductConfiguration{}, err
 		}
 	}
 
@@ -149,7 +164,7 @@ func (ec StagedConfig) Execute(args []string) error {
 	for name, jobGUID := range jobs {
 		jobProperties, err := ec.service.GetStagedProductJobResourceConfig(productGUID, jobGUID)
 		if err != nil {
-			return err
+			return config.ProductConfiguration{}, err
 		}
 		rc := config.ResourceConfig{
 			JobProperties: jobProperties,
@@ -162,7 +177,7 @@ func (ec StagedConfig) Execute(args []string) error {
 
 	errandsListOutput, err := ec.service.ListStagedProductErrands(productGUID)
 	if err != nil {
-		return err
+		return config.ProductConfiguration{}, err
 	}
 
 	errandConfigs := map[string]config.ErrandConfig{}
@@ -175,22 +190,14 @@ func (ec StagedConfig) Execute(args []string) error {
 		errandConfigs[errand.Name] = errandConfig
 	}
 
-	config := config.ProductConfiguration{
+	return config.ProductConfiguration{
 		ProductName:              ec.Options.Product,
 		ProductProperties:        configurableProperties,
 		NetworkProperties:        networks,
 		ResourceConfigProperties: resourceConfig,
 		ErrandConfigs:            errandConfigs,
 		SyslogProperties:         syslogProperties,
-	}
-
-	output, err := yaml.Marshal(config)
-	if err != nil {
-		return fmt.Errorf("failed to unmarshal config: %s", err) // un-tested
-	}
-
-	ec.logger.Println(string(output))
-	return nil
+	}, nil
 }
 
 func (ec StagedConfig) chooseCredentialHandler(productGUID string) configparser.CredentialHandler {
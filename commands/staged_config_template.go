@@ -0,0 +1,379 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/configtemplate/generator"
+	"github.com/pivotal-cf/om/configtemplate/metadata"
+	"github.com/pivotal-cf/om/interpolate"
+	"gopkg.in/yaml.v2"
+)
+
+type StagedConfigTemplate struct {
+	service       stagedConfigService
+	buildProvider stagedConfigTemplateBuildProvider
+	logger        logger
+	Options       struct {
+		Product            string `long:"product-name"        short:"p" required:"true" description:"name of the staged product" complete:"products"`
+		IncludeCredentials bool   `long:"include-credentials" short:"c"                 description:"include credentials in the vars file. note: requires product to have been deployed"`
+
+		PivnetApiToken    string `long:"pivnet-api-token"                                                                                                   `
+		PivnetProductSlug string `long:"pivnet-product-slug"           description:"the product name in pivnet"                                             `
+		ProductVersion    string `long:"product-version"               description:"the version of the product in pivnet matching the staged product"       `
+		PivnetFileGlob    string `long:"pivnet-file-glob"    short:"f" description:"a glob to match exactly one file in the pivnet product slug"  default:"*.pivotal"`
+		PivnetDisableSSL  bool   `long:"pivnet-disable-ssl"            description:"whether to disable ssl validation when contacting the Pivotal Network"`
+
+		ProductPath string `long:"product-path" description:"path to the product file matching the staged product"`
+
+		OutputDirectory string `long:"output-directory" required:"true" description:"a directory to create templates under. must already exist."`
+		ExcludeVersion  bool   `long:"exclude-version"                  description:"if set, will not output a version-specific directory"`
+	}
+}
+
+type stagedConfigTemplateBuildProvider func(*StagedConfigTemplate) MetadataProvider
+
+var DefaultStagedConfigTemplateProvider = func() func(c *StagedConfigTemplate) MetadataProvider {
+	return func(c *StagedConfigTemplate) MetadataProvider {
+		options := c.Options
+		if options.ProductPath != "" {
+			return metadata.NewFileProvider(options.ProductPath)
+		}
+		return metadata.NewPivnetProvider(pivnetHost, options.PivnetApiToken, options.PivnetProductSlug, options.ProductVersion, options.PivnetFileGlob, options.PivnetDisableSSL)
+	}
+}
+
+var placeholderRegexp = regexp.MustCompile(`^\(\(([^()]+)\)\)$`)
+
+type templateOps struct {
+	Type  string      `yaml:"type"`
+	Path  string      `yaml:"path"`
+	Value interface{} `yaml:"value"`
+}
+
+func NewStagedConfigTemplate(service stagedConfigService, bp stagedConfigTemplateBuildProvider, logger logger) *StagedConfigTemplate {
+	return &StagedConfigTemplate{
+		service:       service,
+		buildProvider: bp,
+		logger:        logger,
+	}
+}
+
+func (s *StagedConfigTemplate) Execute(args []string) error {
+	if _, err := jhanda.Parse(&s.Options, args); err != nil {
+		return fmt.Errorf("could not parse staged-config-template flags: %s", err)
+	}
+
+	_, err := os.Stat(s.Options.OutputDirectory)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("output-directory does not exist: %s", s.Options.OutputDirectory)
+	}
+
+	err = validateMetadataSource(s.Options.PivnetApiToken, s.Options.PivnetProductSlug, s.Options.ProductVersion, s.Options.ProductPath)
+	if err != nil {
+		return err
+	}
+
+	metadataBytes, err := s.buildProvider(s).MetadataBytes()
+	if err != nil {
+		return fmt.Errorf("error getting metadata for %s at version %s: %s", s.Options.PivnetProductSlug, s.Options.ProductVersion, err)
+	}
+
+	productMetadata, err := generator.NewMetadata(metadataBytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse product metadata: %s", err)
+	}
+
+	if productMetadata.Name != s.Options.Product {
+		return fmt.Errorf("the product metadata is for %s, not the staged product %s", productMetadata.Name, s.Options.Product)
+	}
+
+	stagedConfig := NewStagedConfig(s.service, s.logger)
+	stagedConfig.Options.Product = s.Options.Product
+	stagedConfig.Options.IncludeCredentials = s.Options.IncludeCredentials
+
+	productConfig, err := stagedConfig.productConfiguration()
+	if err != nil {
+		return err
+	}
+
+	live, err := toGenericYAML(productConfig)
+	if err != nil {
+		return err
+	}
+
+	err = generator.NewExecutor(metadataBytes, s.Options.OutputDirectory, s.Options.ExcludeVersion, true, false).Generate()
+	if err != nil {
+		return err
+	}
+
+	targetDirectory := filepath.Join(s.Options.OutputDirectory, productMetadata.Name)
+	if !s.Options.ExcludeVersion {
+		targetDirectory = filepath.Join(targetDirectory, productMetadata.Version)
+	}
+
+	templateFile := filepath.Join(targetDirectory, "product.yml")
+	templateContents, err := ioutil.ReadFile(templateFile)
+	if err != nil {
+		return err
+	}
+
+	var template interface{}
+	err = yaml.Unmarshal(templateContents, &template)
+	if err != nil {
+		return fmt.Errorf("could not parse %s: %s", templateFile, err)
+	}
+
+	opsFiles, err := selectOpsFiles(targetDirectory, template, live)
+	if err != nil {
+		return err
+	}
+
+	var opsFilePaths []string
+	for _, opsFile := range opsFiles {
+		opsFilePaths = append(opsFilePaths, filepath.Join(targetDirectory, opsFile))
+	}
+
+	rendered, err := interpolate.Execute(interpolate.Options{
+		TemplateFile:  templateFile,
+		OpsFiles:      opsFilePaths,
+		ExpectAllKeys: false,
+	})
+	if err != nil {
+		return fmt.Errorf("could not apply the selected ops files: %s", err)
+	}
+
+	var renderedTemplate interface{}
+	err = yaml.Unmarshal(rendered, &renderedTemplate)
+	if err != nil {
+		return err
+	}
+
+	vars := map[string]interface{}{}
+	var missing []string
+	collectStagedVars(renderedTemplate, live, vars, &missing)
+
+	// the vars may have the credentials of the staged product
+	err = writeYAMLFile(filepath.Join(targetDirectory, "staged-vars.yml"), vars, 0600)
+	if err != nil {
+		return err
+	}
+
+	err = writeYAMLFile(filepath.Join(targetDirectory, "staged-ops-files.yml"), opsFiles, 0644)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Printf("generated a config template for %s %s in %s", productMetadata.Name, productMetadata.Version, targetDirectory)
+	s.logger.Printf("the ops files matching the staged product are listed in staged-ops-files.yml:")
+	for _, opsFile := range opsFiles {
+		s.logger.Printf("  %s", opsFile)
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		s.logger.Printf("the following vars were not found on the staged product and still need values:")
+		for _, name := range missing {
+			s.logger.Printf("  %s", name)
+		}
+	}
+
+	return nil
+}
+
+func (s *StagedConfigTemplate) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "**EXPERIMENTAL** This authenticated command generates a config template (like config-template) for a staged product. It selects the features, optional, network, and resource ops files that match the staged product, and writes the staged values into staged-vars.yml.",
+		ShortDescription: "**EXPERIMENTAL** generates a config template with vars from a staged product",
+		Flags:            s.Options,
+	}
+}
+
+// selectOpsFiles lists the generated ops files (relative to targetDirectory)
+// that bring the template closer to the live configuration.
+func selectOpsFiles(targetDirectory string, template, live interface{}) ([]string, error) {
+	var selected []string
+	for _, directory := range []string{"network", "features", "optional", "resource"} {
+		paths, err := filepath.Glob(filepath.Join(targetDirectory, directory, "*.yml"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(paths)
+
+		for _, path := range paths {
+			contents, err := ioutil.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+
+			var ops []templateOps
+			err = yaml.Unmarshal(contents, &ops)
+			if err != nil {
+				return nil, fmt.Errorf("could not parse ops file %s: %s", path, err)
+			}
+
+			if opsFileMatches(ops, template, live) {
+				selected = append(selected, filepath.Join(directory, filepath.Base(path)))
+			}
+		}
+	}
+
+	return selected, nil
+}
+
+func opsFileMatches(ops []templateOps, template, live interface{}) bool {
+	if len(ops) == 0 || ops[0].Type != "replace" {
+		return false
+	}
+
+	segments := opsPathSegments(ops[0].Path)
+	last := segments[len(segments)-1]
+
+	switch {
+	case last == "-":
+		liveValues, _ := lookupYAML(live, segments[:len(segments)-1]).([]interface{})
+		templateValues, _ := lookupYAML(template, segments[:len(segments)-1]).([]interface{})
+		return containsYAML(liveValues, ops[0].Value) && !containsYAML(templateValues, ops[0].Value)
+	case strings.HasSuffix(last, ":after"):
+		liveValues, _ := lookupYAML(live, segments[:len(segments)-1]).([]interface{})
+		templateValues, _ := lookupYAML(template, segments[:len(segments)-1]).([]interface{})
+		return len(liveValues) == len(templateValues)+len(ops)
+	}
+
+	liveValue := lookupYAML(live, segments)
+	if isEmptyYAML(liveValue) {
+		return false
+	}
+
+	value, _ := ops[0].Value.(map[interface{}]interface{})
+	if selectedOption, ok := value["selected_option"]; ok {
+		liveProperty, _ := liveValue.(map[interface{}]interface{})
+		if liveSelected, ok := liveProperty["selected_option"]; ok {
+			return fmt.Sprintf("%v", liveSelected) == fmt.Sprintf("%v", selectedOption)
+		}
+		return fmt.Sprintf("%v", liveProperty["value"]) == fmt.Sprintf("%v", value["value"])
+	}
+
+	if entries, ok := value["value"].([]interface{}); ok {
+		liveProperty, _ := liveValue.(map[interface{}]interface{})
+		liveEntries, _ := liveProperty["value"].([]interface{})
+		templateProperty, _ := lookupYAML(template, segments).(map[interface{}]interface{})
+		templateEntries, _ := templateProperty["value"].([]interface{})
+		return len(liveEntries) == len(entries) && len(templateEntries) != len(liveEntries)
+	}
+
+	return lookupYAML(template, segments) == nil
+}
+
+func collectStagedVars(template, live interface{}, vars map[string]interface{}, missing *[]string) {
+	switch t := template.(type) {
+	case string:
+		matches := placeholderRegexp.FindStringSubmatch(t)
+		if matches == nil {
+			return
+		}
+		if live == nil {
+			*missing = append(*missing, matches[1])
+			return
+		}
+		vars[matches[1]] = live
+	case map[interface{}]interface{}:
+		liveMap, _ := live.(map[interface{}]interface{})
+		for key, value := range t {
+			collectStagedVars(value, liveMap[key], vars, missing)
+		}
+	case []interface{}:
+		liveList, _ := live.([]interface{})
+		for index, value := range t {
+			var liveValue interface{}
+			if index < len(liveList) {
+				liveValue = liveList[index]
+			}
+			collectStagedVars(value, liveValue, vars, missing)
+		}
+	}
+}
+
+func opsPathSegments(path string) []string {
+	var segments []string
+	for _, segment := range strings.Split(strings.TrimPrefix(path, "/"), "/") {
+		segments = append(segments, strings.TrimSuffix(segment, "?"))
+	}
+	return segments
+}
+
+func lookupYAML(document interface{}, segments []string) interface{} {
+	for _, segment := range segments {
+		switch d := document.(type) {
+		case map[interface{}]interface{}:
+			document = d[segment]
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index >= len(d) {
+				return nil
+			}
+			document = d[index]
+		default:
+			return nil
+		}
+	}
+	return document
+}
+
+func containsYAML(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if fmt.Sprintf("%v", v) == fmt.Sprintf("%v", value) {
+			return true
+		}
+	}
+	return false
+}
+
+func isEmptyYAML(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[interface{}]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+func toGenericYAML(value interface{}) (interface{}, error) {
+	contents, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic interface{}
+	err = yaml.Unmarshal(contents, &generic)
+	return generic, err
+}
+
+func writeYAMLFile(path string, value interface{}, perm os.FileMode) error {
+	contents, err := yaml.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(path, contents, perm)
+	if err != nil {
+		return err
+	}
+
+	// WriteFile keeps the mode of an existing file
+	return os.Chmod(path, perm)
+}
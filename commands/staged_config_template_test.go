@@ -0,0 +1,228 @@
+package commands_test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const stagedConfigTemplateMetadata = `---
+name: some-product
+product_version: 1.2.3
+form_types:
+- name: some-form
+  property_inputs:
+  - reference: .properties.some-string
+  - reference: .properties.some-secret
+  - reference: .properties.optional-string
+  - reference: .properties.some-selector
+    selector_property_inputs:
+    - reference: .properties.some-selector.internal_option
+    - reference: .properties.some-selector.external_option
+property_blueprints:
+- name: some-string
+  type: string
+  configurable: true
+- name: some-secret
+  type: secret
+  configurable: true
+- name: optional-string
+  type: string
+  configurable: true
+  optional: true
+- name: some-selector
+  type: selector
+  configurable: true
+  default: internal
+  option_templates:
+  - name: internal_option
+    select_value: internal
+  - name: external_option
+    select_value: external
+    property_blueprints:
+    - name: url
+      type: string
+      configurable: true
+job_types:
+- name: some-job
+  instance_definition:
+    configurable: true
+    default: 1
+post_deploy_errands:
+- name: smoke-tests
+`
+
+var _ = Describe("StagedConfigTemplate", func() {
+	var (
+		fakeService      *fakes.StagedConfigService
+		metadataProvider *fakes.MetadataProvider
+		logger           *fakes.Logger
+		command          *commands.StagedConfigTemplate
+		outputDirectory  string
+	)
+
+	output := func() []string {
+		var lines []string
+		for i := 0; i < logger.PrintfCallCount(); i++ {
+			format, v := logger.PrintfArgsForCall(i)
+			lines = append(lines, fmt.Sprintf(format, v...))
+		}
+		return lines
+	}
+
+	BeforeEach(func() {
+		var err error
+		outputDirectory, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		fakeService = &fakes.StagedConfigService{}
+		fakeService.InfoReturns(api.Info{Version: "2.6.0"}, nil)
+		fakeService.GetStagedProductByNameReturns(api.StagedProductsFindOutput{
+			Product: api.StagedProduct{GUID: "some-product-guid", Type: "some-product"},
+		}, nil)
+		fakeService.GetStagedProductPropertiesReturns(map[string]api.ResponseProperty{
+			".properties.some-string":                       {Value: "some-value", Type: "string", Configurable: true},
+			".properties.some-secret":                       {Value: map[string]interface{}{"secret": "***"}, Type: "secret", Configurable: true, IsCredential: true},
+			".properties.optional-string":                   {Value: "optional-value", Type: "string", Configurable: true},
+			".properties.some-selector":                     {Value: "external", SelectedOption: "external_option", Type: "selector", Configurable: true},
+			".properties.some-selector.external_option.url": {Value: "https://example.com", Type: "string", Configurable: true},
+		}, nil)
+		fakeService.GetStagedProductNetworksAndAZsReturns(map[string]interface{}{
+			"network":                     map[string]interface{}{"name": "some-network"},
+			"singleton_availability_zone": map[string]interface{}{"name": "az1"},
+			"other_availability_zones": []map[string]interface{}{
+				{"name": "az1"}, {"name": "az2"},
+			},
+		}, nil)
+		fakeService.ListStagedProductJobsReturns(map[string]string{"some-job": "some-job-guid"}, nil)
+		fakeService.GetStagedProductJobResourceConfigReturns(api.JobProperties{
+			"instances":     2,
+			"instance_type": map[string]interface{}{"id": "automatic"},
+			"elb_names":     []string{"some-elb"},
+		}, nil)
+		fakeService.GetStagedProductJobMaxInFlightReturns(map[string]interface{}{"some-job-guid": "20%"}, nil)
+		fakeService.ListStagedProductErrandsReturns(api.ErrandsListOutput{
+			Errands: []api.Errand{{Name: "smoke-tests", PostDeploy: true}},
+		}, nil)
+
+		metadataProvider = &fakes.MetadataProvider{}
+		metadataProvider.MetadataBytesReturns([]byte(stagedConfigTemplateMetadata), nil)
+
+		logger = &fakes.Logger{}
+		command = commands.NewStagedConfigTemplate(fakeService, func(*commands.StagedConfigTemplate) commands.MetadataProvider {
+			return metadataProvider
+		}, logger)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(outputDirectory)).To(Succeed())
+	})
+
+	It("writes a config template with the ops files and vars of the staged product", func() {
+		err := command.Execute([]string{
+			"--product-name", "some-product",
+			"--product-path", "/path/to/product.pivotal",
+			"--output-directory", outputDirectory,
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		productDirectory := filepath.Join(outputDirectory, "some-product", "1.2.3")
+		Expect(filepath.Join(productDirectory, "product.yml")).To(BeAnExistingFile())
+
+		opsFiles, err := ioutil.ReadFile(filepath.Join(productDirectory, "staged-ops-files.yml"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(opsFiles).To(MatchYAML(`
+- network/2-az-configuration.yml
+- features/some-selector-external_option.yml
+- optional/add-optional-string.yml
+- resource/some-job_elb_names.yml
+`))
+
+		info, err := os.Stat(filepath.Join(productDirectory, "staged-vars.yml"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+		vars, err := ioutil.ReadFile(filepath.Join(productDirectory, "staged-vars.yml"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vars).To(MatchYAML(`
+some-string: some-value
+optional-string: optional-value
+some-selector_external_option_url: https://example.com
+network_name: some-network
+singleton_availability_zone: az1
+az2_name: az2
+some-job_instances: 2
+some-job_instance_type: automatic
+some-job_max_in_flight: 20%
+some-job_elb_names: [some-elb]
+smoke-tests_post_deploy_state: true
+`))
+
+		Expect(output()).To(Equal([]string{
+			fmt.Sprintf("generated a config template for some-product 1.2.3 in %s", productDirectory),
+			"the ops files matching the staged product are listed in staged-ops-files.yml:",
+			"  network/2-az-configuration.yml",
+			"  features/some-selector-external_option.yml",
+			"  optional/add-optional-string.yml",
+			"  resource/some-job_elb_names.yml",
+			"the following vars were not found on the staged product and still need values:",
+			"  some-secret",
+		}))
+	})
+
+	Context("failure cases", func() {
+		It("returns an error when an unknown flag is provided", func() {
+			err := command.Execute([]string{"--badflag"})
+			Expect(err).To(MatchError("could not parse staged-config-template flags: flag provided but not defined: -badflag"))
+		})
+
+		It("returns an error when the output directory does not exist", func() {
+			err := command.Execute([]string{
+				"--product-name", "some-product",
+				"--product-path", "/path/to/product.pivotal",
+				"--output-directory", "/not/real/directory",
+			})
+			Expect(err).To(MatchError("output-directory does not exist: /not/real/directory"))
+		})
+
+		It("returns an error when the metadata is for another product", func() {
+			err := command.Execute([]string{
+				"--product-name", "other-product",
+				"--product-path", "/path/to/product.pivotal",
+				"--output-directory", outputDirectory,
+			})
+			Expect(err).To(MatchError("the product metadata is for some-product, not the staged product other-product"))
+		})
+
+		It("returns an error when the staged config cannot be retrieved", func() {
+			fakeService.GetStagedProductByNameReturns(api.StagedProductsFindOutput{}, errors.New("some error"))
+			err := command.Execute([]string{
+				"--product-name", "some-product",
+				"--product-path", "/path/to/product.pivotal",
+				"--output-directory", outputDirectory,
+			})
+			Expect(err).To(MatchError("some error"))
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			command := commands.NewStagedConfigTemplate(nil, nil, nil)
+			Expect(command.Usage()).To(Equal(jhanda.Usage{
+				Description:      "**EXPERIMENTAL** This authenticated command generates a config template (like config-template) for a staged product. It selects the features, optional, network, and resource ops files that match the staged product, and writes the staged values into staged-vars.yml.",
+				ShortDescription: "**EXPERIMENTAL** generates a config template with vars from a staged product",
+				Flags:            command.Options,
+			}))
+		})
+	})
+})
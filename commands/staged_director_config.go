@@ -1,13 +1,16 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
 
+	yamlConverter "github.com/ghodss/yaml"
 	"github.com/pivotal-cf/jhanda"
 	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/presenters"
 	"gopkg.in/yaml.v2"
 )
 
@@ -16,11 +19,21 @@ type StagedDirectorConfig struct {
 	stderr  logger
 	service stagedDirectorConfigService
 	Options struct {
-		IncludePlaceholders bool `long:"include-placeholders" short:"r" description:"Replace obscured credentials to interpolatable placeholders.\n\t\t\t\t    To include credentials hidden by OpsMan, use with \"--no-redact\""`
-		NoRedact            bool `long:"no-redact" description:"Redact IaaS values from director configuration"`
+		IncludePlaceholders bool   `long:"include-placeholders" short:"r" description:"Replace obscured credentials to interpolatable placeholders.\n\t\t\t\t    To include credentials hidden by OpsMan, use with \"--no-redact\""`
+		NoRedact            bool   `long:"no-redact" description:"Redact IaaS values from director configuration"`
+		Format              string `long:"format" short:"f" default:"yaml" description:"Format to print as (options: yaml,json)"`
 	}
 }
 
+// stagedDirectorConfigOutput is the document printed with --format json.
+// The warnings are otherwise printed to stderr.
+type stagedDirectorConfigOutput struct {
+	Config   json.RawMessage `json:"config"`
+	Warnings []string        `json:"warnings"`
+}
+
+const redactedDirectorConfigWarning = "Because `--no-redact` has not been provided, the `iaas-configurations` and other credentials will be hidden."
+
 //counterfeiter:generate -o ./fakes/staged_director_config_service.go --fake-name StagedDirectorConfigService . stagedDirectorConfigService
 type stagedDirectorConfigService interface {
 	GetStagedDirectorProperties(bool) (map[string]interface{}, error)
@@ -52,6 +65,10 @@ func (sdc StagedDirectorConfig) Execute(args []string) error {
 		return fmt.Errorf("could not parse staged-config flags: %s", err)
 	}
 
+	if err := presenters.ValidateFormat(sdc.Options.Format, yamlFormat, jsonFormat); err != nil {
+		return fmt.Errorf("could not parse staged-config flags: %s", err)
+	}
+
 	stagedDirector, err := sdc.service.GetStagedProductByName("p-bosh")
 	if err != nil {
 		return err
@@ -160,9 +177,26 @@ func (sdc StagedDirectorConfig) Execute(args []string) error {
 		return err
 	}
 
-	sdc.stdout.Println(string(configYaml))
+	warnings := []string{}
 	if !sdc.Options.NoRedact {
-		sdc.stderr.Println("NOTE: Because `--no-redact` has not been provided, the `iaas-configurations` and other credentials will be hidden.")
+		warnings = append(warnings, redactedDirectorConfigWarning)
+	}
+
+	if sdc.Options.Format == jsonFormat {
+		configJSON, err := yamlConverter.YAMLToJSON(configYaml)
+		if err != nil {
+			return err
+		}
+
+		return printJSON(sdc.stdout, stagedDirectorConfigOutput{
+			Config:   configJSON,
+			Warnings: warnings,
+		})
+	}
+
+	sdc.stdout.Println(string(configYaml))
+	for _, warning := range warnings {
+		sdc.stderr.Println("NOTE: " + warning)
 	}
 	return nil
 }
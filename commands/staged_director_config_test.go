@@ -246,6 +246,79 @@ vmtypes-configuration:
 			Expect(invocations[0]).To(Equal([]interface{}{false}))
 		})
 
+		It("prints the note about redacted credentials to stderr", func() {
+			command := commands.NewStagedDirectorConfig(fakeService, stdout, stderr)
+			err := command.Execute([]string{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(stderr.PrintlnCallCount()).To(Equal(1))
+			Expect(stderr.PrintlnArgsForCall(0)).To(Equal([]interface{}{
+				"NOTE: Because `--no-redact` has not been provided, the `iaas-configurations` and other credentials will be hidden.",
+			}))
+		})
+
+		When("the format is json", func() {
+			It("prints the config and the warnings as one document to stdout", func() {
+				command := commands.NewStagedDirectorConfig(fakeService, stdout, stderr)
+				err := command.Execute([]string{"--format", "json"})
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(stderr.PrintlnCallCount()).To(Equal(0))
+				Expect(stdout.PrintlnCallCount()).To(Equal(1))
+
+				output := stdout.PrintlnArgsForCall(0)
+				Expect(output).To(HaveLen(1))
+				Expect(output[0]).To(MatchJSON(`{
+					"config": {
+						"az-configuration": [
+							{"name": "some-az", "iaas_configuration_name": "some-iaas"},
+							{"name": "some-other-az", "iaas_configuration_name": "some-other-iaas"}
+						],
+						"network-assignment": {
+							"network": {"name": "network-1"},
+							"singleton_availability_zone": {"name": "some-az"}
+						},
+						"networks-configuration": {
+							"icmp_checks_enabled": false,
+							"networks": [{"name": "network-1"}]
+						},
+						"resource-configuration": {
+							"some-job": {
+								"additional_vm_extensions": ["some-vm-extension"],
+								"instances": 1,
+								"instance_type": {"id": "automatic"}
+							}
+						},
+						"vmextensions-configuration": [
+							{"name": "vm_ext1", "cloud_properties": {"source_dest_check": false}},
+							{"name": "vm_ext2", "cloud_properties": {"key_name": "operations_keypair"}}
+						],
+						"properties-configuration": {
+							"syslog_configuration": {"syslogconfig": "awesome"},
+							"security_configuration": {"trusted_certificates": "some-certificate"},
+							"director_configuration": {
+								"max_threads": 5,
+								"encryption": {"providers": {"client_certificate": "user_provided_cert"}}
+							}
+						},
+						"vmtypes-configuration": {}
+					},
+					"warnings": [
+						"Because ` + "`--no-redact`" + ` has not been provided, the ` + "`iaas-configurations`" + ` and other credentials will be hidden."
+					]
+				}`))
+			})
+
+			It("has no warnings when --no-redact is passed", func() {
+				command := commands.NewStagedDirectorConfig(fakeService, stdout, stderr)
+				err := command.Execute([]string{"--format", "json", "--no-redact"})
+				Expect(err).ToNot(HaveOccurred())
+
+				output := stdout.PrintlnArgsForCall(0)
+				Expect(output[0]).To(ContainSubstring(`"warnings": []`))
+			})
+		})
+
 		When("getting availability_zones returns an empty array", func() {
 			It("doesn't return the az in the config", func() {
 				fakeService.GetStagedDirectorAvailabilityZonesReturns(api.AvailabilityZonesOutput{}, nil)
@@ -972,6 +1045,14 @@ vmtypes-configuration: {}
 				})
 			})
 
+			When("the format is not supported", func() {
+				It("returns an error", func() {
+					command := commands.NewStagedDirectorConfig(fakeService, stdout, stderr)
+					err := command.Execute([]string{"--format", "table"})
+					Expect(err).To(MatchError(`could not parse staged-config flags: unsupported format "table", expected one of yaml, json`))
+				})
+			})
+
 			When("looking up the director GUID fails", func() {
 				BeforeEach(func() {
 					fakeService.GetStagedProductByNameReturns(api.StagedProductsFindOutput{}, errors.New("some-error"))
@@ -11,7 +11,7 @@ type StagedManifest struct {
 	service stagedManifestService
 	logger  logger
 	Options struct {
-		ProductName string `long:"product-name" short:"p" required:"true" description:"name of product"`
+		ProductName string `long:"product-name" short:"p" required:"true" description:"name of product" complete:"products"`
 	}
 }
 
@@ -11,7 +11,7 @@ type StagedProducts struct {
 	presenter presenters.FormattedPresenter
 	service   diagnosticReportService
 	Options   struct {
-		Format string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json)"`
+		Format string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json,yaml,csv)"`
 	}
 }
 
@@ -27,6 +27,10 @@ func (sp StagedProducts) Execute(args []string) error {
 		return fmt.Errorf("could not parse staged-products flags: %s", err)
 	}
 
+	if err := presenters.ValidateFormat(sp.Options.Format); err != nil {
+		return fmt.Errorf("could not parse staged-products flags: %s", err)
+	}
+
 	diagnosticReport, err := sp.service.GetDiagnosticReport()
 	if err != nil {
 		return fmt.Errorf("failed to retrieve staged products %s", err)
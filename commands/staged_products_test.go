@@ -75,6 +75,13 @@ var _ = Describe("StagedProducts", func() {
 				})
 			})
 
+			When("the format is not supported", func() {
+				It("returns an error", func() {
+					err := command.Execute([]string{"--format", "xml"})
+					Expect(err).To(MatchError(`could not parse staged-products flags: unsupported format "xml", expected one of table, json, yaml, csv`))
+				})
+			})
+
 			When("an unknown flag is passed", func() {
 				It("returns an error", func() {
 					err := command.Execute([]string{"--unknown-flag"})
@@ -9,4 +9,5 @@ type tableWriter interface {
 	Render()
 	SetAutoFormatHeaders(bool)
 	SetAutoWrapText(bool)
+	ClearRows()
 }
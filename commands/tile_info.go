@@ -0,0 +1,144 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/configtemplate/generator"
+	"github.com/pivotal-cf/om/models"
+	"github.com/pivotal-cf/om/presenters"
+)
+
+type TileInfo struct {
+	presenter         presenters.FormattedPresenter
+	metadataExtractor metadataExtractor
+	Options           struct {
+		Product string `long:"product" short:"p" required:"true" description:"path to product file"`
+		Format  string `long:"format"  short:"f" default:"table" description:"Format to print as (options: table,json,yaml,csv)"`
+	}
+}
+
+func NewTileInfo(metadataExtractor metadataExtractor, presenter presenters.FormattedPresenter) TileInfo {
+	return TileInfo{
+		metadataExtractor: metadataExtractor,
+		presenter:         presenter,
+	}
+}
+
+func (ti TileInfo) Execute(args []string) error {
+	if _, err := jhanda.Parse(&ti.Options, args); err != nil {
+		return fmt.Errorf("could not parse tile-info flags: %s", err)
+	}
+
+	if err := presenters.ValidateFormat(ti.Options.Format); err != nil {
+		return fmt.Errorf("could not parse tile-info flags: %s", err)
+	}
+
+	extracted, err := ti.metadataExtractor.ExtractMetadata(ti.Options.Product)
+	if err != nil {
+		return fmt.Errorf("failed to extract product metadata: %s", err)
+	}
+
+	metadata, err := generator.NewMetadata(extracted.Raw)
+	if err != nil {
+		return fmt.Errorf("failed to parse product metadata: %s", err)
+	}
+
+	ti.presenter.SetFormat(ti.Options.Format)
+	ti.presenter.PresentTileInfo(newTileInfo(metadata))
+
+	return nil
+}
+
+func (ti TileInfo) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This command prints the releases, stemcell criteria, required products, job types, errands, and configurable properties found in the metadata of a product file",
+		ShortDescription: "prints detailed information about a product file",
+		Flags:            ti.Options,
+	}
+}
+
+func newTileInfo(metadata *generator.Metadata) models.TileInfo {
+	info := models.TileInfo{
+		Name:                     metadata.Name,
+		Version:                  metadata.Version,
+		Label:                    metadata.Label,
+		MinimumVersionForUpgrade: metadata.MinimumVersionForUpgrade,
+		OpsManagerVersions:       []string{},
+		Releases:                 []models.TileRelease{},
+		StemcellCriteria:         []models.TileStemcellCriteria{},
+		RequiredProducts:         []models.TileRequiredProduct{},
+		JobTypes:                 []models.TileJobType{},
+		Errands:                  []models.TileErrand{},
+		Properties:               []models.TileProperty{},
+	}
+
+	if metadata.MetadataVersion != "" {
+		info.OpsManagerVersions = append(info.OpsManagerVersions, fmt.Sprintf(">= %s", metadata.MetadataVersion))
+	}
+
+	for _, release := range metadata.Releases {
+		info.Releases = append(info.Releases, models.TileRelease{
+			Name:    release.Name,
+			Version: release.Version,
+			File:    release.File,
+		})
+	}
+
+	for _, criterion := range metadata.StemcellsCriteria() {
+		info.StemcellCriteria = append(info.StemcellCriteria, models.TileStemcellCriteria{
+			OS:                         criterion.OS,
+			Version:                    criterion.Version,
+			EnablePatchSecurityUpdates: criterion.EnablePatchSecurityUpdates,
+		})
+	}
+
+	for _, requirement := range metadata.RequiresProductVersions {
+		if requirement.Name == "p-bosh" {
+			info.OpsManagerVersions = append(info.OpsManagerVersions, requirement.Version)
+			continue
+		}
+
+		info.RequiredProducts = append(info.RequiredProducts, models.TileRequiredProduct{
+			Name:    requirement.Name,
+			Version: requirement.Version,
+		})
+	}
+
+	for _, job := range metadata.JobTypes {
+		jobType := models.TileJobType{
+			Name:                  job.Name,
+			Instances:             job.InstanceDefinition.Default,
+			InstancesConfigurable: job.InstanceDefinition.Configurable,
+			Resources:             []models.TileResource{},
+		}
+
+		for _, resource := range job.ResourceDefinitions {
+			jobType.Resources = append(jobType.Resources, models.TileResource{
+				Name:         resource.Name,
+				Default:      resource.Default,
+				Configurable: resource.Configurable,
+			})
+		}
+
+		info.JobTypes = append(info.JobTypes, jobType)
+	}
+
+	for _, errand := range metadata.PostDeployErrands {
+		info.Errands = append(info.Errands, models.TileErrand{Name: errand.Name, Type: "post-deploy"})
+	}
+
+	for _, errand := range metadata.PreDeleteErrands {
+		info.Errands = append(info.Errands, models.TileErrand{Name: errand.Name, Type: "pre-delete"})
+	}
+
+	for _, property := range metadata.ConfigurableProperties() {
+		info.Properties = append(info.Properties, models.TileProperty{
+			Reference: property.Reference,
+			Type:      property.Blueprint.Type,
+			Required:  property.Blueprint.IsRequired(),
+		})
+	}
+
+	return info
+}
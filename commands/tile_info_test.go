@@ -0,0 +1,155 @@
+package commands_test
+
+import (
+	"errors"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+	"github.com/pivotal-cf/om/extractor"
+	"github.com/pivotal-cf/om/models"
+	presenterfakes "github.com/pivotal-cf/om/presenters/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TileInfo", func() {
+	var (
+		fakePresenter     *presenterfakes.FormattedPresenter
+		metadataExtractor *fakes.MetadataExtractor
+		command           commands.TileInfo
+	)
+
+	BeforeEach(func() {
+		fakePresenter = &presenterfakes.FormattedPresenter{}
+		metadataExtractor = &fakes.MetadataExtractor{}
+		metadataExtractor.ExtractMetadataReturns(extractor.Metadata{
+			Name:    "some-product",
+			Version: "1.2.3",
+			Raw: []byte(`---
+name: some-product
+label: Some Product
+product_version: 1.2.3
+metadata_version: "2.6"
+minimum_version_for_upgrade: 1.1.0
+releases:
+- name: some-release
+  version: 4.5.6
+  file: some-release-4.5.6.tgz
+stemcell_criteria:
+  os: ubuntu-xenial
+  version: "250.17"
+  enable_patch_security_updates: true
+requires_product_versions:
+- name: p-bosh
+  version: ~> 2.6
+- name: cf
+  version: ~> 2.6
+job_types:
+- name: some-job
+  instance_definition:
+    configurable: true
+    default: 2
+  resource_definitions:
+  - name: ram
+    configurable: true
+    default: 1024
+  property_blueprints:
+  - name: job-property
+    type: boolean
+    configurable: true
+post_deploy_errands:
+- name: smoke-tests
+pre_delete_errands:
+- name: cleanup
+property_blueprints:
+- name: some-string
+  type: string
+  configurable: true
+- name: some-optional-string
+  type: string
+  configurable: true
+  optional: true
+`),
+		}, nil)
+
+		command = commands.NewTileInfo(metadataExtractor, fakePresenter)
+	})
+
+	It("presents the information found in the product metadata", func() {
+		err := command.Execute([]string{"--product", "/path/to/product.pivotal"})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(metadataExtractor.ExtractMetadataArgsForCall(0)).To(Equal("/path/to/product.pivotal"))
+
+		Expect(fakePresenter.SetFormatCallCount()).To(Equal(1))
+		Expect(fakePresenter.SetFormatArgsForCall(0)).To(Equal("table"))
+
+		Expect(fakePresenter.PresentTileInfoCallCount()).To(Equal(1))
+		Expect(fakePresenter.PresentTileInfoArgsForCall(0)).To(Equal(models.TileInfo{
+			Name:                     "some-product",
+			Version:                  "1.2.3",
+			Label:                    "Some Product",
+			MinimumVersionForUpgrade: "1.1.0",
+			OpsManagerVersions:       []string{">= 2.6", "~> 2.6"},
+			Releases:                 []models.TileRelease{{Name: "some-release", Version: "4.5.6", File: "some-release-4.5.6.tgz"}},
+			StemcellCriteria:         []models.TileStemcellCriteria{{OS: "ubuntu-xenial", Version: "250.17", EnablePatchSecurityUpdates: true}},
+			RequiredProducts:         []models.TileRequiredProduct{{Name: "cf", Version: "~> 2.6"}},
+			JobTypes: []models.TileJobType{{
+				Name:                  "some-job",
+				Instances:             2,
+				InstancesConfigurable: true,
+				Resources:             []models.TileResource{{Name: "ram", Default: 1024, Configurable: true}},
+			}},
+			Errands: []models.TileErrand{
+				{Name: "smoke-tests", Type: "post-deploy"},
+				{Name: "cleanup", Type: "pre-delete"},
+			},
+			Properties: []models.TileProperty{
+				{Reference: ".properties.some-string", Type: "string", Required: true},
+				{Reference: ".properties.some-optional-string", Type: "string", Required: false},
+				{Reference: ".some-job.job-property", Type: "boolean", Required: true},
+			},
+		}))
+	})
+
+	When("the format flag is provided", func() {
+		It("sets the format on the presenter", func() {
+			err := command.Execute([]string{"--product", "/path/to/product.pivotal", "--format", "json"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakePresenter.SetFormatArgsForCall(0)).To(Equal("json"))
+		})
+	})
+
+	Context("failure cases", func() {
+		It("returns an error when an unknown flag is provided", func() {
+			err := command.Execute([]string{"--badflag"})
+			Expect(err).To(MatchError("could not parse tile-info flags: flag provided but not defined: -badflag"))
+		})
+
+		It("returns an error when the metadata cannot be extracted", func() {
+			metadataExtractor.ExtractMetadataReturns(extractor.Metadata{}, errors.New("some error"))
+			err := command.Execute([]string{"--product", "/path/to/product.pivotal"})
+			Expect(err).To(MatchError("failed to extract product metadata: some error"))
+		})
+
+		It("returns an error when the metadata cannot be parsed", func() {
+			metadataExtractor.ExtractMetadataReturns(extractor.Metadata{Raw: []byte("{invalid")}, nil)
+			err := command.Execute([]string{"--product", "/path/to/product.pivotal"})
+			Expect(err).To(MatchError(ContainSubstring("failed to parse product metadata")))
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			command := commands.NewTileInfo(nil, nil)
+			Expect(command.Usage()).To(Equal(jhanda.Usage{
+				Description:      "This command prints the releases, stemcell criteria, required products, job types, errands, and configurable properties found in the metadata of a product file",
+				ShortDescription: "prints detailed information about a product file",
+				Flags:            command.Options,
+			}))
+		})
+	})
+})
@@ -11,7 +11,7 @@ type UnstageProduct struct {
 	logger  logger
 	service unstageProductService
 	Options struct {
-		Product string `long:"product-name" short:"p" required:"true" description:"name of product"`
+		Product string `long:"product-name" short:"p" required:"true" description:"name of product" complete:"products"`
 	}
 }
 
@@ -1,28 +1,61 @@
 package commands
 
 import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/pivotal-cf/jhanda"
 	"github.com/pivotal-cf/om/api"
 )
 
 type UpdateSSLCertificate struct {
-	service updateSSLCertificateService
-	logger  logger
-	Options struct {
-		CertPem    string `long:"certificate-pem" required:"true" description:"certificate"`
-		PrivateKey string `long:"private-key-pem" required:"true" description:"private key"`
+	service      updateSSLCertificateService
+	logger       logger
+	stdin        io.Reader
+	target       string
+	waitDuration time.Duration
+	dial         tlsDialer
+	Options      struct {
+		CertPem      string `long:"certificate-pem" description:"certificate, required unless --generate is used"`
+		PrivateKey   string `long:"private-key-pem" description:"private key, required unless --generate is used"`
+		Generate     bool   `long:"generate"        description:"generate a certificate for the --target host, signed by the Ops Manager root CA"`
+		SkipChecks   bool   `long:"skip-checks"     description:"apply the certificate without checking the key, the chain, the SANs and the validity"`
+		ReadyTimeout int    `long:"ready-timeout"   default:"0"   description:"seconds to wait for Ops Manager to serve the new certificate after the update, before offering to roll back. The served certificate is not checked when 0, such as when TLS is terminated by a load balancer"`
 	}
 }
 
+// tlsDialer is tls.DialWithDialer, which connects to Ops Manager
+// to get the certificate that it serves.
+type tlsDialer func(dialer *net.Dialer, network, address string, config *tls.Config) (*tls.Conn, error)
+
 //counterfeiter:generate -o ./fakes/update_ssl_certificate_service.go --fake-name UpdateSSLCertificateService . updateSSLCertificateService
 type updateSSLCertificateService interface {
 	UpdateSSLCertificate(api.SSLCertificateInput) error
+	DeleteSSLCertificate() error
+	GenerateCertificate(api.DomainsInput) (string, error)
+	GetSecurityRootCACertificate() (string, error)
 }
 
-func NewUpdateSSLCertificate(service updateSSLCertificateService, logger logger) UpdateSSLCertificate {
-	return UpdateSSLCertificate{service: service, logger: logger}
+func NewUpdateSSLCertificate(service updateSSLCertificateService, logger logger, stdin io.Reader, target string, waitDuration time.Duration, dial tlsDialer) UpdateSSLCertificate {
+	return UpdateSSLCertificate{
+		service:      service,
+		logger:       logger,
+		stdin:        stdin,
+		target:       target,
+		waitDuration: waitDuration,
+		dial:         dial,
+	}
 }
 
 func (c UpdateSSLCertificate) Execute(args []string) error {
@@ -30,9 +63,40 @@ func (c UpdateSSLCertificate) Execute(args []string) error {
 		return fmt.Errorf("could not parse update-ssl-certificate flags: %s", err)
 	}
 
-	err := c.service.UpdateSSLCertificate(api.SSLCertificateInput{
-		CertPem:       c.Options.CertPem,
-		PrivateKeyPem: c.Options.PrivateKey,
+	err := c.validateOptions()
+	if err != nil {
+		return fmt.Errorf("could not parse update-ssl-certificate flags: %s", err)
+	}
+
+	target, err := targetURL(c.target)
+	if err != nil {
+		return err
+	}
+	host := target.Hostname()
+
+	certPem, privateKey := c.Options.CertPem, c.Options.PrivateKey
+	if c.Options.Generate {
+		certPem, privateKey, err = c.generate(host)
+		if err != nil {
+			return err
+		}
+	}
+
+	bundle, err := parseCertificates(certPem)
+	if err != nil {
+		return fmt.Errorf("the SSL certificate was not applied: %s", err)
+	}
+
+	if !c.Options.SkipChecks {
+		err = c.check(bundle, certPem, privateKey, host)
+		if err != nil {
+			return fmt.Errorf("the SSL certificate was not applied: %s", err)
+		}
+	}
+
+	err = c.service.UpdateSSLCertificate(api.SSLCertificateInput{
+		CertPem:       certPem,
+		PrivateKeyPem: privateKey,
 	})
 	if err != nil {
 		return err
@@ -41,12 +105,278 @@ func (c UpdateSSLCertificate) Execute(args []string) error {
 	c.logger.Printf("Successfully applied custom SSL Certificate.\n")
 	c.logger.Printf("Please allow about 1 min for the new certificate to take effect.\n")
 
+	if c.Options.ReadyTimeout <= 0 {
+		return nil
+	}
+
+	err = c.waitForOpsManager(bundle[0], targetAddress(target))
+	if err != nil {
+		return c.offerRollback(err)
+	}
+
+	c.logger.Printf("Ops Manager is answering with the new certificate.\n")
+
+	return nil
+}
+
+func (c UpdateSSLCertificate) validateOptions() error {
+	if c.Options.Generate {
+		if c.Options.CertPem != "" || c.Options.PrivateKey != "" {
+			return errors.New("--generate cannot be used with --certificate-pem or --private-key-pem")
+		}
+
+		return nil
+	}
+
+	if c.Options.CertPem == "" {
+		return errors.New(`missing required flag "--certificate-pem"`)
+	}
+
+	if c.Options.PrivateKey == "" {
+		return errors.New(`missing required flag "--private-key-pem"`)
+	}
+
+	return nil
+}
+
+func (c UpdateSSLCertificate) generate(host string) (string, string, error) {
+	output, err := c.service.GenerateCertificate(api.DomainsInput{Domains: []string{host}})
+	if err != nil {
+		return "", "", fmt.Errorf("could not generate a certificate for %s: %s", host, err)
+	}
+
+	var generated struct {
+		Certificate string `json:"certificate"`
+		Key         string `json:"key"`
+	}
+	err = json.Unmarshal([]byte(output), &generated)
+	if err != nil {
+		return "", "", fmt.Errorf("could not parse the generated certificate: %s", err)
+	}
+
+	c.logger.Printf("Generated a certificate for %s signed by the Ops Manager root CA.\n", host)
+
+	return generated.Certificate, generated.Key, nil
+}
+
+// parseCertificates returns the certificates of the bundle, starting with the leaf.
+func parseCertificates(certPem string) ([]*x509.Certificate, error) {
+	var bundle []*x509.Certificate
+	rest := []byte(certPem)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse the certificate: %s", err)
+		}
+		bundle = append(bundle, cert)
+	}
+
+	if len(bundle) == 0 {
+		return nil, errors.New("could not parse the certificate: no PEM certificate found")
+	}
+
+	return bundle, nil
+}
+
+// check validates the certificate before it is applied,
+// as a broken certificate makes the Ops Manager UI unreachable.
+func (c UpdateSSLCertificate) check(bundle []*x509.Certificate, certPem, privateKey, host string) error {
+	_, err := tls.X509KeyPair([]byte(certPem), []byte(privateKey))
+	if err != nil {
+		return fmt.Errorf("the private key does not match the certificate: %s", err)
+	}
+
+	leaf := bundle[0]
+	now := time.Now()
+
+	if now.After(leaf.NotAfter) {
+		return fmt.Errorf("the certificate expired on %s", leaf.NotAfter.Format(time.RFC3339))
+	}
+
+	if now.Before(leaf.NotBefore) {
+		return fmt.Errorf("the certificate is not valid before %s", leaf.NotBefore.Format(time.RFC3339))
+	}
+
+	err = leaf.VerifyHostname(host)
+	if err != nil {
+		return fmt.Errorf("the SANs of the certificate do not cover the target: %s", err)
+	}
+
+	roots, err := c.trustedRoots(bundle)
+	if err != nil {
+		return err
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range bundle[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err = leaf.Verify(x509.VerifyOptions{
+		Intermediates: intermediates,
+		Roots:         roots,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	if err != nil {
+		return fmt.Errorf("the certificate chain is incomplete: %s", err)
+	}
+
 	return nil
 }
 
+// trustedRoots are the system roots, the Ops Manager root CA,
+// and the self-signed certificates at the end of the bundle.
+func (c UpdateSSLCertificate) trustedRoots(bundle []*x509.Certificate) (*x509.CertPool, error) {
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		roots = x509.NewCertPool()
+	}
+
+	rootCA, err := c.service.GetSecurityRootCACertificate()
+	if err != nil {
+		return nil, fmt.Errorf("could not get the root CA certificate: %s", err)
+	}
+	roots.AppendCertsFromPEM([]byte(rootCA))
+
+	for _, cert := range bundle {
+		if string(cert.RawIssuer) == string(cert.RawSubject) {
+			roots.AddCert(cert)
+		}
+	}
+
+	return roots, nil
+}
+
+// waitForOpsManager waits until a new TLS connection to Ops Manager
+// is served with the leaf certificate that has been applied, or until the ready timeout has passed.
+// It waits before every attempt, as Ops Manager takes a while to restart its web server.
+func (c UpdateSSLCertificate) waitForOpsManager(leaf *x509.Certificate, address string) error {
+	deadline := time.Now().Add(time.Duration(c.Options.ReadyTimeout) * time.Second)
+
+	for {
+		time.Sleep(c.waitDuration)
+
+		// every attempt is bounded by the time that is left,
+		// so an unresponsive Ops Manager does not hang the command
+		timeout := time.Until(deadline)
+		if timeout < time.Second {
+			timeout = time.Second
+		}
+
+		err := c.servesCertificate(leaf, address, timeout)
+		if err == nil {
+			return nil
+		}
+
+		if !time.Now().Before(deadline) {
+			return err
+		}
+	}
+}
+
+func (c UpdateSSLCertificate) servesCertificate(leaf *x509.Certificate, address string, timeout time.Duration) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	// the served certificate is compared with the applied one instead of being verified,
+	// so the connection does not depend on the trust store of this machine
+	conn, err := c.dial(&net.Dialer{Timeout: timeout}, "tcp", address, &tls.Config{
+		ServerName:         host,
+		InsecureSkipVerify: true,
+	})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	served := conn.ConnectionState().PeerCertificates
+	if len(served) == 0 {
+		return errors.New("Ops Manager did not serve a certificate")
+	}
+
+	if !bytes.Equal(served[0].Raw, leaf.Raw) {
+		return fmt.Errorf("Ops Manager still serves another certificate (subject %q, expires on %s)", served[0].Subject.CommonName, served[0].NotAfter.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// offerRollback reverts to the certificate generated by Ops Manager.
+// The previous certificate cannot be restored, as its key is not exposed by the API.
+func (c UpdateSSLCertificate) offerRollback(cause error) error {
+	c.logger.Printf("Ops Manager is not serving the new certificate after %ds: %s\n", c.Options.ReadyTimeout, cause)
+	c.logger.Printf("Do you want to roll back to the default Ops Manager certificate? [yes/no]: ")
+
+	scanner := bufio.NewScanner(c.stdin)
+	scanner.Scan()
+
+	if strings.TrimSpace(scanner.Text()) != "yes" {
+		return fmt.Errorf("Ops Manager is not answering with the new certificate: %s", cause)
+	}
+
+	err := c.service.DeleteSSLCertificate()
+	if err != nil {
+		return fmt.Errorf("could not roll back to the default Ops Manager certificate: %s", err)
+	}
+
+	return errors.New("Ops Manager is not answering with the new certificate, rolled back to the default Ops Manager certificate")
+}
+
+func targetHost(target string) (string, error) {
+	targetURL, err := targetURL(target)
+	if err != nil {
+		return "", err
+	}
+
+	return targetURL.Hostname(), nil
+}
+
+func targetURL(target string) (*url.URL, error) {
+	if !strings.Contains(target, "//") {
+		target = "//" + target
+	}
+
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse target url: %s", err)
+	}
+
+	if targetURL.Hostname() == "" {
+		return nil, errors.New("target flag is required. Run `om help` for more info.")
+	}
+
+	return targetURL, nil
+}
+
+// targetAddress is the host and port of the target, which is on the https port by default.
+func targetAddress(target *url.URL) string {
+	port := target.Port()
+	if port == "" {
+		port = "443"
+	}
+
+	return net.JoinHostPort(target.Hostname(), port)
+}
+
 func (c UpdateSSLCertificate) Usage() jhanda.Usage {
 	return jhanda.Usage{
-		Description:      "This authenticated command updates the SSL Certificate on the Ops Manager with the given cert and key",
+		Description: "This authenticated command updates the SSL Certificate on the Ops Manager with the given cert and key." +
+			" Before the update, it checks that the key matches the certificate, that the chain is complete," +
+			" that the SANs cover the --target host and that the certificate has not expired, unless --skip-checks is given." +
+			" With --ready-timeout, it waits for Ops Manager to serve the new certificate after the update," +
+			" and offers to roll back to the default Ops Manager certificate if it does not.",
 		ShortDescription: "updates the SSL Certificate on the Ops Manager",
 		Flags:            c.Options,
 	}
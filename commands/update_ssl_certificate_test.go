@@ -1,12 +1,24 @@
 package commands_test
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"github.com/pivotal-cf/jhanda"
 	"github.com/pivotal-cf/om/api"
 	"github.com/pivotal-cf/om/commands"
 	"github.com/pivotal-cf/om/commands/fakes"
@@ -16,44 +28,374 @@ var _ = Describe("UpdateSSLCertificate", func() {
 	var (
 		fakeLogger  *fakes.Logger
 		fakeService *fakes.UpdateSSLCertificateService
+		stdin       *strings.Reader
 		command     commands.UpdateSSLCertificate
+
+		rootKey       *rsa.PrivateKey
+		leafKey       *rsa.PrivateKey
+		root          testCertificate
+		intermediate  testCertificate
+		leaf          testCertificate
+		leafKeyPem    string
+		certificateOf func(template *x509.Certificate, parent *testCertificate) testCertificate
+
+		listener        net.Listener
+		served          atomic.Value
+		applied         atomic.Value
+		staleDials      int
+		dialedAddresses []string
+		dialTimeouts    []time.Duration
+		dial            func(dialer *net.Dialer, network, address string, config *tls.Config) (*tls.Conn, error)
 	)
 
+	logged := func() []string {
+		var lines []string
+		for i := 0; i < fakeLogger.PrintfCallCount(); i++ {
+			format, content := fakeLogger.PrintfArgsForCall(i)
+			lines = append(lines, fmt.Sprintf(format, content...))
+		}
+		return lines
+	}
+
+	leafTemplate := func(notBefore, notAfter time.Time, dnsNames ...string) *x509.Certificate {
+		return &x509.Certificate{
+			SerialNumber: big.NewInt(3),
+			Subject:      pkix.Name{CommonName: "opsman"},
+			NotBefore:    notBefore,
+			NotAfter:     notAfter,
+			DNSNames:     dnsNames,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		}
+	}
+
+	caTemplate := func(serial int64, name string) *x509.Certificate {
+		return &x509.Certificate{
+			SerialNumber:          big.NewInt(serial),
+			Subject:               pkix.Name{CommonName: name},
+			NotBefore:             time.Now().Add(-time.Hour),
+			NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+			IsCA:                  true,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageCertSign,
+		}
+	}
+
 	BeforeEach(func() {
+		var err error
+		rootKey, err = rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).ToNot(HaveOccurred())
+		leafKey, err = rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).ToNot(HaveOccurred())
+
+		certificateOf = func(template *x509.Certificate, parent *testCertificate) testCertificate {
+			if template.IsCA {
+				return newTestCertificate(template, rootKey, parent)
+			}
+			return newTestCertificate(template, leafKey, parent)
+		}
+
+		root = certificateOf(caTemplate(1, "root"), nil)
+		intermediate = certificateOf(caTemplate(2, "intermediate"), &root)
+		leaf = certificateOf(leafTemplate(time.Now().Add(-time.Hour), time.Now().Add(24*time.Hour), "opsman.example.com"), &intermediate)
+		leafKeyPem = string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(leafKey)}))
+
+		previous := certificateOf(leafTemplate(time.Now().Add(-time.Hour), time.Now().Add(24*time.Hour), "opsman.example.com"), &root)
+		previousPair, err := tls.X509KeyPair([]byte(previous.pem), []byte(leafKeyPem))
+		Expect(err).ToNot(HaveOccurred())
+		served = atomic.Value{}
+		served.Store(&previousPair)
+		applied = atomic.Value{}
+
+		listener, err = tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+			GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+				return served.Load().(*tls.Certificate), nil
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		go func(listener net.Listener) {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				_ = conn.(*tls.Conn).Handshake()
+				_ = conn.Close()
+			}
+		}(listener)
+
+		// Ops Manager serves the applied certificate after the stale dials
+		staleDials = 0
+		dialedAddresses = nil
+		dialTimeouts = nil
+		dial = func(dialer *net.Dialer, network, address string, config *tls.Config) (*tls.Conn, error) {
+			dialedAddresses = append(dialedAddresses, address)
+			dialTimeouts = append(dialTimeouts, dialer.Timeout)
+			if len(dialedAddresses) > staleDials {
+				if pair, ok := applied.Load().(*tls.Certificate); ok {
+					served.Store(pair)
+				}
+			}
+			return tls.DialWithDialer(dialer, network, listener.Addr().String(), config)
+		}
+
 		fakeService = &fakes.UpdateSSLCertificateService{}
+		fakeService.GetSecurityRootCACertificateReturns(root.pem, nil)
+		fakeService.UpdateSSLCertificateStub = func(input api.SSLCertificateInput) error {
+			pair, err := tls.X509KeyPair([]byte(input.CertPem), []byte(input.PrivateKeyPem))
+			Expect(err).ToNot(HaveOccurred())
+			applied.Store(&pair)
+			return nil
+		}
 		fakeLogger = &fakes.Logger{}
-		command = commands.NewUpdateSSLCertificate(fakeService, fakeLogger)
+		stdin = strings.NewReader("")
+		command = commands.NewUpdateSSLCertificate(fakeService, fakeLogger, stdin, "https://opsman.example.com/", 0, dial)
+	})
+
+	AfterEach(func() {
+		_ = listener.Close()
 	})
 
 	Describe("Execute", func() {
 		It("makes a request to the Opsman to apply a custom certificate", func() {
 			err := command.Execute([]string{
-				"--certificate-pem", "some CertPem",
-				"--private-key-pem", "some PrivateKey",
+				"--certificate-pem", leaf.pem + intermediate.pem,
+				"--private-key-pem", leafKeyPem,
 			})
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(fakeService.UpdateSSLCertificateCallCount()).To(Equal(1))
 			Expect(fakeService.UpdateSSLCertificateArgsForCall(0)).To(Equal(api.SSLCertificateInput{
-				CertPem:       "some CertPem",
-				PrivateKeyPem: "some PrivateKey",
+				CertPem:       leaf.pem + intermediate.pem,
+				PrivateKeyPem: leafKeyPem,
+			}))
+		})
+
+		It("does not connect to Ops Manager after the update by default", func() {
+			err := command.Execute([]string{
+				"--certificate-pem", leaf.pem + intermediate.pem,
+				"--private-key-pem", leafKeyPem,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(dialedAddresses).To(BeEmpty())
+			Expect(logged()).To(Equal([]string{
+				"Successfully applied custom SSL Certificate.\n",
+				"Please allow about 1 min for the new certificate to take effect.\n",
+			}))
+		})
+
+		It("prints a success message once Ops Manager serves the new certificate", func() {
+			staleDials = 1
+
+			err := command.Execute([]string{
+				"--certificate-pem", leaf.pem + intermediate.pem,
+				"--private-key-pem", leafKeyPem,
+				"--ready-timeout", "60",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(dialedAddresses).To(Equal([]string{"opsman.example.com:443", "opsman.example.com:443"}))
+			for _, timeout := range dialTimeouts {
+				Expect(timeout).To(BeNumerically(">", 0))
+				Expect(timeout).To(BeNumerically("<=", time.Minute))
+			}
+			Expect(logged()).To(Equal([]string{
+				"Successfully applied custom SSL Certificate.\n",
+				"Please allow about 1 min for the new certificate to take effect.\n",
+				"Ops Manager is answering with the new certificate.\n",
 			}))
 		})
 
-		It("prints a success message saying the custom cert was applied", func() {
-			fakeService.UpdateSSLCertificateReturns(nil)
+		It("connects to the port of the target", func() {
+			command = commands.NewUpdateSSLCertificate(fakeService, fakeLogger, stdin, "https://opsman.example.com:8443", 0, dial)
 
 			err := command.Execute([]string{
-				"--certificate-pem", "some CertPem",
-				"--private-key-pem", "some PrivateKey",
+				"--certificate-pem", leaf.pem + intermediate.pem,
+				"--private-key-pem", leafKeyPem,
+				"--ready-timeout", "60",
 			})
 			Expect(err).ToNot(HaveOccurred())
+			Expect(dialedAddresses).To(Equal([]string{"opsman.example.com:8443"}))
+		})
+
+		It("accepts a certificate whose chain ends with a self-signed certificate", func() {
+			fakeService.GetSecurityRootCACertificateReturns("", nil)
+
+			err := command.Execute([]string{
+				"--certificate-pem", leaf.pem + intermediate.pem + root.pem,
+				"--private-key-pem", leafKeyPem,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fakeService.UpdateSSLCertificateCallCount()).To(Equal(1))
+		})
 
-			Expect(fakeLogger.PrintfCallCount()).To(Equal(2))
-			format, content := fakeLogger.PrintfArgsForCall(0)
-			Expect(fmt.Sprintf(format, content...)).To(Equal("Successfully applied custom SSL Certificate.\n"))
-			format, content = fakeLogger.PrintfArgsForCall(1)
-			Expect(fmt.Sprintf(format, content...)).To(Equal("Please allow about 1 min for the new certificate to take effect.\n"))
+		When("the --generate flag is provided", func() {
+			BeforeEach(func() {
+				generated := certificateOf(leafTemplate(time.Now().Add(-time.Hour), time.Now().Add(24*time.Hour), "opsman.example.com"), &root)
+				output, err := json.Marshal(map[string]string{"certificate": generated.pem, "key": leafKeyPem})
+				Expect(err).ToNot(HaveOccurred())
+				fakeService.GenerateCertificateReturns(string(output), nil)
+			})
+
+			It("applies a certificate generated for the target host", func() {
+				err := command.Execute([]string{"--generate"})
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(fakeService.GenerateCertificateArgsForCall(0)).To(Equal(api.DomainsInput{Domains: []string{"opsman.example.com"}}))
+				Expect(fakeService.UpdateSSLCertificateCallCount()).To(Equal(1))
+				Expect(fakeService.UpdateSSLCertificateArgsForCall(0).PrivateKeyPem).To(Equal(leafKeyPem))
+				Expect(logged()[0]).To(Equal("Generated a certificate for opsman.example.com signed by the Ops Manager root CA.\n"))
+			})
+
+			It("returns an error when the certificate cannot be generated", func() {
+				fakeService.GenerateCertificateReturns("", errors.New("boom"))
+
+				err := command.Execute([]string{"--generate"})
+				Expect(err).To(MatchError("could not generate a certificate for opsman.example.com: boom"))
+				Expect(fakeService.UpdateSSLCertificateCallCount()).To(Equal(0))
+			})
+
+			It("returns an error when a certificate is also provided", func() {
+				err := command.Execute([]string{"--generate", "--certificate-pem", leaf.pem})
+				Expect(err).To(MatchError("could not parse update-ssl-certificate flags: --generate cannot be used with --certificate-pem or --private-key-pem"))
+			})
+		})
+
+		Context("pre-flight checks", func() {
+			It("does not apply a certificate that does not match the key", func() {
+				otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = command.Execute([]string{
+					"--certificate-pem", leaf.pem + intermediate.pem,
+					"--private-key-pem", string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(otherKey)})),
+				})
+				Expect(err).To(MatchError(ContainSubstring("the SSL certificate was not applied: the private key does not match the certificate: ")))
+				Expect(fakeService.UpdateSSLCertificateCallCount()).To(Equal(0))
+			})
+
+			It("does not apply a certificate whose chain is incomplete", func() {
+				err := command.Execute([]string{
+					"--certificate-pem", leaf.pem,
+					"--private-key-pem", leafKeyPem,
+				})
+				Expect(err).To(MatchError(ContainSubstring("the SSL certificate was not applied: the certificate chain is incomplete: ")))
+				Expect(fakeService.UpdateSSLCertificateCallCount()).To(Equal(0))
+			})
+
+			It("does not apply a certificate whose SANs do not cover the target", func() {
+				leaf = certificateOf(leafTemplate(time.Now().Add(-time.Hour), time.Now().Add(24*time.Hour), "other.example.com"), &intermediate)
+
+				err := command.Execute([]string{
+					"--certificate-pem", leaf.pem + intermediate.pem,
+					"--private-key-pem", leafKeyPem,
+				})
+				Expect(err).To(MatchError(ContainSubstring("the SSL certificate was not applied: the SANs of the certificate do not cover the target: ")))
+				Expect(fakeService.UpdateSSLCertificateCallCount()).To(Equal(0))
+			})
+
+			It("does not apply a certificate that has expired", func() {
+				expiredAt := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+				leaf = certificateOf(leafTemplate(time.Now().Add(-2*time.Hour), expiredAt, "opsman.example.com"), &intermediate)
+
+				err := command.Execute([]string{
+					"--certificate-pem", leaf.pem + intermediate.pem,
+					"--private-key-pem", leafKeyPem,
+				})
+				Expect(err).To(MatchError(fmt.Sprintf("the SSL certificate was not applied: the certificate expired on %s", expiredAt.Format(time.RFC3339))))
+				Expect(fakeService.UpdateSSLCertificateCallCount()).To(Equal(0))
+			})
+
+			It("applies the certificate without checking it with --skip-checks", func() {
+				err := command.Execute([]string{
+					"--certificate-pem", leaf.pem,
+					"--private-key-pem", leafKeyPem,
+					"--skip-checks",
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(fakeService.GetSecurityRootCACertificateCallCount()).To(Equal(0))
+				Expect(fakeService.UpdateSSLCertificateCallCount()).To(Equal(1))
+			})
+
+			It("does not apply a certificate that cannot be parsed, even with --skip-checks", func() {
+				err := command.Execute([]string{
+					"--certificate-pem", "not a certificate",
+					"--private-key-pem", leafKeyPem,
+					"--skip-checks",
+				})
+				Expect(err).To(MatchError("the SSL certificate was not applied: could not parse the certificate: no PEM certificate found"))
+				Expect(fakeService.UpdateSSLCertificateCallCount()).To(Equal(0))
+			})
+
+			It("returns an error when the root CA certificate cannot be fetched", func() {
+				fakeService.GetSecurityRootCACertificateReturns("", errors.New("boom"))
+
+				err := command.Execute([]string{
+					"--certificate-pem", leaf.pem + intermediate.pem,
+					"--private-key-pem", leafKeyPem,
+				})
+				Expect(err).To(MatchError("the SSL certificate was not applied: could not get the root CA certificate: boom"))
+			})
+		})
+
+		When("Ops Manager does not serve the new certificate after the update", func() {
+			BeforeEach(func() {
+				staleDials = math.MaxInt32
+				command = commands.NewUpdateSSLCertificate(fakeService, fakeLogger, stdin, "https://opsman.example.com/", 100*time.Millisecond, dial)
+			})
+
+			It("rolls back to the default certificate when confirmed", func() {
+				stdin.Reset("yes\n")
+
+				err := command.Execute([]string{
+					"--certificate-pem", leaf.pem + intermediate.pem,
+					"--private-key-pem", leafKeyPem,
+					"--ready-timeout", "1",
+				})
+				Expect(err).To(MatchError("Ops Manager is not answering with the new certificate, rolled back to the default Ops Manager certificate"))
+
+				Expect(fakeService.DeleteSSLCertificateCallCount()).To(Equal(1))
+				Expect(logged()).To(ContainElement(HavePrefix(`Ops Manager is not serving the new certificate after 1s: Ops Manager still serves another certificate (subject "opsman", expires on `)))
+				Expect(logged()).To(ContainElement("Do you want to roll back to the default Ops Manager certificate? [yes/no]: "))
+			})
+
+			It("keeps the new certificate when not confirmed", func() {
+				stdin.Reset("no\n")
+
+				err := command.Execute([]string{
+					"--certificate-pem", leaf.pem + intermediate.pem,
+					"--private-key-pem", leafKeyPem,
+					"--ready-timeout", "1",
+				})
+				Expect(err).To(MatchError(HavePrefix("Ops Manager is not answering with the new certificate: Ops Manager still serves another certificate")))
+				Expect(fakeService.DeleteSSLCertificateCallCount()).To(Equal(0))
+			})
+
+			It("keeps waiting while Ops Manager cannot be reached", func() {
+				stdin.Reset("no\n")
+				Expect(listener.Close()).To(Succeed())
+
+				err := command.Execute([]string{
+					"--certificate-pem", leaf.pem + intermediate.pem,
+					"--private-key-pem", leafKeyPem,
+					"--ready-timeout", "1",
+				})
+				Expect(err).To(MatchError(ContainSubstring("Ops Manager is not answering with the new certificate: ")))
+			})
+
+			It("returns an error when the rollback fails", func() {
+				stdin.Reset("yes\n")
+				fakeService.DeleteSSLCertificateReturns(errors.New("boom"))
+
+				err := command.Execute([]string{
+					"--certificate-pem", leaf.pem + intermediate.pem,
+					"--private-key-pem", leafKeyPem,
+					"--ready-timeout", "1",
+				})
+				Expect(err).To(MatchError("could not roll back to the default Ops Manager certificate: boom"))
+			})
 		})
 
 		Context("failure cases", func() {
@@ -62,8 +404,8 @@ var _ = Describe("UpdateSSLCertificate", func() {
 					fakeService.UpdateSSLCertificateReturns(errors.New("failed to apply certificate"))
 
 					err := command.Execute([]string{
-						"--certificate-pem", "some CertPem",
-						"--private-key-pem", "some PrivateKey",
+						"--certificate-pem", leaf.pem + intermediate.pem,
+						"--private-key-pem", leafKeyPem,
 					})
 					Expect(err).To(MatchError("failed to apply certificate"))
 				})
@@ -93,17 +435,24 @@ var _ = Describe("UpdateSSLCertificate", func() {
 					Expect(err).To(MatchError("could not parse update-ssl-certificate flags: missing required flag \"--private-key-pem\""))
 				})
 			})
+
+			When("the target is not set", func() {
+				It("returns an error", func() {
+					command = commands.NewUpdateSSLCertificate(fakeService, fakeLogger, stdin, "", 0, dial)
+
+					err := command.Execute([]string{"--generate"})
+					Expect(err).To(MatchError("target flag is required. Run `om help` for more info."))
+				})
+			})
 		})
 	})
 
 	Describe("Usage", func() {
 		It("returns usage info", func() {
 			usage := command.Usage()
-			Expect(usage).To(Equal(jhanda.Usage{
-				Description:      "This authenticated command updates the SSL Certificate on the Ops Manager with the given cert and key",
-				ShortDescription: "updates the SSL Certificate on the Ops Manager",
-				Flags:            command.Options,
-			}))
+			Expect(usage.ShortDescription).To(Equal("updates the SSL Certificate on the Ops Manager"))
+			Expect(usage.Description).To(ContainSubstring("checks that the key matches the certificate"))
+			Expect(usage.Flags).To(Equal(command.Options))
 		})
 	})
 })
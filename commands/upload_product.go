@@ -1,10 +1,14 @@
 package commands
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
 
 	"github.com/pivotal-cf/jhanda"
 	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/configtemplate/generator"
 	"github.com/pivotal-cf/om/extractor"
 	"github.com/pivotal-cf/om/network"
 	"github.com/pivotal-cf/om/validator"
@@ -13,15 +17,22 @@ import (
 const maxProductUploadRetries = 2
 
 type UploadProduct struct {
-	multipart multipart
-	logger    logger
-	service   uploadProductService
-	Options   struct {
+	multipart    multipart
+	logger       logger
+	service      uploadProductService
+	strict       bool
+	defaultFlags []string
+	Options      struct {
 		ConfigFile      string `long:"config"           short:"c"   description:"path to yml file for configuration (keys must match the following command line flags)"`
 		Product         string `long:"product"          short:"p"   description:"path to product" required:"true"`
 		PollingInterval int    `long:"polling-interval" short:"pi"  description:"interval (in seconds) at which to print status" default:"1"`
 		Shasum          string `long:"shasum"                       description:"shasum of the provided product file to be used for validation"`
 		Version         string `long:"product-version"              description:"version of the provided product file to be used for validation"`
+		ShaCache        string `long:"sha-cache"                    description:"path to a local cache of product shasums and metadata, used to skip reading unchanged product files"`
+		StatusFile      string `long:"status-file"                  description:"path to write a JSON report of the upload result, including whether the product was already uploaded"`
+
+		CheckCompatibility bool `long:"check-compatibility" description:"after uploading, check that the stemcells and products required by the product are available"`
+		FailOnMissing      bool `long:"fail-on-missing"     description:"exit with an error if required stemcells or products are missing (implies --check-compatibility)"`
 	}
 	metadataExtractor metadataExtractor
 }
@@ -30,6 +41,16 @@ type UploadProduct struct {
 type uploadProductService interface {
 	UploadAvailableProduct(api.UploadAvailableProductInput) (api.UploadAvailableProductOutput, error)
 	CheckProductAvailability(string, string) (bool, error)
+	productCompatibilityService
+}
+
+type uploadProductStatus struct {
+	Name             string   `json:"name"`
+	Version          string   `json:"product_version"`
+	SHA256           string   `json:"sha256,omitempty"`
+	AlreadyUploaded  bool     `json:"already_uploaded"`
+	MissingStemcells []string `json:"missing_stemcells,omitempty"`
+	MissingProducts  []string `json:"missing_products,omitempty"`
 }
 
 //counterfeiter:generate -o ./fakes/metadata_extractor.go --fake-name MetadataExtractor . metadataExtractor
@@ -37,12 +58,13 @@ type metadataExtractor interface {
 	ExtractMetadata(string) (extractor.Metadata, error)
 }
 
-func NewUploadProduct(multipart multipart, metadataExtractor metadataExtractor, service uploadProductService, logger logger) UploadProduct {
+func NewUploadProduct(multipart multipart, metadataExtractor metadataExtractor, service uploadProductService, logger logger, strict bool) UploadProduct {
 	return UploadProduct{
 		multipart:         multipart,
 		metadataExtractor: metadataExtractor,
 		logger:            logger,
 		service:           service,
+		strict:            strict,
 	}
 }
 
@@ -54,20 +76,46 @@ func (up UploadProduct) Usage() jhanda.Usage {
 	}
 }
 
+func (up UploadProduct) WithDefaultFlags(defaultFlags []string) jhanda.Command {
+	up.defaultFlags = defaultFlags
+	return up
+}
+
 func (up UploadProduct) Execute(args []string) error {
-	err := loadConfigFile(args, &up.Options, nil)
+	err := loadConfigFile(args, &up.Options, nil, up.strict, up.defaultFlags)
 	if err != nil {
 		return fmt.Errorf("could not parse upload-product flags: %s", err)
 	}
 
-	if up.Options.Shasum != "" {
-		shaValidator := validator.NewSHA256Calculator()
-		shasum, err := shaValidator.Checksum(up.Options.Product)
+	var (
+		cache    extractor.ShaCache
+		cached   extractor.CachedProduct
+		isCached bool
+		shasum   string
+	)
 
+	if up.Options.ShaCache != "" {
+		cache = extractor.NewShaCache(up.Options.ShaCache)
+		cached, isCached, err = cache.Lookup(up.Options.Product)
+		if err != nil {
+			return fmt.Errorf("failed to read sha cache: %s", err)
+		}
+
+		if isCached {
+			shasum = cached.SHA256
+			up.logger.Printf("found product %s %s in sha cache", cached.Name, cached.Version)
+		}
+	}
+
+	if shasum == "" && (up.Options.Shasum != "" || up.Options.ShaCache != "") {
+		shaValidator := validator.NewSHA256Calculator()
+		shasum, err = shaValidator.Checksum(up.Options.Product)
 		if err != nil {
 			return err
 		}
+	}
 
+	if up.Options.Shasum != "" {
 		if shasum != up.Options.Shasum {
 			return fmt.Errorf("expected shasum %s does not match file shasum %s", up.Options.Shasum, shasum)
 		}
@@ -75,9 +123,21 @@ func (up UploadProduct) Execute(args []string) error {
 		up.logger.Printf("expected shasum matches product shasum.")
 	}
 
-	metadata, err := up.metadataExtractor.ExtractMetadata(up.Options.Product)
-	if err != nil {
-		return fmt.Errorf("failed to extract product metadata: %s", err)
+	var metadata extractor.Metadata
+	if isCached {
+		metadata = extractor.Metadata{Name: cached.Name, Version: cached.Version}
+	} else {
+		metadata, err = up.metadataExtractor.ExtractMetadata(up.Options.Product)
+		if err != nil {
+			return fmt.Errorf("failed to extract product metadata: %s", err)
+		}
+
+		if up.Options.ShaCache != "" {
+			_, err = cache.Record(up.Options.Product, shasum, metadata)
+			if err != nil {
+				return fmt.Errorf("failed to update sha cache: %s", err)
+			}
+		}
 	}
 
 	if up.Options.Version != "" {
@@ -87,6 +147,12 @@ func (up UploadProduct) Execute(args []string) error {
 		up.logger.Printf("expected version matches product version.")
 	}
 
+	status := uploadProductStatus{
+		Name:    metadata.Name,
+		Version: metadata.Version,
+		SHA256:  shasum,
+	}
+
 	var prodAvailable bool
 	prodAvailable, err = up.service.CheckProductAvailability(metadata.Name, metadata.Version)
 	if err != nil {
@@ -95,7 +161,8 @@ func (up UploadProduct) Execute(args []string) error {
 
 	if prodAvailable {
 		up.logger.Printf("product %s %s is already uploaded, nothing to be done", metadata.Name, metadata.Version)
-		return nil
+		status.AlreadyUploaded = true
+		return up.complete(status, metadata)
 	}
 
 	for i := 0; i <= maxProductUploadRetries; i++ {
@@ -126,7 +193,7 @@ func (up UploadProduct) Execute(args []string) error {
 			}
 			if prodAvailable {
 				up.logger.Printf("product %s %s has been successfully uploaded", metadata.Name, metadata.Version)
-				return nil
+				return up.complete(status, metadata)
 			}
 		} else {
 			break
@@ -138,5 +205,63 @@ func (up UploadProduct) Execute(args []string) error {
 
 	up.logger.Printf("finished upload")
 
+	return up.complete(status, metadata)
+}
+
+func (up UploadProduct) complete(status uploadProductStatus, metadata extractor.Metadata) error {
+	if !up.Options.CheckCompatibility && !up.Options.FailOnMissing {
+		return up.writeStatus(status)
+	}
+
+	var err error
+	if len(metadata.Raw) == 0 {
+		metadata, err = up.metadataExtractor.ExtractMetadata(up.Options.Product)
+		if err != nil {
+			return fmt.Errorf("failed to extract product metadata: %s", err)
+		}
+	}
+
+	productMetadata, err := generator.NewMetadata(metadata.Raw)
+	if err != nil {
+		return fmt.Errorf("failed to parse product metadata: %s", err)
+	}
+
+	compatibility, err := checkProductCompatibility(up.service, productMetadata)
+	if err != nil {
+		return fmt.Errorf("failed to check product compatibility: %s", err)
+	}
+
+	compatibility.report(up.logger, metadata.Name, metadata.Version)
+
+	status.MissingStemcells = compatibility.MissingStemcells
+	status.MissingProducts = compatibility.MissingProducts
+
+	err = up.writeStatus(status)
+	if err != nil {
+		return err
+	}
+
+	if up.Options.FailOnMissing && !compatibility.Compatible() {
+		return errors.New("required stemcells or products are missing")
+	}
+
+	return nil
+}
+
+func (up UploadProduct) writeStatus(status uploadProductStatus) error {
+	if up.Options.StatusFile == "" {
+		return nil
+	}
+
+	contents, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("could not encode upload status: %s", err)
+	}
+
+	err = ioutil.WriteFile(up.Options.StatusFile, contents, 0644)
+	if err != nil {
+		return fmt.Errorf("could not write status file %s: %s", up.Options.StatusFile, err)
+	}
+
 	return nil
 }
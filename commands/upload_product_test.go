@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -45,7 +46,7 @@ var _ = Describe("UploadProduct", func() {
 		}
 		multipart.FinalizeReturns(submission)
 
-		command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
+		command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger, false)
 
 		err := command.Execute([]string{
 			"--product", "/path/to/some-product.tgz",
@@ -76,7 +77,7 @@ var _ = Describe("UploadProduct", func() {
 
 	When("the polling interval is provided", func() {
 		It("passes the value to the products service", func() {
-			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
+			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger, false)
 			err := command.Execute([]string{
 				"--product", "/path/to/some-product.tgz",
 				"--polling-interval", "48",
@@ -88,7 +89,7 @@ var _ = Describe("UploadProduct", func() {
 
 	When("the same product is already present", func() {
 		It("does nothing and exits gracefully", func() {
-			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
+			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger, false)
 			metadataExtractor.ExtractMetadataReturns(extractor.Metadata{
 				Name:    "cf",
 				Version: "1.5.0",
@@ -112,6 +113,170 @@ var _ = Describe("UploadProduct", func() {
 		})
 	})
 
+	When("the --sha-cache flag is defined", func() {
+		var (
+			productFile string
+			cacheFile   string
+		)
+
+		BeforeEach(func() {
+			file, err := ioutil.TempFile("", "product-*.pivotal")
+			Expect(err).ToNot(HaveOccurred())
+			_, err = file.WriteString("testing-shasum")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(file.Close()).To(Succeed())
+			productFile = file.Name()
+
+			cacheDir, err := ioutil.TempDir("", "")
+			Expect(err).ToNot(HaveOccurred())
+			cacheFile = filepath.Join(cacheDir, "sha-cache.json")
+
+			metadataExtractor.ExtractMetadataReturns(extractor.Metadata{
+				Name:    "cf",
+				Version: "1.5.0",
+			}, nil)
+		})
+
+		AfterEach(func() {
+			os.Remove(productFile)
+			os.RemoveAll(filepath.Dir(cacheFile))
+		})
+
+		It("records the product and does not extract the metadata again on the next run", func() {
+			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger, false)
+			err := command.Execute([]string{
+				"--product", productFile,
+				"--sha-cache", cacheFile,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(metadataExtractor.ExtractMetadataCallCount()).To(Equal(1))
+			Expect(fakeService.UploadAvailableProductCallCount()).To(Equal(1))
+
+			fakeService.CheckProductAvailabilityReturns(true, nil)
+
+			command = commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger, false)
+			err = command.Execute([]string{
+				"--product", productFile,
+				"--sha-cache", cacheFile,
+				"--shasum", "2815ab9694a4a2cfd59424a734833010e143a0b2db20be3741507f177f289f44",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(metadataExtractor.ExtractMetadataCallCount()).To(Equal(1))
+			Expect(fakeService.UploadAvailableProductCallCount()).To(Equal(1))
+
+			name, version := fakeService.CheckProductAvailabilityArgsForCall(1)
+			Expect(name).To(Equal("cf"))
+			Expect(version).To(Equal("1.5.0"))
+		})
+
+		It("returns an error when the cache cannot be read", func() {
+			err := ioutil.WriteFile(cacheFile, []byte("not-json"), 0644)
+			Expect(err).ToNot(HaveOccurred())
+
+			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger, false)
+			err = command.Execute([]string{
+				"--product", productFile,
+				"--sha-cache", cacheFile,
+			})
+			Expect(err).To(MatchError(ContainSubstring("failed to read sha cache")))
+		})
+	})
+
+	When("the --status-file flag is defined", func() {
+		var statusFile string
+
+		BeforeEach(func() {
+			file, err := ioutil.TempFile("", "status-*.json")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(file.Close()).To(Succeed())
+			statusFile = file.Name()
+
+			metadataExtractor.ExtractMetadataReturns(extractor.Metadata{
+				Name:    "cf",
+				Version: "1.5.0",
+			}, nil)
+		})
+
+		AfterEach(func() {
+			os.Remove(statusFile)
+		})
+
+		It("reports that the product was already uploaded", func() {
+			fakeService.CheckProductAvailabilityReturns(true, nil)
+
+			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger, false)
+			err := command.Execute([]string{
+				"--product", "/path/to/some-product.tgz",
+				"--status-file", statusFile,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			contents, err := ioutil.ReadFile(statusFile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(contents).To(MatchJSON(`{"name":"cf","product_version":"1.5.0","already_uploaded":true}`))
+		})
+
+		It("reports that the product was uploaded", func() {
+			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger, false)
+			err := command.Execute([]string{
+				"--product", "/path/to/some-product.tgz",
+				"--status-file", statusFile,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			contents, err := ioutil.ReadFile(statusFile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(contents).To(MatchJSON(`{"name":"cf","product_version":"1.5.0","already_uploaded":false}`))
+		})
+	})
+
+	When("the --check-compatibility flag is defined", func() {
+		BeforeEach(func() {
+			fakeService.InfoReturns(api.Info{Version: "2.6.0"}, nil)
+			metadataExtractor.ExtractMetadataReturns(extractor.Metadata{
+				Name:    "some-product",
+				Version: "1.2.3",
+				Raw:     []byte(compatibilityMetadata),
+			}, nil)
+		})
+
+		It("reports the missing stemcells and products after the upload", func() {
+			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger, false)
+			err := command.Execute([]string{
+				"--product", "/path/to/some-product.tgz",
+				"--check-compatibility",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fakeService.UploadAvailableProductCallCount()).To(Equal(1))
+
+			format, v := logger.PrintfArgsForCall(3)
+			Expect(fmt.Sprintf(format, v...)).To(ContainSubstring("requires stemcells that have not been uploaded"))
+			format, v = logger.PrintfArgsForCall(4)
+			Expect(fmt.Sprintf(format, v...)).To(ContainSubstring("requires products that have not been deployed"))
+		})
+
+		It("returns an error with --fail-on-missing", func() {
+			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger, false)
+			err := command.Execute([]string{
+				"--product", "/path/to/some-product.tgz",
+				"--fail-on-missing",
+			})
+			Expect(err).To(MatchError("required stemcells or products are missing"))
+			Expect(fakeService.UploadAvailableProductCallCount()).To(Equal(1))
+		})
+
+		It("returns an error when the compatibility cannot be checked", func() {
+			fakeService.ListMultiStemcellsReturns(api.ProductMultiStemcells{}, errors.New("some error"))
+
+			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger, false)
+			err := command.Execute([]string{
+				"--product", "/path/to/some-product.tgz",
+				"--check-compatibility",
+			})
+			Expect(err).To(MatchError("failed to check product compatibility: could not list stemcells: some error"))
+		})
+	})
+
 	When("the --shasum flag is defined", func() {
 		It("proceeds normally when the sha sums match", func() {
 			file, err := ioutil.TempFile("", "test-file.yaml")
@@ -123,7 +288,7 @@ var _ = Describe("UploadProduct", func() {
 			err = file.Close()
 			Expect(err).ToNot(HaveOccurred())
 
-			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
+			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger, false)
 			metadataExtractor.ExtractMetadataReturns(extractor.Metadata{
 				Name:    "cf",
 				Version: "1.5.0",
@@ -157,7 +322,7 @@ var _ = Describe("UploadProduct", func() {
 			err = file.Close()
 			Expect(err).ToNot(HaveOccurred())
 
-			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
+			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger, false)
 			err = command.Execute([]string{
 				"--product", file.Name(),
 				"--shasum", "not-the-correct-shasum",
@@ -167,7 +332,7 @@ var _ = Describe("UploadProduct", func() {
 		})
 
 		It("fails when the file can not calculate a shasum", func() {
-			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
+			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger, false)
 			err := command.Execute([]string{
 				"--product", "/path/to/testing.tgz",
 				"--shasum", "not-the-correct-shasum",
@@ -189,7 +354,7 @@ var _ = Describe("UploadProduct", func() {
 				Name:    "cf",
 				Version: "1.5.0",
 			}, nil)
-			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
+			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger, false)
 			fakeService.CheckProductAvailabilityStub = func(name, version string) (bool, error) {
 				if name == "cf" && version == "1.5.0" {
 					return true, nil
@@ -220,7 +385,7 @@ var _ = Describe("UploadProduct", func() {
 				Name:    "cf",
 				Version: "1.5.0",
 			}, nil)
-			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
+			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger, false)
 			err = command.Execute([]string{
 				"--product", file.Name(),
 				"--product-version", "2.5.0",
@@ -236,7 +401,7 @@ var _ = Describe("UploadProduct", func() {
 				stdout := gbytes.NewBuffer()
 				logger := log.New(stdout, "", 0)
 
-				command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
+				command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger, false)
 
 				fakeService.UploadAvailableProductReturnsOnCall(0, api.UploadAvailableProductOutput{}, errors.Wrap(io.EOF, "some upload error"))
 				fakeService.UploadAvailableProductReturnsOnCall(1, api.UploadAvailableProductOutput{}, nil)
@@ -261,7 +426,7 @@ var _ = Describe("UploadProduct", func() {
 		})
 
 		It("tries again", func() {
-			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
+			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger, false)
 
 			fakeService.UploadAvailableProductReturnsOnCall(0, api.UploadAvailableProductOutput{}, errors.Wrap(io.EOF, "some upload error"))
 			fakeService.UploadAvailableProductReturnsOnCall(1, api.UploadAvailableProductOutput{}, nil)
@@ -279,7 +444,7 @@ var _ = Describe("UploadProduct", func() {
 
 	When("the product fails to upload three times", func() {
 		It("returns an error", func() {
-			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
+			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger, false)
 
 			fakeService.CheckProductAvailabilityReturns(false, nil)
 			fakeService.UploadAvailableProductReturns(api.UploadAvailableProductOutput{}, errors.Wrap(io.EOF, "some upload error"))
@@ -323,7 +488,7 @@ product: will-be-overridden-by-command-line
 				Name:    "cf",
 				Version: "1.5.0",
 			}, nil)
-			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
+			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger, false)
 			fakeService.CheckProductAvailabilityStub = func(name, version string) (bool, error) {
 				if name == "cf" && version == "1.5.0" {
 					return true, nil
@@ -347,7 +512,7 @@ product: will-be-overridden-by-command-line
 	Context("failure cases", func() {
 		When("an unknown flag is provided", func() {
 			It("returns an error", func() {
-				command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
+				command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger, false)
 				err := command.Execute([]string{"--badflag"})
 				Expect(err).To(MatchError("could not parse upload-product flags: flag provided but not defined: -badflag"))
 			})
@@ -355,7 +520,7 @@ product: will-be-overridden-by-command-line
 
 		When("the product flag is not provided", func() {
 			It("returns an error", func() {
-				command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
+				command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger, false)
 				err := command.Execute([]string{})
 				Expect(err).To(MatchError("could not parse upload-product flags: missing required flag \"--product\""))
 			})
@@ -364,7 +529,7 @@ product: will-be-overridden-by-command-line
 		When("extracting the product metadata returns an error", func() {
 			It("returns an error", func() {
 				metadataExtractor.ExtractMetadataReturns(extractor.Metadata{}, errors.New("some error"))
-				command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
+				command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger, false)
 				err := command.Execute([]string{"--product", "/some/path"})
 				Expect(err).To(MatchError("failed to extract product metadata: some error"))
 			})
@@ -373,7 +538,7 @@ product: will-be-overridden-by-command-line
 		When("checking for product availability returns an error", func() {
 			It("returns an error", func() {
 				fakeService.CheckProductAvailabilityReturns(true, errors.New("some error"))
-				command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
+				command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger, false)
 				err := command.Execute([]string{"--product", "/some/path"})
 				Expect(err).To(MatchError("failed to check product availability: some error"))
 			})
@@ -381,7 +546,7 @@ product: will-be-overridden-by-command-line
 
 		When("adding the file fails", func() {
 			It("returns an error", func() {
-				command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
+				command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger, false)
 				multipart.AddFileReturns(errors.New("bad file"))
 
 				err := command.Execute([]string{"--product", "/some/path"})
@@ -391,7 +556,7 @@ product: will-be-overridden-by-command-line
 
 		When("the product cannot be uploaded", func() {
 			It("returns an error", func() {
-				command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
+				command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger, false)
 				fakeService.UploadAvailableProductReturns(api.UploadAvailableProductOutput{}, errors.New("some product error"))
 
 				err := command.Execute([]string{"--product", "/some/path"})
@@ -402,7 +567,7 @@ product: will-be-overridden-by-command-line
 
 	Describe("Usage", func() {
 		It("returns usage information for the command", func() {
-			command := commands.NewUploadProduct(nil, nil, nil, nil)
+			command := commands.NewUploadProduct(nil, nil, nil, nil, false)
 			Expect(command.Usage()).To(Equal(jhanda.Usage{
 				Description:      "This command attempts to upload a product to the Ops Manager",
 				ShortDescription: "uploads a given product to the Ops Manager targeted",
@@ -16,10 +16,12 @@ import (
 const maxStemcellUploadRetries = 2
 
 type UploadStemcell struct {
-	multipart multipart
-	logger    logger
-	service   uploadStemcellService
-	Options   struct {
+	multipart    multipart
+	logger       logger
+	service      uploadStemcellService
+	strict       bool
+	defaultFlags []string
+	Options      struct {
 		ConfigFile string `long:"config"   short:"c"                 description:"path to yml file for configuration (keys must match the following command line flags)"`
 		Stemcell   string `long:"stemcell" short:"s" required:"true" description:"path to stemcell"`
 		Force      bool   `long:"force"    short:"f"                 description:"upload stemcell even if it already exists on the target Ops Manager"`
@@ -43,11 +45,12 @@ type uploadStemcellService interface {
 	Info() (api.Info, error)
 }
 
-func NewUploadStemcell(multipart multipart, service uploadStemcellService, logger logger) UploadStemcell {
+func NewUploadStemcell(multipart multipart, service uploadStemcellService, logger logger, strict bool) UploadStemcell {
 	return UploadStemcell{
 		multipart: multipart,
 		logger:    logger,
 		service:   service,
+		strict:    strict,
 	}
 }
 
@@ -59,8 +62,13 @@ func (us UploadStemcell) Usage() jhanda.Usage {
 	}
 }
 
+func (us UploadStemcell) WithDefaultFlags(defaultFlags []string) jhanda.Command {
+	us.defaultFlags = defaultFlags
+	return us
+}
+
 func (us UploadStemcell) Execute(args []string) error {
-	err := loadConfigFile(args, &us.Options, nil)
+	err := loadConfigFile(args, &us.Options, nil, us.strict, us.defaultFlags)
 	if err != nil {
 		return fmt.Errorf("could not parse upload-stemcell flags: %s", err)
 	}
@@ -44,7 +44,7 @@ var _ = Describe("UploadStemcell", func() {
 
 			fakeService.GetDiagnosticReportReturns(api.DiagnosticReport{Stemcells: []string{}}, nil)
 
-			command := commands.NewUploadStemcell(multipart, fakeService, logger)
+			command := commands.NewUploadStemcell(multipart, fakeService, logger, false)
 
 			err := command.Execute([]string{
 				"--stemcell", "/path/to/stemcell.tgz",
@@ -90,7 +90,7 @@ var _ = Describe("UploadStemcell", func() {
 
 				fakeService.GetDiagnosticReportReturns(api.DiagnosticReport{Stemcells: []string{}}, nil)
 
-				command = commands.NewUploadStemcell(multipart, fakeService, logger)
+				command = commands.NewUploadStemcell(multipart, fakeService, logger, false)
 			})
 
 			It("disables floating", func() {
@@ -159,7 +159,7 @@ var _ = Describe("UploadStemcell", func() {
 
 				fakeService.GetDiagnosticReportReturns(api.DiagnosticReport{Stemcells: []string{}}, nil)
 
-				command := commands.NewUploadStemcell(multipart, fakeService, logger)
+				command := commands.NewUploadStemcell(multipart, fakeService, logger, false)
 
 				fakeService.UploadStemcellReturnsOnCall(0, api.StemcellUploadOutput{}, errors.Wrap(io.EOF, "some upload error"))
 				fakeService.UploadStemcellReturnsOnCall(1, api.StemcellUploadOutput{}, nil)
@@ -189,7 +189,7 @@ var _ = Describe("UploadStemcell", func() {
 
 				fakeService.GetDiagnosticReportReturns(api.DiagnosticReport{Stemcells: []string{}}, nil)
 
-				command := commands.NewUploadStemcell(multipart, fakeService, logger)
+				command := commands.NewUploadStemcell(multipart, fakeService, logger, false)
 
 				fakeService.UploadStemcellReturns(api.StemcellUploadOutput{}, errors.Wrap(io.EOF, "some upload error"))
 
@@ -222,7 +222,7 @@ var _ = Describe("UploadStemcell", func() {
 					Stemcells: []string{"stemcell.tgz"},
 				}, nil)
 
-				command := commands.NewUploadStemcell(multipart, fakeService, logger)
+				command := commands.NewUploadStemcell(multipart, fakeService, logger, false)
 
 				err := command.Execute([]string{
 					"--stemcell", "/path/to/stemcell.tgz",
@@ -254,7 +254,7 @@ var _ = Describe("UploadStemcell", func() {
 						},
 					}, nil)
 
-					command := commands.NewUploadStemcell(multipart, fakeService, logger)
+					command := commands.NewUploadStemcell(multipart, fakeService, logger, false)
 
 					err := command.Execute([]string{
 						"--stemcell", "/path/to/stemcell.tgz",
@@ -281,7 +281,7 @@ var _ = Describe("UploadStemcell", func() {
 					Stemcells: []string{"stemcell.tgz"},
 				}, nil)
 
-				command := commands.NewUploadStemcell(multipart, fakeService, logger)
+				command := commands.NewUploadStemcell(multipart, fakeService, logger, false)
 
 				err := command.Execute([]string{
 					"--stemcell", "/path/to/stemcell.tgz",
@@ -330,7 +330,7 @@ var _ = Describe("UploadStemcell", func() {
 
 			fakeService.GetDiagnosticReportReturns(api.DiagnosticReport{Stemcells: []string{}}, nil)
 
-			command := commands.NewUploadStemcell(multipart, fakeService, logger)
+			command := commands.NewUploadStemcell(multipart, fakeService, logger, false)
 			err = command.Execute([]string{
 				"--stemcell", file.Name(),
 				"--shasum", "2815ab9694a4a2cfd59424a734833010e143a0b2db20be3741507f177f289f44",
@@ -349,7 +349,7 @@ var _ = Describe("UploadStemcell", func() {
 			err = file.Close()
 			Expect(err).ToNot(HaveOccurred())
 
-			command := commands.NewUploadStemcell(multipart, fakeService, logger)
+			command := commands.NewUploadStemcell(multipart, fakeService, logger, false)
 			err = command.Execute([]string{
 				"--stemcell", file.Name(),
 				"--shasum", "not-the-correct-shasum",
@@ -358,7 +358,7 @@ var _ = Describe("UploadStemcell", func() {
 			Expect(err).To(MatchError("expected shasum not-the-correct-shasum does not match file shasum 2815ab9694a4a2cfd59424a734833010e143a0b2db20be3741507f177f289f44"))
 		})
 		It("fails when the file can not calculate a shasum", func() {
-			command := commands.NewUploadStemcell(multipart, fakeService, logger)
+			command := commands.NewUploadStemcell(multipart, fakeService, logger, false)
 			err := command.Execute([]string{
 				"--stemcell", "/path/to/testing.tgz",
 				"--shasum", "2815ab9694a4a2cfd59424a734833010e143a0b2db20be3741507f177f289f44",
@@ -380,7 +380,7 @@ var _ = Describe("UploadStemcell", func() {
 
 			fakeService.GetDiagnosticReportReturns(api.DiagnosticReport{}, api.DiagnosticReportUnavailable{})
 
-			command := commands.NewUploadStemcell(multipart, fakeService, logger)
+			command := commands.NewUploadStemcell(multipart, fakeService, logger, false)
 
 			err := command.Execute([]string{
 				"--stemcell", "/path/to/stemcell.tgz",
@@ -457,7 +457,7 @@ shasum: 2815ab9694a4a2cfd59424a734833010e143a0b2db20be3741507f177f289f44
 
 		It("reads configuration from config file", func() {
 			fakeService.InfoReturns(api.Info{Version: "2.2-build.1"}, nil)
-			command := commands.NewUploadStemcell(multipart, fakeService, logger)
+			command := commands.NewUploadStemcell(multipart, fakeService, logger, false)
 			err := command.Execute([]string{
 				"--stemcell", file.Name(),
 				"--config", configFile.Name(),
@@ -471,7 +471,7 @@ shasum: 2815ab9694a4a2cfd59424a734833010e143a0b2db20be3741507f177f289f44
 	Context("failure cases", func() {
 		When("an unknown flag is provided", func() {
 			It("returns an error", func() {
-				command := commands.NewUploadStemcell(multipart, fakeService, logger)
+				command := commands.NewUploadStemcell(multipart, fakeService, logger, false)
 				err := command.Execute([]string{"--badflag"})
 				Expect(err).To(MatchError("could not parse upload-stemcell flags: flag provided but not defined: -badflag"))
 			})
@@ -479,7 +479,7 @@ shasum: 2815ab9694a4a2cfd59424a734833010e143a0b2db20be3741507f177f289f44
 
 		When("the --stemcell flag is missing", func() {
 			It("returns an error", func() {
-				command := commands.NewUploadStemcell(multipart, fakeService, logger)
+				command := commands.NewUploadStemcell(multipart, fakeService, logger, false)
 				err := command.Execute([]string{})
 				Expect(err).To(MatchError("could not parse upload-stemcell flags: missing required flag \"--stemcell\""))
 			})
@@ -488,7 +488,7 @@ shasum: 2815ab9694a4a2cfd59424a734833010e143a0b2db20be3741507f177f289f44
 		When("the file cannot be opened", func() {
 			It("returns an error", func() {
 				fakeService.InfoReturns(api.Info{Version: "2.2-build.1"}, nil)
-				command := commands.NewUploadStemcell(multipart, fakeService, logger)
+				command := commands.NewUploadStemcell(multipart, fakeService, logger, false)
 				multipart.AddFileReturns(errors.New("bad file"))
 
 				err := command.Execute([]string{"--stemcell", "/some/path"})
@@ -499,7 +499,7 @@ shasum: 2815ab9694a4a2cfd59424a734833010e143a0b2db20be3741507f177f289f44
 		When("the stemcell cannot be uploaded", func() {
 			It("returns an error", func() {
 				fakeService.InfoReturns(api.Info{Version: "2.2-build.1"}, nil)
-				command := commands.NewUploadStemcell(multipart, fakeService, logger)
+				command := commands.NewUploadStemcell(multipart, fakeService, logger, false)
 				fakeService.UploadStemcellReturns(api.StemcellUploadOutput{}, errors.New("some stemcell error"))
 
 				err := command.Execute([]string{"--stemcell", "/some/path"})
@@ -509,7 +509,7 @@ shasum: 2815ab9694a4a2cfd59424a734833010e143a0b2db20be3741507f177f289f44
 
 		When("the diagnostic report cannot be fetched", func() {
 			It("returns an error", func() {
-				command := commands.NewUploadStemcell(multipart, fakeService, logger)
+				command := commands.NewUploadStemcell(multipart, fakeService, logger, false)
 				fakeService.GetDiagnosticReportReturns(api.DiagnosticReport{}, errors.New("some diagnostic error"))
 
 				err := command.Execute([]string{"--stemcell", "/some/path"})
@@ -520,7 +520,7 @@ shasum: 2815ab9694a4a2cfd59424a734833010e143a0b2db20be3741507f177f289f44
 
 	Describe("Usage", func() {
 		It("returns usage information for the command", func() {
-			command := commands.NewUploadStemcell(nil, nil, nil)
+			command := commands.NewUploadStemcell(nil, nil, nil, false)
 			Expect(command.Usage()).To(Equal(jhanda.Usage{
 				Description:      "This command will upload a stemcell to the target Ops Manager. Unless the force flag is used, if the stemcell already exists that upload will be skipped",
 				ShortDescription: "uploads a given stemcell to the Ops Manager targeted",
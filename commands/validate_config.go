@@ -0,0 +1,190 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/configtemplate/generator"
+	"github.com/pivotal-cf/om/configtemplate/metadata"
+	"github.com/pivotal-cf/om/interpolate"
+	"gopkg.in/yaml.v2"
+)
+
+type ValidateConfig struct {
+	environFunc   envProvider
+	buildProvider validateConfigBuildProvider
+	logger        logger
+	strict        bool
+	Options       struct {
+		ConfigFile string   `long:"config"    short:"c"        description:"path to the product config file to validate" required:"true"`
+		VarsFile   []string `long:"vars-file" short:"l"        description:"Load variables from a YAML file"`
+		VarsSource []string `long:"vars-source" env:"OM_VARS_SOURCE" description:"Load variables that are not otherwise provided from a credential store (e.g.: 'vault:address=https://vault.example.com,mount=secret,path=foundation' or 'credhub:address=https://credhub.example.com,path=/foundation')"`
+		VarsStore  string   `long:"vars-store" description:"Load variables from a YAML file, the variables defined in the 'variables' section of the config that are missing are generated and saved to it"`
+		Vars       []string `long:"var" short:"v"              description:"Load variable from the command line. Format: VAR=VAL"`
+		VarsEnv    []string `long:"vars-env" env:"OM_VARS_ENV" description:"Load variables from environment variables (e.g.: 'MY' to load MY_var=value)"`
+		OpsFile    []string `long:"ops-file"  short:"o"        description:"YAML operations file"`
+
+		PivnetApiToken    string `long:"pivnet-api-token"                                                                                                             `
+		PivnetProductSlug string `long:"pivnet-product-slug"           description:"the product name in pivnet"                                                       `
+		ProductVersion    string `long:"product-version"               description:"the version of the product to validate against"                                   `
+		PivnetFileGlob    string `long:"pivnet-file-glob"    short:"f" description:"a glob to match exactly one file in the pivnet product slug"  default:"*.pivotal" `
+		PivnetDisableSSL  bool   `long:"pivnet-disable-ssl"            description:"whether to disable ssl validation when contacting the Pivotal Network"`
+
+		ProductPath string `long:"product-path" description:"path to product file"`
+	}
+}
+
+type validateConfigBuildProvider func(*ValidateConfig) MetadataProvider
+
+var DefaultValidateConfigProvider = func() func(c *ValidateConfig) MetadataProvider {
+	return func(c *ValidateConfig) MetadataProvider {
+		options := c.Options
+		if options.ProductPath != "" {
+			return metadata.NewFileProvider(options.ProductPath)
+		}
+		return metadata.NewPivnetProvider(pivnetHost, options.PivnetApiToken, options.PivnetProductSlug, options.ProductVersion, options.PivnetFileGlob, options.PivnetDisableSSL)
+	}
+}
+
+func NewValidateConfig(bp validateConfigBuildProvider, environFunc envProvider, logger logger, strict bool) *ValidateConfig {
+	return &ValidateConfig{
+		environFunc:   environFunc,
+		buildProvider: bp,
+		logger:        logger,
+		strict:        strict,
+	}
+}
+
+func (vc *ValidateConfig) Execute(args []string) error {
+	if _, err := jhanda.Parse(&vc.Options, args); err != nil {
+		return fmt.Errorf("could not parse validate-config flags: %s", err)
+	}
+
+	err := validateMetadataSource(vc.Options.PivnetApiToken, vc.Options.PivnetProductSlug, vc.Options.ProductVersion, vc.Options.ProductPath)
+	if err != nil {
+		return err
+	}
+
+	configContents, err := interpolate.Execute(interpolate.Options{
+		TemplateFile:      vc.Options.ConfigFile,
+		VarsFiles:         vc.Options.VarsFile,
+		VarsSources:       vc.Options.VarsSource,
+		VarsStore:         vc.Options.VarsStore,
+		Vars:              vc.Options.Vars,
+		EnvironFunc:       vc.environFunc,
+		VarsEnvs:          vc.Options.VarsEnv,
+		OpsFiles:          vc.Options.OpsFile,
+		ExpectAllKeys:     false,
+		ExpectAllVarsUsed: vc.strict,
+	})
+	if err != nil {
+		return err
+	}
+
+	var cfg configureProduct
+	err = yaml.Unmarshal(configContents, &cfg)
+	if err != nil {
+		return fmt.Errorf("%s could not be parsed as valid configuration: %s", vc.Options.ConfigFile, err)
+	}
+
+	metadataBytes, err := vc.buildProvider(vc).MetadataBytes()
+	if err != nil {
+		return fmt.Errorf("error getting metadata for %s at version %s: %s", vc.Options.PivnetProductSlug, vc.Options.ProductVersion, err)
+	}
+
+	productMetadata, err := generator.NewMetadata(metadataBytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse product metadata: %s", err)
+	}
+
+	problems := validateProductConfig(productMetadata, cfg)
+	if len(problems) > 0 {
+		return fmt.Errorf("%s is not valid for %s %s:\n- %s", vc.Options.ConfigFile, productMetadata.Name, productMetadata.Version, strings.Join(problems, "\n- "))
+	}
+
+	vc.logger.Printf("%s is valid for %s %s", vc.Options.ConfigFile, productMetadata.Name, productMetadata.Version)
+
+	return nil
+}
+
+func (vc *ValidateConfig) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This command validates a product config file against the property blueprints, job types, and errands of a product. It reports every unknown property, invalid value, invalid selector option, missing required property, and unknown job or errand name without contacting Ops Manager.",
+		ShortDescription: "validates a product config file against the product metadata",
+		Flags:            vc.Options,
+	}
+}
+
+func validateProductConfig(productMetadata *generator.Metadata, cfg configureProduct) []string {
+	var problems []string
+
+	for _, key := range sortedKeys(cfg.Field) {
+		problems = append(problems, fmt.Sprintf("unrecognized key %s", key))
+	}
+
+	if cfg.ProductName == "" {
+		problems = append(problems, "product-name is required")
+	} else if cfg.ProductName != productMetadata.Name {
+		problems = append(problems, fmt.Sprintf("product-name %s does not match the product name %s in the metadata", cfg.ProductName, productMetadata.Name))
+	}
+
+	problems = append(problems, productMetadata.ValidateProductProperties(cfg.ProductProperties)...)
+
+	var jobs []string
+	for name := range cfg.ResourceConfigProperties {
+		jobs = append(jobs, name)
+	}
+	sort.Strings(jobs)
+
+	for _, name := range jobs {
+		if _, err := productMetadata.GetJob(name); err != nil {
+			problems = append(problems, fmt.Sprintf("unknown job %s in resource-config", name))
+		}
+	}
+
+	var errands []string
+	for name := range cfg.ErrandConfigs {
+		errands = append(errands, name)
+	}
+	sort.Strings(errands)
+
+	for _, name := range errands {
+		if !hasErrand(productMetadata, name) {
+			problems = append(problems, fmt.Sprintf("unknown errand %s in errand-config", name))
+		}
+	}
+
+	return problems
+}
+
+func hasErrand(productMetadata *generator.Metadata, name string) bool {
+	for _, errand := range productMetadata.Errands() {
+		if errand.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(fields map[string]interface{}) []string {
+	var keys []string
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func validateMetadataSource(pivnetApiToken, pivnetProductSlug, productVersion, productPath string) error {
+	if pivnetApiToken != "" && pivnetProductSlug != "" && productVersion != "" && productPath == "" {
+		return nil
+	}
+
+	if pivnetApiToken == "" && pivnetProductSlug == "" && productVersion == "" && productPath != "" {
+		return nil
+	}
+
+	return fmt.Errorf("cannot load tile metadata: please provide either pivnet flags OR product-path")
+}
@@ -0,0 +1,144 @@
+package commands_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ValidateConfig", func() {
+	var (
+		metadataProvider *fakes.MetadataProvider
+		logger           *fakes.Logger
+		command          *commands.ValidateConfig
+	)
+
+	BeforeEach(func() {
+		metadataProvider = &fakes.MetadataProvider{}
+		metadataProvider.MetadataBytesReturns([]byte(`---
+name: some-product
+product_version: 1.2.3
+property_blueprints:
+- name: some-string
+  type: string
+  configurable: true
+- name: some-integer
+  type: integer
+  configurable: true
+  default: 1
+job_types:
+- name: some-job
+post_deploy_errands:
+- name: smoke-tests
+`), nil)
+
+		logger = &fakes.Logger{}
+		command = commands.NewValidateConfig(func(*commands.ValidateConfig) commands.MetadataProvider {
+			return metadataProvider
+		}, func() []string { return []string{"OM_VAR_some_string=hello"} }, logger, false)
+	})
+
+	When("the config matches the product metadata", func() {
+		It("prints that the config is valid", func() {
+			configFile := writeTestConfigFile(`---
+product-name: some-product
+product-properties:
+  .properties.some-string:
+    value: ((some_string))
+  .properties.some-integer:
+    value: ((some_integer))
+resource-config:
+  some-job:
+    instances: 1
+errand-config:
+  smoke-tests:
+    post-deploy-state: true
+`)
+
+			err := command.Execute([]string{"--config", configFile, "--product-path", "/path/to/product.pivotal", "--vars-env", "OM_VAR"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(logger.PrintfCallCount()).To(Equal(1))
+			format, v := logger.PrintfArgsForCall(0)
+			Expect(fmt.Sprintf(format, v...)).To(Equal(fmt.Sprintf("%s is valid for some-product 1.2.3", configFile)))
+		})
+	})
+
+	When("the config does not match the product metadata", func() {
+		It("returns every problem that was found", func() {
+			configFile := writeTestConfigFile(`---
+product-name: other-product
+unknown-key: true
+product-properties:
+  .properties.some-integer:
+    value: one
+resource-config:
+  some-jb:
+    instances: 1
+errand-config:
+  smoke-test:
+    post-deploy-state: true
+`)
+
+			err := command.Execute([]string{"--config", configFile, "--product-path", "/path/to/product.pivotal"})
+			Expect(err).To(MatchError(fmt.Sprintf(`%s is not valid for some-product 1.2.3:
+- unrecognized key unknown-key
+- product-name other-product does not match the product name some-product in the metadata
+- property .properties.some-integer of type integer has an invalid value one
+- missing required property .properties.some-string
+- unknown job some-jb in resource-config
+- unknown errand smoke-test in errand-config`, configFile)))
+		})
+	})
+
+	Context("failure cases", func() {
+		It("returns an error when an unknown flag is provided", func() {
+			err := command.Execute([]string{"--badflag"})
+			Expect(err).To(MatchError("could not parse validate-config flags: flag provided but not defined: -badflag"))
+		})
+
+		It("returns an error when both pivnet flags and the product path are provided", func() {
+			err := command.Execute([]string{
+				"--config", "config.yml",
+				"--product-path", "/path/to/product.pivotal",
+				"--pivnet-api-token", "token",
+			})
+			Expect(err).To(MatchError("cannot load tile metadata: please provide either pivnet flags OR product-path"))
+		})
+
+		It("returns an error when the config file cannot be read", func() {
+			err := command.Execute([]string{"--config", "/does/not/exist.yml", "--product-path", "/path/to/product.pivotal"})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns an error when the metadata cannot be loaded", func() {
+			metadataProvider.MetadataBytesReturns(nil, errors.New("some error"))
+			configFile := writeTestConfigFile(`product-name: some-product`)
+
+			err := command.Execute([]string{
+				"--config", configFile,
+				"--pivnet-api-token", "token",
+				"--pivnet-product-slug", "some-product",
+				"--product-version", "1.2.3",
+			})
+			Expect(err).To(MatchError("error getting metadata for some-product at version 1.2.3: some error"))
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			command := commands.NewValidateConfig(nil, nil, nil, false)
+			Expect(command.Usage()).To(Equal(jhanda.Usage{
+				Description:      "This command validates a product config file against the property blueprints, job types, and errands of a product. It reports every unknown property, invalid value, invalid selector option, missing required property, and unknown job or errand name without contacting Ops Manager.",
+				ShortDescription: "validates a product config file against the product metadata",
+				Flags:            command.Options,
+			}))
+		})
+	})
+})
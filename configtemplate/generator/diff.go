@@ -0,0 +1,202 @@
+package generator
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+type MetadataDiff struct {
+	AddedProperties         []string
+	RemovedProperties       []string
+	TypeChanges             []PropertyChange
+	RequiredChanges         []PropertyChange
+	DefaultChanges          []PropertyChange
+	AddedSelectorOptions    []string
+	AddedJobs               []string
+	RemovedJobs             []string
+	AddedErrands            []string
+	RemovedErrands          []string
+	AddedFeatures           []string
+	RemovedFeatures         []string
+	AddedOptionalOpsFiles   []string
+	RemovedOptionalOpsFiles []string
+	NewVars                 []VarsFileEntries
+}
+
+type PropertyChange struct {
+	Reference string
+	From      interface{}
+	To        interface{}
+}
+
+type VarsFileEntries struct {
+	File  string
+	Names []string
+}
+
+// HasChanges is false when nothing that config-template generates
+// differs between the two versions.
+func (d MetadataDiff) HasChanges() bool {
+	return !reflect.DeepEqual(d, MetadataDiff{})
+}
+
+// DiffMetadata compares the metadata of two versions of a product
+// the way it is reflected in the output of config-template.
+func DiffMetadata(from, to *Metadata) (MetadataDiff, error) {
+	var diff MetadataDiff
+
+	fromProperties := propertiesByReference(from)
+	toProperties := propertiesByReference(to)
+
+	diff.AddedProperties, diff.RemovedProperties = diffNames(keys(fromProperties), keys(toProperties))
+
+	for _, reference := range keys(toProperties) {
+		previous, ok := fromProperties[reference]
+		if !ok {
+			continue
+		}
+		current := toProperties[reference]
+
+		if previous.Type != current.Type {
+			diff.TypeChanges = append(diff.TypeChanges, PropertyChange{Reference: reference, From: previous.Type, To: current.Type})
+		}
+
+		if needsValue(previous) != needsValue(current) {
+			diff.RequiredChanges = append(diff.RequiredChanges, PropertyChange{Reference: reference, From: needsValue(previous), To: needsValue(current)})
+		}
+
+		if !reflect.DeepEqual(previous.Default, current.Default) {
+			diff.DefaultChanges = append(diff.DefaultChanges, PropertyChange{Reference: reference, From: previous.Default, To: current.Default})
+		}
+
+		if current.IsSelector() {
+			added, _ := diffNames(optionTemplateNames(previous), optionTemplateNames(current))
+			for _, option := range added {
+				diff.AddedSelectorOptions = append(diff.AddedSelectorOptions, fmt.Sprintf("%s.%s", reference, option))
+			}
+		}
+	}
+
+	diff.AddedJobs, diff.RemovedJobs = diffNames(jobNames(from), jobNames(to))
+	diff.AddedErrands, diff.RemovedErrands = diffNames(errandNames(from), errandNames(to))
+
+	fromFeatures, err := CreateProductPropertiesFeaturesOpsFiles(from)
+	if err != nil {
+		return MetadataDiff{}, err
+	}
+	toFeatures, err := CreateProductPropertiesFeaturesOpsFiles(to)
+	if err != nil {
+		return MetadataDiff{}, err
+	}
+	diff.AddedFeatures, diff.RemovedFeatures = diffNames(keys(fromFeatures), keys(toFeatures))
+
+	fromOptional, err := CreateProductPropertiesOptionalOpsFiles(from)
+	if err != nil {
+		return MetadataDiff{}, err
+	}
+	toOptional, err := CreateProductPropertiesOptionalOpsFiles(to)
+	if err != nil {
+		return MetadataDiff{}, err
+	}
+	diff.AddedOptionalOpsFiles, diff.RemovedOptionalOpsFiles = diffNames(keys(fromOptional), keys(toOptional))
+
+	fromVars, err := varsFiles(from)
+	if err != nil {
+		return MetadataDiff{}, err
+	}
+	toVars, err := varsFiles(to)
+	if err != nil {
+		return MetadataDiff{}, err
+	}
+	for _, file := range keys(toVars) {
+		added, _ := diffNames(keys(fromVars[file]), keys(toVars[file]))
+		if len(added) > 0 {
+			diff.NewVars = append(diff.NewVars, VarsFileEntries{File: file, Names: added})
+		}
+	}
+
+	return diff, nil
+}
+
+func propertiesByReference(metadata *Metadata) map[string]PropertyBlueprint {
+	properties := map[string]PropertyBlueprint{}
+	for _, property := range metadata.ConfigurableProperties() {
+		properties[property.Reference] = property.Blueprint
+	}
+	return properties
+}
+
+func jobNames(metadata *Metadata) []string {
+	var names []string
+	for _, job := range metadata.JobTypes {
+		names = append(names, job.Name)
+	}
+	return names
+}
+
+func errandNames(metadata *Metadata) []string {
+	var names []string
+	for _, errand := range metadata.Errands() {
+		names = append(names, errand.Name)
+	}
+	return names
+}
+
+func varsFiles(metadata *Metadata) (map[string]map[string]interface{}, error) {
+	requiredVars, err := GetRequiredPropertyVars(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultVars, err := GetDefaultPropertyVars(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]map[string]interface{}{
+		"required-vars.yml": requiredVars,
+		"default-vars.yml":  defaultVars,
+		"resource-vars.yml": CreateResourceVars(metadata),
+		"errand-vars.yml":   CreateErrandVars(metadata),
+	}, nil
+}
+
+func keys(m interface{}) []string {
+	var names []string
+	for _, key := range reflect.ValueOf(m).MapKeys() {
+		names = append(names, key.String())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// diffNames returns the sorted names that were added to and removed from previous.
+func diffNames(previous, current []string) ([]string, []string) {
+	previousSet := map[string]bool{}
+	for _, name := range previous {
+		previousSet[name] = true
+	}
+
+	currentSet := map[string]bool{}
+	for _, name := range current {
+		currentSet[name] = true
+	}
+
+	var added, removed []string
+	for name := range currentSet {
+		if !previousSet[name] {
+			added = append(added, name)
+		}
+	}
+	for name := range previousSet {
+		if !currentSet[name] {
+			removed = append(removed, name)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+
+	return added, removed
+}
@@ -0,0 +1,125 @@
+package generator_test
+
+import (
+	"github.com/pivotal-cf/om/configtemplate/generator"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DiffMetadata", func() {
+	newMetadata := func(contents string) *generator.Metadata {
+		metadata, err := generator.NewMetadata([]byte(contents))
+		Expect(err).ToNot(HaveOccurred())
+		return metadata
+	}
+
+	It("reports the differences between two versions", func() {
+		from := newMetadata(`---
+name: some-product
+product_version: 1.0.0
+form_types:
+- name: some-form
+  property_inputs:
+  - reference: .properties.kept
+  - reference: .properties.removed
+  - reference: .properties.some-selector
+property_blueprints:
+- name: kept
+  type: integer
+  configurable: true
+  default: 1
+- name: removed
+  type: string
+  configurable: true
+- name: some-selector
+  type: selector
+  configurable: true
+  default: internal
+  option_templates:
+  - name: internal_option
+    select_value: internal
+job_types:
+- name: old-job
+  instance_definition:
+    configurable: true
+    default: 1
+post_deploy_errands:
+- name: old-errand
+`)
+		to := newMetadata(`---
+name: some-product
+product_version: 1.1.0
+form_types:
+- name: some-form
+  property_inputs:
+  - reference: .properties.kept
+  - reference: .properties.added
+  - reference: .properties.some-selector
+    selector_property_inputs:
+    - reference: .properties.some-selector.internal_option
+    - reference: .properties.some-selector.external_option
+property_blueprints:
+- name: kept
+  type: integer
+  configurable: true
+- name: added
+  type: string
+  configurable: true
+- name: some-selector
+  type: selector
+  configurable: true
+  default: internal
+  option_templates:
+  - name: internal_option
+    select_value: internal
+  - name: external_option
+    select_value: external
+job_types:
+- name: new-job
+  instance_definition:
+    configurable: true
+    default: 1
+post_deploy_errands:
+- name: new-errand
+`)
+
+		diff, err := generator.DiffMetadata(from, to)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(diff.HasChanges()).To(BeTrue())
+
+		Expect(diff.AddedProperties).To(Equal([]string{".properties.added"}))
+		Expect(diff.RemovedProperties).To(Equal([]string{".properties.removed"}))
+		Expect(diff.TypeChanges).To(BeEmpty())
+		Expect(diff.RequiredChanges).To(Equal([]generator.PropertyChange{
+			{Reference: ".properties.kept", From: false, To: true},
+		}))
+		Expect(diff.DefaultChanges).To(Equal([]generator.PropertyChange{
+			{Reference: ".properties.kept", From: 1, To: nil},
+		}))
+		Expect(diff.AddedSelectorOptions).To(Equal([]string{".properties.some-selector.external_option"}))
+		Expect(diff.AddedJobs).To(Equal([]string{"new-job"}))
+		Expect(diff.RemovedJobs).To(Equal([]string{"old-job"}))
+		Expect(diff.AddedErrands).To(Equal([]string{"new-errand"}))
+		Expect(diff.RemovedErrands).To(Equal([]string{"old-errand"}))
+		Expect(diff.AddedFeatures).To(Equal([]string{"some-selector-external_option"}))
+		Expect(diff.RemovedFeatures).To(BeEmpty())
+		Expect(diff.NewVars).To(Equal([]generator.VarsFileEntries{
+			{File: "errand-vars.yml", Names: []string{"new-errand_post_deploy_state"}},
+			{File: "required-vars.yml", Names: []string{"added", "kept"}},
+			{File: "resource-vars.yml", Names: []string{"new-job_instance_type", "new-job_instances", "new-job_max_in_flight"}},
+		}))
+	})
+
+	It("reports no changes for the same metadata", func() {
+		metadataBytes, err := getFileBytes("./fixtures/pas.yml")
+		Expect(err).ToNot(HaveOccurred())
+
+		metadata, err := generator.NewMetadata(metadataBytes)
+		Expect(err).ToNot(HaveOccurred())
+
+		diff, err := generator.DiffMetadata(metadata, metadata)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(diff.HasChanges()).To(BeFalse())
+	})
+})
@@ -1,6 +1,7 @@
 package generator
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -14,14 +15,16 @@ type Executor struct {
 	baseDirectory              string
 	doNotIncludeProductVersion bool
 	includeErrands             bool
+	includeJSONSchema          bool
 }
 
-func NewExecutor(metadataBytes []byte, baseDirectory string, doNotIncludeProductVersion, includeErrands bool) *Executor {
+func NewExecutor(metadataBytes []byte, baseDirectory string, doNotIncludeProductVersion, includeErrands, includeJSONSchema bool) *Executor {
 	return &Executor{
 		metdataBytes:               metadataBytes,
 		baseDirectory:              baseDirectory,
 		doNotIncludeProductVersion: doNotIncludeProductVersion,
 		includeErrands:             includeErrands,
+		includeJSONSchema:          includeJSONSchema,
 	}
 }
 
@@ -76,6 +79,12 @@ func (e *Executor) Generate() error {
 		return err
 	}
 
+	if e.includeJSONSchema {
+		if err = e.writeJSONFile(path.Join(targetDirectory, "product.schema.json"), CreateJSONSchema(metadata)); err != nil {
+			return err
+		}
+	}
+
 	networkOpsFiles, err := CreateNetworkOpsFiles(metadata)
 	if err != nil {
 		return err
@@ -218,6 +227,14 @@ func (e *Executor) createDirectory(path string) error {
 	return nil
 }
 
+func (e *Executor) writeJSONFile(targetFile string, dataType interface{}) error {
+	data, err := json.MarshalIndent(dataType, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(targetFile, data, 0755)
+}
+
 func (e *Executor) writeYamlFile(targetFile string, dataType interface{}) error {
 	if dataType != nil {
 		data, err := yaml.Marshal(dataType)
@@ -71,7 +71,7 @@ var _ = Describe("Executor", func() {
 		It("Should generate files for p-healthwatch", func() {
 			metadataBytes, err := getFileBytes("./fixtures/p_healthwatch.yml")
 			Expect(err).ToNot(HaveOccurred())
-			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true)
+			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true, false)
 			err = gen.Generate()
 			Expect(err).ToNot(HaveOccurred())
 		})
@@ -79,35 +79,35 @@ var _ = Describe("Executor", func() {
 		It("Should generate files for pas", func() {
 			metadataBytes, err := getFileBytes("./fixtures/pas.yml")
 			Expect(err).ToNot(HaveOccurred())
-			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true)
+			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true, false)
 			err = gen.Generate()
 			Expect(err).ToNot(HaveOccurred())
 		})
 		It("Should generate files for pas 2.2", func() {
 			metadataBytes, err := getFileBytes("./fixtures/pas_2_2.yml")
 			Expect(err).ToNot(HaveOccurred())
-			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true)
+			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true, false)
 			err = gen.Generate()
 			Expect(err).ToNot(HaveOccurred())
 		})
 		It("Should generate files for mysql_v2", func() {
 			metadataBytes, err := getFileBytes("./fixtures/mysql_v2.yml")
 			Expect(err).ToNot(HaveOccurred())
-			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true)
+			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true, false)
 			err = gen.Generate()
 			Expect(err).ToNot(HaveOccurred())
 		})
 		It("Should generate files for scs", func() {
 			metadataBytes, err := getFileBytes("./fixtures/scs.yml")
 			Expect(err).ToNot(HaveOccurred())
-			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true)
+			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true, false)
 			err = gen.Generate()
 			Expect(err).ToNot(HaveOccurred())
 		})
 		It("Should generate files for srt", func() {
 			metadataBytes, err := getFileBytes("./fixtures/srt.yml")
 			Expect(err).ToNot(HaveOccurred())
-			gen = generator.NewExecutor(metadataBytes, tmpPath, true, true)
+			gen = generator.NewExecutor(metadataBytes, tmpPath, true, true, false)
 			err = gen.Generate()
 			Expect(err).ToNot(HaveOccurred())
 		})
@@ -115,7 +115,7 @@ var _ = Describe("Executor", func() {
 		It("Should generate files for push notifications", func() {
 			metadataBytes, err := getFileBytes("./fixtures/p_push_notifications.yml")
 			Expect(err).ToNot(HaveOccurred())
-			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true)
+			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true, false)
 			err = gen.Generate()
 			Expect(err).ToNot(HaveOccurred())
 		})
@@ -123,42 +123,42 @@ var _ = Describe("Executor", func() {
 		It("Should generate files for pivotal cloud cache", func() {
 			metadataBytes, err := getFileBytes("./fixtures/cloudcache.yml")
 			Expect(err).ToNot(HaveOccurred())
-			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true)
+			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true, false)
 			err = gen.Generate()
 			Expect(err).ToNot(HaveOccurred())
 		})
 		It("Should generate files for rabbitmq", func() {
 			metadataBytes, err := getFileBytes("./fixtures/rabbit-mq.yml")
 			Expect(err).ToNot(HaveOccurred())
-			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true)
+			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true, false)
 			err = gen.Generate()
 			Expect(err).ToNot(HaveOccurred())
 		})
 		It("Should generate files for rabbitmq 1.4", func() {
 			metadataBytes, err := getFileBytes("./fixtures/rabbit-mq-1.4.yml")
 			Expect(err).ToNot(HaveOccurred())
-			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true)
+			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true, false)
 			err = gen.Generate()
 			Expect(err).ToNot(HaveOccurred())
 		})
 		It("Should generate files for redis", func() {
 			metadataBytes, err := getFileBytes("./fixtures/p-redis.yml")
 			Expect(err).ToNot(HaveOccurred())
-			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true)
+			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true, false)
 			err = gen.Generate()
 			Expect(err).ToNot(HaveOccurred())
 		})
 		It("Should generate files for apigee", func() {
 			metadataBytes, err := getFileBytes("./fixtures/apigee.yml")
 			Expect(err).ToNot(HaveOccurred())
-			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true)
+			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true, false)
 			err = gen.Generate()
 			Expect(err).ToNot(HaveOccurred())
 		})
 		It("Should generate files for pks", func() {
 			metadataBytes, err := getFileBytes("./fixtures/pks.yml")
 			Expect(err).ToNot(HaveOccurred())
-			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true)
+			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true, false)
 			err = gen.Generate()
 			Expect(err).ToNot(HaveOccurred())
 			template, err := unmarshalProduct(path.Join(tmpPath, "pivotal-container-service", "1.1.3-build.11", "product.yml"))
@@ -169,7 +169,7 @@ var _ = Describe("Executor", func() {
 		It("Should generate files for nsx-t", func() {
 			metadataBytes, err := getFileBytes("./fixtures/nsx-t.yml")
 			Expect(err).ToNot(HaveOccurred())
-			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true)
+			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true, false)
 			err = gen.Generate()
 			Expect(err).ToNot(HaveOccurred())
 			template, err := unmarshalProduct(path.Join(tmpPath, "VMware-NSX-T", "2.2.1.9149087", "product.yml"))
@@ -181,7 +181,7 @@ var _ = Describe("Executor", func() {
 		It("Should generate files for aws-services", func() {
 			metadataBytes, err := getFileBytes("./fixtures/aws-services.yml")
 			Expect(err).ToNot(HaveOccurred())
-			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true)
+			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true, false)
 			err = gen.Generate()
 			Expect(err).ToNot(HaveOccurred())
 		})
@@ -189,7 +189,7 @@ var _ = Describe("Executor", func() {
 		It("Should generate files for a9s postgres", func() {
 			metadataBytes, err := getFileBytes("./fixtures/a9s_postgres.yml")
 			Expect(err).ToNot(HaveOccurred())
-			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true)
+			gen = generator.NewExecutor(metadataBytes, tmpPath, false, true, false)
 			err = gen.Generate()
 			Expect(err).ToNot(HaveOccurred())
 		})
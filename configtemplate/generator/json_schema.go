@@ -0,0 +1,261 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+)
+
+// JSONSchema is a JSON Schema document, or one of its subschemas.
+type JSONSchema map[string]interface{}
+
+var variableSchema = JSONSchema{
+	"type":        "string",
+	"pattern":     `^\(\(.+\)\)$`,
+	"description": "a variable that is interpolated before configuring the product",
+}
+
+// CreateJSONSchema describes the product config accepted by configure-product
+// (product-properties, network-properties, resource-config, and errand-config)
+// as a JSON Schema, so that editors can autocomplete and validate it.
+func CreateJSONSchema(metadata *Metadata) JSONSchema {
+	return JSONSchema{
+		"$schema":              "http://json-schema.org/draft-07/schema#",
+		"title":                fmt.Sprintf("%s %s product configuration", metadata.Name, metadata.Version),
+		"type":                 "object",
+		"additionalProperties": false,
+		"required":             []string{"product-name"},
+		"properties": JSONSchema{
+			"product-name":             JSONSchema{"const": metadata.Name},
+			"product-properties":       productPropertiesSchema(metadata),
+			"network-properties":       networkPropertiesSchema(),
+			"resource-config":          resourceConfigSchema(metadata),
+			"errand-config":            errandConfigSchema(metadata),
+			"syslog-properties":        JSONSchema{"type": "object"},
+			"validate-config-complete": JSONSchema{"type": "boolean"},
+		},
+	}
+}
+
+func productPropertiesSchema(metadata *Metadata) JSONSchema {
+	properties := JSONSchema{}
+	for _, property := range metadata.ConfigurableProperties() {
+		properties[property.Reference] = propertySchema(property.Blueprint)
+	}
+
+	required := []string{}
+	for _, property := range metadata.topLevelProperties() {
+		if property.Blueprint.IsConfigurable() && needsValue(property.Blueprint) {
+			required = append(required, property.Reference)
+		}
+	}
+	sort.Strings(required)
+
+	return JSONSchema{
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties":           properties,
+		"required":             required,
+	}
+}
+
+func propertySchema(blueprint PropertyBlueprint) JSONSchema {
+	value := valueSchema(blueprint)
+	if blueprint.HasDefault() {
+		value["default"] = jsonValue(blueprint.Default)
+	}
+
+	schema := JSONSchema{
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties": JSONSchema{
+			"value": value,
+		},
+	}
+
+	if blueprint.IsSelector() {
+		schema["properties"].(JSONSchema)["selected_option"] = orVariable(JSONSchema{"enum": optionTemplateNames(blueprint)})
+		schema["minProperties"] = 1
+	} else {
+		schema["required"] = []string{"value"}
+	}
+
+	return schema
+}
+
+func valueSchema(blueprint PropertyBlueprint) JSONSchema {
+	switch {
+	case blueprint.Type == "dropdown_select":
+		names := []interface{}{}
+		for _, option := range blueprint.Options {
+			names = append(names, option.Name)
+		}
+		return orVariable(JSONSchema{"enum": names})
+	case blueprint.IsSelector():
+		return orVariable(JSONSchema{"enum": optionTemplateSelectValues(blueprint)})
+	case blueprint.IsMultiSelect():
+		return orVariable(JSONSchema{
+			"type":  "array",
+			"items": orVariable(JSONSchema{"enum": optionNames(blueprint)}),
+		})
+	case blueprint.IsInt():
+		return orVariable(JSONSchema{"type": "integer"})
+	case blueprint.IsBool():
+		return orVariable(JSONSchema{"type": "boolean"})
+	case blueprint.IsString():
+		return JSONSchema{"type": "string"}
+	case blueprint.IsSecret():
+		return orVariable(objectSchema("secret"))
+	case blueprint.IsSimpleCredentials():
+		return orVariable(objectSchema("identity", "password"))
+	case blueprint.IsCertificate():
+		return orVariable(objectSchema("cert_pem", "private_key_pem"))
+	case blueprint.IsCollection():
+		fields := JSONSchema{}
+		for _, subBlueprint := range blueprint.PropertyBlueprints {
+			fields[subBlueprint.Name] = valueSchema(subBlueprint)
+		}
+		return orVariable(JSONSchema{
+			"type": "array",
+			"items": JSONSchema{
+				"type":                 "object",
+				"additionalProperties": false,
+				"properties":           fields,
+			},
+		})
+	}
+
+	return JSONSchema{}
+}
+
+func objectSchema(keys ...string) JSONSchema {
+	properties := JSONSchema{}
+	for _, key := range keys {
+		properties[key] = JSONSchema{"type": "string"}
+	}
+
+	return JSONSchema{
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties":           properties,
+		"required":             keys,
+	}
+}
+
+func orVariable(schema JSONSchema) JSONSchema {
+	return JSONSchema{"anyOf": []JSONSchema{schema, variableSchema}}
+}
+
+// jsonValue converts the maps decoded from the metadata YAML
+// into maps that can be encoded as JSON.
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		converted := map[string]interface{}{}
+		for key, nested := range v {
+			converted[fmt.Sprintf("%v", key)] = jsonValue(nested)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for i, nested := range v {
+			converted[i] = jsonValue(nested)
+		}
+		return converted
+	}
+	return value
+}
+
+func networkPropertiesSchema() JSONSchema {
+	name := JSONSchema{
+		"type":     "object",
+		"required": []string{"name"},
+		"properties": JSONSchema{
+			"name": JSONSchema{"type": "string"},
+		},
+	}
+
+	return JSONSchema{
+		"type": "object",
+		"properties": JSONSchema{
+			"network":                     name,
+			"service_network":             name,
+			"singleton_availability_zone": name,
+			"other_availability_zones": orVariable(JSONSchema{
+				"type":  "array",
+				"items": name,
+			}),
+		},
+	}
+}
+
+func resourceConfigSchema(metadata *Metadata) JSONSchema {
+	automatic := func(schema JSONSchema) JSONSchema {
+		return JSONSchema{"anyOf": []JSONSchema{schema, {"const": "automatic"}, variableSchema}}
+	}
+
+	jobs := JSONSchema{}
+	for _, job := range metadata.JobTypes {
+		jobs[job.Name] = JSONSchema{
+			"type": "object",
+			"properties": JSONSchema{
+				"instances": automatic(JSONSchema{"type": "integer"}),
+				"instance_type": JSONSchema{
+					"type": "object",
+					"properties": JSONSchema{
+						"id": JSONSchema{"type": "string"},
+					},
+				},
+				"persistent_disk": JSONSchema{
+					"type": "object",
+					"properties": JSONSchema{
+						"size_mb": JSONSchema{"type": "string"},
+					},
+				},
+				"max_in_flight":            JSONSchema{"type": []string{"integer", "string"}},
+				"elb_names":                orVariable(JSONSchema{"type": "array", "items": JSONSchema{"type": "string"}}),
+				"additional_vm_extensions": orVariable(JSONSchema{"type": "array", "items": JSONSchema{"type": "string"}}),
+				"internet_connected":       orVariable(JSONSchema{"type": "boolean"}),
+			},
+		}
+	}
+
+	return JSONSchema{
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties":           jobs,
+	}
+}
+
+func errandConfigSchema(metadata *Metadata) JSONSchema {
+	state := JSONSchema{"type": []string{"boolean", "string"}}
+
+	errands := JSONSchema{}
+	for _, errand := range metadata.PostDeployErrands {
+		errands[errand.Name] = JSONSchema{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": JSONSchema{
+				"post-deploy-state": state,
+			},
+		}
+	}
+
+	for _, errand := range metadata.PreDeleteErrands {
+		schema, ok := errands[errand.Name].(JSONSchema)
+		if !ok {
+			schema = JSONSchema{
+				"type":                 "object",
+				"additionalProperties": false,
+				"properties":           JSONSchema{},
+			}
+		}
+		schema["properties"].(JSONSchema)["pre-delete-state"] = state
+		errands[errand.Name] = schema
+	}
+
+	return JSONSchema{
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties":           errands,
+	}
+}
@@ -0,0 +1,134 @@
+package generator_test
+
+import (
+	"encoding/json"
+
+	"github.com/pivotal-cf/om/configtemplate/generator"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CreateJSONSchema", func() {
+	It("describes the product config accepted by configure-product", func() {
+		metadata, err := generator.NewMetadata([]byte(`---
+name: some-product
+product_version: 1.2.3
+property_blueprints:
+- name: some-string
+  type: string
+  configurable: true
+- name: some-port
+  type: port
+  configurable: true
+  default: 8080
+- name: some-selector
+  type: selector
+  configurable: true
+  default: internal
+  option_templates:
+  - name: internal_option
+    select_value: internal
+    property_blueprints:
+    - name: some-secret
+      type: secret
+      configurable: true
+- name: some-collection
+  type: collection
+  configurable: true
+  optional: true
+  default:
+  - name: first
+  property_blueprints:
+  - name: name
+    type: string
+- name: some-multi-select
+  type: multi_select_options
+  configurable: true
+  optional: true
+job_types:
+- name: some-job
+  property_blueprints:
+  - name: job-string
+    type: string
+    configurable: true
+post_deploy_errands:
+- name: smoke-tests
+pre_delete_errands:
+- name: smoke-tests
+- name: cleanup
+`))
+		Expect(err).ToNot(HaveOccurred())
+
+		contents, err := json.Marshal(generator.CreateJSONSchema(metadata))
+		Expect(err).ToNot(HaveOccurred())
+
+		var schema struct {
+			Title      string `json:"title"`
+			Properties struct {
+				ValidateConfigComplete json.RawMessage `json:"validate-config-complete"`
+				ProductName            struct {
+					Const string `json:"const"`
+				} `json:"product-name"`
+				ProductProperties struct {
+					Properties map[string]struct {
+						Properties map[string]json.RawMessage `json:"properties"`
+						Required   []string                   `json:"required"`
+					} `json:"properties"`
+					Required []string `json:"required"`
+				} `json:"product-properties"`
+				ResourceConfig struct {
+					Properties map[string]json.RawMessage `json:"properties"`
+				} `json:"resource-config"`
+				ErrandConfig struct {
+					Properties map[string]struct {
+						Properties map[string]json.RawMessage `json:"properties"`
+					} `json:"properties"`
+				} `json:"errand-config"`
+			} `json:"properties"`
+		}
+		Expect(json.Unmarshal(contents, &schema)).To(Succeed())
+
+		Expect(schema.Title).To(Equal("some-product 1.2.3 product configuration"))
+		Expect(schema.Properties.ProductName.Const).To(Equal("some-product"))
+
+		Expect(schema.Properties.ValidateConfigComplete).To(MatchJSON(`{"type": "boolean"}`))
+
+		productProperties := schema.Properties.ProductProperties
+		Expect(productProperties.Properties).To(HaveLen(7))
+		Expect(productProperties.Properties).To(HaveKey(".properties.some-selector.internal_option.some-secret"))
+		Expect(productProperties.Required).To(Equal([]string{".properties.some-string", ".some-job.job-string"}))
+		Expect(string(productProperties.Properties[".properties.some-multi-select"].Properties["value"])).To(ContainSubstring(`"enum":[]`))
+
+		Expect(productProperties.Properties[".properties.some-string"].Properties["value"]).To(MatchJSON(`{"type": "string"}`))
+		Expect(productProperties.Properties[".properties.some-string"].Required).To(Equal([]string{"value"}))
+		Expect(productProperties.Properties[".properties.some-port"].Properties["value"]).To(MatchJSON(`{
+			"anyOf": [
+				{"type": "integer"},
+				{"type": "string", "pattern": "^\\(\\(.+\\)\\)$", "description": "a variable that is interpolated before configuring the product"}
+			],
+			"default": 8080
+		}`))
+		Expect(productProperties.Properties[".properties.some-selector"].Properties).To(HaveKey("selected_option"))
+		Expect(productProperties.Properties[".properties.some-selector"].Required).To(BeEmpty())
+		Expect(string(productProperties.Properties[".properties.some-collection"].Properties["value"])).To(ContainSubstring(`"default":[{"name":"first"}]`))
+
+		Expect(schema.Properties.ResourceConfig.Properties).To(HaveKey("some-job"))
+		Expect(schema.Properties.ErrandConfig.Properties["smoke-tests"].Properties).To(HaveKey("post-deploy-state"))
+		Expect(schema.Properties.ErrandConfig.Properties["smoke-tests"].Properties).To(HaveKey("pre-delete-state"))
+		Expect(schema.Properties.ErrandConfig.Properties["cleanup"].Properties).ToNot(HaveKey("post-deploy-state"))
+	})
+
+	It("can be encoded for every fixture", func() {
+		for _, fixture := range []string{"pas.yml", "p_healthwatch.yml", "pks.yml", "cloudcache.yml", "p-redis.yml"} {
+			metadataBytes, err := getFileBytes("./fixtures/" + fixture)
+			Expect(err).ToNot(HaveOccurred())
+
+			metadata, err := generator.NewMetadata(metadataBytes)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = json.Marshal(generator.CreateJSONSchema(metadata))
+			Expect(err).ToNot(HaveOccurred(), fixture)
+		}
+	})
+})
@@ -17,19 +17,53 @@ func NewMetadata(fileBytes []byte) (*Metadata, error) {
 }
 
 type Metadata struct {
-	Name               string              `yaml:"name"`
-	Version            string              `yaml:"product_version"`
-	FormTypes          []FormType          `yaml:"form_types"`
-	PropertyBlueprints []PropertyBlueprint `yaml:"property_blueprints"`
-	JobTypes           []JobType           `yaml:"job_types"`
-	PostDeployErrands  []ErrandMetadata    `yaml:"post_deploy_errands"`
-	PreDeleteErrands   []ErrandMetadata    `yaml:"pre_delete_errands"`
+	Name                        string                      `yaml:"name"`
+	Version                     string                      `yaml:"product_version"`
+	Label                       string                      `yaml:"label"`
+	MetadataVersion             string                      `yaml:"metadata_version"`
+	MinimumVersionForUpgrade    string                      `yaml:"minimum_version_for_upgrade"`
+	Releases                    []Release                   `yaml:"releases"`
+	FormTypes                   []FormType                  `yaml:"form_types"`
+	PropertyBlueprints          []PropertyBlueprint         `yaml:"property_blueprints"`
+	JobTypes                    []JobType                   `yaml:"job_types"`
+	PostDeployErrands           []ErrandMetadata            `yaml:"post_deploy_errands"`
+	PreDeleteErrands            []ErrandMetadata            `yaml:"pre_delete_errands"`
+	StemcellCriteria            StemcellCriteria            `yaml:"stemcell_criteria"`
+	AdditionalStemcellsCriteria []StemcellCriteria          `yaml:"additional_stemcells_criteria"`
+	RequiresProductVersions     []ProductVersionRequirement `yaml:"requires_product_versions"`
+}
+
+type Release struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
+	File    string `yaml:"file"`
+}
+
+type StemcellCriteria struct {
+	OS                         string `yaml:"os"`
+	Version                    string `yaml:"version"`
+	EnablePatchSecurityUpdates bool   `yaml:"enable_patch_security_updates"`
+}
+
+type ProductVersionRequirement struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
 }
 
 func (m *Metadata) Errands() []ErrandMetadata {
 	return append(m.PostDeployErrands, m.PreDeleteErrands...)
 }
 
+// StemcellsCriteria returns the stemcell criteria of the tile followed by its additional stemcells criteria.
+// The stemcell criteria are left out when the tile does not give an OS.
+func (m *Metadata) StemcellsCriteria() []StemcellCriteria {
+	if m.StemcellCriteria.OS == "" {
+		return m.AdditionalStemcellsCriteria
+	}
+
+	return append([]StemcellCriteria{m.StemcellCriteria}, m.AdditionalStemcellsCriteria...)
+}
+
 type ErrandMetadata struct {
 	Name string `yaml:"name"`
 }
@@ -107,6 +141,48 @@ func (m *Metadata) GetPropertyBlueprint(propertyReference string) (*PropertyBlue
 	return nil, fmt.Errorf("property %s not found", propertyReference)
 }
 
+// PropertyReference is a configurable property blueprint
+// together with the reference used for it in product-properties.
+type PropertyReference struct {
+	Reference string
+	Blueprint PropertyBlueprint
+}
+
+// ConfigurableProperties lists the configurable product and job properties,
+// including the properties of every selector option.
+func (m *Metadata) ConfigurableProperties() []PropertyReference {
+	var references []PropertyReference
+	for _, property := range m.PropertyBlueprints {
+		references = appendConfigurableProperties(references, ".properties."+property.Name, property)
+	}
+
+	for _, job := range m.JobTypes {
+		for _, property := range job.PropertyBlueprint {
+			references = appendConfigurableProperties(references, fmt.Sprintf(".%s.%s", job.Name, property.Name), property)
+		}
+	}
+
+	return references
+}
+
+func appendConfigurableProperties(references []PropertyReference, reference string, property PropertyBlueprint) []PropertyReference {
+	if !property.IsConfigurable() {
+		return references
+	}
+
+	references = append(references, PropertyReference{Reference: reference, Blueprint: property})
+
+	if property.IsSelector() {
+		for _, option := range property.OptionTemplates {
+			for _, optionProperty := range option.PropertyBlueprints {
+				references = appendConfigurableProperties(references, fmt.Sprintf("%s.%s.%s", reference, option.Name, optionProperty.Name), optionProperty)
+			}
+		}
+	}
+
+	return references
+}
+
 func (m *Metadata) PropertyInputs() []PropertyInput {
 	var propertyInputs []PropertyInput
 	for _, form := range m.FormTypes {
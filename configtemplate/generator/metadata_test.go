@@ -53,6 +53,48 @@ var _ = Describe("Metadata", func() {
 		})
 	})
 
+	Context("ConfigurableProperties", func() {
+		It("lists product, selector option, and job properties", func() {
+			metadata, err := generator.NewMetadata([]byte(`---
+property_blueprints:
+- name: some-string
+  type: string
+  configurable: true
+- name: not-configurable
+  type: string
+- name: some-selector
+  type: selector
+  configurable: true
+  option_templates:
+  - name: some-option
+    select_value: some-option
+    property_blueprints:
+    - name: option-property
+      type: integer
+      configurable: true
+      optional: true
+job_types:
+- name: some-job
+  property_blueprints:
+  - name: job-property
+    type: boolean
+    configurable: true
+`))
+			Expect(err).ToNot(HaveOccurred())
+
+			var references []string
+			for _, property := range metadata.ConfigurableProperties() {
+				references = append(references, property.Reference)
+			}
+			Expect(references).To(Equal([]string{
+				".properties.some-string",
+				".properties.some-selector",
+				".properties.some-selector.some-option.option-property",
+				".some-job.job-property",
+			}))
+		})
+	})
+
 	Context("Product Name", func() {
 		It("Should return cf as product name", func() {
 			fileData, err := ioutil.ReadFile("fixtures/pas.yml")
@@ -111,6 +153,35 @@ var _ = Describe("Metadata", func() {
 		})
 	})
 
+	Context("StemcellsCriteria", func() {
+		It("returns the stemcell criteria followed by the additional stemcells criteria", func() {
+			metadata, err := generator.NewMetadata([]byte(`
+stemcell_criteria:
+  os: ubuntu-xenial
+additional_stemcells_criteria:
+- os: windows2019
+  version: "2019.7"
+`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(metadata.StemcellsCriteria()).To(Equal([]generator.StemcellCriteria{
+				{OS: "ubuntu-xenial"},
+				{OS: "windows2019", Version: "2019.7"},
+			}))
+		})
+
+		It("leaves out the stemcell criteria without an OS", func() {
+			metadata, err := generator.NewMetadata([]byte(`
+additional_stemcells_criteria:
+- os: windows2019
+  version: "2019.7"
+`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(metadata.StemcellsCriteria()).To(Equal([]generator.StemcellCriteria{
+				{OS: "windows2019", Version: "2019.7"},
+			}))
+		})
+	})
+
 	table.DescribeTable("ProductVersion tile metadata fixture tests", func(fixtureFilepath string, expectedVersion string) {
 		fileData, err := ioutil.ReadFile(fixtureFilepath)
 		Expect(err).ToNot(HaveOccurred())
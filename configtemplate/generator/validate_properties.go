@@ -0,0 +1,347 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ValidateProductProperties compares the product-properties of a product config
+// with the property blueprints, and returns every problem that was found.
+// Values that still contain a ((variable)) are not type checked.
+func (m *Metadata) ValidateProductProperties(properties map[string]interface{}) []string {
+	blueprints := map[string]PropertyBlueprint{}
+	for _, property := range m.ConfigurableProperties() {
+		blueprints[property.Reference] = property.Blueprint
+	}
+
+	var references []string
+	for reference := range properties {
+		references = append(references, reference)
+	}
+	sort.Strings(references)
+
+	var problems []string
+	for _, reference := range references {
+		blueprint, ok := blueprints[reference]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown property %s", reference))
+			continue
+		}
+
+		problems = append(problems, validatePropertyValue(reference, blueprint, properties[reference])...)
+	}
+
+	for _, property := range m.topLevelProperties() {
+		problems = append(problems, missingRequiredProperties(property.Reference, property.Blueprint, properties)...)
+	}
+
+	return problems
+}
+
+// topLevelProperties lists the product and job properties,
+// without the properties of the selector options.
+func (m *Metadata) topLevelProperties() []PropertyReference {
+	var references []PropertyReference
+	for _, property := range m.PropertyBlueprints {
+		references = append(references, PropertyReference{Reference: ".properties." + property.Name, Blueprint: property})
+	}
+
+	for _, job := range m.JobTypes {
+		for _, property := range job.PropertyBlueprint {
+			references = append(references, PropertyReference{Reference: fmt.Sprintf(".%s.%s", job.Name, property.Name), Blueprint: property})
+		}
+	}
+
+	return references
+}
+
+func validatePropertyValue(reference string, blueprint PropertyBlueprint, property interface{}) []string {
+	fields, ok := property.(map[interface{}]interface{})
+	if !ok {
+		return []string{fmt.Sprintf("property %s must be a map with a value or selected_option", reference)}
+	}
+
+	var problems []string
+	for key := range fields {
+		if key != "value" && key != "selected_option" {
+			problems = append(problems, fmt.Sprintf("property %s has an unknown key %v", reference, key))
+		}
+	}
+
+	if selectedOption, ok := fields["selected_option"]; ok {
+		if !blueprint.IsSelector() {
+			problems = append(problems, fmt.Sprintf("property %s is not a selector but has a selected_option", reference))
+		} else if !isVariable(selectedOption) && namedOptionTemplate(blueprint, selectedOption) == nil {
+			problems = append(problems, fmt.Sprintf("property %s has an invalid selected_option %v (options: %s)", reference, selectedOption, strings.Join(optionTemplateNames(blueprint), ", ")))
+		}
+	}
+
+	value, ok := fields["value"]
+	if !ok {
+		if _, ok := fields["selected_option"]; !ok {
+			problems = append(problems, fmt.Sprintf("property %s must be a map with a value or selected_option", reference))
+		}
+		return problems
+	}
+
+	if problem := validateValue(reference, blueprint, value); problem != "" {
+		problems = append(problems, problem)
+	}
+
+	if blueprint.IsCollection() {
+		problems = append(problems, validateCollection(reference, blueprint, value)...)
+	}
+
+	return problems
+}
+
+func validateValue(reference string, blueprint PropertyBlueprint, value interface{}) string {
+	if value == nil || isVariable(value) {
+		return ""
+	}
+
+	invalid := fmt.Sprintf("property %s of type %s has an invalid value %v", reference, blueprint.Type, value)
+
+	switch {
+	case blueprint.Type == "dropdown_select":
+		if !containsOption(optionNames(blueprint), value) {
+			return fmt.Sprintf("%s (options: %s)", invalid, strings.Join(optionNames(blueprint), ", "))
+		}
+	case blueprint.IsSelector():
+		if selectedOptionTemplate(blueprint, value) == nil {
+			return fmt.Sprintf("%s (options: %s)", invalid, strings.Join(optionTemplateSelectValues(blueprint), ", "))
+		}
+	case blueprint.IsMultiSelect():
+		values, ok := value.([]interface{})
+		if !ok {
+			return invalid
+		}
+		for _, v := range values {
+			if !isVariable(v) && !containsOption(optionNames(blueprint), v) {
+				return fmt.Sprintf("%s (options: %s)", invalid, strings.Join(optionNames(blueprint), ", "))
+			}
+		}
+	case blueprint.IsInt():
+		if !isInt(value) {
+			return invalid
+		}
+	case blueprint.IsBool():
+		if _, ok := value.(bool); !ok {
+			return invalid
+		}
+	case blueprint.IsString():
+		switch value.(type) {
+		case string, int, float64:
+		default:
+			return invalid
+		}
+	case blueprint.IsSecret():
+		if !hasKeys(value, "secret") {
+			return invalid
+		}
+	case blueprint.IsSimpleCredentials():
+		if !hasKeys(value, "identity", "password") {
+			return invalid
+		}
+	case blueprint.IsCertificate():
+		if !hasKeys(value, "cert_pem", "private_key_pem") {
+			return invalid
+		}
+	case blueprint.IsCollection():
+		if _, ok := value.([]interface{}); !ok {
+			return invalid
+		}
+	}
+
+	return ""
+}
+
+func validateCollection(reference string, blueprint PropertyBlueprint, value interface{}) []string {
+	entries, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var problems []string
+	for index, entry := range entries {
+		entryReference := fmt.Sprintf("%s[%d]", reference, index)
+
+		fields, ok := entry.(map[interface{}]interface{})
+		if !ok {
+			if !isVariable(entry) {
+				problems = append(problems, fmt.Sprintf("property %s must be a map", entryReference))
+			}
+			continue
+		}
+
+		var names []string
+		for name := range fields {
+			names = append(names, fmt.Sprintf("%v", name))
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			subBlueprint, ok := collectionBlueprint(blueprint, name)
+			if !ok {
+				problems = append(problems, fmt.Sprintf("property %s has an unknown field %s", entryReference, name))
+				continue
+			}
+
+			if problem := validateValue(fmt.Sprintf("%s.%s", entryReference, name), subBlueprint, fields[name]); problem != "" {
+				problems = append(problems, problem)
+			}
+		}
+	}
+
+	return problems
+}
+
+func missingRequiredProperties(reference string, blueprint PropertyBlueprint, properties map[string]interface{}) []string {
+	if !blueprint.IsConfigurable() {
+		return nil
+	}
+
+	property, configured := properties[reference]
+	if !configured && needsValue(blueprint) {
+		return []string{fmt.Sprintf("missing required property %s", reference)}
+	}
+
+	if !blueprint.IsSelector() {
+		return nil
+	}
+
+	option := selectedOption(blueprint, property)
+	if option == nil {
+		return nil
+	}
+
+	var problems []string
+	for _, optionProperty := range option.PropertyBlueprints {
+		problems = append(problems, missingRequiredProperties(fmt.Sprintf("%s.%s.%s", reference, option.Name, optionProperty.Name), optionProperty, properties)...)
+	}
+
+	return problems
+}
+
+func needsValue(blueprint PropertyBlueprint) bool {
+	if !blueprint.IsRequired() || blueprint.HasDefault() {
+		return false
+	}
+
+	return !blueprint.IsBool() && !blueprint.IsMultiSelect() && !blueprint.IsDropdown()
+}
+
+func selectedOption(blueprint PropertyBlueprint, property interface{}) *OptionTemplate {
+	if fields, ok := property.(map[interface{}]interface{}); ok {
+		if name, ok := fields["selected_option"]; ok {
+			return namedOptionTemplate(blueprint, name)
+		}
+		if value, ok := fields["value"]; ok {
+			return selectedOptionTemplate(blueprint, value)
+		}
+	}
+
+	if blueprint.HasDefault() {
+		return selectedOptionTemplate(blueprint, blueprint.Default)
+	}
+
+	return nil
+}
+
+// selectedOptionTemplate returns the option whose select_value is the value of the selector.
+// Like the JSON schema, it only accepts the exact select_value.
+func selectedOptionTemplate(blueprint PropertyBlueprint, value interface{}) *OptionTemplate {
+	for _, option := range blueprint.OptionTemplates {
+		if option.SelectValue == fmt.Sprintf("%v", value) {
+			option := option
+			return &option
+		}
+	}
+	return nil
+}
+
+// namedOptionTemplate returns the option whose name is the selected_option of the selector.
+// Like the JSON schema, it only accepts the exact name.
+func namedOptionTemplate(blueprint PropertyBlueprint, name interface{}) *OptionTemplate {
+	for _, option := range blueprint.OptionTemplates {
+		if option.Name == fmt.Sprintf("%v", name) {
+			option := option
+			return &option
+		}
+	}
+	return nil
+}
+
+func collectionBlueprint(blueprint PropertyBlueprint, name string) (PropertyBlueprint, bool) {
+	for _, subBlueprint := range blueprint.PropertyBlueprints {
+		if subBlueprint.Name == name {
+			return subBlueprint, true
+		}
+	}
+	return PropertyBlueprint{}, false
+}
+
+func optionNames(blueprint PropertyBlueprint) []string {
+	names := []string{}
+	for _, option := range blueprint.Options {
+		names = append(names, fmt.Sprintf("%v", option.Name))
+	}
+	return names
+}
+
+func optionTemplateNames(blueprint PropertyBlueprint) []string {
+	names := []string{}
+	for _, option := range blueprint.OptionTemplates {
+		names = append(names, option.Name)
+	}
+	return names
+}
+
+func optionTemplateSelectValues(blueprint PropertyBlueprint) []string {
+	values := []string{}
+	for _, option := range blueprint.OptionTemplates {
+		values = append(values, option.SelectValue)
+	}
+	return values
+}
+
+func containsOption(options []string, value interface{}) bool {
+	for _, option := range options {
+		if option == fmt.Sprintf("%v", value) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasKeys(value interface{}, keys ...string) bool {
+	if isVariable(value) {
+		return true
+	}
+
+	fields, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return false
+	}
+
+	for _, key := range keys {
+		if _, ok := fields[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func isInt(value interface{}) bool {
+	switch value.(type) {
+	case int, int64, uint64:
+		return true
+	}
+	return false
+}
+
+func isVariable(value interface{}) bool {
+	s, ok := value.(string)
+	return ok && strings.Contains(s, "((") && strings.Contains(s, "))")
+}
@@ -0,0 +1,170 @@
+package generator_test
+
+import (
+	"github.com/pivotal-cf/om/configtemplate/generator"
+	"gopkg.in/yaml.v2"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ValidateProductProperties", func() {
+	var metadata *generator.Metadata
+
+	BeforeEach(func() {
+		var err error
+		metadata, err = generator.NewMetadata([]byte(`---
+name: some-product
+property_blueprints:
+- name: some-string
+  type: string
+  configurable: true
+- name: some-port
+  type: port
+  configurable: true
+  default: 8080
+- name: some-boolean
+  type: boolean
+  configurable: true
+- name: some-secret
+  type: secret
+  configurable: true
+  optional: true
+- name: some-dropdown
+  type: dropdown_select
+  configurable: true
+  default: small
+  options:
+  - name: small
+  - name: large
+- name: some-collection
+  type: collection
+  configurable: true
+  optional: true
+  property_blueprints:
+  - name: name
+    type: string
+  - name: port
+    type: integer
+- name: some-selector
+  type: selector
+  configurable: true
+  default: internal
+  option_templates:
+  - name: internal_option
+    select_value: internal
+    property_blueprints: []
+  - name: external_option
+    select_value: external
+    property_blueprints:
+    - name: url
+      type: http_url
+      configurable: true
+job_types:
+- name: some-job
+  property_blueprints:
+  - name: job-integer
+    type: integer
+    configurable: true
+    default: 1
+  - name: job-string
+    type: string
+    configurable: true
+`))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	properties := func(contents string) map[string]interface{} {
+		var properties map[string]interface{}
+		Expect(yaml.Unmarshal([]byte(contents), &properties)).To(Succeed())
+		return properties
+	}
+
+	It("returns no problems for a valid config", func() {
+		Expect(metadata.ValidateProductProperties(properties(`
+.properties.some-string:
+  value: hello
+.properties.some-port:
+  value: 443
+.properties.some-secret:
+  value:
+    secret: ((some_secret))
+.properties.some-dropdown:
+  value: large
+.properties.some-collection:
+  value:
+  - name: first
+    port: 80
+.properties.some-selector:
+  selected_option: internal_option
+  value: internal
+.some-job.job-integer:
+  value: ((job_integer))
+.some-job.job-string:
+  value: hello
+`))).To(BeEmpty())
+	})
+
+	It("returns every problem at once", func() {
+		Expect(metadata.ValidateProductProperties(properties(`
+.properties.some-strng:
+  value: typo
+.properties.some-port:
+  value: not-a-port
+.properties.some-boolean:
+  value: "yes"
+.properties.some-secret:
+  value: plain-text
+.properties.some-dropdown:
+  value: medium
+.properties.some-collection:
+  value:
+  - name: first
+    prot: 80
+.properties.some-selector:
+  value: external
+.some-job.job-integer: 1
+`))).To(Equal([]string{
+			"property .properties.some-boolean of type boolean has an invalid value yes",
+			"property .properties.some-collection[0] has an unknown field prot",
+			"property .properties.some-dropdown of type dropdown_select has an invalid value medium (options: small, large)",
+			"property .properties.some-port of type port has an invalid value not-a-port",
+			"property .properties.some-secret of type secret has an invalid value plain-text",
+			"unknown property .properties.some-strng",
+			"property .some-job.job-integer must be a map with a value or selected_option",
+			"missing required property .properties.some-string",
+			"missing required property .properties.some-selector.external_option.url",
+			"missing required property .some-job.job-string",
+		}))
+	})
+
+	It("reports invalid selector options", func() {
+		Expect(metadata.ValidateProductProperties(properties(`
+.properties.some-string:
+  value: hello
+.properties.some-selector:
+  selected_option: unknown_option
+  value: unknown
+.some-job.job-string:
+  value: hello
+`))).To(Equal([]string{
+			"property .properties.some-selector has an invalid selected_option unknown_option (options: internal_option, external_option)",
+			"property .properties.some-selector of type selector has an invalid value unknown (options: internal, external)",
+		}))
+	})
+
+	It("only accepts the exact name and select_value of a selector option", func() {
+		Expect(metadata.ValidateProductProperties(properties(`
+.properties.some-string:
+  value: hello
+.properties.some-selector:
+  selected_option: Internal_Option
+  value: internal_option
+.some-job.job-string:
+  value: hello
+`))).To(Equal([]string{
+			"property .properties.some-selector has an invalid selected_option Internal_Option (options: internal_option, external_option)",
+			"property .properties.some-selector of type selector has an invalid value internal_option (options: internal, external)",
+		}))
+	})
+})
@@ -40,7 +40,12 @@ func (ex Executor) GetCommandNamesAndDescriptions() (map[string]string, error) {
 			continue
 		}
 
-		if isCommand && commandLine != "" {
+		// the plugins found on the PATH are listed after the commands
+		if isCommand && commandLine == "" {
+			break
+		}
+
+		if isCommand {
 			splitCommandLine := strings.Fields(commandLine)
 			commands[splitCommandLine[0]] = strings.Join(splitCommandLine[1:], " ")
 		}
@@ -45,6 +45,30 @@ Commands:
 		}))
 	})
 
+	It("does not retrieve the plugins as command names", func() {
+		os.Setenv("STUB_OUTPUT", `ॐ
+om helps you interact with an Ops Manager
+
+Usage: om [options] <command> [<args>]
+  --ca-cert, OM_CA_CERT                                  string  OpsManager CA certificate path or value
+
+Commands:
+  apply-changes  triggers an install on the Ops Manager targeted
+  errands        list errands for a product
+
+Plugins:
+  hello          /usr/local/bin/om-hello
+`)
+
+		ex := executor.NewExecutor(pathToStub)
+		output, err := ex.GetCommandNamesAndDescriptions()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(Equal(map[string]string{
+			"apply-changes": "triggers an install on the Ops Manager targeted",
+			"errands":       "list errands for a product",
+		}))
+	})
+
 	It("retrieves the command description from om", func() {
 		os.Setenv("STUB_OUTPUT", `ॐ  activate-certificate-authority
 This authenticated command activates an existing certificate authority on the Ops Manager
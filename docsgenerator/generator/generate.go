@@ -306,6 +306,10 @@ func (g *Generator) cleanupExtraDirs(baseDir string, commandDescriptions map[str
 	}
 
 	for _, dir := range dirs {
+		if dir == filepath.Join(g.docsDir, ManDirName) {
+			continue
+		}
+
 		var commandExists bool
 		for command := range commandDescriptions {
 			if strings.Contains(dir, command) {
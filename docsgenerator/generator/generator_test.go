@@ -346,6 +346,19 @@ var _ = Describe("Generator", func() {
 			_, err = os.Stat(docsDir)
 			Expect(err).ToNot(HaveOccurred())
 		})
+
+		It("doesn't remove the man pages dir", func() {
+			manDir := filepath.Join(docsDir, generator.ManDirName)
+			err := os.MkdirAll(manDir, 0755)
+			Expect(err).ToNot(HaveOccurred())
+
+			gen := generator.NewGenerator(templatesDir, docsDir, ex, GinkgoWriter)
+			err = gen.GenerateDocs()
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = os.Stat(manDir)
+			Expect(err).ToNot(HaveOccurred())
+		})
 	})
 
 	When("om can't return the command names and descriptions", func() {
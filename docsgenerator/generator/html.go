@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"html/template"
+	"os"
+	"strings"
+)
+
+const HTMLFileName = "reference.html"
+
+var htmlTemplate = template.Must(template.New("reference").Funcs(template.FuncMap{
+	"names":  func(flag FlagReference) string { return strings.Join(flag.names(), ", ") },
+	"env":    func(flag FlagReference) string { return strings.Join(flag.Env, ", ") },
+	"search": searchText,
+}).Parse(`<!DOCTYPE html>
+<!-- This file is autogenerated by docsgenerator -->
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>om command reference</title>
+<style>
+body { font-family: sans-serif; margin: 0; display: flex; }
+nav { position: sticky; top: 0; height: 100vh; overflow-y: auto; min-width: 18em; padding: 1em; border-right: 1px solid #ddd; box-sizing: border-box; }
+nav ul { list-style: none; padding: 0; }
+main { padding: 1em 2em; max-width: 70em; }
+input { width: 100%; box-sizing: border-box; padding: 0.4em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #ddd; padding: 0.3em 0.5em; text-align: left; vertical-align: top; }
+td:first-child { white-space: nowrap; font-family: monospace; }
+.description { white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<nav>
+<input id="search" type="search" placeholder="Search commands and flags" autofocus>
+<ul>
+{{- range .Commands}}
+<li data-search="{{search .}}"><a href="#{{.Name}}">{{.Name}}</a></li>
+{{- end}}
+</ul>
+</nav>
+<main>
+<h1>{{.Name}}</h1>
+<p>{{.Description}}</p>
+<section id="global-flags">
+<h2>Global Flags</h2>
+{{template "flags" .GlobalFlags}}
+</section>
+{{- range .Commands}}
+<section id="{{.Name}}" data-search="{{search .}}">
+<h2><code>om {{.Name}}</code></h2>
+<p class="description">{{.Description}}</p>
+{{- if .Flags}}
+{{template "flags" .Flags}}
+{{- end}}
+</section>
+{{- end}}
+</main>
+<script>
+document.getElementById('search').addEventListener('input', function (event) {
+  var query = event.target.value.toLowerCase();
+  document.querySelectorAll('[data-search]').forEach(function (element) {
+    element.hidden = query !== '' && element.getAttribute('data-search').indexOf(query) === -1;
+  });
+});
+</script>
+</body>
+</html>
+{{define "flags"}}<table>
+<tr><th>Flag</th><th>Type</th><th>Environment</th><th>Default</th><th>Description</th></tr>
+{{- range .}}
+<tr><td>{{names .}}</td><td>{{.Type}}{{if .Required}} (required){{end}}{{if .Variadic}} (variadic){{end}}</td><td>{{env .}}</td><td>{{if .Default}}{{.Default}}{{end}}</td><td class="description">{{if .Deprecated}}<strong>DEPRECATED</strong> {{end}}{{if .Experimental}}<strong>EXPERIMENTAL</strong> {{end}}{{.Description}}</td></tr>
+{{- end}}
+</table>{{end}}
+`))
+
+// GenerateHTML writes a single page reference of every command,
+// which can be searched by command, flag and description.
+func (g *Generator) GenerateHTML(path string) error {
+	reference, err := g.getReference()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	err = htmlTemplate.Execute(f, reference)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func searchText(command CommandReference) string {
+	words := []string{command.Name, command.ShortDescription, command.Description}
+	for _, flag := range command.Flags {
+		words = append(words, flag.names()...)
+		words = append(words, flag.Env...)
+	}
+
+	return strings.ToLower(strings.Join(strings.Fields(strings.Join(words, " ")), " "))
+}
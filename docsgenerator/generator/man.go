@@ -0,0 +1,140 @@
+package generator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const ManDirName = "man"
+
+// GenerateManPages writes the om(1) man page and a man page for each command,
+// such as om-apply-changes(1).
+func (g *Generator) GenerateManPages(manDir string) error {
+	reference, err := g.getReference()
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(manDir, 0755)
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(filepath.Join(manDir, "om.1"), []byte(omManPage(reference)), 0644)
+	if err != nil {
+		return err
+	}
+
+	for _, command := range reference.Commands {
+		err = ioutil.WriteFile(filepath.Join(manDir, fmt.Sprintf("om-%s.1", command.Name)), []byte(commandManPage(command)), 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func omManPage(reference Reference) string {
+	var page strings.Builder
+
+	fmt.Fprintf(&page, ".TH OM 1 \"\" \"om\" \"om Manual\"\n")
+	fmt.Fprintf(&page, ".SH NAME\nom \\- %s\n", roffEscape(reference.Description))
+	fmt.Fprintf(&page, ".SH SYNOPSIS\n\\fBom\\fR [\\fIoptions\\fR] \\fIcommand\\fR [\\fIargs\\fR]\n")
+	fmt.Fprintf(&page, ".SH OPTIONS\n%s", manFlags(reference.GlobalFlags))
+
+	page.WriteString(".SH COMMANDS\n")
+	for _, command := range reference.Commands {
+		fmt.Fprintf(&page, ".TP\n\\fBom\\-%s\\fR(1)\n%s\n", roffEscape(command.Name), roffEscape(command.ShortDescription))
+	}
+
+	return page.String()
+}
+
+func commandManPage(command CommandReference) string {
+	var page strings.Builder
+
+	name := roffEscape(command.Name)
+	fmt.Fprintf(&page, ".TH OM\\-%s 1 \"\" \"om\" \"om Manual\"\n", strings.ToUpper(name))
+	fmt.Fprintf(&page, ".SH NAME\nom\\-%s \\- %s\n", name, roffEscape(command.ShortDescription))
+	fmt.Fprintf(&page, ".SH SYNOPSIS\n\\fBom\\fR [\\fIoptions\\fR] \\fB%s\\fR [\\fIargs\\fR]\n", name)
+	fmt.Fprintf(&page, ".SH DESCRIPTION\n%s\n", roffParagraphs(command.Description))
+
+	if len(command.Flags) > 0 {
+		fmt.Fprintf(&page, ".SH OPTIONS\n%s", manFlags(command.Flags))
+	}
+
+	page.WriteString(".SH GLOBAL OPTIONS\nSee \\fBom\\fR(1).\n")
+	page.WriteString(".SH SEE ALSO\n\\fBom\\fR(1)\n")
+
+	return page.String()
+}
+
+func manFlags(flags []FlagReference) string {
+	var section strings.Builder
+
+	for _, flag := range flags {
+		var names []string
+		for _, name := range flag.names() {
+			names = append(names, fmt.Sprintf("\\fB%s\\fR", roffEscape(name)))
+		}
+		for _, env := range flag.Env {
+			names = append(names, fmt.Sprintf("\\fB$%s\\fR", roffEscape(env)))
+		}
+
+		fmt.Fprintf(&section, ".TP\n%s \\fI%s\\fR\n", strings.Join(names, ", "), roffEscape(flag.Type))
+
+		var details []string
+		if flag.Deprecated {
+			details = append(details, "Deprecated.")
+		}
+		if flag.Experimental {
+			details = append(details, "Experimental.")
+		}
+		if flag.Required {
+			details = append(details, "Required.")
+		}
+		if flag.Variadic {
+			details = append(details, "Can be given multiple times.")
+		}
+		if flag.Default != nil {
+			details = append(details, fmt.Sprintf("Default: %s.", *flag.Default))
+		}
+
+		description := flag.Description
+		if len(details) > 0 {
+			description = strings.TrimSpace(description + "\n" + strings.Join(details, " "))
+		}
+		fmt.Fprintf(&section, "%s\n", roffParagraphs(description))
+	}
+
+	return section.String()
+}
+
+// roffParagraphs keeps the lines of the text as they are printed by om.
+func roffParagraphs(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			lines = append(lines, ".sp")
+			continue
+		}
+
+		lines = append(lines, roffEscape(line))
+	}
+
+	return strings.Join(lines, "\n.br\n")
+}
+
+func roffEscape(text string) string {
+	text = strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(text)
+	if strings.HasPrefix(text, ".") || strings.HasPrefix(text, "'") {
+		text = `\&` + text
+	}
+
+	return text
+}
@@ -0,0 +1,251 @@
+package generator
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const JSONFileName = "commands.json"
+
+// Reference describes om, its global flags and every command,
+// as parsed from the help of om.
+type Reference struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	GlobalFlags []FlagReference    `json:"global_flags"`
+	Commands    []CommandReference `json:"commands"`
+}
+
+type CommandReference struct {
+	Name             string          `json:"name"`
+	ShortDescription string          `json:"short_description"`
+	Description      string          `json:"description"`
+	Flags            []FlagReference `json:"flags"`
+}
+
+type FlagReference struct {
+	Long         string   `json:"long,omitempty"`
+	Short        string   `json:"short,omitempty"`
+	Aliases      []string `json:"aliases,omitempty"`
+	Env          []string `json:"env,omitempty"`
+	Type         string   `json:"type"`
+	Required     bool     `json:"required"`
+	Variadic     bool     `json:"variadic"`
+	Default      *string  `json:"default,omitempty"`
+	Deprecated   bool     `json:"deprecated"`
+	Experimental bool     `json:"experimental"`
+	Description  string   `json:"description"`
+}
+
+var (
+	flagLine       = regexp.MustCompile(`^  (--?[^\s,]+|[A-Z][A-Z0-9_]*)(, |\s\s|$)`)
+	columnSplit    = regexp.MustCompile(`\s{2,}`)
+	defaultSuffix  = regexp.MustCompile(`(?s)^(.*?) ?\(default: (.*)\)$`)
+	aliasesLine    = regexp.MustCompile(`^\(aliases: (.*)\)$`)
+	deprecatedTag  = "**DEPRECATED** "
+	experimentTag  = "**EXPERIMENTAL** "
+	usagePrefix    = "Usage:"
+	argumentsTitle = "Command Arguments:"
+	commandsTitle  = "Commands:"
+)
+
+func (g *Generator) GenerateJSON(path string) error {
+	reference, err := g.getReference()
+	if err != nil {
+		return err
+	}
+
+	contents, err := json.MarshalIndent(reference, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, append(contents, '\n'), 0644)
+}
+
+func (g *Generator) getReference() (Reference, error) {
+	help, err := g.executor.RunOmCommand("--help")
+	if err != nil {
+		return Reference{}, err
+	}
+
+	commandDescriptions, err := g.executor.GetCommandNamesAndDescriptions()
+	if err != nil {
+		return Reference{}, err
+	}
+
+	lines := strings.Split(string(help), "\n")
+	reference := Reference{
+		Name:        "om",
+		GlobalFlags: parseFlags(sectionLines(lines, usagePrefix, commandsTitle)),
+	}
+	if len(lines) > 1 {
+		reference.Description = strings.TrimSpace(lines[1])
+	}
+
+	var commandNames []string
+	for commandName := range commandDescriptions {
+		commandNames = append(commandNames, commandName)
+	}
+	sort.Strings(commandNames)
+
+	for _, commandName := range commandNames {
+		help, err := g.executor.GetCommandHelp(commandName)
+		if err != nil {
+			return Reference{}, err
+		}
+
+		lines := strings.Split(string(help), "\n")
+		reference.Commands = append(reference.Commands, CommandReference{
+			Name:             commandName,
+			ShortDescription: commandDescriptions[commandName],
+			Description:      parseDescription(lines),
+			Flags:            parseFlags(sectionLines(lines, argumentsTitle, "")),
+		})
+	}
+
+	return reference, nil
+}
+
+// parseDescription returns the lines between the title and the usage of a command help.
+func parseDescription(lines []string) string {
+	var description []string
+	for i, line := range lines {
+		if i == 0 {
+			continue
+		}
+
+		if strings.HasPrefix(line, usagePrefix) {
+			break
+		}
+
+		description = append(description, line)
+	}
+
+	return strings.TrimSpace(strings.Join(description, "\n"))
+}
+
+// sectionLines returns the lines after the line starting with start,
+// until the line starting with end or the end of the help.
+func sectionLines(lines []string, start string, end string) []string {
+	var section []string
+	var inSection bool
+	for _, line := range lines {
+		if strings.HasPrefix(line, start) {
+			inSection = true
+			continue
+		}
+
+		if !inSection {
+			continue
+		}
+
+		if end != "" && strings.HasPrefix(line, end) {
+			break
+		}
+
+		section = append(section, line)
+	}
+
+	return section
+}
+
+// parseFlags parses the flags printed by jhanda:
+//
+//	--long, -s, ENV_VAR  type (required, variadic)  **EXPERIMENTAL** description (default: value)
+//	  (aliases: --alias)
+func parseFlags(lines []string) []FlagReference {
+	flags := []FlagReference{}
+	var descriptions [][]string
+
+	for _, line := range lines {
+		if flagLine.MatchString(line) {
+			columns := columnSplit.Split(strings.TrimSpace(line), 3)
+
+			var flag FlagReference
+			for _, name := range strings.Split(columns[0], ", ") {
+				switch {
+				case strings.HasPrefix(name, "--"):
+					flag.Long = strings.TrimPrefix(name, "--")
+				case strings.HasPrefix(name, "-"):
+					flag.Short = strings.TrimPrefix(name, "-")
+				default:
+					flag.Env = append(flag.Env, name)
+				}
+			}
+
+			if len(columns) > 1 {
+				kind := strings.SplitN(columns[1], " ", 2)
+				flag.Type = kind[0]
+				if len(kind) > 1 {
+					flag.Required = strings.Contains(kind[1], "required")
+					flag.Variadic = strings.Contains(kind[1], "variadic")
+				}
+			}
+
+			var description []string
+			if len(columns) > 2 {
+				description = append(description, columns[2])
+			}
+
+			flags = append(flags, flag)
+			descriptions = append(descriptions, description)
+			continue
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" || len(flags) == 0 {
+			continue
+		}
+
+		last := len(flags) - 1
+		if matches := aliasesLine.FindStringSubmatch(line); matches != nil {
+			for _, alias := range strings.Split(matches[1], ", ") {
+				flags[last].Aliases = append(flags[last].Aliases, strings.TrimPrefix(alias, "--"))
+			}
+			continue
+		}
+
+		descriptions[last] = append(descriptions[last], line)
+	}
+
+	for i := range flags {
+		description := strings.Join(descriptions[i], "\n")
+
+		if matches := defaultSuffix.FindStringSubmatch(description); matches != nil {
+			description = matches[1]
+			flags[i].Default = &matches[2]
+		}
+
+		if strings.HasPrefix(description, experimentTag) {
+			description = strings.TrimPrefix(description, experimentTag)
+			flags[i].Experimental = true
+		}
+
+		if strings.HasPrefix(description, deprecatedTag) {
+			description = strings.TrimPrefix(description, deprecatedTag)
+			flags[i].Deprecated = true
+		}
+
+		flags[i].Description = description
+	}
+
+	return flags
+}
+
+func (f FlagReference) names() []string {
+	var names []string
+	if f.Long != "" {
+		names = append(names, "--"+f.Long)
+	}
+	if f.Short != "" {
+		names = append(names, "-"+f.Short)
+	}
+	for _, alias := range f.Aliases {
+		names = append(names, "--"+alias)
+	}
+	return names
+}
@@ -0,0 +1,206 @@
+package generator_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/om/docsgenerator/fakes"
+	"github.com/pivotal-cf/om/docsgenerator/generator"
+)
+
+const (
+	omHelp = `ॐ
+om helps you interact with an Ops Manager
+
+Usage: om [options] <command> [<args>]
+  --env, -e                  string  env file with login credentials
+  --target, -t, OM_TARGET    string  location of the Ops Manager VM
+  --trace, -tr, OM_TRACE     bool    prints HTTP requests and response payloads (default: false)
+  OM_VARS_ENV                string  **EXPERIMENTAL** load vars from environment variables
+
+Commands:
+  bake   bakes you a cake
+  clean  cleans up after baking
+`
+
+	bakeHelp = `ॐ  bake
+This command will help you bake a cake.
+It is a piece of cake.
+
+Usage: om [options] bake [<args>]
+  --env, -e                  string  env file with login credentials
+
+Command Arguments:
+  --butter, -b     int (required, variadic)  sticks of butter
+  --flour          string                    type of flour,
+				like rye or wheat (default: wheat)
+    (aliases: --meal)
+  --oven, OM_OVEN  string                    **DEPRECATED** the oven to use
+`
+
+	cleanHelp = `ॐ  clean
+This command cleans up after baking.
+
+Usage: om [options] clean
+  --env, -e                  string  env file with login credentials
+`
+)
+
+var _ = Describe("Reference", func() {
+	var (
+		ex        *fakes.Executor
+		outputDir string
+		gen       generator.Generator
+	)
+
+	BeforeEach(func() {
+		var err error
+		outputDir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		ex = &fakes.Executor{}
+		ex.RunOmCommandReturns([]byte(omHelp), nil)
+		ex.GetCommandNamesAndDescriptionsReturns(map[string]string{
+			"bake":  "bakes you a cake",
+			"clean": "cleans up after baking",
+		}, nil)
+		ex.GetCommandHelpStub = func(commandName string) ([]byte, error) {
+			if commandName == "bake" {
+				return []byte(bakeHelp), nil
+			}
+
+			return []byte(cleanHelp), nil
+		}
+
+		gen = generator.NewGenerator("", "", ex, GinkgoWriter)
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(outputDir)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Describe("GenerateJSON", func() {
+		It("writes the flags of every command from the help of om", func() {
+			path := filepath.Join(outputDir, generator.JSONFileName)
+			err := gen.GenerateJSON(path)
+			Expect(err).ToNot(HaveOccurred())
+
+			contents, err := ioutil.ReadFile(path)
+			Expect(err).ToNot(HaveOccurred())
+
+			var reference generator.Reference
+			err = json.Unmarshal(contents, &reference)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ex.RunOmCommandArgsForCall(0)).To(Equal([]string{"--help"}))
+
+			wheat := "wheat"
+			disabled := "false"
+			Expect(reference).To(Equal(generator.Reference{
+				Name:        "om",
+				Description: "om helps you interact with an Ops Manager",
+				GlobalFlags: []generator.FlagReference{
+					{Long: "env", Short: "e", Type: "string", Description: "env file with login credentials"},
+					{Long: "target", Short: "t", Env: []string{"OM_TARGET"}, Type: "string", Description: "location of the Ops Manager VM"},
+					{Long: "trace", Short: "tr", Env: []string{"OM_TRACE"}, Type: "bool", Default: &disabled, Description: "prints HTTP requests and response payloads"},
+					{Env: []string{"OM_VARS_ENV"}, Type: "string", Experimental: true, Description: "load vars from environment variables"},
+				},
+				Commands: []generator.CommandReference{
+					{
+						Name:             "bake",
+						ShortDescription: "bakes you a cake",
+						Description:      "This command will help you bake a cake.\nIt is a piece of cake.",
+						Flags: []generator.FlagReference{
+							{Long: "butter", Short: "b", Type: "int", Required: true, Variadic: true, Description: "sticks of butter"},
+							{Long: "flour", Aliases: []string{"meal"}, Type: "string", Default: &wheat, Description: "type of flour,\nlike rye or wheat"},
+							{Long: "oven", Env: []string{"OM_OVEN"}, Type: "string", Deprecated: true, Description: "the oven to use"},
+						},
+					},
+					{
+						Name:             "clean",
+						ShortDescription: "cleans up after baking",
+						Description:      "This command cleans up after baking.",
+						Flags:            []generator.FlagReference{},
+					},
+				},
+			}))
+		})
+
+		It("returns an error when om can't return its help", func() {
+			ex.RunOmCommandReturns(nil, errors.New("om help error"))
+
+			err := gen.GenerateJSON(filepath.Join(outputDir, generator.JSONFileName))
+			Expect(err).To(MatchError("om help error"))
+		})
+
+		It("returns an error when om can't return the command help", func() {
+			ex.GetCommandHelpStub = nil
+			ex.GetCommandHelpReturns(nil, errors.New("om command help error"))
+
+			err := gen.GenerateJSON(filepath.Join(outputDir, generator.JSONFileName))
+			Expect(err).To(MatchError("om command help error"))
+		})
+	})
+
+	Describe("GenerateManPages", func() {
+		It("writes a man page for om and for each command", func() {
+			manDir := filepath.Join(outputDir, generator.ManDirName)
+			err := gen.GenerateManPages(manDir)
+			Expect(err).ToNot(HaveOccurred())
+
+			files, err := ioutil.ReadDir(manDir)
+			Expect(err).ToNot(HaveOccurred())
+
+			var names []string
+			for _, file := range files {
+				names = append(names, file.Name())
+			}
+			Expect(names).To(Equal([]string{"om-bake.1", "om-clean.1", "om.1"}))
+
+			om, err := ioutil.ReadFile(filepath.Join(manDir, "om.1"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(om)).To(ContainSubstring(".TH OM 1"))
+			Expect(string(om)).To(ContainSubstring("om \\- om helps you interact with an Ops Manager\n"))
+			Expect(string(om)).To(ContainSubstring(".TP\n\\fB\\-\\-target\\fR, \\fB\\-t\\fR, \\fB$OM_TARGET\\fR \\fIstring\\fR\nlocation of the Ops Manager VM\n"))
+			Expect(string(om)).To(ContainSubstring(".TP\n\\fBom\\-bake\\fR(1)\nbakes you a cake\n"))
+
+			bake, err := ioutil.ReadFile(filepath.Join(manDir, "om-bake.1"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(bake)).To(ContainSubstring(".TH OM\\-BAKE 1"))
+			Expect(string(bake)).To(ContainSubstring(".SH NAME\nom\\-bake \\- bakes you a cake\n"))
+			Expect(string(bake)).To(ContainSubstring(".SH DESCRIPTION\nThis command will help you bake a cake.\n.br\nIt is a piece of cake.\n"))
+			Expect(string(bake)).To(ContainSubstring(".TP\n\\fB\\-\\-butter\\fR, \\fB\\-b\\fR \\fIint\\fR\nsticks of butter\n.br\nRequired. Can be given multiple times.\n"))
+			Expect(string(bake)).To(ContainSubstring(".TP\n\\fB\\-\\-flour\\fR, \\fB\\-\\-meal\\fR \\fIstring\\fR\ntype of flour,\n.br\nlike rye or wheat\n.br\nDefault: wheat.\n"))
+			Expect(string(bake)).To(ContainSubstring(".SH SEE ALSO\n\\fBom\\fR(1)\n"))
+
+			clean, err := ioutil.ReadFile(filepath.Join(manDir, "om-clean.1"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(clean)).ToNot(ContainSubstring(".SH OPTIONS"))
+		})
+	})
+
+	Describe("GenerateHTML", func() {
+		It("writes a single page reference which can be searched", func() {
+			path := filepath.Join(outputDir, generator.HTMLFileName)
+			err := gen.GenerateHTML(path)
+			Expect(err).ToNot(HaveOccurred())
+
+			contents, err := ioutil.ReadFile(path)
+			Expect(err).ToNot(HaveOccurred())
+
+			html := string(contents)
+			Expect(html).To(ContainSubstring(`<input id="search" type="search"`))
+			Expect(html).To(ContainSubstring(`<li data-search="bake bakes you a cake this command will help you bake a cake. it is a piece of cake. --butter -b --flour --meal --oven om_oven"><a href="#bake">bake</a></li>`))
+			Expect(html).To(ContainSubstring(`<section id="clean" data-search="clean cleans up after baking this command cleans up after baking.">`))
+			Expect(html).To(ContainSubstring(`<tr><td>--butter, -b</td><td>int (required) (variadic)</td><td></td><td></td><td class="description">sticks of butter</td></tr>`))
+			Expect(html).To(ContainSubstring(`<tr><td>--oven</td><td>string</td><td>OM_OVEN</td><td></td><td class="description"><strong>DEPRECATED</strong> the oven to use</td></tr>`))
+			Expect(html).To(ContainSubstring(`<tr><td>--target, -t</td><td>string</td><td>OM_TARGET</td><td></td><td class="description">location of the Ops Manager VM</td></tr>`))
+		})
+	})
+})
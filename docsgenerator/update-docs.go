@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"github.com/onsi/gomega/gexec"
 	"github.com/pivotal-cf/om/docsgenerator/executor"
@@ -10,13 +11,17 @@ import (
 )
 
 func main() {
-	homePath, err := os.UserHomeDir()
+	workingDir, err := os.Getwd()
 	if err != nil {
-		fmt.Printf("could not determine user home directory: %s", err)
+		fmt.Printf("could not determine working directory: %s\n", err)
 		os.Exit(1)
 	}
 
-	currentPath := filepath.Join(homePath, "workspace", "om")
+	currentPath, err := findRepository(workingDir)
+	if err != nil {
+		fmt.Printf("could not find the om repository: %s\n", err)
+		os.Exit(1)
+	}
 
 	omPath, err := gexec.Build(filepath.Join(currentPath, "main.go"), "-ldflags", "-X main.applySleepDurationString=1ms -X github.com/pivotal-cf/om/commands.pivnetHost=http://example.com")
 	if err != nil {
@@ -34,4 +39,41 @@ func main() {
 		fmt.Printf("could not generate docs: %s\n", err)
 		os.Exit(1)
 	}
+
+	err = gen.GenerateManPages(filepath.Join(docsDir, generator.ManDirName))
+	if err != nil {
+		fmt.Printf("could not generate man pages: %s\n", err)
+		os.Exit(1)
+	}
+
+	err = gen.GenerateHTML(filepath.Join(docsDir, generator.HTMLFileName))
+	if err != nil {
+		fmt.Printf("could not generate html reference: %s\n", err)
+		os.Exit(1)
+	}
+
+	err = gen.GenerateJSON(filepath.Join(docsDir, generator.JSONFileName))
+	if err != nil {
+		fmt.Printf("could not generate json reference: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// findRepository returns the closest directory containing
+// the main.go and the docsgenerator of om, starting from dir.
+func findRepository(dir string) (string, error) {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "docsgenerator", "templates")); err == nil {
+			if _, err := os.Stat(filepath.Join(dir, "main.go")); err == nil {
+				return dir, nil
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("run update-docs from within the om repository")
+		}
+
+		dir = parent
+	}
 }
@@ -75,7 +75,12 @@ func getCommandNames() []string {
 			continue
 		}
 
-		if isCommand && commandLine != "" {
+		// the plugins found on the PATH are listed after the commands
+		if isCommand && commandLine == "" {
+			break
+		}
+
+		if isCommand {
 			splitCommandLine := strings.Fields(commandLine)
 			commands = append(commands, splitCommandLine[0])
 		}
@@ -1,34 +1,30 @@
 package download_clients
 
 import (
-	"github.com/graymeta/stow"
-	"github.com/graymeta/stow/azure"
 	"github.com/pivotal-cf/om/commands"
-	"gopkg.in/go-playground/validator.v9"
+	"github.com/pivotal-cf/om/stowconfig"
 	"io"
 	"log"
 )
 
 type AzureConfiguration struct {
-	StorageAccount string `validate:"required"`
-	Key            string `validate:"required"`
-	Container      string `validate:"required"`
+	StorageAccount string
+	Key            string
+	Container      string
 	ProductPath    string
 	StemcellPath   string
 }
 
 func NewAzureClient(stower Stower, config AzureConfiguration, progressWriter io.Writer) (stowClient, error) {
-	validate := validator.New()
-	err := validate.Struct(config)
+	stowConfig, err := stowconfig.Azure(stowconfig.AzureConfiguration{
+		StorageAccount: config.StorageAccount,
+		Key:            config.Key,
+		Container:      config.Container,
+	})
 	if err != nil {
 		return stowClient{}, err
 	}
 
-	stowConfig := stow.ConfigMap{
-		azure.ConfigAccount: config.StorageAccount,
-		azure.ConfigKey:     config.Key,
-	}
-
 	return NewStowClient(
 		stower,
 		config.Container,
@@ -1,36 +1,31 @@
 package download_clients
 
 import (
-	"github.com/graymeta/stow"
-	"github.com/graymeta/stow/google"
 	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/stowconfig"
 	storage "google.golang.org/api/storage/v1beta2"
-	"gopkg.in/go-playground/validator.v9"
 	"io"
 	"log"
 )
 
 type GCSConfiguration struct {
-	Bucket             string `validate:"required"`
-	ServiceAccountJSON string `validate:"required"`
-	ProjectID          string `validate:"required"`
+	Bucket             string
+	ServiceAccountJSON string
+	ProjectID          string
 	ProductPath        string
 	StemcellPath       string
 }
 
 func NewGCSClient(stower Stower, config GCSConfiguration, progressWriter io.Writer) (stowClient, error) {
-	validate := validator.New()
-	err := validate.Struct(config)
+	stowConfig, err := stowconfig.GCS(stowconfig.GCSConfiguration{
+		Bucket:             config.Bucket,
+		ServiceAccountJSON: config.ServiceAccountJSON,
+		ProjectID:          config.ProjectID,
+	}, storage.DevstorageReadOnlyScope)
 	if err != nil {
 		return stowClient{}, err
 	}
 
-	stowConfig := stow.ConfigMap{
-		google.ConfigJSON:      config.ServiceAccountJSON,
-		google.ConfigProjectId: config.ProjectID,
-		google.ConfigScopes:    storage.DevstorageReadOnlyScope,
-	}
-
 	return NewStowClient(
 		stower,
 		config.Bucket,
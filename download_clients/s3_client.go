@@ -1,21 +1,17 @@
 package download_clients
 
 import (
-	"fmt"
-	"github.com/graymeta/stow"
-	"github.com/graymeta/stow/s3"
 	"github.com/pivotal-cf/om/commands"
-	"gopkg.in/go-playground/validator.v9"
+	"github.com/pivotal-cf/om/stowconfig"
 	"io"
 	"log"
-	"strconv"
 )
 
 type S3Configuration struct {
-	Bucket          string `validate:"required"`
+	Bucket          string
 	AccessKeyID     string
 	SecretAccessKey string
-	RegionName      string `validate:"required"`
+	RegionName      string
 	Endpoint        string
 	DisableSSL      bool
 	EnableV2Signing bool
@@ -25,33 +21,20 @@ type S3Configuration struct {
 }
 
 func NewS3Client(stower Stower, config S3Configuration, progressWriter io.Writer) (stowClient, error) {
-	validate := validator.New()
-	err := validate.Struct(config)
+	stowConfig, err := stowconfig.S3(stowconfig.S3Configuration{
+		Bucket:          config.Bucket,
+		AccessKeyID:     config.AccessKeyID,
+		SecretAccessKey: config.SecretAccessKey,
+		RegionName:      config.RegionName,
+		Endpoint:        config.Endpoint,
+		DisableSSL:      config.DisableSSL,
+		EnableV2Signing: config.EnableV2Signing,
+		AuthType:        config.AuthType,
+	})
 	if err != nil {
 		return stowClient{}, err
 	}
 
-	disableSSL := strconv.FormatBool(config.DisableSSL)
-	enableV2Signing := strconv.FormatBool(config.EnableV2Signing)
-	if config.AuthType == "" {
-		config.AuthType = "accesskey"
-	}
-
-	err = validateAccessKeyAuthType(config)
-	if err != nil {
-		return stowClient{}, err
-	}
-
-	stowConfig := stow.ConfigMap{
-		s3.ConfigAccessKeyID: config.AccessKeyID,
-		s3.ConfigSecretKey:   config.SecretAccessKey,
-		s3.ConfigRegion:      config.RegionName,
-		s3.ConfigEndpoint:    config.Endpoint,
-		s3.ConfigDisableSSL:  disableSSL,
-		s3.ConfigV2Signing:   enableV2Signing,
-		s3.ConfigAuthType:    config.AuthType,
-	}
-
 	return NewStowClient(
 		stower,
 		config.Bucket,
@@ -63,18 +46,6 @@ func NewS3Client(stower Stower, config S3Configuration, progressWriter io.Writer
 	), nil
 }
 
-func validateAccessKeyAuthType(config S3Configuration) error {
-	if config.AuthType == "iam" {
-		return nil
-	}
-
-	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
-		return fmt.Errorf("the flags \"s3-access-key-id\" and \"s3-secret-access-key\" are required when the \"auth-type\" is \"accesskey\"")
-	}
-
-	return nil
-}
-
 func init() {
 	initializer := func(
 		c commands.DownloadProductOptions,
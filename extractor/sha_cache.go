@@ -0,0 +1,127 @@
+package extractor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ShaCache records the sha256 and metadata of product files that have
+// already been processed, so a file that has not changed on disk
+// does not need to be read again.
+type ShaCache struct {
+	path string
+}
+
+type CachedProduct struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	SHA256  string    `json:"sha256"`
+	Name    string    `json:"name"`
+	Version string    `json:"product_version"`
+}
+
+func NewShaCache(path string) ShaCache {
+	return ShaCache{path: path}
+}
+
+func (c ShaCache) Lookup(productPath string) (CachedProduct, bool, error) {
+	absPath, info, err := statProduct(productPath)
+	if err != nil {
+		return CachedProduct{}, false, err
+	}
+
+	entries, err := c.load()
+	if err != nil {
+		return CachedProduct{}, false, err
+	}
+
+	for _, entry := range entries {
+		if entry.Path == absPath && entry.Size == info.Size() && entry.ModTime.Equal(info.ModTime()) {
+			return entry, true, nil
+		}
+	}
+
+	return CachedProduct{}, false, nil
+}
+
+func (c ShaCache) Record(productPath string, sha256 string, metadata Metadata) (CachedProduct, error) {
+	absPath, info, err := statProduct(productPath)
+	if err != nil {
+		return CachedProduct{}, err
+	}
+
+	entries, err := c.load()
+	if err != nil {
+		return CachedProduct{}, err
+	}
+
+	entry := CachedProduct{
+		Path:    absPath,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+		SHA256:  sha256,
+		Name:    metadata.Name,
+		Version: metadata.Version,
+	}
+
+	var updated []CachedProduct
+	for _, existing := range entries {
+		if existing.Path != absPath {
+			updated = append(updated, existing)
+		}
+	}
+	updated = append(updated, entry)
+
+	contents, err := json.MarshalIndent(updated, "", "  ")
+	if err != nil {
+		return CachedProduct{}, err
+	}
+
+	err = ioutil.WriteFile(c.path, contents, 0644)
+	if err != nil {
+		return CachedProduct{}, fmt.Errorf("could not write sha cache %s: %s", c.path, err)
+	}
+
+	return entry, nil
+}
+
+func (c ShaCache) load() ([]CachedProduct, error) {
+	contents, err := ioutil.ReadFile(c.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("could not read sha cache %s: %s", c.path, err)
+	}
+
+	if len(contents) == 0 {
+		return nil, nil
+	}
+
+	var entries []CachedProduct
+	err = json.Unmarshal(contents, &entries)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse sha cache %s: %s", c.path, err)
+	}
+
+	return entries, nil
+}
+
+func statProduct(productPath string) (string, os.FileInfo, error) {
+	absPath, err := filepath.Abs(productPath)
+	if err != nil {
+		return "", nil, err
+	}
+
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return absPath, info, nil
+}
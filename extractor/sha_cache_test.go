@@ -0,0 +1,112 @@
+package extractor_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pivotal-cf/om/extractor"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ShaCache", func() {
+	var (
+		cacheDir    string
+		productFile *os.File
+		cache       extractor.ShaCache
+	)
+
+	BeforeEach(func() {
+		var err error
+		cacheDir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		productFile = createProductFile("metadata/some-product.yml", validYAML)
+		cache = extractor.NewShaCache(filepath.Join(cacheDir, "cache.json"))
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(cacheDir)
+		os.Remove(productFile.Name())
+	})
+
+	It("returns nothing when the cache file does not exist", func() {
+		_, found, err := cache.Lookup(productFile.Name())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeFalse())
+	})
+
+	It("returns a recorded product that has not changed on disk", func() {
+		_, err := cache.Record(productFile.Name(), "some-sha", extractor.Metadata{Name: "some-product", Version: "1.8.14"})
+		Expect(err).ToNot(HaveOccurred())
+
+		entry, found, err := cache.Lookup(productFile.Name())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(entry.SHA256).To(Equal("some-sha"))
+		Expect(entry.Name).To(Equal("some-product"))
+		Expect(entry.Version).To(Equal("1.8.14"))
+	})
+
+	It("does not return a product that has changed on disk", func() {
+		_, err := cache.Record(productFile.Name(), "some-sha", extractor.Metadata{Name: "some-product", Version: "1.8.14"})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = productFile.WriteString("more content")
+		Expect(err).ToNot(HaveOccurred())
+
+		_, found, err := cache.Lookup(productFile.Name())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeFalse())
+	})
+
+	It("replaces the previous entry for the same product", func() {
+		_, err := cache.Record(productFile.Name(), "some-sha", extractor.Metadata{Name: "some-product", Version: "1.8.14"})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = cache.Record(productFile.Name(), "other-sha", extractor.Metadata{Name: "some-product", Version: "1.8.15"})
+		Expect(err).ToNot(HaveOccurred())
+
+		entry, found, err := cache.Lookup(productFile.Name())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(entry.SHA256).To(Equal("other-sha"))
+
+		contents, err := ioutil.ReadFile(filepath.Join(cacheDir, "cache.json"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(contents)).ToNot(ContainSubstring("some-sha"))
+	})
+
+	It("keeps the entries of the copies of a product", func() {
+		copyFile := createProductFile("metadata/some-product.yml", validYAML)
+		defer os.Remove(copyFile.Name())
+
+		_, err := cache.Record(productFile.Name(), "some-sha", extractor.Metadata{Name: "some-product", Version: "1.8.14"})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = cache.Record(copyFile.Name(), "some-sha", extractor.Metadata{Name: "some-product", Version: "1.8.14"})
+		Expect(err).ToNot(HaveOccurred())
+
+		for _, file := range []*os.File{productFile, copyFile} {
+			entry, found, err := cache.Lookup(file.Name())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(entry.SHA256).To(Equal("some-sha"))
+		}
+	})
+
+	When("an error occurs", func() {
+		It("returns an error when the product does not exist", func() {
+			_, _, err := cache.Lookup("fake-file")
+			Expect(err).To(MatchError(ContainSubstring("no such file or directory")))
+		})
+
+		It("returns an error when the cache file is not valid", func() {
+			err := ioutil.WriteFile(filepath.Join(cacheDir, "cache.json"), []byte("not-json"), 0644)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, _, err = cache.Lookup(productFile.Name())
+			Expect(err).To(MatchError(ContainSubstring("could not parse sha cache")))
+		})
+	})
+})
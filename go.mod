@@ -2,6 +2,7 @@ module github.com/pivotal-cf/om
 
 require (
 	cloud.google.com/go v0.38.0
+	filippo.io/age v1.0.0
 	github.com/Azure/go-autorest/autorest/to v0.3.0 // indirect
 	github.com/StackExchange/wmi v0.0.0-20190523213315-cbe66965904d // indirect
 	github.com/aws/aws-sdk-go v1.23.19 // indirect
@@ -36,11 +37,9 @@ require (
 	github.com/pivotal/uilive v0.0.0-20181204013807-921d4ab784bd
 	github.com/pkg/errors v0.8.1
 	github.com/shirou/gopsutil v2.18.12+incompatible // indirect
-	golang.org/x/crypto v0.0.0-20191117063200-497ca9f6d64f // indirect
-	golang.org/x/net v0.0.0-20191118183410-d06c31c94cae // indirect
+	golang.org/x/crypto v0.0.0-20210817164053-32db794688a5
 	golang.org/x/oauth2 v0.0.0-20190604053449-0f29369cfe45
 	golang.org/x/sync v0.0.0-20190911185100-cd5d95a43a6e // indirect
-	golang.org/x/sys v0.0.0-20191118133127-cf1e2d577169 // indirect
 	golang.org/x/tools v0.0.0-20191118211312-13f8be5f577f // indirect
 	golang.org/x/xerrors v0.0.0-20191011141410-1b5146add898 // indirect
 	google.golang.org/api v0.8.0
@@ -0,0 +1,174 @@
+package interpolate
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/cloudfoundry/bosh-cli/director/template"
+)
+
+// credhubSource reads the current value of credentials from the CredHub data API.
+// Each variable is the credential <path>/<name>. It authenticates with the
+// token in CREDHUB_TOKEN, or requests one from CredHub's UAA with the
+// client credentials in CREDHUB_CLIENT and CREDHUB_SECRET.
+type credhubSource struct {
+	client       *http.Client
+	address      string
+	path         string
+	clientID     string
+	clientSecret string
+
+	tokenOnce sync.Once
+	token     string
+	tokenErr  error
+}
+
+func newCredHubSource(params map[string]string, env map[string]string) (*credhubSource, error) {
+	if params["address"] == "" {
+		params["address"] = env["CREDHUB_SERVER"]
+	}
+
+	err := requireParams("credhub", params, []string{"address", "path", "skip-ssl-validation", "ca-cert"}, "address")
+	if err != nil {
+		return nil, err
+	}
+
+	source := &credhubSource{
+		address:      strings.TrimSuffix(params["address"], "/"),
+		path:         strings.TrimSuffix(params["path"], "/"),
+		token:        env["CREDHUB_TOKEN"],
+		clientID:     env["CREDHUB_CLIENT"],
+		clientSecret: env["CREDHUB_SECRET"],
+	}
+
+	if source.token == "" && (source.clientID == "" || source.clientSecret == "") {
+		return nil, fmt.Errorf("invalid credhub vars source: CREDHUB_TOKEN or CREDHUB_CLIENT and CREDHUB_SECRET must be set in the environment")
+	}
+
+	source.client, err = newHTTPClient(params)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credhub vars source: %s", err)
+	}
+
+	if source.token != "" {
+		source.tokenOnce.Do(func() {})
+	}
+
+	return source, nil
+}
+
+func (c *credhubSource) Get(definition template.VariableDefinition) (interface{}, bool, error) {
+	c.tokenOnce.Do(func() {
+		c.token, c.tokenErr = c.requestToken()
+	})
+	if c.tokenErr != nil {
+		return nil, false, c.tokenErr
+	}
+
+	name := definition.Name
+	if !strings.HasPrefix(name, "/") {
+		name = c.path + "/" + name
+		if !strings.HasPrefix(name, "/") {
+			name = "/" + name
+		}
+	}
+
+	query := url.Values{}
+	query.Set("name", name)
+	query.Set("current", "true")
+
+	request, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v1/data?%s", c.address, query.Encode()), nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not create request to credhub: %s", err)
+	}
+	request.Header.Set("Authorization", "Bearer "+c.token)
+
+	response, err := c.client.Do(request)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not reach credhub at %s: %s", c.address, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("could not read %s from credhub: unexpected response %s", name, response.Status)
+	}
+
+	var credentials struct {
+		Data []struct {
+			Value interface{} `json:"value"`
+		} `json:"data"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&credentials)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not decode %s from credhub: %s", name, err)
+	}
+
+	if len(credentials.Data) == 0 {
+		return nil, false, nil
+	}
+
+	converted, err := fromJSON(credentials.Data[0].Value)
+	return converted, true, err
+}
+
+func (c *credhubSource) List() ([]template.VariableDefinition, error) {
+	return nil, nil
+}
+
+func (c *credhubSource) requestToken() (string, error) {
+	response, err := c.client.Get(c.address + "/info")
+	if err != nil {
+		return "", fmt.Errorf("could not reach credhub at %s: %s", c.address, err)
+	}
+	defer response.Body.Close()
+
+	var info struct {
+		AuthServer struct {
+			URL string `json:"url"`
+		} `json:"auth-server"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&info)
+	if err != nil || info.AuthServer.URL == "" {
+		return "", fmt.Errorf("could not determine the credhub auth server from %s/info", c.address)
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("response_type", "token")
+
+	request, err := http.NewRequest("POST", strings.TrimSuffix(info.AuthServer.URL, "/")+"/oauth/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("could not create token request: %s", err)
+	}
+	request.SetBasicAuth(c.clientID, c.clientSecret)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+
+	tokenResponse, err := c.client.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("could not reach the credhub auth server at %s: %s", info.AuthServer.URL, err)
+	}
+	defer tokenResponse.Body.Close()
+
+	if tokenResponse.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("could not authenticate with credhub as %s: unexpected response %s", c.clientID, tokenResponse.Status)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	err = json.NewDecoder(tokenResponse.Body).Decode(&token)
+	if err != nil || token.AccessToken == "" {
+		return "", fmt.Errorf("could not authenticate with credhub as %s: no access token returned", c.clientID)
+	}
+
+	return token.AccessToken, nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/cloudfoundry/bosh-cli/director/template"
 	"github.com/cppforlife/go-patch/patch"
+	"github.com/pivotal-cf/om/models"
 	"gopkg.in/yaml.v2"
 	"io/ioutil"
 	"regexp"
@@ -19,27 +20,87 @@ type Options struct {
 	VarsFiles     []string
 	Vars          []string
 	OpsFiles      []string
+	VarsSources   []string
+	VarsStore     string
 	EnvironFunc   func() []string
 	ExpectAllKeys bool
 	Path          string
+
+	// ExpectAllVarsUsed fails when a provided variable is not used,
+	// reporting all the missing and unused variables at once.
+	ExpectAllVarsUsed bool
 }
 
 func Execute(o Options) ([]byte, error) {
+	if !o.ExpectAllVarsUsed {
+		contents, _, store, err := evaluate(o, o.ExpectAllKeys)
+		if err != nil {
+			return nil, err
+		}
+
+		return contents, store.save()
+	}
+
+	// in strict mode every missing and unused variable is reported at once,
+	// instead of failing on the first evaluation error
+	contents, report, store, err := evaluate(o, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if !o.ExpectAllKeys {
+		report.Missing = nil
+	}
+
+	err = strictError(report)
+	if err != nil {
+		return nil, err
+	}
+
+	return contents, store.save()
+}
+
+// Check interpolates the template like Execute,
+// but reports how the variables have been resolved
+// instead of failing on missing variables.
+// The variables of the vars store are reported as generated, but are not saved.
+func Check(o Options) (models.InterpolationReport, error) {
+	_, report, store, err := evaluate(o, false)
+	if err != nil {
+		return models.InterpolationReport{}, err
+	}
+
+	if store != nil {
+		report.Generated = store.generated
+	}
+
+	return report, nil
+}
+
+// evaluate interpolates the template, returning the vars store, if any,
+// whose generated variables have not been saved yet.
+func evaluate(o Options, expectAllKeys bool) ([]byte, models.InterpolationReport, *varsStore, error) {
 	contents, err := ioutil.ReadFile(o.TemplateFile)
 	if err != nil {
-		return nil, fmt.Errorf("could not read file (%s): %s", o.TemplateFile, err.Error())
+		return nil, models.InterpolationReport{}, nil, fmt.Errorf("could not read file (%s): %s", o.TemplateFile, err.Error())
 	}
 
 	tpl := template.NewTemplate(contents)
 	staticVars := template.StaticVariables{}
 	ops := patch.Ops{}
 
+	// layers keeps where the static variables come from,
+	// in the order they override each other
+	var layers []varsLayer
+
 	for _, varsEnv := range o.VarsEnvs {
+		envVars := template.StaticVariables{}
+
 		for _, envVar := range o.EnvironFunc() {
 
 			pieces := strings.SplitN(envVar, "=", 2)
 			if len(pieces) != 2 {
-				return []byte{}, errors.New("Expected environment variable to be key-value pair")
+				return []byte{}, models.InterpolationReport{}, nil, errors.New("Expected environment variable to be key-value pair")
 			}
 
 			if !strings.HasPrefix(pieces[0], varsEnv+"_") {
@@ -50,7 +111,7 @@ func Execute(o Options) ([]byte, error) {
 			var val interface{}
 			err = yaml.Unmarshal([]byte(v), &val)
 			if err != nil {
-				return []byte{}, fmt.Errorf("Could not deserialize YAML from environment variable %q", pieces[0])
+				return []byte{}, models.InterpolationReport{}, nil, fmt.Errorf("Could not deserialize YAML from environment variable %q", pieces[0])
 			}
 
 			// The environment variable value is treated as YAML, but multi-line strings
@@ -69,60 +130,126 @@ func Execute(o Options) ([]byte, error) {
 
 				err = yaml.Unmarshal(b, &val)
 				if err != nil {
-					return []byte{}, fmt.Errorf("Could not deserialize string from environment variable %q", pieces[0])
+					return []byte{}, models.InterpolationReport{}, nil, fmt.Errorf("Could not deserialize string from environment variable %q", pieces[0])
 				}
 			}
 
-			staticVars[strings.TrimPrefix(pieces[0], varsEnv+"_")] = val
+			envVars[strings.TrimPrefix(pieces[0], varsEnv+"_")] = val
+		}
+
+		for k, v := range envVars {
+			staticVars[k] = v
 		}
+		layers = append(layers, varsLayer{source: "--vars-env " + varsEnv, vars: envVars})
 	}
 
 	for _, path := range o.VarsFiles {
-		var fileVars template.StaticVariables
-		err = readYAMLFile(path, &fileVars)
+		fileVars, err := readVarsFile(path, o.EnvironFunc)
 		if err != nil {
-			return nil, err
+			return nil, models.InterpolationReport{}, nil, err
 		}
 		for k, v := range fileVars {
 			staticVars[k] = v
 		}
+		layers = append(layers, varsLayer{source: "--vars-file " + path, vars: fileVars})
+	}
+
+	if len(o.Vars) > 0 {
+		cmdVars := template.StaticVariables{}
+		readCommandLineVars(o.Vars, cmdVars)
+		for k, v := range cmdVars {
+			staticVars[k] = v
+		}
+		layers = append(layers, varsLayer{source: "--var", vars: cmdVars})
 	}
 
-	readCommandLineVars(o.Vars, staticVars)
+	// variables provided directly always take precedence over the vars sources,
+	// which are asked in order for the variables that are still missing
+	vars := []template.Variables{staticVars}
+	sources := []string{""}
+	for _, spec := range o.VarsSources {
+		source, err := NewVarsSource(spec, o.EnvironFunc)
+		if err != nil {
+			return nil, models.InterpolationReport{}, nil, err
+		}
+		vars = append(vars, source)
+		sources = append(sources, "--vars-source "+spec)
+	}
+
+	// the vars store is asked last, so that only the variables
+	// that are not provided anywhere else are generated
+	var store *varsStore
+	if o.VarsStore != "" {
+		store, err = newVarsStore(o.VarsStore)
+		if err != nil {
+			return nil, models.InterpolationReport{}, nil, err
+		}
+		vars = append(vars, store)
+		sources = append(sources, "--vars-store "+o.VarsStore)
+		store.vars = template.NewMultiVars(vars)
+
+		stored := template.StaticVariables{}
+		for k, v := range store.values {
+			stored[k] = v
+		}
+		layers = append([]varsLayer{{source: "--vars-store " + o.VarsStore, vars: stored}}, layers...)
+	}
 
 	for _, path := range o.OpsFiles {
 		var opDefs []patch.OpDefinition
 		err = readYAMLFile(path, &opDefs)
 		if err != nil {
-			return nil, err
+			return nil, models.InterpolationReport{}, nil, err
 		}
 		op, err := patch.NewOpsFromDefinitions(opDefs)
 		if err != nil {
-			return nil, fmt.Errorf("Building ops (%s)", err.Error())
+			return nil, models.InterpolationReport{}, nil, fmt.Errorf("Building ops (%s)", err.Error())
 		}
 		ops = append(ops, op)
 	}
 
 	evalOpts := template.EvaluateOpts{
 		UnescapedMultiline: true,
-		ExpectAllKeys:      o.ExpectAllKeys,
+		ExpectAllKeys:      expectAllKeys,
 	}
 
 	path, err := patch.NewPointerFromString(o.Path)
 	if err != nil {
-		return nil, fmt.Errorf("cannot parse path: %s", err)
+		return nil, models.InterpolationReport{}, nil, fmt.Errorf("cannot parse path: %s", err)
 	}
 
+	postOps := patch.Ops{removeEmptyVariablesOp{}}
 	if path.IsSet() {
-		evalOpts.PostVarSubstitutionOp = patch.FindOp{Path: path}
+		postOps = append(postOps, patch.FindOp{Path: path})
 	}
+	evalOpts.PostVarSubstitutionOp = postOps
+
+	recorder := newRecordingVars(vars, sources)
 
-	bytes, err := tpl.Evaluate(staticVars, ops, evalOpts)
+	bytes, err := tpl.Evaluate(recorder, ops, evalOpts)
 	if err != nil {
-		return nil, err
+		return nil, models.InterpolationReport{}, nil, err
+	}
+
+	return bytes, recorder.report(layers), store, nil
+}
+
+// removeEmptyVariablesOp removes the `variables:` section
+// once all of its definitions have been found or generated,
+// so that it is not passed on as configuration.
+type removeEmptyVariablesOp struct{}
+
+func (removeEmptyVariablesOp) Apply(doc interface{}) (interface{}, error) {
+	document, ok := doc.(map[interface{}]interface{})
+	if !ok {
+		return doc, nil
 	}
 
-	return bytes, nil
+	if variables, ok := document["variables"].([]interface{}); ok && len(variables) == 0 {
+		delete(document, "variables")
+	}
+
+	return document, nil
 }
 
 func readCommandLineVars(vars []string, staticVars template.StaticVariables) {
@@ -145,6 +272,29 @@ func readCommandLineVars(vars []string, staticVars template.StaticVariables) {
 	}
 }
 
+// readVarsFile reads a vars file, decrypting it in memory if it has been encrypted with SOPS.
+func readVarsFile(path string, environFunc func() []string) (template.StaticVariables, error) {
+	payload, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read file (%s): %s", path, err.Error())
+	}
+
+	if IsEncrypted(payload) {
+		payload, err = DecryptVars(payload, environFunc)
+		if err != nil {
+			return nil, fmt.Errorf("could not decrypt file (%s): %s", path, err)
+		}
+	}
+
+	var vars template.StaticVariables
+	err = yaml.Unmarshal(payload, &vars)
+	if err != nil {
+		return nil, fmt.Errorf("could not unmarshal file (%s): %s", path, err.Error())
+	}
+
+	return vars, nil
+}
+
 func readYAMLFile(path string, dataType interface{}) error {
 	payload, err := ioutil.ReadFile(path)
 	if err != nil {
@@ -0,0 +1,273 @@
+package interpolate
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/bosh-cli/director/template"
+	"golang.org/x/crypto/ssh"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	passwordCharacters    = "abcdefghijklmnopqrstuvwxyz0123456789"
+	defaultPasswordLength = 20
+	defaultKeyBits        = 2048
+	defaultCertDays       = 365
+)
+
+// generator creates the value for a variable definition,
+// following the types and options of BOSH's `variables:` section.
+type generator func(definition template.VariableDefinition, vars template.Variables) (interface{}, error)
+
+var generators = map[string]generator{
+	"password":    generatePassword,
+	"certificate": generateCertificate,
+	"rsa":         generateRSA,
+	"ssh":         generateSSH,
+}
+
+func generatePassword(definition template.VariableDefinition, _ template.Variables) (interface{}, error) {
+	var options struct {
+		Length int `yaml:"length"`
+	}
+	err := decodeOptions(definition, &options)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Length <= 0 {
+		options.Length = defaultPasswordLength
+	}
+
+	max := big.NewInt(int64(len(passwordCharacters)))
+	password := make([]byte, options.Length)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return nil, fmt.Errorf("could not generate password: %s", err)
+		}
+		password[i] = passwordCharacters[n.Int64()]
+	}
+
+	return string(password), nil
+}
+
+func generateRSA(_ template.VariableDefinition, _ template.Variables) (interface{}, error) {
+	key, err := rsa.GenerateKey(rand.Reader, defaultKeyBits)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate rsa key: %s", err)
+	}
+
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode rsa public key: %s", err)
+	}
+
+	return map[interface{}]interface{}{
+		"private_key": encodePEM("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key)),
+		"public_key":  encodePEM("PUBLIC KEY", publicKey),
+	}, nil
+}
+
+func generateSSH(_ template.VariableDefinition, _ template.Variables) (interface{}, error) {
+	key, err := rsa.GenerateKey(rand.Reader, defaultKeyBits)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate ssh key: %s", err)
+	}
+
+	publicKey, err := ssh.NewPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode ssh public key: %s", err)
+	}
+
+	return map[interface{}]interface{}{
+		"private_key":            encodePEM("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key)),
+		"public_key":             strings.TrimSpace(string(ssh.MarshalAuthorizedKey(publicKey))),
+		"public_key_fingerprint": ssh.FingerprintLegacyMD5(publicKey),
+	}, nil
+}
+
+func generateCertificate(definition template.VariableDefinition, vars template.Variables) (interface{}, error) {
+	var options struct {
+		CA               string   `yaml:"ca"`
+		CommonName       string   `yaml:"common_name"`
+		Organization     string   `yaml:"organization"`
+		AlternativeNames []string `yaml:"alternative_names"`
+		IsCA             bool     `yaml:"is_ca"`
+		ExtendedKeyUsage []string `yaml:"extended_key_usage"`
+		Duration         int      `yaml:"duration"`
+	}
+	err := decodeOptions(definition, &options)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.CA == "" && !options.IsCA {
+		return nil, fmt.Errorf("certificate %q must either reference a ca or be a ca (is_ca: true)", definition.Name)
+	}
+
+	if options.Duration <= 0 {
+		options.Duration = defaultCertDays
+	}
+
+	if options.Organization == "" {
+		options.Organization = "Cloud Foundry"
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, defaultKeyBits)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate certificate key: %s", err)
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("could not generate certificate serial number: %s", err)
+	}
+
+	notBefore := time.Now().Add(-time.Minute)
+	certificate := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Country:      []string{"USA"},
+			Organization: []string{options.Organization},
+			CommonName:   options.CommonName,
+		},
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.AddDate(0, 0, options.Duration),
+		BasicConstraintsValid: true,
+		IsCA:                  options.IsCA,
+		SubjectKeyId:          subjectKeyID(&key.PublicKey),
+	}
+
+	if options.IsCA {
+		certificate.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	} else {
+		certificate.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
+	}
+
+	for _, name := range options.AlternativeNames {
+		if ip := net.ParseIP(name); ip != nil {
+			certificate.IPAddresses = append(certificate.IPAddresses, ip)
+		} else {
+			certificate.DNSNames = append(certificate.DNSNames, name)
+		}
+	}
+
+	for _, usage := range options.ExtendedKeyUsage {
+		switch usage {
+		case "server_auth":
+			certificate.ExtKeyUsage = append(certificate.ExtKeyUsage, x509.ExtKeyUsageServerAuth)
+		case "client_auth":
+			certificate.ExtKeyUsage = append(certificate.ExtKeyUsage, x509.ExtKeyUsageClientAuth)
+		default:
+			return nil, fmt.Errorf("certificate %q has an unsupported extended_key_usage %q, expected server_auth or client_auth", definition.Name, usage)
+		}
+	}
+
+	parent, parentKey := certificate, key
+	var caPEM string
+	if options.CA != "" {
+		caPEM, parent, parentKey, err = loadCA(options.CA, vars)
+		if err != nil {
+			return nil, fmt.Errorf("certificate %q: %s", definition.Name, err)
+		}
+		certificate.AuthorityKeyId = parent.SubjectKeyId
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, certificate, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate certificate %q: %s", definition.Name, err)
+	}
+
+	certificatePEM := encodePEM("CERTIFICATE", der)
+	if caPEM == "" {
+		caPEM = certificatePEM
+	}
+
+	return map[interface{}]interface{}{
+		"ca":          caPEM,
+		"certificate": certificatePEM,
+		"private_key": encodePEM("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key)),
+	}, nil
+}
+
+// loadCA looks up the certificate and private key of the ca
+// a certificate references, which can be generated or provided.
+func loadCA(name string, vars template.Variables) (string, *x509.Certificate, *rsa.PrivateKey, error) {
+	value, found, err := vars.Get(template.VariableDefinition{Name: name})
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if !found {
+		return "", nil, nil, fmt.Errorf("could not find ca %q, it must be defined before the certificates that reference it", name)
+	}
+
+	var ca struct {
+		Certificate string `yaml:"certificate"`
+		PrivateKey  string `yaml:"private_key"`
+	}
+	contents, err := yaml.Marshal(value)
+	if err == nil {
+		err = yaml.Unmarshal(contents, &ca)
+	}
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("could not read ca %q: %s", name, err)
+	}
+
+	certificateBlock, _ := pem.Decode([]byte(ca.Certificate))
+	if certificateBlock == nil {
+		return "", nil, nil, fmt.Errorf("ca %q does not contain a PEM encoded certificate", name)
+	}
+
+	certificate, err := x509.ParseCertificate(certificateBlock.Bytes)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("could not parse the certificate of ca %q: %s", name, err)
+	}
+
+	keyBlock, _ := pem.Decode([]byte(ca.PrivateKey))
+	if keyBlock == nil {
+		return "", nil, nil, fmt.Errorf("ca %q does not contain a PEM encoded private key", name)
+	}
+
+	key, err := x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("could not parse the private key of ca %q: %s", name, err)
+	}
+
+	return ca.Certificate, certificate, key, nil
+}
+
+func decodeOptions(definition template.VariableDefinition, options interface{}) error {
+	if definition.Options == nil {
+		return nil
+	}
+
+	contents, err := yaml.Marshal(definition.Options)
+	if err == nil {
+		err = yaml.UnmarshalStrict(contents, options)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid options for %s %q: %s", definition.Type, definition.Name, err)
+	}
+
+	return nil
+}
+
+func subjectKeyID(key *rsa.PublicKey) []byte {
+	hash := sha1.Sum(x509.MarshalPKCS1PublicKey(key))
+	return hash[:]
+}
+
+func encodePEM(blockType string, der []byte) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}))
+}
@@ -0,0 +1,133 @@
+package interpolate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-cli/director/template"
+	"github.com/pivotal-cf/om/models"
+)
+
+// strictError lists the missing and the unused variables of the report.
+func strictError(r models.InterpolationReport) error {
+	if !r.HasProblems() {
+		return nil
+	}
+
+	var problems []string
+	for _, name := range r.Missing {
+		problems = append(problems, fmt.Sprintf("- missing ((%s))", name))
+	}
+	for _, unused := range r.Unused {
+		for _, name := range unused.Names {
+			problems = append(problems, fmt.Sprintf("- unused %q from %s", name, unused.Source))
+		}
+	}
+
+	return fmt.Errorf("the variables are not strictly used:\n%s", strings.Join(problems, "\n"))
+}
+
+// varsLayer are the static variables from one source,
+// such as a vars file or the --var flags.
+type varsLayer struct {
+	source string
+	vars   template.StaticVariables
+}
+
+// recordingVars asks the variables in order like template.MultiVars,
+// and records which one has resolved each variable.
+type recordingVars struct {
+	vars     []template.Variables
+	sources  []string
+	resolved map[string]string
+	missing  map[string]struct{}
+}
+
+func newRecordingVars(vars []template.Variables, sources []string) recordingVars {
+	return recordingVars{
+		vars:     vars,
+		sources:  sources,
+		resolved: map[string]string{},
+		missing:  map[string]struct{}{},
+	}
+}
+
+func (r recordingVars) Get(definition template.VariableDefinition) (interface{}, bool, error) {
+	for i, vars := range r.vars {
+		value, found, err := vars.Get(definition)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if found {
+			r.resolved[definition.Name] = r.sources[i]
+			return value, true, nil
+		}
+	}
+
+	r.missing[definition.Name] = struct{}{}
+	return nil, false, nil
+}
+
+func (r recordingVars) List() ([]template.VariableDefinition, error) {
+	return template.NewMultiVars(r.vars).List()
+}
+
+// report compares the variables the template has asked for
+// with the static variables that have been provided.
+// The layers are in the order in which they override each other.
+func (r recordingVars) report(layers []varsLayer) models.InterpolationReport {
+	report := models.InterpolationReport{
+		Missing:    []string{},
+		Unused:     []models.UnusedVariables{},
+		Overridden: []models.OverriddenVariable{},
+	}
+
+	for name := range r.missing {
+		if _, ok := r.resolved[name]; !ok {
+			report.Missing = append(report.Missing, name)
+		}
+	}
+	sort.Strings(report.Missing)
+
+	providedBy := map[string][]string{}
+
+	for _, layer := range layers {
+		definitions, _ := layer.vars.List()
+
+		var unused []string
+		for _, definition := range definitions {
+			if _, ok := r.resolved[definition.Name]; !ok {
+				unused = append(unused, definition.Name)
+				continue
+			}
+			providedBy[definition.Name] = append([]string{layer.source}, providedBy[definition.Name]...)
+		}
+
+		if len(unused) > 0 {
+			sort.Strings(unused)
+			report.Unused = append(report.Unused, models.UnusedVariables{
+				Source: layer.source,
+				Names:  unused,
+			})
+		}
+	}
+
+	for name, sources := range providedBy {
+		if len(sources) < 2 {
+			continue
+		}
+
+		report.Overridden = append(report.Overridden, models.OverriddenVariable{
+			Name:       name,
+			Source:     sources[0],
+			Overridden: sources[1:],
+		})
+	}
+	sort.Slice(report.Overridden, func(i, j int) bool {
+		return report.Overridden[i].Name < report.Overridden[j].Name
+	})
+
+	return report
+}
@@ -0,0 +1,131 @@
+package interpolate_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/om/interpolate"
+	"github.com/pivotal-cf/om/models"
+)
+
+var _ = Describe("Check", func() {
+	It("reports every missing variable", func() {
+		report, err := interpolate.Check(interpolate.Options{
+			TemplateFile: writeFile(`{name: ((username)), password: ((password)), port: ((port))}`),
+			VarsFiles:    []string{writeFile(`port: 8443`)},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Missing).To(Equal([]string{"password", "username"}))
+		Expect(report.HasProblems()).To(BeTrue())
+	})
+
+	It("reports the unused variables of each source", func() {
+		varsFile := writeFile(`{username: Bob, pasword: typo}`)
+		otherVarsFile := writeFile(`{port: 8443}`)
+
+		report, err := interpolate.Check(interpolate.Options{
+			TemplateFile: writeFile(`{name: ((username)), address: ((address.host))}`),
+			VarsFiles:    []string{varsFile, otherVarsFile},
+			Vars:         []string{"address.host=example.com", "debug=true"},
+			VarsEnvs:     []string{"PREFIX"},
+			EnvironFunc: func() []string {
+				return []string{"PREFIX_unused=value", "OTHER_name=value"}
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Missing).To(BeEmpty())
+		Expect(report.Unused).To(Equal([]models.UnusedVariables{
+			{Source: "--vars-env PREFIX", Names: []string{"unused"}},
+			{Source: "--vars-file " + varsFile, Names: []string{"pasword"}},
+			{Source: "--vars-file " + otherVarsFile, Names: []string{"port"}},
+			{Source: "--var", Names: []string{"debug"}},
+		}))
+	})
+
+	It("reports which source is used for variables that are provided more than once", func() {
+		varsFile := writeFile(`{username: Bob, password: from-file}`)
+		otherVarsFile := writeFile(`{username: Susie}`)
+
+		report, err := interpolate.Check(interpolate.Options{
+			TemplateFile: writeFile(`{name: ((username)), password: ((password))}`),
+			VarsFiles:    []string{varsFile, otherVarsFile},
+			Vars:         []string{"username=Alice"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.HasProblems()).To(BeFalse())
+		Expect(report.Overridden).To(Equal([]models.OverriddenVariable{{
+			Name:       "username",
+			Source:     "--var",
+			Overridden: []string{"--vars-file " + otherVarsFile, "--vars-file " + varsFile},
+		}}))
+	})
+
+	It("includes the values that are already in the vars store", func() {
+		storeDir, err := ioutil.TempDir("", "vars-store")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(storeDir)
+
+		storePath := filepath.Join(storeDir, "creds.yml")
+		Expect(ioutil.WriteFile(storePath, []byte(`{password: stored, stale: old}`), 0600)).To(Succeed())
+
+		varsFile := writeFile(`{password: from-file}`)
+
+		report, err := interpolate.Check(interpolate.Options{
+			TemplateFile: writeFile(`{password: ((password)), secret: ((secret))}`),
+			VarsFiles:    []string{varsFile},
+			VarsStore:    storePath,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Missing).To(Equal([]string{"secret"}))
+		Expect(report.Unused).To(Equal([]models.UnusedVariables{
+			{Source: "--vars-store " + storePath, Names: []string{"stale"}},
+		}))
+		Expect(report.Overridden).To(Equal([]models.OverriddenVariable{{
+			Name:       "password",
+			Source:     "--vars-file " + varsFile,
+			Overridden: []string{"--vars-store " + storePath},
+		}}))
+	})
+})
+
+var _ = Describe("Execute with ExpectAllVarsUsed", func() {
+	It("fails with every missing and unused variable", func() {
+		varsFile := writeFile(`{username: Bob, pasword: typo}`)
+
+		_, err := interpolate.Execute(interpolate.Options{
+			TemplateFile:      writeFile(`{name: ((username)), password: ((password)), port: ((port))}`),
+			VarsFiles:         []string{varsFile},
+			ExpectAllKeys:     true,
+			ExpectAllVarsUsed: true,
+		})
+		Expect(err).To(MatchError("the variables are not strictly used:\n" +
+			"- missing ((password))\n" +
+			"- missing ((port))\n" +
+			`- unused "pasword" from --vars-file ` + varsFile))
+	})
+
+	It("ignores missing variables when they are allowed", func() {
+		contents, err := interpolate.Execute(interpolate.Options{
+			TemplateFile:      writeFile(`{name: ((username)), password: ((password))}`),
+			VarsFiles:         []string{writeFile(`{username: Bob}`)},
+			ExpectAllVarsUsed: true,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(contents).To(MatchYAML(`{name: Bob, password: ((password))}`))
+	})
+
+	It("succeeds when every variable is used", func() {
+		contents, err := interpolate.Execute(interpolate.Options{
+			TemplateFile:      writeFile(`{name: ((username))}`),
+			VarsFiles:         []string{writeFile(`{username: Bob}`)},
+			Vars:              []string{"username=Susie"},
+			ExpectAllKeys:     true,
+			ExpectAllVarsUsed: true,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(contents).To(MatchYAML(`{name: Susie}`))
+	})
+})
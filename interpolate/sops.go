@@ -0,0 +1,454 @@
+package interpolate
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// The vars files are encrypted in the format of SOPS (https://github.com/getsops/sops):
+// every value is encrypted with AES-GCM using a data key, which is
+// in turn encrypted for each age recipient and PGP key in the `sops` metadata.
+const (
+	sopsVersion           = "3.7.3"
+	sopsMetadataKey       = "sops"
+	sopsUnencryptedSuffix = "_unencrypted"
+	sopsDataKeySize       = 32
+	sopsNonceSize         = 32
+)
+
+var sopsValueRegexp = regexp.MustCompile(`^ENC\[AES256_GCM,data:(.*),iv:(.+),tag:(.+),type:(.+)\]$`)
+
+type sopsMetadata struct {
+	KMS               []interface{} `yaml:"kms"`
+	GCPKMS            []interface{} `yaml:"gcp_kms"`
+	AzureKV           []interface{} `yaml:"azure_kv"`
+	HCVault           []interface{} `yaml:"hc_vault"`
+	Age               []sopsAgeKey  `yaml:"age"`
+	LastModified      string        `yaml:"lastmodified"`
+	MAC               string        `yaml:"mac"`
+	PGP               []sopsPGPKey  `yaml:"pgp"`
+	KeyGroups         []interface{} `yaml:"key_groups,omitempty"`
+	UnencryptedSuffix string        `yaml:"unencrypted_suffix,omitempty"`
+	EncryptedSuffix   string        `yaml:"encrypted_suffix,omitempty"`
+	UnencryptedRegex  string        `yaml:"unencrypted_regex,omitempty"`
+	EncryptedRegex    string        `yaml:"encrypted_regex,omitempty"`
+	MACOnlyEncrypted  bool          `yaml:"mac_only_encrypted,omitempty"`
+	Version           string        `yaml:"version"`
+}
+
+type sopsAgeKey struct {
+	Recipient    string `yaml:"recipient"`
+	EncryptedKey string `yaml:"enc"`
+}
+
+type sopsPGPKey struct {
+	CreatedAt    string `yaml:"created_at"`
+	EncryptedKey string `yaml:"enc"`
+	Fingerprint  string `yaml:"fp"`
+}
+
+// EncryptionKeys are the keys that a vars file is encrypted for.
+// Anyone with the private key of one of them can decrypt it.
+type EncryptionKeys struct {
+	AgeRecipients []string
+	PGPPublicKeys [][]byte
+}
+
+// IsEncrypted is true for YAML documents that have been encrypted with SOPS.
+func IsEncrypted(contents []byte) bool {
+	var document struct {
+		Metadata *struct {
+			MAC string `yaml:"mac"`
+		} `yaml:"sops"`
+	}
+
+	err := yaml.Unmarshal(contents, &document)
+	return err == nil && document.Metadata != nil && document.Metadata.MAC != ""
+}
+
+// EncryptVars encrypts all values of a YAML document for the given keys,
+// except for the ones whose keys end with `_unencrypted`.
+func EncryptVars(contents []byte, keys EncryptionKeys) ([]byte, error) {
+	if len(keys.AgeRecipients) == 0 && len(keys.PGPPublicKeys) == 0 {
+		return nil, errors.New("at least one age recipient or PGP public key is required to encrypt")
+	}
+
+	var document yaml.MapSlice
+	err := yaml.Unmarshal(contents, &document)
+	if err != nil {
+		return nil, fmt.Errorf("could not unmarshal vars: %s", err)
+	}
+
+	for _, item := range document {
+		if item.Key == sopsMetadataKey {
+			return nil, errors.New("the vars are already encrypted")
+		}
+	}
+
+	dataKey := make([]byte, sopsDataKeySize)
+	_, err = rand.Read(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate data key: %s", err)
+	}
+
+	metadata := sopsMetadata{
+		KMS:               []interface{}{},
+		GCPKMS:            []interface{}{},
+		AzureKV:           []interface{}{},
+		HCVault:           []interface{}{},
+		Age:               []sopsAgeKey{},
+		PGP:               []sopsPGPKey{},
+		LastModified:      time.Now().UTC().Format(time.RFC3339),
+		UnencryptedSuffix: sopsUnencryptedSuffix,
+		Version:           sopsVersion,
+	}
+
+	for _, recipient := range keys.AgeRecipients {
+		key, err := encryptAgeDataKey(dataKey, recipient)
+		if err != nil {
+			return nil, err
+		}
+		metadata.Age = append(metadata.Age, key)
+	}
+
+	for _, publicKey := range keys.PGPPublicKeys {
+		pgpKeys, err := encryptPGPDataKey(dataKey, publicKey)
+		if err != nil {
+			return nil, err
+		}
+		metadata.PGP = append(metadata.PGP, pgpKeys...)
+	}
+
+	hash := sha512.New()
+	encrypted, err := walkSOPSValue(document, nil, func(value interface{}, path []string) (interface{}, error) {
+		hash.Write(sopsBytes(value))
+
+		if !metadata.encrypts(path) {
+			return value, nil
+		}
+		return encryptSOPSValue(value, dataKey, sopsAdditionalData(path))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	metadata.MAC, err = encryptSOPSValue(fmt.Sprintf("%X", hash.Sum(nil)), dataKey, metadata.LastModified)
+	if err != nil {
+		return nil, err
+	}
+
+	output := append(encrypted.(yaml.MapSlice), yaml.MapItem{Key: sopsMetadataKey, Value: metadata})
+	return yaml.Marshal(output)
+}
+
+// DecryptVars decrypts a YAML document that has been encrypted with SOPS.
+// The decrypted document is only kept in memory.
+func DecryptVars(contents []byte, environFunc func() []string) ([]byte, error) {
+	document, err := decryptSOPSDocument(contents, environFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(document)
+}
+
+func decryptSOPSDocument(contents []byte, environFunc func() []string) (yaml.MapSlice, error) {
+	var document yaml.MapSlice
+	err := yaml.Unmarshal(contents, &document)
+	if err != nil {
+		return nil, fmt.Errorf("could not unmarshal encrypted vars: %s", err)
+	}
+
+	var (
+		metadata sopsMetadata
+		found    bool
+		values   yaml.MapSlice
+	)
+	for _, item := range document {
+		if item.Key != sopsMetadataKey {
+			values = append(values, item)
+			continue
+		}
+
+		found = true
+		rawMetadata, err := yaml.Marshal(item.Value)
+		if err == nil {
+			err = yaml.Unmarshal(rawMetadata, &metadata)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read the sops metadata: %s", err)
+		}
+	}
+
+	if !found {
+		return nil, errors.New("the vars are not encrypted: no sops metadata found")
+	}
+
+	if len(metadata.KeyGroups) > 0 {
+		return nil, errors.New("cannot decrypt vars that use sops key groups")
+	}
+
+	dataKey, err := decryptDataKey(metadata, environFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha512.New()
+	decrypted, err := walkSOPSValue(values, nil, func(value interface{}, path []string) (interface{}, error) {
+		encrypted := metadata.encrypts(path)
+		if encrypted {
+			ciphertext, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("could not decrypt %s: the value is not encrypted", strings.Join(path, "."))
+			}
+
+			plaintext, err := decryptSOPSValue(ciphertext, dataKey, sopsAdditionalData(path))
+			if err != nil {
+				return nil, fmt.Errorf("could not decrypt %s: %s", strings.Join(path, "."), err)
+			}
+			value = plaintext
+		}
+
+		if encrypted || !metadata.MACOnlyEncrypted {
+			hash.Write(sopsBytes(value))
+		}
+		return value, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	lastModified, err := time.Parse(time.RFC3339, metadata.LastModified)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the sops lastmodified timestamp: %s", err)
+	}
+
+	mac, err := decryptSOPSValue(metadata.MAC, dataKey, lastModified.Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt the sops mac: %s", err)
+	}
+
+	if mac != fmt.Sprintf("%X", hash.Sum(nil)) {
+		return nil, errors.New("the encrypted vars have been modified: the sops mac does not match")
+	}
+
+	if decrypted == nil {
+		return yaml.MapSlice{}, nil
+	}
+
+	return decrypted.(yaml.MapSlice), nil
+}
+
+// encrypts follows the rules of SOPS to decide whether the value at path is encrypted.
+func (m sopsMetadata) encrypts(path []string) bool {
+	if m.UnencryptedSuffix != "" {
+		for _, key := range path {
+			if strings.HasSuffix(key, m.UnencryptedSuffix) {
+				return false
+			}
+		}
+	}
+
+	if m.EncryptedSuffix != "" {
+		for _, key := range path {
+			if strings.HasSuffix(key, m.EncryptedSuffix) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if m.UnencryptedRegex != "" {
+		re, err := regexp.Compile(m.UnencryptedRegex)
+		if err == nil {
+			for _, key := range path {
+				if re.MatchString(key) {
+					return false
+				}
+			}
+		}
+	}
+
+	if m.EncryptedRegex != "" {
+		re, err := regexp.Compile(m.EncryptedRegex)
+		if err == nil {
+			for _, key := range path {
+				if re.MatchString(key) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+// walkSOPSValue calls leaf for every value that is not a map, a list, or null.
+// Like SOPS, the items of a list have the same path as the list.
+func walkSOPSValue(value interface{}, path []string, leaf func(interface{}, []string) (interface{}, error)) (interface{}, error) {
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		walked := yaml.MapSlice{}
+		for _, item := range v {
+			keyPath := append(append([]string{}, path...), fmt.Sprintf("%v", item.Key))
+			walkedValue, err := walkSOPSValue(item.Value, keyPath, leaf)
+			if err != nil {
+				return nil, err
+			}
+			walked = append(walked, yaml.MapItem{Key: item.Key, Value: walkedValue})
+		}
+		return walked, nil
+	case []interface{}:
+		walked := []interface{}{}
+		for _, item := range v {
+			walkedValue, err := walkSOPSValue(item, path, leaf)
+			if err != nil {
+				return nil, err
+			}
+			walked = append(walked, walkedValue)
+		}
+		return walked, nil
+	case nil:
+		return nil, nil
+	}
+
+	return leaf(value, path)
+}
+
+func sopsAdditionalData(path []string) string {
+	return strings.Join(path, ":") + ":"
+}
+
+// sopsBytes is how SOPS represents values in its message authentication code.
+func sopsBytes(value interface{}) []byte {
+	switch v := value.(type) {
+	case string:
+		return []byte(v)
+	case int:
+		return []byte(strconv.Itoa(v))
+	case int64:
+		return []byte(strconv.FormatInt(v, 10))
+	case uint64:
+		return []byte(strconv.FormatUint(v, 10))
+	case float64:
+		return []byte(strconv.FormatFloat(v, 'f', -1, 64))
+	case bool:
+		return []byte(strings.Title(strconv.FormatBool(v)))
+	}
+	return []byte(fmt.Sprintf("%v", value))
+}
+
+func encryptSOPSValue(value interface{}, key []byte, additionalData string) (string, error) {
+	var (
+		valueType string
+		plaintext []byte
+	)
+
+	switch v := value.(type) {
+	case string:
+		// like SOPS, empty strings are left as they are
+		if v == "" {
+			return "", nil
+		}
+		valueType, plaintext = "str", []byte(v)
+	case int, int64, uint64:
+		valueType, plaintext = "int", sopsBytes(v)
+	case float64:
+		valueType, plaintext = "float", sopsBytes(v)
+	case bool:
+		valueType, plaintext = "bool", []byte(strconv.FormatBool(v))
+	default:
+		return "", fmt.Errorf("cannot encrypt a value of type %T", value)
+	}
+
+	gcm, err := sopsCipher(key, sopsNonceSize)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, sopsNonceSize)
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return "", fmt.Errorf("could not generate nonce: %s", err)
+	}
+
+	sealed := gcm.Seal(nil, nonce, plaintext, []byte(additionalData))
+	data, tag := sealed[:len(sealed)-gcm.Overhead()], sealed[len(sealed)-gcm.Overhead():]
+
+	return fmt.Sprintf("ENC[AES256_GCM,data:%s,iv:%s,tag:%s,type:%s]",
+		base64.StdEncoding.EncodeToString(data),
+		base64.StdEncoding.EncodeToString(nonce),
+		base64.StdEncoding.EncodeToString(tag),
+		valueType,
+	), nil
+}
+
+// decryptSOPSValue returns an empty string as it is, as SOPS does not encrypt empty strings.
+func decryptSOPSValue(value string, key []byte, additionalData string) (interface{}, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	matches := sopsValueRegexp.FindStringSubmatch(value)
+	if matches == nil {
+		return nil, errors.New("the value is not encrypted")
+	}
+
+	var decoded [3][]byte
+	for i := range decoded {
+		var err error
+		decoded[i], err = base64.StdEncoding.DecodeString(matches[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("the value is not valid base64: %s", err)
+		}
+	}
+	data, nonce, tag := decoded[0], decoded[1], decoded[2]
+
+	gcm, err := sopsCipher(key, len(nonce))
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := gcm.Open(nil, nonce, append(data, tag...), []byte(additionalData))
+	if err != nil {
+		return nil, errors.New("the value could not be authenticated")
+	}
+
+	switch valueType := matches[4]; valueType {
+	case "str", "bytes", "comment":
+		return string(plaintext), nil
+	case "int":
+		return strconv.Atoi(string(plaintext))
+	case "float":
+		return strconv.ParseFloat(string(plaintext), 64)
+	case "bool":
+		return strconv.ParseBool(string(plaintext))
+	default:
+		return nil, fmt.Errorf("unknown value type %q", valueType)
+	}
+}
+
+func sopsCipher(key []byte, nonceSize int) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data key: %s", err)
+	}
+
+	if nonceSize == 0 {
+		return nil, errors.New("the value has no iv")
+	}
+
+	return cipher.NewGCMWithNonceSize(block, nonceSize)
+}
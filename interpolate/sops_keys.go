@@ -0,0 +1,296 @@
+package interpolate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+	agearmor "filippo.io/age/armor"
+	"golang.org/x/crypto/openpgp"
+	pgparmor "golang.org/x/crypto/openpgp/armor"
+
+	// PGP keys without hash preferences are assumed to support RIPEMD160
+	_ "golang.org/x/crypto/ripemd160"
+)
+
+// The private keys to decrypt vars files are read from the environment:
+//
+//	SOPS_AGE_KEY             age identities
+//	SOPS_AGE_KEY_FILE        a file with age identities (default: ~/.config/sops/age/keys.txt)
+//	OM_PGP_PRIVATE_KEY       an armored PGP private key
+//	OM_PGP_PRIVATE_KEY_FILE  a file with an armored PGP private key
+//	OM_PGP_PASSPHRASE        the passphrase of the PGP private key
+const (
+	ageKeyEnv        = "SOPS_AGE_KEY"
+	ageKeyFileEnv    = "SOPS_AGE_KEY_FILE"
+	pgpKeyEnv        = "OM_PGP_PRIVATE_KEY"
+	pgpKeyFileEnv    = "OM_PGP_PRIVATE_KEY_FILE"
+	pgpPassphraseEnv = "OM_PGP_PASSPHRASE"
+)
+
+func encryptAgeDataKey(dataKey []byte, recipient string) (sopsAgeKey, error) {
+	ageRecipient, err := age.ParseX25519Recipient(recipient)
+	if err != nil {
+		return sopsAgeKey{}, fmt.Errorf("invalid age recipient %q: %s", recipient, err)
+	}
+
+	var encrypted bytes.Buffer
+	armorWriter := agearmor.NewWriter(&encrypted)
+	writer, err := age.Encrypt(armorWriter, ageRecipient)
+	if err != nil {
+		return sopsAgeKey{}, fmt.Errorf("could not encrypt data key for %s: %s", recipient, err)
+	}
+
+	_, err = writer.Write(dataKey)
+	if err == nil {
+		err = writer.Close()
+	}
+	if err == nil {
+		err = armorWriter.Close()
+	}
+	if err != nil {
+		return sopsAgeKey{}, fmt.Errorf("could not encrypt data key for %s: %s", recipient, err)
+	}
+
+	return sopsAgeKey{
+		Recipient:    recipient,
+		EncryptedKey: encrypted.String(),
+	}, nil
+}
+
+func encryptPGPDataKey(dataKey []byte, publicKey []byte) ([]sopsPGPKey, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(publicKey))
+	if err != nil {
+		return nil, fmt.Errorf("could not read PGP public key: %s", err)
+	}
+
+	var keys []sopsPGPKey
+	for _, entity := range entities {
+		var encrypted bytes.Buffer
+		armorWriter, err := pgparmor.Encode(&encrypted, "PGP MESSAGE", nil)
+		if err != nil {
+			return nil, err
+		}
+
+		fingerprint := fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint)
+
+		writer, err := openpgp.Encrypt(armorWriter, []*openpgp.Entity{entity}, nil, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("could not encrypt data key for %s: %s", fingerprint, err)
+		}
+
+		_, err = writer.Write(dataKey)
+		if err == nil {
+			err = writer.Close()
+		}
+		if err == nil {
+			err = armorWriter.Close()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not encrypt data key for %s: %s", fingerprint, err)
+		}
+
+		keys = append(keys, sopsPGPKey{
+			CreatedAt:    time.Now().UTC().Format(time.RFC3339),
+			EncryptedKey: encrypted.String(),
+			Fingerprint:  fingerprint,
+		})
+	}
+
+	return keys, nil
+}
+
+// decryptDataKey tries all private keys from the environment
+// against the encrypted data keys of the vars file.
+func decryptDataKey(metadata sopsMetadata, environFunc func() []string) ([]byte, error) {
+	if environFunc == nil {
+		environFunc = os.Environ
+	}
+	env := environment(environFunc())
+
+	var problems []string
+
+	if len(metadata.Age) > 0 {
+		identities, err := ageIdentities(env)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(identities) == 0 {
+			problems = append(problems, fmt.Sprintf("no age identities found (set %s or %s)", ageKeyEnv, ageKeyFileEnv))
+		}
+
+		for _, key := range metadata.Age {
+			if len(identities) == 0 {
+				break
+			}
+
+			reader, err := age.Decrypt(agearmor.NewReader(strings.NewReader(key.EncryptedKey)), identities...)
+			if err == nil {
+				var dataKey []byte
+				dataKey, err = ioutil.ReadAll(reader)
+				if err == nil && len(dataKey) == sopsDataKeySize {
+					return dataKey, nil
+				}
+			}
+			problems = append(problems, fmt.Sprintf("age recipient %s: %s", key.Recipient, describeKeyError(err)))
+		}
+	}
+
+	if len(metadata.PGP) > 0 {
+		keyring, err := pgpKeyring(env)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(keyring) == 0 {
+			problems = append(problems, fmt.Sprintf("no PGP private key found (set %s or %s)", pgpKeyEnv, pgpKeyFileEnv))
+		}
+
+		for _, key := range metadata.PGP {
+			if len(keyring) == 0 {
+				break
+			}
+
+			dataKey, err := decryptPGPDataKey(key.EncryptedKey, keyring, env[pgpPassphraseEnv])
+			if err == nil && len(dataKey) == sopsDataKeySize {
+				return dataKey, nil
+			}
+			problems = append(problems, fmt.Sprintf("PGP key %s: %s", key.Fingerprint, describeKeyError(err)))
+		}
+	}
+
+	if len(metadata.Age) == 0 && len(metadata.PGP) == 0 {
+		return nil, errors.New("could not decrypt the vars: only age and PGP keys are supported, but none are in the sops metadata")
+	}
+
+	return nil, fmt.Errorf("could not decrypt the data key of the vars:\n- %s", strings.Join(problems, "\n- "))
+}
+
+func describeKeyError(err error) string {
+	if err == nil {
+		return "invalid data key"
+	}
+	return err.Error()
+}
+
+func ageIdentities(env map[string]string) ([]age.Identity, error) {
+	var identities []age.Identity
+
+	if keys := env[ageKeyEnv]; keys != "" {
+		parsed, err := age.ParseIdentities(strings.NewReader(keys))
+		if err != nil {
+			return nil, fmt.Errorf("could not parse the age identities in %s: %s", ageKeyEnv, err)
+		}
+		identities = append(identities, parsed...)
+	}
+
+	keyFile := env[ageKeyFileEnv]
+	if keyFile == "" {
+		keyFile = defaultAgeKeyFile(env)
+		if _, err := os.Stat(keyFile); err != nil {
+			return identities, nil
+		}
+	}
+
+	contents, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the age identities file (%s): %s", keyFile, err)
+	}
+
+	parsed, err := age.ParseIdentities(bytes.NewReader(contents))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the age identities file (%s): %s", keyFile, err)
+	}
+
+	return append(identities, parsed...), nil
+}
+
+func defaultAgeKeyFile(env map[string]string) string {
+	configDir := env["XDG_CONFIG_HOME"]
+	if configDir == "" {
+		configDir = filepath.Join(env["HOME"], ".config")
+	}
+	return filepath.Join(configDir, "sops", "age", "keys.txt")
+}
+
+func pgpKeyring(env map[string]string) (openpgp.EntityList, error) {
+	var keyring openpgp.EntityList
+
+	readKeys := func(source string, reader io.Reader) error {
+		entities, err := openpgp.ReadArmoredKeyRing(reader)
+		if err != nil {
+			return fmt.Errorf("could not read the PGP private key in %s: %s", source, err)
+		}
+
+		if passphrase := env[pgpPassphraseEnv]; passphrase != "" {
+			for _, entity := range entities {
+				if entity.PrivateKey != nil && entity.PrivateKey.Encrypted {
+					_ = entity.PrivateKey.Decrypt([]byte(passphrase))
+				}
+				for _, subkey := range entity.Subkeys {
+					if subkey.PrivateKey != nil && subkey.PrivateKey.Encrypted {
+						_ = subkey.PrivateKey.Decrypt([]byte(passphrase))
+					}
+				}
+			}
+		}
+
+		keyring = append(keyring, entities...)
+		return nil
+	}
+
+	if key := env[pgpKeyEnv]; key != "" {
+		err := readKeys(pgpKeyEnv, strings.NewReader(key))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if keyFile := env[pgpKeyFileEnv]; keyFile != "" {
+		file, err := os.Open(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read the PGP private key file (%s): %s", keyFile, err)
+		}
+		defer file.Close()
+
+		err = readKeys(keyFile, file)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return keyring, nil
+}
+
+func decryptPGPDataKey(encryptedKey string, keyring openpgp.EntityList, passphrase string) ([]byte, error) {
+	block, err := pgparmor.Decode(strings.NewReader(encryptedKey))
+	if err != nil {
+		return nil, fmt.Errorf("could not decode the encrypted data key: %s", err)
+	}
+
+	prompted := false
+	message, err := openpgp.ReadMessage(block.Body, keyring, func(keys []openpgp.Key, symmetric bool) ([]byte, error) {
+		if prompted || passphrase == "" {
+			return nil, fmt.Errorf("the private key is encrypted, set %s", pgpPassphraseEnv)
+		}
+		prompted = true
+
+		for _, key := range keys {
+			_ = key.PrivateKey.Decrypt([]byte(passphrase))
+		}
+		return nil, nil
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return ioutil.ReadAll(message.UnverifiedBody)
+}
@@ -0,0 +1,185 @@
+package interpolate_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"filippo.io/age"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/om/interpolate"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+)
+
+// the identity fixtures/encrypted-vars.yml has been encrypted for with sops
+const fixtureAgeKey = "AGE-SECRET-KEY-1ZAMV9L36S9T7PWR53M0SEDTVR79EL6YD6AT4Y3JS9TUYJMTNEEASJLPCTM"
+
+const plaintextVars = `password: some-password
+port: 8443
+enabled: true
+ratio: 1.5
+nested:
+  username: admin
+  list:
+  - first
+  - 2
+public_unencrypted: visible
+empty: null
+`
+
+var _ = Describe("encrypted vars files", func() {
+	var identity *age.X25519Identity
+
+	BeforeEach(func() {
+		var err error
+		identity, err = age.GenerateX25519Identity()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	environ := func(vars ...string) func() []string {
+		return func() []string {
+			return append(vars, "HOME=/nonexistent")
+		}
+	}
+
+	It("decrypts files that have been encrypted with sops", func() {
+		contents, err := ioutil.ReadFile(filepath.Join("fixtures", "encrypted-vars.yml"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(interpolate.IsEncrypted(contents)).To(BeTrue())
+
+		decrypted, err := interpolate.DecryptVars(contents, environ("SOPS_AGE_KEY="+fixtureAgeKey))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(decrypted)).To(Equal(plaintextVars))
+	})
+
+	It("decrypts vars files transparently when interpolating", func() {
+		keyFile := writeFile("# created: 2021-01-01T00:00:00Z\n" + fixtureAgeKey + "\n")
+
+		contents, err := interpolate.Execute(interpolate.Options{
+			TemplateFile:  writeFile(`{password: ((password)), port: ((port)), username: ((nested.username)), public: ((public_unencrypted))}`),
+			VarsFiles:     []string{filepath.Join("fixtures", "encrypted-vars.yml")},
+			EnvironFunc:   environ("SOPS_AGE_KEY_FILE=" + keyFile),
+			ExpectAllKeys: true,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(contents).To(MatchYAML(`{password: some-password, port: 8443, username: admin, public: visible}`))
+	})
+
+	It("encrypts every value for age recipients", func() {
+		encrypted, err := interpolate.EncryptVars([]byte(plaintextVars), interpolate.EncryptionKeys{
+			AgeRecipients: []string{identity.Recipient().String()},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(interpolate.IsEncrypted(encrypted)).To(BeTrue())
+
+		Expect(string(encrypted)).ToNot(ContainSubstring("some-password"))
+		Expect(string(encrypted)).ToNot(ContainSubstring("admin"))
+		Expect(string(encrypted)).To(ContainSubstring("public_unencrypted: visible"))
+		Expect(string(encrypted)).To(ContainSubstring("recipient: " + identity.Recipient().String()))
+
+		decrypted, err := interpolate.DecryptVars(encrypted, environ("SOPS_AGE_KEY="+identity.String()))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(decrypted)).To(Equal(plaintextVars))
+	})
+
+	It("leaves empty strings unencrypted, like sops", func() {
+		vars := "password: some-password\nblank: \"\"\n"
+
+		encrypted, err := interpolate.EncryptVars([]byte(vars), interpolate.EncryptionKeys{
+			AgeRecipients: []string{identity.Recipient().String()},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(encrypted)).To(ContainSubstring(`blank: ""`))
+
+		decrypted, err := interpolate.DecryptVars(encrypted, environ("SOPS_AGE_KEY="+identity.String()))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(decrypted)).To(Equal(vars))
+	})
+
+	It("encrypts for PGP keys", func() {
+		entity, err := openpgp.NewEntity("om", "test", "om@example.com", nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		var publicKey, privateKey bytes.Buffer
+		writer, err := armor.Encode(&privateKey, openpgp.PrivateKeyType, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entity.SerializePrivate(writer, nil)).To(Succeed())
+		Expect(writer.Close()).To(Succeed())
+
+		writer, err = armor.Encode(&publicKey, openpgp.PublicKeyType, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entity.Serialize(writer)).To(Succeed())
+		Expect(writer.Close()).To(Succeed())
+
+		encrypted, err := interpolate.EncryptVars([]byte(plaintextVars), interpolate.EncryptionKeys{
+			PGPPublicKeys: [][]byte{publicKey.Bytes()},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(encrypted)).To(ContainSubstring("BEGIN PGP MESSAGE"))
+
+		decrypted, err := interpolate.DecryptVars(encrypted, environ("OM_PGP_PRIVATE_KEY_FILE="+writeFile(privateKey.String())))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(decrypted)).To(Equal(plaintextVars))
+	})
+
+	When("the vars cannot be decrypted", func() {
+		var encrypted []byte
+
+		BeforeEach(func() {
+			var err error
+			encrypted, err = interpolate.EncryptVars([]byte(plaintextVars), interpolate.EncryptionKeys{
+				AgeRecipients: []string{identity.Recipient().String()},
+			})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("errors without a matching key", func() {
+			other, err := age.GenerateX25519Identity()
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = interpolate.DecryptVars(encrypted, environ("SOPS_AGE_KEY="+other.String()))
+			Expect(err).To(MatchError(ContainSubstring("could not decrypt the data key of the vars:\n- age recipient " + identity.Recipient().String())))
+
+			_, err = interpolate.DecryptVars(encrypted, environ())
+			Expect(err).To(MatchError(ContainSubstring("no age identities found (set SOPS_AGE_KEY or SOPS_AGE_KEY_FILE)")))
+		})
+
+		It("errors when an unencrypted value has been changed", func() {
+			tampered := strings.Replace(string(encrypted), "public_unencrypted: visible", "public_unencrypted: changed", 1)
+
+			_, err := interpolate.DecryptVars([]byte(tampered), environ("SOPS_AGE_KEY="+identity.String()))
+			Expect(err).To(MatchError("the encrypted vars have been modified: the sops mac does not match"))
+		})
+
+		It("errors when an encrypted value has been moved", func() {
+			tampered := strings.Replace(string(encrypted), "password: ENC", "other: ENC", 1)
+
+			_, err := interpolate.DecryptVars([]byte(tampered), environ("SOPS_AGE_KEY="+identity.String()))
+			Expect(err).To(MatchError("could not decrypt other: the value could not be authenticated"))
+		})
+
+		It("reports which vars file could not be decrypted", func() {
+			path := writeFile(string(encrypted))
+
+			_, err := interpolate.Execute(interpolate.Options{
+				TemplateFile: writeFile(`{password: ((password))}`),
+				VarsFiles:    []string{path},
+				EnvironFunc:  environ(),
+			})
+			Expect(err).To(MatchError(ContainSubstring("could not decrypt file (" + path + ")")))
+		})
+	})
+
+	It("does not encrypt twice", func() {
+		contents, err := ioutil.ReadFile(filepath.Join("fixtures", "encrypted-vars.yml"))
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = interpolate.EncryptVars(contents, interpolate.EncryptionKeys{
+			AgeRecipients: []string{identity.Recipient().String()},
+		})
+		Expect(err).To(MatchError("the vars are already encrypted"))
+	})
+})
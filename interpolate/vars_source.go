@@ -0,0 +1,146 @@
+package interpolate
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/bosh-cli/director/template"
+	"gopkg.in/yaml.v2"
+)
+
+// VarsSource resolves variables that were not provided by vars files,
+// environment variables, or the command line from a credential store.
+type VarsSource interface {
+	template.Variables
+}
+
+// NewVarsSource builds a VarsSource from a spec of the form
+// `<type>:<key>=<value>,<key>=<value>`, for example
+// `vault:address=https://vault.example.com:8200,mount=secret,path=foundation`
+// or `credhub:address=https://credhub.example.com:8844,path=/concourse/main`.
+//
+// Tokens and client credentials are never part of the spec,
+// they are read from the environment (VAULT_TOKEN, CREDHUB_TOKEN,
+// or CREDHUB_CLIENT and CREDHUB_SECRET).
+func NewVarsSource(spec string, environFunc func() []string) (VarsSource, error) {
+	pieces := strings.SplitN(spec, ":", 2)
+	if len(pieces) != 2 {
+		return nil, fmt.Errorf("invalid vars source %q: expected <type>:<key>=<value>,...", spec)
+	}
+
+	sourceType := pieces[0]
+	params := map[string]string{}
+	for _, param := range strings.Split(pieces[1], ",") {
+		if param == "" {
+			continue
+		}
+
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid vars source %q: expected %q to be a key=value pair", spec, param)
+		}
+		params[kv[0]] = kv[1]
+	}
+
+	if environFunc == nil {
+		environFunc = os.Environ
+	}
+	env := environment(environFunc())
+
+	switch sourceType {
+	case "vault":
+		return newVaultSource(params, env)
+	case "credhub":
+		return newCredHubSource(params, env)
+	}
+
+	return nil, fmt.Errorf("invalid vars source %q: unsupported type %q, expected vault or credhub", spec, sourceType)
+}
+
+func environment(environ []string) map[string]string {
+	env := map[string]string{}
+	for _, envVar := range environ {
+		pieces := strings.SplitN(envVar, "=", 2)
+		if len(pieces) == 2 {
+			env[pieces[0]] = pieces[1]
+		}
+	}
+	return env
+}
+
+func requireParams(sourceType string, params map[string]string, allowed []string, required ...string) error {
+	for key := range params {
+		var known bool
+		for _, name := range allowed {
+			if key == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("invalid %s vars source: unknown key %q, expected one of %s", sourceType, key, strings.Join(allowed, ", "))
+		}
+	}
+
+	for _, key := range required {
+		if params[key] == "" {
+			return fmt.Errorf("invalid %s vars source: %q is required", sourceType, key)
+		}
+	}
+
+	return nil
+}
+
+func newHTTPClient(params map[string]string) (*http.Client, error) {
+	tlsConfig := &tls.Config{}
+
+	if value, ok := params["skip-ssl-validation"]; ok {
+		skip, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for skip-ssl-validation: %s", err)
+		}
+		tlsConfig.InsecureSkipVerify = skip
+	}
+
+	if path, ok := params["ca-cert"]; ok {
+		contents, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read ca-cert (%s): %s", path, err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(contents) {
+			return nil, fmt.Errorf("could not use ca-cert (%s): no PEM encoded certificates found", path)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		},
+	}, nil
+}
+
+// fromJSON converts a decoded JSON value into the YAML representation
+// the template expects, so that ((name.key)) lookups work on it.
+func fromJSON(value interface{}) (interface{}, error) {
+	contents, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var converted interface{}
+	err = yaml.Unmarshal(contents, &converted)
+	return converted, err
+}
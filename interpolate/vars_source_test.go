@@ -0,0 +1,209 @@
+package interpolate_test
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	"github.com/pivotal-cf/om/interpolate"
+)
+
+var _ = Describe("VarsSources", func() {
+	var server *ghttp.Server
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	When("using vault", func() {
+		var environ func() []string
+
+		BeforeEach(func() {
+			environ = func() []string {
+				return []string{"VAULT_TOKEN=some-token"}
+			}
+		})
+
+		It("reads variables from the kv secrets engine", func() {
+			server.RouteToHandler("GET", "/v1/kv/data/foundation/password", ghttp.CombineHandlers(
+				ghttp.VerifyHeaderKV("X-Vault-Token", "some-token"),
+				ghttp.RespondWith(http.StatusOK, `{"data": {"data": {"value": "some-password"}, "metadata": {"version": 1}}}`),
+			))
+			server.RouteToHandler("GET", "/v1/kv/data/foundation/cert", ghttp.CombineHandlers(
+				ghttp.VerifyHeaderKV("X-Vault-Token", "some-token"),
+				ghttp.RespondWith(http.StatusOK, `{"data": {"data": {"certificate": "some-cert", "private_key": "some-key"}}}`),
+			))
+
+			contents, err := interpolate.Execute(interpolate.Options{
+				TemplateFile:  writeFile(`{password: ((password)), cert: ((cert.certificate))}`),
+				VarsSources:   []string{"vault:address=" + server.URL() + ",mount=kv,path=foundation"},
+				EnvironFunc:   environ,
+				ExpectAllKeys: true,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(contents).To(MatchYAML(`{password: some-password, cert: some-cert}`))
+		})
+
+		It("prefers variables that are provided directly", func() {
+			contents, err := interpolate.Execute(interpolate.Options{
+				TemplateFile:  writeFile(`{password: ((password))}`),
+				VarsFiles:     []string{writeFile(`password: from-file`)},
+				VarsSources:   []string{"vault:address=" + server.URL()},
+				EnvironFunc:   environ,
+				ExpectAllKeys: true,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(contents).To(MatchYAML(`{password: from-file}`))
+			Expect(server.ReceivedRequests()).To(HaveLen(0))
+		})
+
+		It("reports variables that are not in vault as missing", func() {
+			server.AppendHandlers(ghttp.RespondWith(http.StatusNotFound, `{"errors": []}`))
+
+			_, err := interpolate.Execute(interpolate.Options{
+				TemplateFile:  writeFile(`{password: ((password))}`),
+				VarsSources:   []string{"vault:address=" + server.URL()},
+				EnvironFunc:   environ,
+				ExpectAllKeys: true,
+			})
+			Expect(err).To(MatchError(ContainSubstring("Expected to find variables: password")))
+			Expect(server.ReceivedRequests()[0].URL.Path).To(Equal("/v1/secret/data/password"))
+		})
+
+		It("errors when vault cannot be read", func() {
+			server.AppendHandlers(ghttp.RespondWith(http.StatusForbidden, `{"errors": ["permission denied"]}`))
+
+			_, err := interpolate.Execute(interpolate.Options{
+				TemplateFile: writeFile(`{password: ((password))}`),
+				VarsSources:  []string{"vault:address=" + server.URL()},
+				EnvironFunc:  environ,
+			})
+			Expect(err).To(MatchError(ContainSubstring("could not read secret/password from vault: unexpected response 403 Forbidden")))
+		})
+
+		It("errors when there is no token", func() {
+			_, err := interpolate.Execute(interpolate.Options{
+				TemplateFile: writeFile(`{password: ((password))}`),
+				VarsSources:  []string{"vault:address=" + server.URL()},
+				EnvironFunc:  func() []string { return nil },
+			})
+			Expect(err).To(MatchError("invalid vault vars source: VAULT_TOKEN must be set in the environment"))
+		})
+	})
+
+	When("using credhub", func() {
+		It("reads the current value of credentials with a token", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v1/data", "current=true&name=%2Ffoundation%2Fadmin"),
+					ghttp.VerifyHeaderKV("Authorization", "Bearer some-token"),
+					ghttp.RespondWith(http.StatusOK, `{"data": [{"type": "user", "value": {"username": "admin", "password": "some-password"}}]}`),
+				),
+			)
+
+			contents, err := interpolate.Execute(interpolate.Options{
+				TemplateFile: writeFile(`{password: ((admin.password))}`),
+				VarsSources:  []string{"credhub:address=" + server.URL() + ",path=/foundation"},
+				EnvironFunc: func() []string {
+					return []string{"CREDHUB_TOKEN=some-token"}
+				},
+				ExpectAllKeys: true,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(contents).To(MatchYAML(`{password: some-password}`))
+		})
+
+		It("authenticates with client credentials", func() {
+			uaa := ghttp.NewServer()
+			defer uaa.Close()
+
+			uaa.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/oauth/token"),
+					ghttp.VerifyBasicAuth("some-client", "some-secret"),
+					ghttp.VerifyForm(map[string][]string{"grant_type": {"client_credentials"}}),
+					ghttp.RespondWith(http.StatusOK, `{"access_token": "uaa-token", "token_type": "bearer"}`),
+				),
+			)
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/info"),
+					ghttp.RespondWith(http.StatusOK, `{"auth-server": {"url": "`+uaa.URL()+`"}}`),
+				),
+			)
+			server.RouteToHandler("GET", "/api/v1/data", ghttp.CombineHandlers(
+				ghttp.VerifyHeaderKV("Authorization", "Bearer uaa-token"),
+				func(w http.ResponseWriter, r *http.Request) {
+					switch r.URL.Query().Get("name") {
+					case "/password":
+						w.Write([]byte(`{"data": [{"type": "password", "value": "some-password"}]}`))
+					case "/absolute/name":
+						w.Write([]byte(`{"data": [{"type": "value", "value": 123}]}`))
+					default:
+						w.WriteHeader(http.StatusNotFound)
+					}
+				},
+			))
+
+			contents, err := interpolate.Execute(interpolate.Options{
+				TemplateFile: writeFile(`{password: ((password)), number: ((/absolute/name))}`),
+				VarsSources:  []string{"credhub:address=" + server.URL()},
+				EnvironFunc: func() []string {
+					return []string{"CREDHUB_CLIENT=some-client", "CREDHUB_SECRET=some-secret"}
+				},
+				ExpectAllKeys: true,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(contents).To(MatchYAML(`{password: some-password, number: 123}`))
+		})
+
+		It("asks the next source for credentials that do not exist", func() {
+			vault := ghttp.NewServer()
+			defer vault.Close()
+
+			server.AppendHandlers(ghttp.RespondWith(http.StatusNotFound, `{"error": "The request could not be completed because the credential does not exist or you do not have sufficient authorization."}`))
+			vault.AppendHandlers(ghttp.RespondWith(http.StatusOK, `{"data": {"data": {"value": "from-vault"}}}`))
+
+			contents, err := interpolate.Execute(interpolate.Options{
+				TemplateFile: writeFile(`{password: ((password))}`),
+				VarsSources: []string{
+					"credhub:address=" + server.URL(),
+					"vault:address=" + vault.URL(),
+				},
+				EnvironFunc: func() []string {
+					return []string{"CREDHUB_TOKEN=some-token", "VAULT_TOKEN=some-token"}
+				},
+				ExpectAllKeys: true,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(contents).To(MatchYAML(`{password: from-vault}`))
+		})
+	})
+
+	It("errors with an invalid spec", func() {
+		_, err := interpolate.Execute(interpolate.Options{
+			TemplateFile: writeFile(`{}`),
+			VarsSources:  []string{"vault"},
+		})
+		Expect(err).To(MatchError(`invalid vars source "vault": expected <type>:<key>=<value>,...`))
+
+		_, err = interpolate.Execute(interpolate.Options{
+			TemplateFile: writeFile(`{}`),
+			VarsSources:  []string{"consul:address=http://example.com"},
+		})
+		Expect(err).To(MatchError(`invalid vars source "consul:address=http://example.com": unsupported type "consul", expected vault or credhub`))
+
+		_, err = interpolate.Execute(interpolate.Options{
+			TemplateFile: writeFile(`{}`),
+			VarsSources:  []string{"vault:address=http://example.com,token=secret"},
+			EnvironFunc:  func() []string { return []string{"VAULT_TOKEN=some-token"} },
+		})
+		Expect(err).To(MatchError(`invalid vault vars source: unknown key "token", expected one of address, mount, path, namespace, skip-ssl-validation, ca-cert`))
+	})
+})
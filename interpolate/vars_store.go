@@ -0,0 +1,101 @@
+package interpolate
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/cloudfoundry/bosh-cli/director/template"
+	"gopkg.in/yaml.v2"
+)
+
+// varsStore is a YAML file of variables like a vars file.
+// Variables that are missing from it, but have a definition with a type
+// in the `variables:` section of the template, are generated,
+// and saved to the file once the template has been interpolated, so they are reused on the next run.
+type varsStore struct {
+	path      string
+	values    template.StaticVariables
+	generated []string
+
+	// vars resolves the certificate authorities
+	// that generated certificates reference
+	vars template.Variables
+}
+
+func newVarsStore(path string) (*varsStore, error) {
+	store := &varsStore{
+		path:   path,
+		values: template.StaticVariables{},
+	}
+
+	_, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+
+	err = readYAMLFile(path, &store.values)
+	if err != nil {
+		return nil, err
+	}
+
+	if store.values == nil {
+		store.values = template.StaticVariables{}
+	}
+
+	return store, nil
+}
+
+func (s *varsStore) Get(definition template.VariableDefinition) (interface{}, bool, error) {
+	if value, ok := s.values[definition.Name]; ok {
+		return value, true, nil
+	}
+
+	if definition.Type == "" {
+		return nil, false, nil
+	}
+
+	generate, ok := generators[definition.Type]
+	if !ok {
+		return nil, false, fmt.Errorf("cannot generate variable %q: unsupported type %q, expected one of certificate, password, rsa, ssh", definition.Name, definition.Type)
+	}
+
+	vars := s.vars
+	if vars == nil {
+		vars = s.values
+	}
+
+	value, err := generate(definition, vars)
+	if err != nil {
+		return nil, false, err
+	}
+
+	s.values[definition.Name] = value
+	s.generated = append(s.generated, definition.Name)
+
+	return value, true, nil
+}
+
+func (s *varsStore) List() ([]template.VariableDefinition, error) {
+	return s.values.List()
+}
+
+// save writes the values to the file when variables have been generated.
+// It does nothing without a vars store.
+func (s *varsStore) save() error {
+	if s == nil || len(s.generated) == 0 {
+		return nil
+	}
+
+	contents, err := yaml.Marshal(s.values)
+	if err != nil {
+		return fmt.Errorf("could not marshal vars store (%s): %s", s.path, err)
+	}
+
+	err = ioutil.WriteFile(s.path, contents, 0600)
+	if err != nil {
+		return fmt.Errorf("could not write vars store (%s): %s", s.path, err)
+	}
+
+	return nil
+}
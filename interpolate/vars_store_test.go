@@ -0,0 +1,352 @@
+package interpolate_test
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/om/interpolate"
+	"gopkg.in/yaml.v2"
+)
+
+var _ = Describe("VarsStore", func() {
+	var (
+		storeDir  string
+		storePath string
+	)
+
+	BeforeEach(func() {
+		var err error
+		storeDir, err = ioutil.TempDir("", "vars-store")
+		Expect(err).ToNot(HaveOccurred())
+
+		storePath = filepath.Join(storeDir, "creds.yml")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(storeDir)).To(Succeed())
+	})
+
+	readStore := func() map[string]interface{} {
+		contents, err := ioutil.ReadFile(storePath)
+		Expect(err).ToNot(HaveOccurred())
+
+		var values map[string]interface{}
+		Expect(yaml.Unmarshal(contents, &values)).To(Succeed())
+		return values
+	}
+
+	parseCertificate := func(value interface{}) *x509.Certificate {
+		block, _ := pem.Decode([]byte(value.(string)))
+		Expect(block).ToNot(BeNil())
+
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		Expect(err).ToNot(HaveOccurred())
+		return certificate
+	}
+
+	It("generates the missing variables and reuses them", func() {
+		template := writeFile(`
+password: ((admin-password))
+short: ((short-password))
+variables:
+- name: admin-password
+  type: password
+- name: short-password
+  type: password
+  options:
+    length: 8
+`)
+
+		contents, err := interpolate.Execute(interpolate.Options{
+			TemplateFile:  template,
+			VarsStore:     storePath,
+			ExpectAllKeys: true,
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		values := readStore()
+		Expect(values["admin-password"]).To(MatchRegexp(`^[a-z0-9]{20}$`))
+		Expect(values["short-password"]).To(MatchRegexp(`^[a-z0-9]{8}$`))
+		Expect(contents).To(MatchYAML("password: " + values["admin-password"].(string) + "\nshort: " + values["short-password"].(string)))
+
+		info, err := os.Stat(storePath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+		again, err := interpolate.Execute(interpolate.Options{
+			TemplateFile:  template,
+			VarsStore:     storePath,
+			ExpectAllKeys: true,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(again).To(Equal(contents))
+	})
+
+	It("does not generate variables that are provided elsewhere", func() {
+		contents, err := interpolate.Execute(interpolate.Options{
+			TemplateFile: writeFile(`
+password: ((admin-password))
+variables:
+- name: admin-password
+  type: password
+`),
+			Vars:          []string{"admin-password=provided"},
+			VarsStore:     storePath,
+			ExpectAllKeys: true,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(contents).To(MatchYAML(`password: provided`))
+		Expect(storePath).ToNot(BeAnExistingFile())
+	})
+
+	It("does not save the generated variables when the interpolation fails", func() {
+		_, err := interpolate.Execute(interpolate.Options{
+			TemplateFile: writeFile(`
+password: ((admin-password))
+missing: ((missing))
+variables:
+- name: admin-password
+  type: password
+`),
+			VarsStore:     storePath,
+			ExpectAllKeys: true,
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(storePath).ToNot(BeAnExistingFile())
+	})
+
+	It("does not save the generated variables when a variable is not strictly used", func() {
+		_, err := interpolate.Execute(interpolate.Options{
+			TemplateFile: writeFile(`
+password: ((admin-password))
+variables:
+- name: admin-password
+  type: password
+`),
+			Vars:              []string{"unused=value"},
+			VarsStore:         storePath,
+			ExpectAllKeys:     true,
+			ExpectAllVarsUsed: true,
+		})
+		Expect(err).To(MatchError(ContainSubstring(`unused "unused" from --var`)))
+		Expect(storePath).ToNot(BeAnExistingFile())
+	})
+
+	It("reports the variables that would be generated on a check, without saving them", func() {
+		Expect(ioutil.WriteFile(storePath, []byte(`existing: from-store`), 0600)).To(Succeed())
+
+		report, err := interpolate.Check(interpolate.Options{
+			TemplateFile: writeFile(`
+existing: ((existing))
+password: ((admin-password))
+variables:
+- name: admin-password
+  type: password
+`),
+			VarsStore: storePath,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Generated).To(Equal([]string{"admin-password"}))
+		Expect(report.HasProblems()).To(BeFalse())
+		Expect(readStore()).To(Equal(map[string]interface{}{"existing": "from-store"}))
+	})
+
+	It("uses values already in the store without a definition", func() {
+		Expect(ioutil.WriteFile(storePath, []byte(`existing: from-store`), 0600)).To(Succeed())
+
+		contents, err := interpolate.Execute(interpolate.Options{
+			TemplateFile:  writeFile(`value: ((existing))`),
+			VarsStore:     storePath,
+			ExpectAllKeys: true,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(contents).To(MatchYAML(`value: from-store`))
+	})
+
+	It("generates certificates signed by a ca", func() {
+		_, err := interpolate.Execute(interpolate.Options{
+			TemplateFile: writeFile(`
+ca: ((root-ca.certificate))
+certificate: ((server.certificate))
+variables:
+- name: root-ca
+  type: certificate
+  options:
+    is_ca: true
+    common_name: root-ca
+- name: server
+  type: certificate
+  options:
+    ca: root-ca
+    common_name: example.com
+    alternative_names: [example.com, 10.0.0.1]
+    extended_key_usage: [server_auth]
+`),
+			VarsStore:     storePath,
+			ExpectAllKeys: true,
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		values := readStore()
+		ca := values["root-ca"].(map[interface{}]interface{})
+		server := values["server"].(map[interface{}]interface{})
+
+		caCertificate := parseCertificate(ca["certificate"])
+		Expect(caCertificate.IsCA).To(BeTrue())
+		Expect(ca["ca"]).To(Equal(ca["certificate"]))
+
+		serverCertificate := parseCertificate(server["certificate"])
+		Expect(serverCertificate.IsCA).To(BeFalse())
+		Expect(serverCertificate.Subject.CommonName).To(Equal("example.com"))
+		Expect(serverCertificate.DNSNames).To(Equal([]string{"example.com"}))
+		Expect(serverCertificate.IPAddresses[0].String()).To(Equal("10.0.0.1"))
+		Expect(serverCertificate.ExtKeyUsage).To(Equal([]x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}))
+		Expect(serverCertificate.CheckSignatureFrom(caCertificate)).To(Succeed())
+		Expect(server["ca"]).To(Equal(ca["certificate"]))
+		Expect(server["private_key"]).To(ContainSubstring("BEGIN RSA PRIVATE KEY"))
+	})
+
+	It("signs certificates with a ca that is provided in a vars file", func() {
+		Expect(ioutil.WriteFile(storePath, []byte(""), 0600)).To(Succeed())
+
+		_, err := interpolate.Execute(interpolate.Options{
+			TemplateFile: writeFile(`
+variables:
+- name: provided-ca
+  type: certificate
+  options: {is_ca: true, common_name: provided}
+`),
+			VarsStore: storePath,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		caContents, err := yaml.Marshal(readStore())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.Remove(storePath)).To(Succeed())
+
+		_, err = interpolate.Execute(interpolate.Options{
+			TemplateFile: writeFile(`
+certificate: ((leaf.certificate))
+variables:
+- name: leaf
+  type: certificate
+  options: {ca: provided-ca, common_name: leaf}
+`),
+			VarsFiles:     []string{writeFile(string(caContents))},
+			VarsStore:     storePath,
+			ExpectAllKeys: true,
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		values := readStore()
+		Expect(values).ToNot(HaveKey("provided-ca"))
+		leaf := values["leaf"].(map[interface{}]interface{})
+		Expect(parseCertificate(leaf["certificate"]).Issuer.CommonName).To(Equal("provided"))
+	})
+
+	It("generates rsa and ssh keys", func() {
+		_, err := interpolate.Execute(interpolate.Options{
+			TemplateFile: writeFile(`
+variables:
+- name: jwt
+  type: rsa
+- name: jumpbox
+  type: ssh
+`),
+			VarsStore: storePath,
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		values := readStore()
+		jwt := values["jwt"].(map[interface{}]interface{})
+		Expect(jwt["private_key"]).To(ContainSubstring("BEGIN RSA PRIVATE KEY"))
+		Expect(jwt["public_key"]).To(ContainSubstring("BEGIN PUBLIC KEY"))
+
+		jumpbox := values["jumpbox"].(map[interface{}]interface{})
+		Expect(jumpbox["private_key"]).To(ContainSubstring("BEGIN RSA PRIVATE KEY"))
+		Expect(strings.HasPrefix(jumpbox["public_key"].(string), "ssh-rsa ")).To(BeTrue())
+		Expect(jumpbox["public_key_fingerprint"]).To(MatchRegexp(`^([0-9a-f]{2}:){15}[0-9a-f]{2}$`))
+	})
+
+	It("removes the variables section once every variable has been generated", func() {
+		contents, err := interpolate.Execute(interpolate.Options{
+			TemplateFile: writeFile(`
+name: some-name
+variables:
+- name: unused
+  type: password
+`),
+			VarsStore: storePath,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(contents).To(MatchYAML(`name: some-name`))
+	})
+
+	When("the definitions are invalid", func() {
+		It("errors for unsupported types", func() {
+			_, err := interpolate.Execute(interpolate.Options{
+				TemplateFile: writeFile(`
+variables:
+- name: some-value
+  type: user
+`),
+				VarsStore: storePath,
+			})
+			Expect(err).To(MatchError(ContainSubstring(`cannot generate variable "some-value": unsupported type "user", expected one of certificate, password, rsa, ssh`)))
+		})
+
+		It("errors when a certificate is neither a ca nor references one", func() {
+			_, err := interpolate.Execute(interpolate.Options{
+				TemplateFile: writeFile(`
+variables:
+- name: leaf
+  type: certificate
+  options: {common_name: leaf}
+`),
+				VarsStore: storePath,
+			})
+			Expect(err).To(MatchError(ContainSubstring(`certificate "leaf" must either reference a ca or be a ca (is_ca: true)`)))
+		})
+
+		It("errors when the referenced ca does not exist", func() {
+			_, err := interpolate.Execute(interpolate.Options{
+				TemplateFile: writeFile(`
+variables:
+- name: leaf
+  type: certificate
+  options: {ca: missing-ca}
+`),
+				VarsStore: storePath,
+			})
+			Expect(err).To(MatchError(ContainSubstring(`certificate "leaf": could not find ca "missing-ca", it must be defined before the certificates that reference it`)))
+		})
+
+		It("errors with unknown options", func() {
+			_, err := interpolate.Execute(interpolate.Options{
+				TemplateFile: writeFile(`
+variables:
+- name: some-password
+  type: password
+  options: {size: 10}
+`),
+				VarsStore: storePath,
+			})
+			Expect(err).To(MatchError(ContainSubstring(`invalid options for password "some-password"`)))
+		})
+	})
+
+	It("errors when the store cannot be read", func() {
+		Expect(ioutil.WriteFile(storePath, []byte(`{]`), 0600)).To(Succeed())
+
+		_, err := interpolate.Execute(interpolate.Options{
+			TemplateFile: writeFile(`value: ((value))`),
+			VarsStore:    storePath,
+		})
+		Expect(err).To(MatchError(ContainSubstring("could not unmarshal file (" + storePath + ")")))
+	})
+})
@@ -0,0 +1,120 @@
+package interpolate
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-cli/director/template"
+)
+
+// vaultSource reads variables from a Vault KV version 2 secrets engine.
+// Each variable is a secret at <mount>/<path>/<name>: a secret with only
+// a `value` key resolves to that value, any other secret resolves to
+// all of its keys (e.g. ((name.certificate))).
+type vaultSource struct {
+	client    *http.Client
+	address   string
+	mount     string
+	path      string
+	token     string
+	namespace string
+}
+
+func newVaultSource(params map[string]string, env map[string]string) (*vaultSource, error) {
+	if params["address"] == "" {
+		params["address"] = env["VAULT_ADDR"]
+	}
+
+	err := requireParams("vault", params, []string{"address", "mount", "path", "namespace", "skip-ssl-validation", "ca-cert"}, "address")
+	if err != nil {
+		return nil, err
+	}
+
+	token := env["VAULT_TOKEN"]
+	if token == "" {
+		return nil, fmt.Errorf("invalid vault vars source: VAULT_TOKEN must be set in the environment")
+	}
+
+	client, err := newHTTPClient(params)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vault vars source: %s", err)
+	}
+
+	mount := params["mount"]
+	if mount == "" {
+		mount = "secret"
+	}
+
+	namespace := params["namespace"]
+	if namespace == "" {
+		namespace = env["VAULT_NAMESPACE"]
+	}
+
+	return &vaultSource{
+		client:    client,
+		address:   strings.TrimSuffix(params["address"], "/"),
+		mount:     strings.Trim(mount, "/"),
+		path:      strings.Trim(params["path"], "/"),
+		token:     token,
+		namespace: namespace,
+	}, nil
+}
+
+func (v *vaultSource) Get(definition template.VariableDefinition) (interface{}, bool, error) {
+	secretPath := definition.Name
+	if v.path != "" {
+		secretPath = v.path + "/" + definition.Name
+	}
+
+	request, err := http.NewRequest("GET", fmt.Sprintf("%s/v1/%s/data/%s", v.address, v.mount, secretPath), nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not create request to vault: %s", err)
+	}
+	request.Header.Set("X-Vault-Token", v.token)
+	if v.namespace != "" {
+		request.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+
+	response, err := v.client.Do(request)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not reach vault at %s: %s", v.address, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("could not read %s/%s from vault: unexpected response %s", v.mount, secretPath, response.Status)
+	}
+
+	var secret struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&secret)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not decode %s/%s from vault: %s", v.mount, secretPath, err)
+	}
+
+	// a deleted secret version returns no data
+	if secret.Data.Data == nil {
+		return nil, false, nil
+	}
+
+	if value, ok := secret.Data.Data["value"]; ok && len(secret.Data.Data) == 1 {
+		converted, err := fromJSON(value)
+		return converted, true, err
+	}
+
+	converted, err := fromJSON(secret.Data.Data)
+	return converted, true, err
+}
+
+func (v *vaultSource) List() ([]template.VariableDefinition, error) {
+	return nil, nil
+}
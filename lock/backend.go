@@ -0,0 +1,306 @@
+package lock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/graymeta/stow"
+	"github.com/pivotal-cf/om/models"
+)
+
+const lockSuffix = ".lock"
+
+// removeGuardTimeout is how old the guard of a lock being removed is
+// when the om that created it has been killed before deleting it.
+const removeGuardTimeout = 10 * time.Second
+
+// Backend stores the locks, by name.
+type Backend interface {
+	// Create writes the lock, unless there is a lock of the same name already.
+	Create(lock models.Lock) (bool, error)
+	Read(name string) (models.Lock, bool, error)
+	// Write writes the lock, replacing the lock of the same name.
+	Write(lock models.Lock) error
+	Delete(name string) error
+	// Remove deletes the lock of the name of lock when it still has the ID of lock,
+	// and reports whether it has been deleted.
+	Remove(lock models.Lock) (bool, error)
+	List() ([]models.Lock, error)
+}
+
+type fileBackend struct {
+	dir string
+}
+
+// NewFileBackend stores the locks as files in dir, which is created if needed.
+// Creating a lock is atomic.
+func NewFileBackend(dir string) (Backend, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("could not create the lock directory: %s", err)
+	}
+
+	return fileBackend{dir: dir}, nil
+}
+
+func (f fileBackend) Create(lock models.Lock) (bool, error) {
+	temp, err := f.writeTemp(lock)
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(temp)
+
+	// unlike renaming, linking fails when the lock exists
+	err = os.Link(temp, f.path(lock.Name))
+	if os.IsExist(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+func (f fileBackend) Read(name string) (models.Lock, bool, error) {
+	contents, err := ioutil.ReadFile(f.path(name))
+	if os.IsNotExist(err) {
+		return models.Lock{}, false, nil
+	}
+	if err != nil {
+		return models.Lock{}, false, err
+	}
+
+	lock, err := decodeLock(name, contents)
+	return lock, err == nil, err
+}
+
+func (f fileBackend) Write(lock models.Lock) error {
+	temp, err := f.writeTemp(lock)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(temp, f.path(lock.Name))
+	if err != nil {
+		_ = os.Remove(temp)
+	}
+
+	return err
+}
+
+func (f fileBackend) Delete(name string) error {
+	err := os.Remove(f.path(name))
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return err
+}
+
+// Remove checks and deletes the lock while holding a guard file, which is created exclusively,
+// so that two om do not delete the lock that one of them has just created.
+// Another lock cannot be created between the check and the deletion, as the lock file exists.
+func (f fileBackend) Remove(lock models.Lock) (bool, error) {
+	guard := f.path(lock.Name) + ".remove"
+	for {
+		file, err := os.OpenFile(guard, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			_ = file.Close()
+			break
+		}
+		if !os.IsExist(err) {
+			return false, err
+		}
+
+		// another om holds the guard for an instant, unless it has been killed meanwhile
+		info, err := os.Stat(guard)
+		if err == nil && time.Since(info.ModTime()) > removeGuardTimeout {
+			_ = os.Remove(guard)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	defer os.Remove(guard)
+
+	current, ok, err := f.Read(lock.Name)
+	if err != nil || !ok || current.ID != lock.ID {
+		return false, err
+	}
+
+	return true, f.Delete(lock.Name)
+}
+
+func (f fileBackend) List() ([]models.Lock, error) {
+	paths, err := filepath.Glob(filepath.Join(f.dir, "*"+lockSuffix))
+	if err != nil {
+		return nil, err
+	}
+
+	locks := []models.Lock{}
+	for _, p := range paths {
+		lock, ok, err := f.Read(strings.TrimSuffix(filepath.Base(p), lockSuffix))
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			locks = append(locks, lock)
+		}
+	}
+
+	return locks, nil
+}
+
+func (f fileBackend) path(name string) string {
+	return filepath.Join(f.dir, name+lockSuffix)
+}
+
+func (f fileBackend) writeTemp(lock models.Lock) (string, error) {
+	file, err := ioutil.TempFile(f.dir, "."+lock.Name+lockSuffix+".tmp-")
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	err = json.NewEncoder(file).Encode(lock)
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return "", err
+	}
+
+	return file.Name(), nil
+}
+
+type stowBackend struct {
+	container stow.Container
+	prefix    string
+}
+
+// NewStowBackend stores the locks as objects of the container, under the path prefix.
+// Creating a lock is not atomic, and the Locker checks that a lock it has created has not been overwritten.
+func NewStowBackend(container stow.Container, prefix string) Backend {
+	return stowBackend{
+		container: container,
+		prefix:    strings.Trim(prefix, "/"),
+	}
+}
+
+func (s stowBackend) Create(lock models.Lock) (bool, error) {
+	_, ok, err := s.Read(lock.Name)
+	if err != nil || ok {
+		return false, err
+	}
+
+	return true, s.Write(lock)
+}
+
+func (s stowBackend) Read(name string) (models.Lock, bool, error) {
+	item, err := s.container.Item(s.key(name))
+	if err == stow.ErrNotFound {
+		return models.Lock{}, false, nil
+	}
+	if err != nil {
+		return models.Lock{}, false, err
+	}
+
+	reader, err := item.Open()
+	if err != nil {
+		return models.Lock{}, false, err
+	}
+	defer reader.Close()
+
+	contents, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return models.Lock{}, false, err
+	}
+
+	lock, err := decodeLock(name, contents)
+	return lock, err == nil, err
+}
+
+func (s stowBackend) Write(lock models.Lock) error {
+	contents, err := json.Marshal(lock)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.container.Put(s.key(lock.Name), bytes.NewReader(contents), int64(len(contents)), nil)
+	return err
+}
+
+func (s stowBackend) Delete(name string) error {
+	item, err := s.container.Item(s.key(name))
+	if err == stow.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.container.RemoveItem(item.ID())
+}
+
+// Remove is not atomic, like Create: the Locker checks the lock it creates after it.
+func (s stowBackend) Remove(lock models.Lock) (bool, error) {
+	current, ok, err := s.Read(lock.Name)
+	if err != nil || !ok || current.ID != lock.ID {
+		return false, err
+	}
+
+	return true, s.Delete(lock.Name)
+}
+
+func (s stowBackend) List() ([]models.Lock, error) {
+	prefix := s.prefix
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	var names []string
+	err := stow.Walk(s.container, prefix, 100, func(item stow.Item, err error) error {
+		if err != nil {
+			return err
+		}
+
+		key := filepath.ToSlash(item.Name())
+		if path.Dir(key) == path.Dir(s.key("")) && strings.HasSuffix(key, lockSuffix) {
+			names = append(names, strings.TrimSuffix(path.Base(key), lockSuffix))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	locks := []models.Lock{}
+	for _, name := range names {
+		lock, ok, err := s.Read(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			locks = append(locks, lock)
+		}
+	}
+
+	return locks, nil
+}
+
+func (s stowBackend) key(name string) string {
+	return path.Join(s.prefix, name+lockSuffix)
+}
+
+func decodeLock(name string, contents []byte) (models.Lock, error) {
+	var lock models.Lock
+	err := json.Unmarshal(contents, &lock)
+	if err != nil {
+		return models.Lock{}, fmt.Errorf("could not parse the lock %s: %s", name, err)
+	}
+
+	return lock, nil
+}
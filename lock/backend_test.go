@@ -0,0 +1,154 @@
+package lock_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/graymeta/stow"
+	_ "github.com/graymeta/stow/local"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/om/lock"
+	"github.com/pivotal-cf/om/models"
+)
+
+var _ = Describe("Backend", func() {
+	var (
+		dir     string
+		backend lock.Backend
+		now     time.Time
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "lock-backend")
+		Expect(err).ToNot(HaveOccurred())
+
+		now = time.Now().UTC().Truncate(time.Second)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	newLock := func(name, id string) models.Lock {
+		return models.Lock{
+			Name:       name,
+			ID:         id,
+			Holder:     "some-holder",
+			Command:    "apply-changes",
+			AcquiredAt: now,
+			ExpiresAt:  now.Add(time.Hour),
+		}
+	}
+
+	itStoresTheLocks := func() {
+		It("creates, reads, writes, lists and deletes the locks", func() {
+			_, ok, err := backend.Read("opsman")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ok).To(BeFalse())
+
+			created, err := backend.Create(newLock("opsman", "first-id"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(created).To(BeTrue())
+
+			created, err = backend.Create(newLock("opsman", "second-id"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(created).To(BeFalse())
+
+			stored, ok, err := backend.Read("opsman")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(stored).To(Equal(newLock("opsman", "first-id")))
+
+			renewed := newLock("opsman", "first-id")
+			renewed.ExpiresAt = now.Add(2 * time.Hour)
+			Expect(backend.Write(renewed)).To(Succeed())
+
+			stored, _, err = backend.Read("opsman")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored).To(Equal(renewed))
+
+			_, err = backend.Create(newLock("other-opsman", "other-id"))
+			Expect(err).ToNot(HaveOccurred())
+
+			locks, err := backend.List()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(locks).To(ConsistOf(renewed, newLock("other-opsman", "other-id")))
+
+			deleted, err := backend.Remove(newLock("opsman", "stale-id"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deleted).To(BeFalse())
+
+			stored, _, err = backend.Read("opsman")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored).To(Equal(renewed))
+
+			Expect(backend.Delete("opsman")).To(Succeed())
+			Expect(backend.Delete("opsman")).To(Succeed())
+
+			created, err = backend.Create(newLock("opsman", "stale-id"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(created).To(BeTrue())
+
+			deleted, err = backend.Remove(newLock("opsman", "stale-id"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deleted).To(BeTrue())
+
+			deleted, err = backend.Remove(newLock("opsman", "stale-id"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deleted).To(BeFalse())
+
+			locks, err = backend.List()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(locks).To(Equal([]models.Lock{newLock("other-opsman", "other-id")}))
+		})
+	}
+
+	Describe("NewFileBackend", func() {
+		BeforeEach(func() {
+			var err error
+			backend, err = lock.NewFileBackend(filepath.Join(dir, "locks"))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		itStoresTheLocks()
+
+		It("returns an error when a lock cannot be parsed", func() {
+			Expect(ioutil.WriteFile(filepath.Join(dir, "locks", "opsman.lock"), []byte("not json"), 0644)).To(Succeed())
+
+			_, _, err := backend.Read("opsman")
+			Expect(err).To(MatchError(ContainSubstring("could not parse the lock opsman")))
+		})
+
+		When("the directory cannot be created", func() {
+			It("returns an error", func() {
+				file := filepath.Join(dir, "file")
+				Expect(ioutil.WriteFile(file, nil, 0644)).To(Succeed())
+
+				_, err := lock.NewFileBackend(filepath.Join(file, "locks"))
+				Expect(err).To(MatchError(ContainSubstring("could not create the lock directory")))
+			})
+		})
+	})
+
+	Describe("NewStowBackend", func() {
+		BeforeEach(func() {
+			location, err := stow.Dial("local", stow.ConfigMap{"path": dir})
+			Expect(err).ToNot(HaveOccurred())
+
+			container, err := location.CreateContainer("bucket")
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = container.Put("other-prefix/opsman.lock", strings.NewReader("{}"), 2, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			backend = lock.NewStowBackend(container, "/locks/")
+		})
+
+		itStoresTheLocks()
+	})
+})
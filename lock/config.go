@@ -0,0 +1,197 @@
+package lock
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/user"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pivotal-cf/om/interpolate"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	defaultTTL    = time.Hour
+	defaultPrefix = "om-locks"
+)
+
+var validName = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// Config is the lock config file given with the global --lock.
+type Config struct {
+	// Backend is where the locks are stored: file, s3, gcs or azure.
+	Backend string
+	// Name identifies the Ops Manager: the commands holding a lease
+	// with the same name exclude each other.
+	Name string
+	// Holder identifies this om in the lock.
+	Holder string
+	// TTL is how long a lease lasts if it is not renewed, e.g. when om is killed.
+	TTL time.Duration
+	// Wait is how long to wait for a lease held by another om.
+	Wait time.Duration
+	// Path is the directory of the file backend, or the path prefix of the blobstore backends.
+	Path string
+
+	Bucket                string
+	S3AccessKeyID         string
+	S3SecretAccessKey     string
+	S3RegionName          string
+	S3Endpoint            string
+	S3DisableSSL          bool
+	S3EnableV2Signing     bool
+	S3AuthType            string
+	GCSServiceAccountJSON string
+	GCSProjectID          string
+	AzureStorageAccount   string
+	AzureKey              string
+}
+
+type rawConfig struct {
+	Backend string `yaml:"backend"`
+	Name    string `yaml:"name"`
+	Holder  string `yaml:"holder"`
+	TTL     string `yaml:"ttl"`
+	Wait    string `yaml:"wait"`
+	Path    string `yaml:"path"`
+
+	Bucket                string `yaml:"blobstore-bucket"`
+	S3AccessKeyID         string `yaml:"s3-access-key-id"`
+	S3SecretAccessKey     string `yaml:"s3-secret-access-key"`
+	S3RegionName          string `yaml:"s3-region-name"`
+	S3Endpoint            string `yaml:"s3-endpoint"`
+	S3DisableSSL          bool   `yaml:"s3-disable-ssl"`
+	S3EnableV2Signing     bool   `yaml:"s3-enable-v2-signing"`
+	S3AuthType            string `yaml:"s3-auth-type"`
+	GCSServiceAccountJSON string `yaml:"gcs-service-account-json"`
+	GCSProjectID          string `yaml:"gcs-project-id"`
+	AzureStorageAccount   string `yaml:"azure-storage-account"`
+	AzureKey              string `yaml:"azure-storage-key"`
+}
+
+// LoadConfig reads the lock config file, interpolated like the env file.
+// The name of the lock defaults to the host of the target.
+func LoadConfig(path, target string, environFunc func() []string, varsEnvs []string) (Config, error) {
+	_, err := os.Stat(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("lock config file does not exist: %s", err)
+	}
+
+	contents, err := interpolate.Execute(interpolate.Options{
+		TemplateFile:  path,
+		EnvironFunc:   environFunc,
+		VarsEnvs:      varsEnvs,
+		ExpectAllKeys: false,
+	})
+	if err != nil {
+		return Config{}, err
+	}
+
+	var raw rawConfig
+	err = yaml.UnmarshalStrict(contents, &raw)
+	if err != nil {
+		return Config{}, fmt.Errorf("could not parse the lock config file: %s", err)
+	}
+
+	config := Config{
+		Backend:               raw.Backend,
+		Name:                  raw.Name,
+		Holder:                raw.Holder,
+		TTL:                   defaultTTL,
+		Path:                  raw.Path,
+		Bucket:                raw.Bucket,
+		S3AccessKeyID:         raw.S3AccessKeyID,
+		S3SecretAccessKey:     raw.S3SecretAccessKey,
+		S3RegionName:          raw.S3RegionName,
+		S3Endpoint:            raw.S3Endpoint,
+		S3DisableSSL:          raw.S3DisableSSL,
+		S3EnableV2Signing:     raw.S3EnableV2Signing,
+		S3AuthType:            raw.S3AuthType,
+		GCSServiceAccountJSON: raw.GCSServiceAccountJSON,
+		GCSProjectID:          raw.GCSProjectID,
+		AzureStorageAccount:   raw.AzureStorageAccount,
+		AzureKey:              raw.AzureKey,
+	}
+
+	if raw.TTL != "" {
+		config.TTL, err = time.ParseDuration(raw.TTL)
+		if err != nil || config.TTL <= 0 {
+			return Config{}, fmt.Errorf("found problem in the lock config file: ttl %q is not a positive duration (e.g. 1h)", raw.TTL)
+		}
+	}
+
+	if raw.Wait != "" {
+		config.Wait, err = time.ParseDuration(raw.Wait)
+		if err != nil || config.Wait < 0 {
+			return Config{}, fmt.Errorf("found problem in the lock config file: wait %q is not a duration (e.g. 10m)", raw.Wait)
+		}
+	}
+
+	switch config.Backend {
+	case "file":
+		if config.Path == "" {
+			return Config{}, fmt.Errorf("found problem in the lock config file: the file backend requires a path")
+		}
+	case "s3", "gcs", "azure":
+		if config.Path == "" {
+			config.Path = defaultPrefix
+		}
+	case "":
+		return Config{}, fmt.Errorf("found problem in the lock config file: backend is required (options: file,s3,gcs,azure)")
+	default:
+		return Config{}, fmt.Errorf("found problem in the lock config file: unsupported backend %q (options: file,s3,gcs,azure)", config.Backend)
+	}
+
+	if config.Name == "" {
+		config.Name = nameFromTarget(target)
+		if config.Name == "" {
+			return Config{}, fmt.Errorf("found problem in the lock config file: name is required when there is no target")
+		}
+	}
+
+	if !validName.MatchString(config.Name) {
+		return Config{}, fmt.Errorf("found problem in the lock config file: name %q may only contain letters, digits, '.', '_' and '-'", config.Name)
+	}
+
+	if config.Holder == "" {
+		config.Holder = defaultHolder()
+	}
+
+	return config, nil
+}
+
+// nameFromTarget returns the host of the target, with the characters
+// that cannot be part of a lock name replaced.
+func nameFromTarget(target string) string {
+	if target == "" {
+		return ""
+	}
+
+	if !strings.Contains(target, "://") {
+		target = "https://" + target
+	}
+
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+
+	return regexp.MustCompile(`[^A-Za-z0-9._-]`).ReplaceAllString(u.Host, "_")
+}
+
+func defaultHolder() string {
+	username := "unknown"
+	if current, err := user.Current(); err == nil {
+		username = current.Username
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return fmt.Sprintf("%s@%s (pid %d)", username, hostname, os.Getpid())
+}
@@ -0,0 +1,103 @@
+package lock_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/om/lock"
+)
+
+var _ = Describe("LoadConfig", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "lock-config")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	writeConfig := func(contents string) string {
+		path := filepath.Join(dir, "lock.yml")
+		Expect(ioutil.WriteFile(path, []byte(contents), 0600)).To(Succeed())
+		return path
+	}
+
+	environ := func() []string {
+		return []string{"OM_VAR_secret_key=some-secret-key"}
+	}
+
+	It("loads the config, interpolated with the vars of the environment", func() {
+		path := writeConfig(`---
+backend: s3
+name: production
+holder: ci
+ttl: 30m
+wait: 10m
+blobstore-bucket: locks
+s3-access-key-id: some-key-id
+s3-secret-access-key: ((secret_key))
+s3-region-name: us-east-1
+`)
+
+		config, err := lock.LoadConfig(path, "https://opsman.example.com", environ, []string{"OM_VAR"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config).To(Equal(lock.Config{
+			Backend:           "s3",
+			Name:              "production",
+			Holder:            "ci",
+			TTL:               30 * time.Minute,
+			Wait:              10 * time.Minute,
+			Path:              "om-locks",
+			Bucket:            "locks",
+			S3AccessKeyID:     "some-key-id",
+			S3SecretAccessKey: "some-secret-key",
+			S3RegionName:      "us-east-1",
+		}))
+	})
+
+	It("defaults the name to the host of the target, the holder and the ttl", func() {
+		path := writeConfig("backend: file\npath: /tmp/locks\n")
+
+		config, err := lock.LoadConfig(path, "https://opsman.example.com:8443", environ, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config.Name).To(Equal("opsman.example.com_8443"))
+		Expect(config.Holder).To(MatchRegexp(`@.* \(pid \d+\)$`))
+		Expect(config.TTL).To(Equal(time.Hour))
+		Expect(config.Wait).To(BeZero())
+
+		config, err = lock.LoadConfig(path, "opsman.example.com", environ, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config.Name).To(Equal("opsman.example.com"))
+	})
+
+	DescribeTable("returns an error when the config is not valid",
+		func(contents, target, message string) {
+			path := writeConfig(contents)
+
+			_, err := lock.LoadConfig(path, target, environ, nil)
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("unknown key", "backend: file\npath: /tmp\nunknown: value\n", "opsman", "could not parse the lock config file"),
+		Entry("no backend", "path: /tmp\n", "opsman", "backend is required"),
+		Entry("unknown backend", "backend: nfs\n", "opsman", `unsupported backend "nfs"`),
+		Entry("file backend without a path", "backend: file\n", "opsman", "the file backend requires a path"),
+		Entry("no name nor target", "backend: file\npath: /tmp\n", "", "name is required when there is no target"),
+		Entry("invalid name", "backend: file\npath: /tmp\nname: prod/east\n", "opsman", `name "prod/east" may only contain`),
+		Entry("invalid ttl", "backend: file\npath: /tmp\nttl: forever\n", "opsman", `ttl "forever" is not a positive duration`),
+		Entry("invalid wait", "backend: file\npath: /tmp\nwait: -1m\n", "opsman", `wait "-1m" is not a duration`),
+	)
+
+	It("returns an error when the file does not exist", func() {
+		_, err := lock.LoadConfig(filepath.Join(dir, "missing.yml"), "opsman", environ, nil)
+		Expect(err).To(MatchError(ContainSubstring("lock config file does not exist")))
+	})
+})
@@ -0,0 +1,333 @@
+package lock
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/graymeta/stow"
+	"github.com/graymeta/stow/google"
+	"github.com/pivotal-cf/om/models"
+	"github.com/pivotal-cf/om/stowconfig"
+	storage "google.golang.org/api/storage/v1beta2"
+)
+
+// blobstoreSettle is how long the Locker waits after creating a lock in a blobstore
+// before checking that another om has not overwritten it.
+const blobstoreSettle = 2 * time.Second
+
+// Locker acquires, lists and breaks the leases on the Ops Manager.
+type Locker struct {
+	backend Backend
+	config  Config
+	settle  time.Duration
+
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// NewLocker uses the backend to store the locks. When creating a lock in the backend
+// is not atomic, settle is how long to wait before checking a created lock.
+func NewLocker(backend Backend, config Config, settle time.Duration) *Locker {
+	return &Locker{
+		backend: backend,
+		config:  config,
+		settle:  settle,
+		now:     time.Now,
+		sleep:   time.Sleep,
+	}
+}
+
+// New returns the Locker of the backend of the config.
+func New(config Config) (*Locker, error) {
+	if config.Backend == "file" {
+		backend, err := NewFileBackend(config.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewLocker(backend, config, 0), nil
+	}
+
+	var (
+		kind       string
+		stowConfig stow.ConfigMap
+		err        error
+	)
+
+	switch config.Backend {
+	case "s3":
+		kind = "s3"
+		stowConfig, err = stowconfig.S3(stowconfig.S3Configuration{
+			Bucket:          config.Bucket,
+			AccessKeyID:     config.S3AccessKeyID,
+			SecretAccessKey: config.S3SecretAccessKey,
+			RegionName:      config.S3RegionName,
+			Endpoint:        config.S3Endpoint,
+			DisableSSL:      config.S3DisableSSL,
+			EnableV2Signing: config.S3EnableV2Signing,
+			AuthType:        config.S3AuthType,
+		})
+	case "gcs":
+		kind = google.Kind
+		stowConfig, err = stowconfig.GCS(stowconfig.GCSConfiguration{
+			Bucket:             config.Bucket,
+			ServiceAccountJSON: config.GCSServiceAccountJSON,
+			ProjectID:          config.GCSProjectID,
+		}, storage.DevstorageReadWriteScope)
+	case "azure":
+		kind = "azure"
+		stowConfig, err = stowconfig.Azure(stowconfig.AzureConfiguration{
+			StorageAccount: config.AzureStorageAccount,
+			Key:            config.AzureKey,
+			Container:      config.Bucket,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported lock backend %q", config.Backend)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not configure the %s lock backend: %s", config.Backend, err)
+	}
+
+	location, err := stow.Dial(kind, stowConfig)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to the %s lock backend: %s", config.Backend, err)
+	}
+
+	container, err := location.Container(config.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("could not find the bucket %s of the %s lock backend: %s", config.Bucket, config.Backend, err)
+	}
+
+	return NewLocker(NewStowBackend(container, config.Path), config, blobstoreSettle), nil
+}
+
+// Acquire holds the lease on the Ops Manager for the command, waiting for
+// the lease of another om up to the wait of the config. An expired lease is broken.
+func (l *Locker) Acquire(command string) (*Lease, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, fmt.Errorf("could not create the lock %s: %s", l.config.Name, err)
+	}
+
+	deadline := l.now().Add(l.config.Wait)
+	for {
+		acquired, held, err := l.tryAcquire(id, command)
+		if err != nil {
+			return nil, fmt.Errorf("could not acquire the lock %s: %s", l.config.Name, err)
+		}
+
+		if acquired {
+			return &Lease{locker: l, id: id, stop: make(chan struct{})}, nil
+		}
+
+		// held is nil when the lock has been broken while it was created:
+		// it is tried again like a held lock, so that it does not spin past the wait
+		remaining := deadline.Sub(l.now())
+		if remaining <= 0 {
+			if held == nil {
+				return nil, fmt.Errorf("could not acquire the lock %s: it has been broken every time it was created", l.config.Name)
+			}
+
+			return nil, fmt.Errorf(
+				"the lock %s is held by %s (%s) since %s, until %s: wait for it, or break it with om locks --break %s",
+				held.Name,
+				held.Holder,
+				held.Command,
+				held.AcquiredAt.Format(time.RFC3339),
+				held.ExpiresAt.Format(time.RFC3339),
+				held.Name,
+			)
+		}
+
+		if remaining > time.Second {
+			remaining = time.Second
+		}
+		l.sleep(remaining)
+	}
+}
+
+// tryAcquire creates the lock with the id, or returns the lock held by another om.
+func (l *Locker) tryAcquire(id, command string) (bool, *models.Lock, error) {
+	name := l.config.Name
+
+	existing, ok, err := l.backend.Read(name)
+	if err != nil {
+		return false, nil, err
+	}
+
+	if ok {
+		if l.now().Before(existing.ExpiresAt) {
+			return false, &existing, nil
+		}
+
+		// another om may have broken the expired lock and created its own in the meantime,
+		// which is not deleted
+		_, err = l.backend.Remove(existing)
+		if err != nil {
+			return false, nil, err
+		}
+	}
+
+	now := l.now().UTC()
+	created, err := l.backend.Create(models.Lock{
+		Name:       name,
+		ID:         id,
+		Holder:     l.config.Holder,
+		Command:    command,
+		AcquiredAt: now,
+		ExpiresAt:  now.Add(l.config.TTL),
+	})
+	if err != nil {
+		return false, nil, err
+	}
+
+	if created && l.settle > 0 {
+		l.sleep(l.settle)
+	}
+
+	current, ok, err := l.backend.Read(name)
+	if err != nil {
+		return false, nil, err
+	}
+
+	if !ok {
+		return false, nil, nil
+	}
+
+	if current.ID != id {
+		return false, &current, nil
+	}
+
+	return true, nil, nil
+}
+
+// List returns the locks of the backend, marking the expired ones.
+func (l *Locker) List() ([]models.Lock, error) {
+	locks, err := l.backend.List()
+	if err != nil {
+		return nil, fmt.Errorf("could not list the locks: %s", err)
+	}
+
+	now := l.now()
+	for i := range locks {
+		locks[i].Expired = !now.Before(locks[i].ExpiresAt)
+	}
+
+	return locks, nil
+}
+
+// Break deletes the lock, whoever holds it.
+func (l *Locker) Break(name string) error {
+	_, ok, err := l.backend.Read(name)
+	if err != nil {
+		return fmt.Errorf("could not break the lock %s: %s", name, err)
+	}
+
+	if !ok {
+		return fmt.Errorf("could not break the lock %s: there is no such lock", name)
+	}
+
+	err = l.backend.Delete(name)
+	if err != nil {
+		return fmt.Errorf("could not break the lock %s: %s", name, err)
+	}
+
+	return nil
+}
+
+// Lease is a lock held by this om.
+type Lease struct {
+	locker *Locker
+	id     string
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     sync.WaitGroup
+}
+
+// KeepAlive renews the lease every third of its TTL until it is released,
+// and writes a warning when the lease has been lost.
+func (l *Lease) KeepAlive(warnings io.Writer) {
+	interval := l.locker.config.TTL / 3
+
+	l.done.Add(1)
+	go func() {
+		defer l.done.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-l.stop:
+				return
+			case <-ticker.C:
+				err := l.renew()
+				if err != nil {
+					fmt.Fprintf(warnings, "WARNING: %s\n", err)
+				}
+			}
+		}
+	}()
+}
+
+func (l *Lease) renew() error {
+	name := l.locker.config.Name
+
+	current, ok, err := l.locker.backend.Read(name)
+	if err != nil {
+		return fmt.Errorf("could not renew the lock %s: %s", name, err)
+	}
+
+	if !ok || current.ID != l.id {
+		return fmt.Errorf("the lock %s has been broken, and is no longer held by this om", name)
+	}
+
+	current.ExpiresAt = l.locker.now().UTC().Add(l.locker.config.TTL)
+	err = l.locker.backend.Write(current)
+	if err != nil {
+		return fmt.Errorf("could not renew the lock %s: %s", name, err)
+	}
+
+	// another om may have broken the lock between the read and the write
+	renewed, ok, err := l.locker.backend.Read(name)
+	if err != nil {
+		return fmt.Errorf("could not renew the lock %s: %s", name, err)
+	}
+
+	if !ok || renewed.ID != l.id {
+		return fmt.Errorf("the lock %s has been broken, and is no longer held by this om", name)
+	}
+
+	return nil
+}
+
+// Release stops renewing the lease, and deletes the lock unless it has been broken.
+func (l *Lease) Release() error {
+	l.stopOnce.Do(func() { close(l.stop) })
+	l.done.Wait()
+
+	name := l.locker.config.Name
+
+	// the lock is left alone when another om has broken it and created its own
+	_, err := l.locker.backend.Remove(models.Lock{Name: name, ID: l.id})
+	if err != nil {
+		return fmt.Errorf("could not release the lock %s: %s", name, err)
+	}
+
+	return nil
+}
+
+func newID() (string, error) {
+	id := make([]byte, 16)
+	_, err := rand.Read(id)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(id), nil
+}
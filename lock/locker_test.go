@@ -0,0 +1,304 @@
+package lock_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/om/lock"
+	"github.com/pivotal-cf/om/models"
+)
+
+var _ = Describe("Locker", func() {
+	var (
+		dir     string
+		backend lock.Backend
+		config  lock.Config
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "locker")
+		Expect(err).ToNot(HaveOccurred())
+
+		backend, err = lock.NewFileBackend(dir)
+		Expect(err).ToNot(HaveOccurred())
+
+		config = lock.Config{
+			Backend: "file",
+			Path:    dir,
+			Name:    "opsman",
+			Holder:  "some-holder",
+			TTL:     time.Hour,
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	Describe("Acquire", func() {
+		It("holds the lease until it is released", func() {
+			locker := lock.NewLocker(backend, config, 0)
+
+			lease, err := locker.Acquire("apply-changes")
+			Expect(err).ToNot(HaveOccurred())
+
+			held, ok, err := backend.Read("opsman")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(held.Holder).To(Equal("some-holder"))
+			Expect(held.Command).To(Equal("apply-changes"))
+			Expect(held.ExpiresAt.Sub(held.AcquiredAt)).To(Equal(time.Hour))
+
+			other := config
+			other.Holder = "other-holder"
+			_, err = lock.NewLocker(backend, other, 0).Acquire("configure-product")
+			Expect(err).To(MatchError(ContainSubstring("the lock opsman is held by some-holder (apply-changes) since")))
+			Expect(err).To(MatchError(ContainSubstring("om locks --break opsman")))
+
+			Expect(lease.Release()).To(Succeed())
+
+			_, ok, err = backend.Read("opsman")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ok).To(BeFalse())
+
+			lease, err = lock.NewLocker(backend, other, 0).Acquire("configure-product")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(lease.Release()).To(Succeed())
+		})
+
+		It("waits for the lease held by another om", func() {
+			lease, err := lock.NewLocker(backend, config, 0).Acquire("apply-changes")
+			Expect(err).ToNot(HaveOccurred())
+
+			go func() {
+				defer GinkgoRecover()
+
+				time.Sleep(500 * time.Millisecond)
+				Expect(lease.Release()).To(Succeed())
+			}()
+
+			waiting := config
+			waiting.Wait = 10 * time.Second
+			waitingLease, err := lock.NewLocker(backend, waiting, 0).Acquire("configure-product")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(waitingLease.Release()).To(Succeed())
+		})
+
+		It("breaks an expired lease", func() {
+			Expect(backend.Write(models.Lock{
+				Name:       "opsman",
+				ID:         "expired-id",
+				Holder:     "killed-holder",
+				AcquiredAt: time.Now().Add(-2 * time.Hour),
+				ExpiresAt:  time.Now().Add(-time.Hour),
+			})).To(Succeed())
+
+			lease, err := lock.NewLocker(backend, config, 0).Acquire("apply-changes")
+			Expect(err).ToNot(HaveOccurred())
+
+			held, _, err := backend.Read("opsman")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(held.Holder).To(Equal("some-holder"))
+
+			Expect(lease.Release()).To(Succeed())
+		})
+
+		It("lets a single om break an expired lease when several race for it", func() {
+			for round := 0; round < 20; round++ {
+				Expect(backend.Write(models.Lock{
+					Name:       "opsman",
+					ID:         "expired-id",
+					Holder:     "killed-holder",
+					AcquiredAt: time.Now().Add(-2 * time.Hour),
+					ExpiresAt:  time.Now().Add(-time.Hour),
+				})).To(Succeed())
+
+				const racers = 4
+				var waiting [2]sync.WaitGroup
+				waiting[0].Add(racers)
+				waiting[1].Add(racers)
+
+				var racing sync.WaitGroup
+
+				leases := make(chan *lock.Lease, racers)
+				for i := 0; i < racers; i++ {
+					racing.Add(1)
+					go func(i int) {
+						defer racing.Done()
+
+						racer := config
+						racer.Holder = fmt.Sprintf("holder-%d", i)
+						racer.Wait = 50 * time.Millisecond
+						lease, err := lock.NewLocker(&racingBackend{Backend: backend, racers: &waiting, delay: time.Duration(i) * 10 * time.Millisecond}, racer, 0).Acquire("apply-changes")
+						if err == nil {
+							leases <- lease
+						}
+					}(i)
+				}
+				racing.Wait()
+				close(leases)
+
+				Expect(leases).To(HaveLen(1))
+
+				held, ok, err := backend.Read("opsman")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(ok).To(BeTrue())
+				Expect(held.ID).ToNot(Equal("expired-id"))
+
+				Expect((<-leases).Release()).To(Succeed())
+			}
+		})
+
+		It("gives up after the wait when the created lock keeps being broken", func() {
+			config.Wait = 50 * time.Millisecond
+			_, err := lock.NewLocker(vanishingBackend{backend}, config, 0).Acquire("apply-changes")
+			Expect(err).To(MatchError("could not acquire the lock opsman: it has been broken every time it was created"))
+		})
+
+		It("checks the created lock after the settle time", func() {
+			lease, err := lock.NewLocker(backend, config, 10*time.Millisecond).Acquire("apply-changes")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(lease.Release()).To(Succeed())
+		})
+	})
+
+	Describe("Lease", func() {
+		It("renews the lease until it is released", func() {
+			config.TTL = 300 * time.Millisecond
+			lease, err := lock.NewLocker(backend, config, 0).Acquire("apply-changes")
+			Expect(err).ToNot(HaveOccurred())
+
+			acquired, _, err := backend.Read("opsman")
+			Expect(err).ToNot(HaveOccurred())
+
+			warnings := &bytes.Buffer{}
+			lease.KeepAlive(warnings)
+
+			Eventually(func() time.Time {
+				held, _, err := backend.Read("opsman")
+				Expect(err).ToNot(HaveOccurred())
+				return held.ExpiresAt
+			}).Should(BeTemporally(">", acquired.ExpiresAt))
+
+			Expect(lease.Release()).To(Succeed())
+			Expect(warnings.String()).To(BeEmpty())
+		})
+
+		It("warns when the lease has been broken, and does not release the lock of another om", func() {
+			config.TTL = 300 * time.Millisecond
+			locker := lock.NewLocker(backend, config, 0)
+			lease, err := locker.Acquire("apply-changes")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(locker.Break("opsman")).To(Succeed())
+
+			other := config
+			other.TTL = time.Hour
+			other.Holder = "other-holder"
+			_, err = lock.NewLocker(backend, other, 0).Acquire("configure-product")
+			Expect(err).ToNot(HaveOccurred())
+
+			warnings := &bytes.Buffer{}
+			lease.KeepAlive(warnings)
+			time.Sleep(250 * time.Millisecond)
+
+			Expect(lease.Release()).To(Succeed())
+			Expect(warnings.String()).To(ContainSubstring("WARNING: the lock opsman has been broken"))
+
+			held, ok, err := backend.Read("opsman")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(held.Holder).To(Equal("other-holder"))
+		})
+	})
+
+	Describe("List", func() {
+		It("lists the locks, marking the expired ones", func() {
+			expired := models.Lock{
+				Name:      "expired",
+				ID:        "expired-id",
+				ExpiresAt: time.Now().Add(-time.Minute).UTC().Truncate(time.Second),
+			}
+			Expect(backend.Write(expired)).To(Succeed())
+
+			lease, err := lock.NewLocker(backend, config, 0).Acquire("apply-changes")
+			Expect(err).ToNot(HaveOccurred())
+			defer lease.Release()
+
+			locks, err := lock.NewLocker(backend, config, 0).List()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(locks).To(HaveLen(2))
+
+			expired.Expired = true
+			Expect(locks[0]).To(Equal(expired))
+			Expect(locks[1].Name).To(Equal("opsman"))
+			Expect(locks[1].Expired).To(BeFalse())
+		})
+	})
+
+	Describe("Break", func() {
+		It("deletes the lock", func() {
+			locker := lock.NewLocker(backend, config, 0)
+			_, err := locker.Acquire("apply-changes")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(locker.Break("opsman")).To(Succeed())
+
+			_, ok, err := backend.Read("opsman")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+
+		It("returns an error when there is no such lock", func() {
+			err := lock.NewLocker(backend, config, 0).Break("missing")
+			Expect(err).To(MatchError("could not break the lock missing: there is no such lock"))
+		})
+	})
+
+	Describe("New", func() {
+		It("returns an error when the blobstore config is not valid", func() {
+			_, err := lock.New(lock.Config{Backend: "s3", Name: "opsman"})
+			Expect(err).To(MatchError(ContainSubstring("could not configure the s3 lock backend")))
+		})
+	})
+})
+
+// vanishingBackend loses every lock, as if another om broke it right after it was created.
+type vanishingBackend struct {
+	lock.Backend
+}
+
+func (vanishingBackend) Read(string) (models.Lock, bool, error) {
+	return models.Lock{}, false, nil
+}
+
+// racingBackend makes the racing om wait for each other at their first two reads,
+// then go on one after the other, like a slow om racing with a fast one.
+type racingBackend struct {
+	lock.Backend
+	racers *[2]sync.WaitGroup
+	delay  time.Duration
+	reads  int
+}
+
+func (r *racingBackend) Read(name string) (models.Lock, bool, error) {
+	lock, ok, err := r.Backend.Read(name)
+
+	if r.reads < len(r.racers) {
+		r.racers[r.reads].Done()
+		r.racers[r.reads].Wait()
+		if r.reads == 1 {
+			time.Sleep(r.delay)
+		}
+	}
+	r.reads++
+
+	return lock, ok, err
+}
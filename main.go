@@ -1,12 +1,20 @@
 package main
 
 import (
+	"crypto/tls"
 	"fmt"
+	"io/ioutil"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"reflect"
 	"regexp"
 	"strings"
+	"syscall"
 
 	"github.com/pivotal-cf/om/interpolate"
 
@@ -15,13 +23,16 @@ import (
 	"github.com/olekukonko/tablewriter"
 	"github.com/pivotal-cf/om/renderers"
 	"github.com/pivotal/uilive"
+	"golang.org/x/crypto/ssh"
 	"gopkg.in/yaml.v2"
 
 	"github.com/pivotal-cf/jhanda"
 	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/audit"
 	"github.com/pivotal-cf/om/commands"
 	"github.com/pivotal-cf/om/extractor"
 	"github.com/pivotal-cf/om/formcontent"
+	"github.com/pivotal-cf/om/lock"
 	"github.com/pivotal-cf/om/network"
 	"github.com/pivotal-cf/om/presenters"
 	"github.com/pivotal-cf/om/progress"
@@ -38,6 +49,7 @@ type httpClient interface {
 }
 
 type options struct {
+	AuditLog             string `yaml:"-"                                long:"audit-log"             env:"OM_AUDIT_LOG"                           description:"append an audit record of every request to the Ops Manager that is not a GET request to this file, or to syslog with 'syslog'"`
 	CACert               string `yaml:"ca-cert" long:"ca-cert" env:"OM_CA_CERT" description:"OpsManager CA certificate path or value"`
 	ClientID             string `yaml:"client-id"             short:"c"  long:"client-id"             env:"OM_CLIENT_ID"                           description:"Client ID for the Ops Manager VM (not required for unauthenticated commands)"`
 	ClientSecret         string `yaml:"client-secret"         short:"s"  long:"client-secret"         env:"OM_CLIENT_SECRET"                       description:"Client Secret for the Ops Manager VM (not required for unauthenticated commands)"`
@@ -45,9 +57,11 @@ type options struct {
 	DecryptionPassphrase string `yaml:"decryption-passphrase" short:"d"  long:"decryption-passphrase" env:"OM_DECRYPTION_PASSPHRASE"             description:"Passphrase to decrypt the installation if the Ops Manager VM has been rebooted (optional for most commands)"`
 	Env                  string `                             short:"e"  long:"env"                                                              description:"env file with login credentials"`
 	Help                 bool   `                             short:"h"  long:"help"                                             default:"false" description:"prints this usage information"`
+	Lock                 string `yaml:"-"                                long:"lock"                  env:"OM_LOCK"                                description:"lock config file: hold a lease on the Ops Manager for the whole command, so that two om do not change it at the same time"`
 	Password             string `yaml:"password"              short:"p"  long:"password"              env:"OM_PASSWORD"                            description:"admin password for the Ops Manager VM (not required for unauthenticated commands)"`
 	RequestTimeout       int    `yaml:"request-timeout"       short:"r"  long:"request-timeout"       env:"OM_REQUEST_TIMEOUT"     default:"1800"  description:"timeout in seconds for HTTP requests to Ops Manager"`
 	SkipSSLValidation    bool   `yaml:"skip-ssl-validation"   short:"k"  long:"skip-ssl-validation"   env:"OM_SKIP_SSL_VALIDATION" default:"false" description:"skip ssl certificate validation during http requests"`
+	Strict               bool   `yaml:"-"                                long:"strict"                env:"OM_STRICT"              default:"false" description:"fail when a variable of a config file is missing or a provided variable is not used"`
 	Target               string `yaml:"target"                short:"t"  long:"target"                env:"OM_TARGET"                              description:"location of the Ops Manager VM"`
 	Trace                bool   `yaml:"trace"                 short:"tr" long:"trace"                 env:"OM_TRACE"                               description:"prints HTTP requests and response payloads"`
 	Username             string `yaml:"username"              short:"u"  long:"username"              env:"OM_USERNAME"                            description:"admin username for the Ops Manager VM (not required for unauthenticated commands)"`
@@ -68,11 +82,32 @@ func main() {
 		stderr.Fatal(err)
 	}
 
-	err = setEnvFileProperties(&global)
+	userConfig, err := loadUserConfig(os.Getenv)
 	if err != nil {
 		stderr.Fatal(err)
 	}
 
+	if global.Env == "" {
+		global.Env = userConfig.Env
+	}
+
+	envFile, err := readEnvFile(global)
+	if err != nil {
+		stderr.Fatal(err)
+	}
+
+	err = setDefaultOptions(&global, os.Args[1:len(os.Args)-len(args)], userConfig.options, envFile)
+	if err != nil {
+		stderr.Fatal(err)
+	}
+
+	if global.Env != "" {
+		err = checkForVars(&global)
+		if err != nil {
+			stderr.Fatalf("found problem in --env file: %s", err)
+		}
+	}
+
 	globalFlagsUsage, err := jhanda.PrintUsage(global)
 	if err != nil {
 		stderr.Fatal(err)
@@ -104,12 +139,31 @@ func main() {
 		stderr.Fatal(err)
 	}
 
-	authedClient, err = network.NewOAuthClient(global.Target, global.Username, global.Password, global.ClientID, global.ClientSecret, global.SkipSSLValidation, global.CACert, connectTimeout, requestTimeout)
+	oauthClient, err := network.NewOAuthClient(global.Target, global.Username, global.Password, global.ClientID, global.ClientSecret, global.SkipSSLValidation, global.CACert, connectTimeout, requestTimeout)
 
 	if err != nil {
 		stderr.Fatal(err)
 	}
+	authedClient = oauthClient
+
+	var auditLog *audit.Log
+	if global.AuditLog != "" {
+		auditWriter, err := audit.Open(global.AuditLog)
+		if err != nil {
+			stderr.Fatalf("could not open the audit log: %s", err)
+		}
+
+		user := global.ClientID
+		if user == "" {
+			user = global.Username
+		}
 
+		auditLog = audit.New(auditWriter, global.Target, user)
+		unauthenticatedClient = network.NewAuditClient(unauthenticatedClient, auditLog, os.Stderr)
+		authedClient = network.NewAuditClient(authedClient, auditLog, os.Stderr)
+	}
+
+	// the unlock requests of the decryption are audited with the requests of the command
 	if global.DecryptionPassphrase != "" {
 		authedClient = network.NewDecryptClient(authedClient, unauthenticatedClient, global.DecryptionPassphrase, os.Stderr)
 	}
@@ -147,30 +201,42 @@ func main() {
 
 	metadataExtractor := extractor.MetadataExtractor{}
 
-	presenter := presenters.NewPresenter(presenters.NewTablePresenter(tableWriter), presenters.NewJSONPresenter(os.Stdout))
+	presenter := presenters.NewPresenter(
+		presenters.NewTablePresenter(tableWriter),
+		presenters.NewJSONPresenter(os.Stdout),
+		presenters.NewYAMLPresenter(os.Stdout),
+		presenters.NewCSVPresenter(os.Stdout),
+	)
 	envRendererFactory := renderers.NewFactory(renderers.NewEnvGetter())
 
 	commandSet := jhanda.CommandSet{}
 	commandSet["activate-certificate-authority"] = commands.NewActivateCertificateAuthority(api, stdout)
 	commandSet["apply-changes"] = commands.NewApplyChanges(api, api, logWriter, stdout, applySleepDuration)
-	commandSet["assign-multi-stemcell"] = commands.NewAssignMultiStemcell(api, stdout)
-	commandSet["assign-stemcell"] = commands.NewAssignStemcell(api, stdout)
+	commandSet["assign-multi-stemcell"] = commands.NewAssignMultiStemcell(api, stdout, global.Strict)
+	commandSet["assign-stemcell"] = commands.NewAssignStemcell(api, stdout, global.Strict)
+	commandSet["audit-log"] = commands.NewAuditLog(global.AuditLog, presenter)
 	commandSet["available-products"] = commands.NewAvailableProducts(api, presenter, stdout)
 	commandSet["bosh-diff"] = commands.NewBoshDiff(api, stdout)
 	commandSet["bosh-env"] = commands.NewBoshEnvironment(api, stdout, global.Target, envRendererFactory)
+	commandSet["bosh-proxy"] = commands.NewBoshProxy(global.Target, stdout, ssh.Dial, net.Listen)
 	commandSet["certificate-authorities"] = commands.NewCertificateAuthorities(api, presenter)
 	commandSet["certificate-authority"] = commands.NewCertificateAuthority(api, presenter, stdout)
-	commandSet["config-template"] = commands.NewConfigTemplate(commands.DefaultProvider())
-	commandSet["configure-authentication"] = commands.NewConfigureAuthentication(os.Environ, api, stdout)
-	commandSet["configure-director"] = commands.NewConfigureDirector(os.Environ, api, stdout)
-	commandSet["configure-ldap-authentication"] = commands.NewConfigureLDAPAuthentication(os.Environ, api, stdout)
-	commandSet["configure-product"] = commands.NewConfigureProduct(os.Environ, api, global.Target, stdout)
-	commandSet["configure-saml-authentication"] = commands.NewConfigureSAMLAuthentication(os.Environ, api, stdout)
+	commandSet["certificate-inventory"] = commands.NewCertificateInventory(api, presenter)
+	commandSet["check-product-compatibility"] = commands.NewCheckProductCompatibility(metadataExtractor, api, stdout, global.Strict)
+	commandSet["completion"] = commands.NewCompletion(commandSet, global, api, os.Stdout)
+	commandSet["config-template"] = commands.NewConfigTemplate(commands.DefaultProvider(), global.Strict)
+	commandSet["config-template-diff"] = commands.NewConfigTemplateDiff(commands.DefaultConfigTemplateDiffProvider(), os.Environ, stdout, global.Strict)
+	commandSet["configure-authentication"] = commands.NewConfigureAuthentication(os.Environ, api, stdout, global.Strict)
+	commandSet["configure-director"] = commands.NewConfigureDirector(os.Environ, api, stdout, global.Strict)
+	commandSet["configure-ldap-authentication"] = commands.NewConfigureLDAPAuthentication(os.Environ, api, stdout, global.Strict)
+	commandSet["configure-product"] = commands.NewConfigureProduct(os.Environ, api, global.Target, stdout, global.Strict)
+	commandSet["configure-saml-authentication"] = commands.NewConfigureSAMLAuthentication(os.Environ, api, stdout, global.Strict)
 	commandSet["create-certificate-authority"] = commands.NewCreateCertificateAuthority(api, presenter)
-	commandSet["create-vm-extension"] = commands.NewCreateVMExtension(os.Environ, api, stdout)
+	commandSet["create-vm-extension"] = commands.NewCreateVMExtension(os.Environ, api, stdout, global.Strict)
 	commandSet["credential-references"] = commands.NewCredentialReferences(api, presenter, stdout)
 	commandSet["credentials"] = commands.NewCredentials(api, presenter, stdout)
 	commandSet["curl"] = commands.NewCurl(api, stdout, stderr)
+	commandSet["decrypt-vars"] = commands.NewDecryptVars(os.Environ, stdout)
 	commandSet["delete-certificate-authority"] = commands.NewDeleteCertificateAuthority(api, stdout)
 	commandSet["delete-installation"] = commands.NewDeleteInstallation(api, logWriter, stdout, os.Stdin, applySleepDuration)
 	commandSet["delete-product"] = commands.NewDeleteProduct(api)
@@ -179,52 +245,209 @@ func main() {
 	commandSet["deployed-manifest"] = commands.NewDeployedManifest(api, stdout)
 	commandSet["deployed-products"] = commands.NewDeployedProducts(presenter, api)
 	commandSet["diagnostic-report"] = commands.NewDiagnosticReport(presenter, api)
-	commandSet["disable-director-verifiers"] = commands.NewDisableDirectorVerifiers(presenter, api, stdout)
+	commandSet["disable-director-verifiers"] = commands.NewDisableDirectorVerifiers(presenter, api, stdout, global.Strict)
 	commandSet["disable-product-verifiers"] = commands.NewDisableProductVerifiers(presenter, api, stdout)
-	commandSet["download-product"] = commands.NewDownloadProduct(os.Environ, stdout, stderr, os.Stderr)
+	commandSet["download-product"] = commands.NewDownloadProduct(os.Environ, stdout, stderr, os.Stderr, global.Strict)
+	commandSet["encrypt-vars"] = commands.NewEncryptVars(stdout)
 	commandSet["errands"] = commands.NewErrands(presenter, api)
 	commandSet["expiring-certificates"] = commands.NewExpiringCertificates(api, stdout)
 	commandSet["export-installation"] = commands.NewExportInstallation(api, stderr)
 	commandSet["generate-certificate"] = commands.NewGenerateCertificate(api, stdout)
 	commandSet["generate-certificate-authority"] = commands.NewGenerateCertificateAuthority(api, presenter)
 	commandSet["help"] = commands.NewHelp(os.Stdout, globalFlagsUsage, commandSet)
-	commandSet["import-installation"] = commands.NewImportInstallation(form, api, global.DecryptionPassphrase, stdout)
+	commandSet["import-installation"] = commands.NewImportInstallation(form, api, global.DecryptionPassphrase, stdout, global.Strict)
 	commandSet["installation-log"] = commands.NewInstallationLog(api, stdout)
 	commandSet["installations"] = commands.NewInstallations(api, presenter)
-	commandSet["interpolate"] = commands.NewInterpolate(os.Environ, stdout, os.Stdin)
+	commandSet["interpolate"] = commands.NewInterpolate(os.Environ, presenter, stdout, os.Stdin, global.Strict)
+	commandSet["locks"] = commands.NewLocks(nil, presenter, stdout)
+	commandSet["metrics-server"] = commands.NewMetricsServer(api, stdout, http.ListenAndServe)
 	commandSet["pending-changes"] = commands.NewPendingChanges(presenter, api)
 	commandSet["pre-deploy-check"] = commands.NewPreDeployCheck(presenter, api, stdout)
 	commandSet["product-metadata"] = commands.NewProductMetadata(stdout)
 	commandSet["regenerate-certificates"] = commands.NewRegenerateCertificates(api, stdout)
 	commandSet["revert-staged-changes"] = commands.NewRevertStagedChanges(api, stdout)
+	commandSet["rotate-certificate-authority"] = commands.NewRotateCertificateAuthority(api, os.Stdout, stdout, applySleepDuration)
 	commandSet["ssl-certificate"] = commands.NewSSLCertificate(api, presenter)
 	commandSet["stage-product"] = commands.NewStageProduct(api, stdout)
 	commandSet["staged-config"] = commands.NewStagedConfig(api, stdout)
+	commandSet["staged-config-template"] = commands.NewStagedConfigTemplate(api, commands.DefaultStagedConfigTemplateProvider(), stdout)
 	commandSet["staged-director-config"] = commands.NewStagedDirectorConfig(api, stdout, stderr)
 	commandSet["staged-manifest"] = commands.NewStagedManifest(api, stdout)
 	commandSet["staged-products"] = commands.NewStagedProducts(presenter, api)
+	commandSet["tile-info"] = commands.NewTileInfo(metadataExtractor, presenter)
 	commandSet["tile-metadata"] = commands.NewDeprecatedProductMetadata(stdout)
 	commandSet["unstage-product"] = commands.NewUnstageProduct(api, stdout)
-	commandSet["update-ssl-certificate"] = commands.NewUpdateSSLCertificate(api, stdout)
-	commandSet["upload-product"] = commands.NewUploadProduct(form, metadataExtractor, api, stdout)
-	commandSet["upload-stemcell"] = commands.NewUploadStemcell(form, api, stdout)
+	commandSet["update-ssl-certificate"] = commands.NewUpdateSSLCertificate(api, stdout, os.Stdin, global.Target, applySleepDuration, tls.DialWithDialer)
+	commandSet["upload-product"] = commands.NewUploadProduct(form, metadataExtractor, api, stdout, global.Strict)
+	commandSet["upload-stemcell"] = commands.NewUploadStemcell(form, api, stdout, global.Strict)
+	commandSet["validate-config"] = commands.NewValidateConfig(commands.DefaultValidateConfigProvider(), os.Environ, stdout, global.Strict)
 	commandSet["version"] = commands.NewVersion(version, os.Stdout)
 
-	err = commandSet.Execute(command, args)
+	pluginGlobals := commands.PluginGlobals{
+		Target:            global.Target,
+		CACert:            global.CACert,
+		SkipSSLValidation: global.SkipSSLValidation,
+		ConnectTimeout:    global.ConnectTimeout,
+		RequestTimeout:    global.RequestTimeout,
+	}
+	if global.Username != "" || global.ClientID != "" {
+		pluginGlobals.Token = oauthClient.Token
+	}
+
+	// the PATH is only searched when a plugin can be run or listed,
+	// so built-in commands do not pay for reading every directory of the PATH
+	if _, ok := commandSet[command]; !ok || command == "help" {
+		for name, executable := range commands.FindPlugins(os.Getenv("PATH")) {
+			if _, ok := commandSet[name]; !ok {
+				commandSet[name] = commands.NewPlugin(name, executable, pluginGlobals, os.Environ, os.Stdin, os.Stdout, os.Stderr)
+			}
+		}
+	}
+
+	// the plugins are only looked up for the commands of the user config file that are not built in,
+	// as the PATH has not been searched for a built-in command
+	for name := range userConfig.Commands {
+		if _, ok := commandSet[name]; ok {
+			continue
+		}
+
+		if _, err := exec.LookPath(commands.PluginExecutable(name)); err != nil {
+			stderr.Printf("warning: ignoring unknown command %q in the user config file %s", name, userConfig.path)
+			delete(userConfig.Commands, name)
+		}
+	}
+
+	if c, ok := commandSet[command]; ok && userConfig.Commands[command] != nil {
+		defaultFlags, err := commands.LoadDefaultFlags(command, c.Usage().Flags, userConfig.Commands[command], os.Environ)
+		if err != nil {
+			stderr.Fatalf("found problem in the user config file %s: %s", userConfig.path, err)
+		}
+
+		if dc, ok := c.(commands.DefaultFlagsCommand); ok {
+			commandSet[command] = dc.WithDefaultFlags(defaultFlags)
+		} else {
+			args = commands.MergeDefaultFlags(c.Usage().Flags, defaultFlags, args)
+		}
+	}
+
+	if auditLog != nil {
+		var flags interface{}
+		if c, ok := commandSet[command]; ok {
+			flags = c.Usage().Flags
+		}
+
+		auditLog.SetCommand(command, audit.RedactArgs(flags, args))
+	}
+
+	// the lock config is only loaded by the commands that use it,
+	// so a broken or unreachable lock does not get in the way of the others
+	_, isPlugin := commandSet[command].(commands.Plugin)
+	var locker *lock.Locker
+	if global.Lock != "" && (mutatingCommands[command] || isPlugin || command == "locks") {
+		lockConfig, err := lock.LoadConfig(global.Lock, global.Target, os.Environ, []string{global.VarsEnv})
+		if err != nil {
+			stderr.Fatal(err)
+		}
+
+		locker, err = lock.New(lockConfig)
+		if err != nil {
+			stderr.Fatal(err)
+		}
+
+		if command == "locks" {
+			commandSet["locks"] = commands.NewLocks(locker, presenter, stdout)
+		}
+	}
+
+	var lease *lock.Lease
+	if locker != nil && (mutatingCommands[command] || isPlugin) {
+		lease, err = locker.Acquire(command)
+		if err != nil {
+			stderr.Fatal(err)
+		}
+		lease.KeepAlive(os.Stderr)
+
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-signals
+			releaseLease(lease, stderr)
+			os.Exit(1)
+		}()
+	}
+
+	// plugins handle their own --help, and exit with their own status
+	if plugin, ok := commandSet[command].(commands.Plugin); ok {
+		err = plugin.Execute(args)
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			releaseLease(lease, stderr)
+			os.Exit(exitErr.ExitCode())
+		}
+	} else {
+		err = commandSet.Execute(command, args)
+	}
+
+	releaseLease(lease, stderr)
+
 	if err != nil {
 		stderr.Fatal(err)
 	}
 }
 
-func setEnvFileProperties(global *options) error {
+// mutatingCommands hold the lease of --lock, as they change the Ops Manager.
+// The plugins hold it too, as what they change is not known.
+var mutatingCommands = map[string]bool{
+	"activate-certificate-authority": true,
+	"apply-changes":                  true,
+	"assign-multi-stemcell":          true,
+	"assign-stemcell":                true,
+	"configure-authentication":       true,
+	"configure-director":             true,
+	"configure-ldap-authentication":  true,
+	"configure-product":              true,
+	"configure-saml-authentication":  true,
+	"create-certificate-authority":   true,
+	"create-vm-extension":            true,
+	"curl":                           true,
+	"delete-certificate-authority":   true,
+	"delete-installation":            true,
+	"delete-product":                 true,
+	"delete-ssl-certificate":         true,
+	"delete-unused-products":         true,
+	"disable-director-verifiers":     true,
+	"disable-product-verifiers":      true,
+	"generate-certificate-authority": true,
+	"import-installation":            true,
+	"regenerate-certificates":        true,
+	"revert-staged-changes":          true,
+	"rotate-certificate-authority":   true,
+	"stage-product":                  true,
+	"unstage-product":                true,
+	"update-ssl-certificate":         true,
+	"upload-product":                 true,
+	"upload-stemcell":                true,
+}
+
+func releaseLease(lease *lock.Lease, stderr *log.Logger) {
+	if lease == nil {
+		return
+	}
+
+	err := lease.Release()
+	if err != nil {
+		stderr.Printf("WARNING: %s", err)
+	}
+}
+
+// readEnvFile returns the options of the env file by their flag name.
+func readEnvFile(global options) (map[string]interface{}, error) {
 	if global.Env == "" {
-		return nil
+		return nil, nil
 	}
 
-	var opts options
 	_, err := os.Open(global.Env)
 	if err != nil {
-		return fmt.Errorf("env file does not exist: %s", err)
+		return nil, fmt.Errorf("env file does not exist: %s", err)
 	}
 
 	contents, err := interpolate.Execute(interpolate.Options{
@@ -234,54 +457,130 @@ func setEnvFileProperties(global *options) error {
 		ExpectAllKeys: false,
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	var opts options
 	err = yaml.UnmarshalStrict(contents, &opts)
 	if err != nil {
-		return fmt.Errorf("could not parse env file: %s", err)
+		return nil, fmt.Errorf("could not parse env file: %s", err)
 	}
 
-	if global.ClientID == "" {
-		global.ClientID = opts.ClientID
+	var values map[string]interface{}
+	err = yaml.Unmarshal(contents, &values)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse env file: %s", err)
 	}
-	if global.ClientSecret == "" {
-		global.ClientSecret = opts.ClientSecret
+
+	// only the options with a yaml tag are read from the env file,
+	// the keys of the other options are accepted but ignored
+	envFile := map[string]interface{}{}
+	t := reflect.TypeOf(opts)
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Tag.Get("yaml")
+		if value, ok := values[name]; ok && name != "" && name != "-" && value != nil {
+			envFile[name] = value
+		}
 	}
-	if global.Password == "" {
-		global.Password = opts.Password
+
+	return envFile, nil
+}
+
+// setDefaultOptions parses the global flags again, with the options of the user config file
+// and the env file as default flags in front of them.
+// The flags and the environment variables take precedence over the env file,
+// which takes precedence over the user config file.
+func setDefaultOptions(global *options, globalArgs []string, userConfig, envFile map[string]interface{}) error {
+	defaults := map[string]interface{}{}
+	for name, value := range userConfig {
+		defaults[name] = value
 	}
-	if global.ConnectTimeout == 10 && opts.ConnectTimeout != 0 {
-		global.ConnectTimeout = opts.ConnectTimeout
+	for name, value := range envFile {
+		defaults[name] = value
 	}
-	if global.RequestTimeout == 1800 && opts.RequestTimeout != 0 {
-		global.RequestTimeout = opts.RequestTimeout
+
+	defaultFlags, err := commands.LoadDefaultFlags("om", options{}, defaults, os.Environ)
+	if err != nil {
+		return err
 	}
-	if global.SkipSSLValidation == false {
-		global.SkipSSLValidation = opts.SkipSSLValidation
+
+	env := global.Env
+
+	*global = options{}
+	_, err = jhanda.Parse(global, commands.MergeDefaultFlags(options{}, defaultFlags, globalArgs))
+	if err != nil {
+		return err
 	}
-	if global.Target == "" {
-		global.Target = opts.Target
+
+	if global.Env == "" {
+		global.Env = env
 	}
-	if global.Trace == false {
-		global.Trace = opts.Trace
+
+	return nil
+}
+
+// userConfig is the user config file, $XDG_CONFIG_HOME/om/config.yml or ~/.config/om/config.yml.
+// It has the defaults of the global options that are not credentials,
+// and the defaults of the flags of commands by command name.
+type userConfig struct {
+	AuditLog          string `yaml:"audit-log"`
+	CACert            string `yaml:"ca-cert"`
+	ConnectTimeout    int    `yaml:"connect-timeout"`
+	Env               string `yaml:"env"`
+	Lock              string `yaml:"lock"`
+	RequestTimeout    int    `yaml:"request-timeout"`
+	SkipSSLValidation bool   `yaml:"skip-ssl-validation"`
+	Strict            bool   `yaml:"strict"`
+	Target            string `yaml:"target"`
+	Trace             bool   `yaml:"trace"`
+
+	Commands map[string]map[string]interface{} `yaml:",inline"`
+
+	// options are the global options that are set in the file, by their flag name,
+	// as the fields above do not tell an option that is not set from its zero value
+	options map[string]interface{}
+	path    string
+}
+
+func loadUserConfig(getenv func(string) string) (userConfig, error) {
+	configDir := getenv("XDG_CONFIG_HOME")
+	if configDir == "" {
+		home := getenv("HOME")
+		if home == "" {
+			return userConfig{}, nil
+		}
+		configDir = filepath.Join(home, ".config")
 	}
-	if global.Username == "" {
-		global.Username = opts.Username
+
+	config := userConfig{path: filepath.Join(configDir, "om", "config.yml")}
+
+	contents, err := ioutil.ReadFile(config.path)
+	if os.IsNotExist(err) {
+		return config, nil
 	}
-	if global.DecryptionPassphrase == "" {
-		global.DecryptionPassphrase = opts.DecryptionPassphrase
+	if err != nil {
+		return config, fmt.Errorf("could not read the user config file: %s", err)
 	}
-	if global.CACert == "" {
-		global.CACert = opts.CACert
+
+	err = yaml.UnmarshalStrict(contents, &config)
+	if err != nil {
+		return config, fmt.Errorf("could not parse the user config file %s: %s", config.path, err)
 	}
 
-	err = checkForVars(global)
+	var values map[string]interface{}
+	err = yaml.Unmarshal(contents, &values)
 	if err != nil {
-		return fmt.Errorf("found problem in --env file: %s", err)
+		return config, fmt.Errorf("could not parse the user config file %s: %s", config.path, err)
 	}
 
-	return nil
+	config.options = map[string]interface{}{}
+	for name, value := range values {
+		if _, ok := config.Commands[name]; !ok && value != nil {
+			config.options[name] = value
+		}
+	}
+
+	return config, nil
 }
 
 func checkForVars(opts *options) error {
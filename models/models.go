@@ -20,3 +20,125 @@ type Errand struct {
 	PostDeployEnabled string `json:"post_deploy_enabled,omitempty"`
 	PreDeleteEnabled  string `json:"pre_delete_enabled,omitempty"`
 }
+
+type TileInfo struct {
+	Name                     string                 `json:"name"`
+	Version                  string                 `json:"version"`
+	Label                    string                 `json:"label,omitempty"`
+	MinimumVersionForUpgrade string                 `json:"minimum_version_for_upgrade,omitempty"`
+	OpsManagerVersions       []string               `json:"ops_manager_versions"`
+	Releases                 []TileRelease          `json:"releases"`
+	StemcellCriteria         []TileStemcellCriteria `json:"stemcell_criteria"`
+	RequiredProducts         []TileRequiredProduct  `json:"required_products"`
+	JobTypes                 []TileJobType          `json:"job_types"`
+	Errands                  []TileErrand           `json:"errands"`
+	Properties               []TileProperty         `json:"properties"`
+}
+
+type TileRelease struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	File    string `json:"file,omitempty"`
+}
+
+type TileStemcellCriteria struct {
+	OS                         string `json:"os"`
+	Version                    string `json:"version"`
+	EnablePatchSecurityUpdates bool   `json:"enable_patch_security_updates"`
+}
+
+type TileRequiredProduct struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type TileJobType struct {
+	Name                  string         `json:"name"`
+	Instances             int            `json:"instances"`
+	InstancesConfigurable bool           `json:"instances_configurable"`
+	Resources             []TileResource `json:"resources"`
+}
+
+type TileResource struct {
+	Name         string      `json:"name"`
+	Default      interface{} `json:"default"`
+	Configurable bool        `json:"configurable"`
+}
+
+type TileErrand struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type TileProperty struct {
+	Reference string `json:"reference"`
+	Type      string `json:"type"`
+	Required  bool   `json:"required"`
+}
+
+type Certificate struct {
+	Source             string     `json:"source"`
+	Name               string     `json:"name"`
+	Product            string     `json:"product,omitempty"`
+	Location           string     `json:"location,omitempty"`
+	Subject            string     `json:"subject,omitempty"`
+	SANs               []string   `json:"sans"`
+	KeyType            string     `json:"key_type,omitempty"`
+	KeySize            int        `json:"key_size,omitempty"`
+	SignatureAlgorithm string     `json:"signature_algorithm,omitempty"`
+	Issuer             string     `json:"issuer"`
+	IssuerChain        []string   `json:"issuer_chain"`
+	NotBefore          *time.Time `json:"not_before,omitempty"`
+	NotAfter           *time.Time `json:"not_after"`
+	DaysLeft           int        `json:"days_left"`
+}
+
+type AuditRecord struct {
+	Time     time.Time `json:"time"`
+	Command  string    `json:"command"`
+	Args     []string  `json:"args"`
+	Target   string    `json:"target"`
+	User     string    `json:"user,omitempty"`
+	Method   string    `json:"method"`
+	Endpoint string    `json:"endpoint"`
+	Status   int       `json:"status,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+type Lock struct {
+	Name       string    `json:"name"`
+	ID         string    `json:"id"`
+	Holder     string    `json:"holder"`
+	Command    string    `json:"command"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Expired    bool      `json:"expired,omitempty"`
+}
+
+// InterpolationReport describes how the variables of a template have been resolved.
+// Generated are the variables that the vars store would generate.
+type InterpolationReport struct {
+	Missing    []string             `json:"missing"`
+	Unused     []UnusedVariables    `json:"unused"`
+	Overridden []OverriddenVariable `json:"overridden"`
+	Generated  []string             `json:"generated"`
+}
+
+// UnusedVariables are the variables of a source that the template does not use.
+type UnusedVariables struct {
+	Source string   `json:"source"`
+	Names  []string `json:"names"`
+}
+
+// OverriddenVariable is a variable that is provided by more than one source.
+// Source is the one whose value has been used.
+type OverriddenVariable struct {
+	Name       string   `json:"name"`
+	Source     string   `json:"source"`
+	Overridden []string `json:"overridden"`
+}
+
+// HasProblems is true when a variable is missing or a provided variable is not used.
+func (r InterpolationReport) HasProblems() bool {
+	return len(r.Missing) > 0 || len(r.Unused) > 0
+}
@@ -0,0 +1,49 @@
+package network
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+//counterfeiter:generate -o ./fakes/audit_log.go --fake-name AuditLog . auditLog
+
+type auditLog interface {
+	Record(method, endpoint string, status int, err error) error
+}
+
+// AuditClient records every request, except the GET requests, to the audit log.
+// A record that cannot be written is reported to writer,
+// as the request has already changed the Ops Manager.
+type AuditClient struct {
+	client httpClient
+	log    auditLog
+	writer io.Writer
+}
+
+func NewAuditClient(client httpClient, log auditLog, writer io.Writer) *AuditClient {
+	return &AuditClient{
+		client: client,
+		log:    log,
+		writer: writer,
+	}
+}
+
+func (c *AuditClient) Do(request *http.Request) (*http.Response, error) {
+	response, err := c.client.Do(request)
+	if request.Method == http.MethodGet {
+		return response, err
+	}
+
+	var status int
+	if response != nil {
+		status = response.StatusCode
+	}
+
+	auditErr := c.log.Record(request.Method, request.URL.Path, status, err)
+	if auditErr != nil {
+		_, _ = fmt.Fprintf(c.writer, "warning: could not write the audit record of %s %s: %s\n", request.Method, request.URL.Path, auditErr)
+	}
+
+	return response, err
+}
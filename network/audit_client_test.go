@@ -0,0 +1,94 @@
+package network_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/om/network"
+	"github.com/pivotal-cf/om/network/fakes"
+)
+
+var _ = Describe("Audit Client", func() {
+	var (
+		fakeClient  *fakes.HttpClient
+		fakeLog     *fakes.AuditLog
+		stderr      *gbytes.Buffer
+		auditClient *network.AuditClient
+	)
+
+	BeforeEach(func() {
+		fakeClient = &fakes.HttpClient{}
+		fakeLog = &fakes.AuditLog{}
+		stderr = gbytes.NewBuffer()
+		auditClient = network.NewAuditClient(fakeClient, fakeLog, stderr)
+	})
+
+	It("records the requests that are not GET requests", func() {
+		response := &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(""))}
+		fakeClient.DoReturns(response, nil)
+
+		request, err := http.NewRequest("POST", "https://example.com/api/v0/installations?some=query", nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		actualResponse, err := auditClient.Do(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actualResponse).To(Equal(response))
+
+		Expect(fakeClient.DoArgsForCall(0)).To(Equal(request))
+		Expect(fakeLog.RecordCallCount()).To(Equal(1))
+
+		method, endpoint, status, requestErr := fakeLog.RecordArgsForCall(0)
+		Expect(method).To(Equal("POST"))
+		Expect(endpoint).To(Equal("/api/v0/installations"))
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(requestErr).ToNot(HaveOccurred())
+	})
+
+	It("does not record the GET requests", func() {
+		fakeClient.DoReturns(&http.Response{StatusCode: http.StatusOK}, nil)
+
+		request, err := http.NewRequest("GET", "https://example.com/api/v0/installations", nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = auditClient.Do(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fakeLog.RecordCallCount()).To(Equal(0))
+	})
+
+	It("records the requests that fail", func() {
+		fakeClient.DoReturns(nil, errors.New("connection refused"))
+
+		request, err := http.NewRequest("DELETE", "https://example.com/api/v0/staged/products/cf-guid", nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = auditClient.Do(request)
+		Expect(err).To(MatchError("connection refused"))
+
+		method, endpoint, status, requestErr := fakeLog.RecordArgsForCall(0)
+		Expect(method).To(Equal("DELETE"))
+		Expect(endpoint).To(Equal("/api/v0/staged/products/cf-guid"))
+		Expect(status).To(Equal(0))
+		Expect(requestErr).To(MatchError("connection refused"))
+	})
+
+	When("the record cannot be written", func() {
+		It("warns and returns the response of the request", func() {
+			response := &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(""))}
+			fakeClient.DoReturns(response, nil)
+			fakeLog.RecordReturns(errors.New("disk full"))
+
+			request, err := http.NewRequest("PUT", "https://example.com/api/v0/staged/director/properties", nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			actualResponse, err := auditClient.Do(request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(actualResponse).To(Equal(response))
+			Expect(stderr).To(gbytes.Say("warning: could not write the audit record of PUT /api/v0/staged/director/properties: disk full"))
+		})
+	})
+})
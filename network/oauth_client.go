@@ -69,30 +69,11 @@ func NewOAuthClient(
 func (oc OAuthClient) Do(request *http.Request) (*http.Response, error) {
 	var client *http.Client
 
-	if oc.target == "" {
-		return nil, fmt.Errorf("target flag is required. Run `om help` for more info.")
-	}
-
-	targetURL, err := url.Parse(oc.target)
-	if err != nil {
-		return nil, fmt.Errorf("could not parse target url: %s", err)
-	}
-
-	if targetURL.Scheme == "" {
-		targetURL.Scheme = "https"
-	}
-
-	// if scheme is missing when parse you clobber the host
-	// when setting the Path value below.
-	targetURL, err = url.Parse(targetURL.String())
+	targetURL, err := oc.setTokenURL()
 	if err != nil {
-		return nil, fmt.Errorf("could not parse target url: %s", err)
+		return nil, err
 	}
 
-	targetURL.Path = "/uaa/oauth/token"
-	oc.oauthConfigCC.TokenURL = targetURL.String()
-	oc.oauthConfig.Endpoint.TokenURL = targetURL.String()
-
 	if oc.oauthConfigCC.ClientID != "" {
 		client = oc.oauthConfigCC.Client(oc.context)
 	} else {
@@ -121,6 +102,58 @@ func (oc OAuthClient) Do(request *http.Request) (*http.Response, error) {
 	return client.Do(request)
 }
 
+// Token returns an access token for the target,
+// retrieved with the client credentials or the username and password.
+func (oc OAuthClient) Token() (string, error) {
+	_, err := oc.setTokenURL()
+	if err != nil {
+		return "", err
+	}
+
+	var token *oauth2.Token
+	if oc.oauthConfigCC.ClientID != "" {
+		token, err = oc.oauthConfigCC.Token(oc.context)
+		if err != nil {
+			return "", fmt.Errorf("token could not be retrieved from target url: %s", err)
+		}
+	} else {
+		token, err = retrieveTokenWithRetry(oc.oauthConfig, oc.context, oc.username, oc.password)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return token.AccessToken, nil
+}
+
+func (oc OAuthClient) setTokenURL() (*url.URL, error) {
+	if oc.target == "" {
+		return nil, fmt.Errorf("target flag is required. Run `om help` for more info.")
+	}
+
+	targetURL, err := url.Parse(oc.target)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse target url: %s", err)
+	}
+
+	if targetURL.Scheme == "" {
+		targetURL.Scheme = "https"
+	}
+
+	// if scheme is missing when parse you clobber the host
+	// when setting the Path value below.
+	targetURL, err = url.Parse(targetURL.String())
+	if err != nil {
+		return nil, fmt.Errorf("could not parse target url: %s", err)
+	}
+
+	targetURL.Path = "/uaa/oauth/token"
+	oc.oauthConfigCC.TokenURL = targetURL.String()
+	oc.oauthConfig.Endpoint.TokenURL = targetURL.String()
+
+	return targetURL, nil
+}
+
 func retrieveTokenWithRetry(config *oauth2.Config, ctx context.Context, username, password string) (*oauth2.Token, error) {
 	var token *oauth2.Token
 	var err error
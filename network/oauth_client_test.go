@@ -280,4 +280,40 @@ var _ = Describe("OAuthClient", func() {
 			})
 		})
 	})
+
+	Describe("Token", func() {
+		It("retrieves a token with the username and password", func() {
+			client, err := network.NewOAuthClient(server.URL, "opsman-username", "opsman-password", "", "", true, "", time.Duration(5)*time.Second, time.Duration(30)*time.Second)
+			Expect(err).ToNot(HaveOccurred())
+
+			token, err := client.Token()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(token).To(Equal("some-opsman-token"))
+
+			Expect(callCount).To(Equal(1))
+			Expect(string(receivedRequest)).To(ContainSubstring("grant_type=password"))
+			Expect(string(receivedRequest)).To(ContainSubstring("username=opsman-username"))
+		})
+
+		It("retrieves a token with the client credentials", func() {
+			client, err := network.NewOAuthClient(server.URL, "", "", "client-id", "client-secret", true, "", time.Duration(5)*time.Second, time.Duration(30)*time.Second)
+			Expect(err).ToNot(HaveOccurred())
+
+			token, err := client.Token()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(token).To(Equal("some-opsman-token"))
+
+			Expect(string(receivedRequest)).To(ContainSubstring("grant_type=client_credentials"))
+		})
+
+		When("the target url is empty", func() {
+			It("returns an error", func() {
+				client, err := network.NewOAuthClient("", "username", "password", "", "", false, "", time.Duration(5)*time.Second, time.Duration(30)*time.Second)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = client.Token()
+				Expect(err).To(MatchError(ContainSubstring("target flag is required")))
+			})
+		})
+	})
 })
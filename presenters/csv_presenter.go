@@ -0,0 +1,50 @@
+package presenters
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// CSVPresenter prints the same rows as the TablePresenter, as CSV.
+type CSVPresenter struct {
+	TablePresenter
+}
+
+func NewCSVPresenter(stdout io.Writer) CSVPresenter {
+	return CSVPresenter{
+		TablePresenter: NewTablePresenter(&csvWriter{writer: csv.NewWriter(stdout)}),
+	}
+}
+
+// csvWriter is a tableWriter that writes the header and rows of a table as CSV records.
+type csvWriter struct {
+	writer *csv.Writer
+	header []string
+	rows   [][]string
+}
+
+func (c *csvWriter) SetHeader(header []string) {
+	c.header = header
+}
+
+func (c *csvWriter) Append(row []string) {
+	c.rows = append(c.rows, row)
+}
+
+func (c *csvWriter) Render() {
+	if len(c.header) > 0 {
+		_ = c.writer.Write(c.header)
+	}
+	_ = c.writer.WriteAll(c.rows)
+
+	c.ClearRows()
+}
+
+func (c *csvWriter) ClearRows() {
+	c.header = nil
+	c.rows = nil
+}
+
+func (c *csvWriter) SetAlignment(int)          {}
+func (c *csvWriter) SetAutoFormatHeaders(bool) {}
+func (c *csvWriter) SetAutoWrapText(bool)      {}
@@ -0,0 +1,70 @@
+package presenters_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/models"
+	"github.com/pivotal-cf/om/presenters"
+)
+
+var _ = Describe("CSVPresenter", func() {
+	var (
+		stdout       *gbytes.Buffer
+		csvPresenter presenters.CSVPresenter
+	)
+
+	BeforeEach(func() {
+		stdout = gbytes.NewBuffer()
+		csvPresenter = presenters.NewCSVPresenter(stdout)
+	})
+
+	It("prints the rows of the table with a header", func() {
+		csvPresenter.PresentAvailableProducts([]models.Product{
+			{Name: "some-name", Version: "some-version"},
+			{Name: "some-other-name", Version: "1.2.3"},
+		})
+
+		Expect(string(stdout.Contents())).To(Equal("Name,Version\nsome-name,some-version\nsome-other-name,1.2.3\n"))
+	})
+
+	It("quotes values with commas and new lines", func() {
+		csvPresenter.PresentCertificateAuthorities([]api.CA{{
+			GUID:      "some-guid",
+			Issuer:    "CN=Pivotal, O=Ops Manager",
+			CreatedOn: "2017-01-09",
+			ExpiresOn: "2021-01-09",
+			Active:    true,
+			CertPEM:   "-----BEGIN CERTIFICATE-----\nsome-cert\n",
+		}})
+
+		Expect(string(stdout.Contents())).To(Equal(
+			"id,issuer,active,created on,expires on,certicate pem\n" +
+				"some-guid,\"CN=Pivotal, O=Ops Manager\",true,2017-01-09,2021-01-09,\"-----BEGIN CERTIFICATE-----\nsome-cert\n\"\n",
+		))
+	})
+
+	It("only prints the header when there are no rows", func() {
+		csvPresenter.PresentStagedProducts([]api.DiagnosticProduct{})
+
+		Expect(string(stdout.Contents())).To(Equal("Name,Version\n"))
+	})
+
+	It("prints every table of the tile info", func() {
+		csvPresenter.PresentTileInfo(models.TileInfo{
+			Name:    "some-product",
+			Version: "1.0.0",
+			Releases: []models.TileRelease{
+				{Name: "some-release", Version: "2.0.0", File: "some-release.tgz"},
+			},
+		})
+
+		Expect(string(stdout.Contents())).To(Equal(
+			"Name,Version,Label,Minimum Version For Upgrade,Ops Manager Versions\n" +
+				"some-product,1.0.0,,,\n" +
+				"Release,Version,File\n" +
+				"some-release,2.0.0,some-release.tgz\n",
+		))
+	})
+})
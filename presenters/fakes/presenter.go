@@ -10,6 +10,11 @@ import (
 )
 
 type Presenter struct {
+	PresentAuditRecordsStub        func([]models.AuditRecord)
+	presentAuditRecordsMutex       sync.RWMutex
+	presentAuditRecordsArgsForCall []struct {
+		arg1 []models.AuditRecord
+	}
 	PresentAvailableProductsStub        func([]models.Product)
 	presentAvailableProductsMutex       sync.RWMutex
 	presentAvailableProductsArgsForCall []struct {
@@ -25,6 +30,11 @@ type Presenter struct {
 	presentCertificateAuthorityArgsForCall []struct {
 		arg1 api.CA
 	}
+	PresentCertificateInventoryStub        func([]models.Certificate)
+	presentCertificateInventoryMutex       sync.RWMutex
+	presentCertificateInventoryArgsForCall []struct {
+		arg1 []models.Certificate
+	}
 	PresentCredentialReferencesStub        func([]string)
 	presentCredentialReferencesMutex       sync.RWMutex
 	presentCredentialReferencesArgsForCall []struct {
@@ -55,6 +65,16 @@ type Presenter struct {
 	presentInstallationsArgsForCall []struct {
 		arg1 []models.Installation
 	}
+	PresentInterpolationReportStub        func(models.InterpolationReport)
+	presentInterpolationReportMutex       sync.RWMutex
+	presentInterpolationReportArgsForCall []struct {
+		arg1 models.InterpolationReport
+	}
+	PresentLocksStub        func([]models.Lock)
+	presentLocksMutex       sync.RWMutex
+	presentLocksArgsForCall []struct {
+		arg1 []models.Lock
+	}
 	PresentPendingChangesStub        func(api.PendingChangesOutput)
 	presentPendingChangesMutex       sync.RWMutex
 	presentPendingChangesArgsForCall []struct {
@@ -70,10 +90,52 @@ type Presenter struct {
 	presentStagedProductsArgsForCall []struct {
 		arg1 []api.DiagnosticProduct
 	}
+	PresentTileInfoStub        func(models.TileInfo)
+	presentTileInfoMutex       sync.RWMutex
+	presentTileInfoArgsForCall []struct {
+		arg1 models.TileInfo
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *Presenter) PresentAuditRecords(arg1 []models.AuditRecord) {
+	var arg1Copy []models.AuditRecord
+	if arg1 != nil {
+		arg1Copy = make([]models.AuditRecord, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.presentAuditRecordsMutex.Lock()
+	fake.presentAuditRecordsArgsForCall = append(fake.presentAuditRecordsArgsForCall, struct {
+		arg1 []models.AuditRecord
+	}{arg1Copy})
+	stub := fake.PresentAuditRecordsStub
+	fake.recordInvocation("PresentAuditRecords", []interface{}{arg1Copy})
+	fake.presentAuditRecordsMutex.Unlock()
+	if stub != nil {
+		fake.PresentAuditRecordsStub(arg1)
+	}
+}
+
+func (fake *Presenter) PresentAuditRecordsCallCount() int {
+	fake.presentAuditRecordsMutex.RLock()
+	defer fake.presentAuditRecordsMutex.RUnlock()
+	return len(fake.presentAuditRecordsArgsForCall)
+}
+
+func (fake *Presenter) PresentAuditRecordsCalls(stub func([]models.AuditRecord)) {
+	fake.presentAuditRecordsMutex.Lock()
+	defer fake.presentAuditRecordsMutex.Unlock()
+	fake.PresentAuditRecordsStub = stub
+}
+
+func (fake *Presenter) PresentAuditRecordsArgsForCall(i int) []models.AuditRecord {
+	fake.presentAuditRecordsMutex.RLock()
+	defer fake.presentAuditRecordsMutex.RUnlock()
+	argsForCall := fake.presentAuditRecordsArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *Presenter) PresentAvailableProducts(arg1 []models.Product) {
 	var arg1Copy []models.Product
 	if arg1 != nil {
@@ -84,9 +146,10 @@ func (fake *Presenter) PresentAvailableProducts(arg1 []models.Product) {
 	fake.presentAvailableProductsArgsForCall = append(fake.presentAvailableProductsArgsForCall, struct {
 		arg1 []models.Product
 	}{arg1Copy})
+	stub := fake.PresentAvailableProductsStub
 	fake.recordInvocation("PresentAvailableProducts", []interface{}{arg1Copy})
 	fake.presentAvailableProductsMutex.Unlock()
-	if fake.PresentAvailableProductsStub != nil {
+	if stub != nil {
 		fake.PresentAvailableProductsStub(arg1)
 	}
 }
@@ -120,9 +183,10 @@ func (fake *Presenter) PresentCertificateAuthorities(arg1 []api.CA) {
 	fake.presentCertificateAuthoritiesArgsForCall = append(fake.presentCertificateAuthoritiesArgsForCall, struct {
 		arg1 []api.CA
 	}{arg1Copy})
+	stub := fake.PresentCertificateAuthoritiesStub
 	fake.recordInvocation("PresentCertificateAuthorities", []interface{}{arg1Copy})
 	fake.presentCertificateAuthoritiesMutex.Unlock()
-	if fake.PresentCertificateAuthoritiesStub != nil {
+	if stub != nil {
 		fake.PresentCertificateAuthoritiesStub(arg1)
 	}
 }
@@ -151,9 +215,10 @@ func (fake *Presenter) PresentCertificateAuthority(arg1 api.CA) {
 	fake.presentCertificateAuthorityArgsForCall = append(fake.presentCertificateAuthorityArgsForCall, struct {
 		arg1 api.CA
 	}{arg1})
+	stub := fake.PresentCertificateAuthorityStub
 	fake.recordInvocation("PresentCertificateAuthority", []interface{}{arg1})
 	fake.presentCertificateAuthorityMutex.Unlock()
-	if fake.PresentCertificateAuthorityStub != nil {
+	if stub != nil {
 		fake.PresentCertificateAuthorityStub(arg1)
 	}
 }
@@ -177,6 +242,43 @@ func (fake *Presenter) PresentCertificateAuthorityArgsForCall(i int) api.CA {
 	return argsForCall.arg1
 }
 
+func (fake *Presenter) PresentCertificateInventory(arg1 []models.Certificate) {
+	var arg1Copy []models.Certificate
+	if arg1 != nil {
+		arg1Copy = make([]models.Certificate, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.presentCertificateInventoryMutex.Lock()
+	fake.presentCertificateInventoryArgsForCall = append(fake.presentCertificateInventoryArgsForCall, struct {
+		arg1 []models.Certificate
+	}{arg1Copy})
+	stub := fake.PresentCertificateInventoryStub
+	fake.recordInvocation("PresentCertificateInventory", []interface{}{arg1Copy})
+	fake.presentCertificateInventoryMutex.Unlock()
+	if stub != nil {
+		fake.PresentCertificateInventoryStub(arg1)
+	}
+}
+
+func (fake *Presenter) PresentCertificateInventoryCallCount() int {
+	fake.presentCertificateInventoryMutex.RLock()
+	defer fake.presentCertificateInventoryMutex.RUnlock()
+	return len(fake.presentCertificateInventoryArgsForCall)
+}
+
+func (fake *Presenter) PresentCertificateInventoryCalls(stub func([]models.Certificate)) {
+	fake.presentCertificateInventoryMutex.Lock()
+	defer fake.presentCertificateInventoryMutex.Unlock()
+	fake.PresentCertificateInventoryStub = stub
+}
+
+func (fake *Presenter) PresentCertificateInventoryArgsForCall(i int) []models.Certificate {
+	fake.presentCertificateInventoryMutex.RLock()
+	defer fake.presentCertificateInventoryMutex.RUnlock()
+	argsForCall := fake.presentCertificateInventoryArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *Presenter) PresentCredentialReferences(arg1 []string) {
 	var arg1Copy []string
 	if arg1 != nil {
@@ -187,9 +289,10 @@ func (fake *Presenter) PresentCredentialReferences(arg1 []string) {
 	fake.presentCredentialReferencesArgsForCall = append(fake.presentCredentialReferencesArgsForCall, struct {
 		arg1 []string
 	}{arg1Copy})
+	stub := fake.PresentCredentialReferencesStub
 	fake.recordInvocation("PresentCredentialReferences", []interface{}{arg1Copy})
 	fake.presentCredentialReferencesMutex.Unlock()
-	if fake.PresentCredentialReferencesStub != nil {
+	if stub != nil {
 		fake.PresentCredentialReferencesStub(arg1)
 	}
 }
@@ -218,9 +321,10 @@ func (fake *Presenter) PresentCredentials(arg1 map[string]string) {
 	fake.presentCredentialsArgsForCall = append(fake.presentCredentialsArgsForCall, struct {
 		arg1 map[string]string
 	}{arg1})
+	stub := fake.PresentCredentialsStub
 	fake.recordInvocation("PresentCredentials", []interface{}{arg1})
 	fake.presentCredentialsMutex.Unlock()
-	if fake.PresentCredentialsStub != nil {
+	if stub != nil {
 		fake.PresentCredentialsStub(arg1)
 	}
 }
@@ -254,9 +358,10 @@ func (fake *Presenter) PresentDeployedProducts(arg1 []api.DiagnosticProduct) {
 	fake.presentDeployedProductsArgsForCall = append(fake.presentDeployedProductsArgsForCall, struct {
 		arg1 []api.DiagnosticProduct
 	}{arg1Copy})
+	stub := fake.PresentDeployedProductsStub
 	fake.recordInvocation("PresentDeployedProducts", []interface{}{arg1Copy})
 	fake.presentDeployedProductsMutex.Unlock()
-	if fake.PresentDeployedProductsStub != nil {
+	if stub != nil {
 		fake.PresentDeployedProductsStub(arg1)
 	}
 }
@@ -285,9 +390,10 @@ func (fake *Presenter) PresentDiagnosticReport(arg1 api.DiagnosticReport) {
 	fake.presentDiagnosticReportArgsForCall = append(fake.presentDiagnosticReportArgsForCall, struct {
 		arg1 api.DiagnosticReport
 	}{arg1})
+	stub := fake.PresentDiagnosticReportStub
 	fake.recordInvocation("PresentDiagnosticReport", []interface{}{arg1})
 	fake.presentDiagnosticReportMutex.Unlock()
-	if fake.PresentDiagnosticReportStub != nil {
+	if stub != nil {
 		fake.PresentDiagnosticReportStub(arg1)
 	}
 }
@@ -321,9 +427,10 @@ func (fake *Presenter) PresentErrands(arg1 []models.Errand) {
 	fake.presentErrandsArgsForCall = append(fake.presentErrandsArgsForCall, struct {
 		arg1 []models.Errand
 	}{arg1Copy})
+	stub := fake.PresentErrandsStub
 	fake.recordInvocation("PresentErrands", []interface{}{arg1Copy})
 	fake.presentErrandsMutex.Unlock()
-	if fake.PresentErrandsStub != nil {
+	if stub != nil {
 		fake.PresentErrandsStub(arg1)
 	}
 }
@@ -357,9 +464,10 @@ func (fake *Presenter) PresentInstallations(arg1 []models.Installation) {
 	fake.presentInstallationsArgsForCall = append(fake.presentInstallationsArgsForCall, struct {
 		arg1 []models.Installation
 	}{arg1Copy})
+	stub := fake.PresentInstallationsStub
 	fake.recordInvocation("PresentInstallations", []interface{}{arg1Copy})
 	fake.presentInstallationsMutex.Unlock()
-	if fake.PresentInstallationsStub != nil {
+	if stub != nil {
 		fake.PresentInstallationsStub(arg1)
 	}
 }
@@ -383,14 +491,84 @@ func (fake *Presenter) PresentInstallationsArgsForCall(i int) []models.Installat
 	return argsForCall.arg1
 }
 
+func (fake *Presenter) PresentInterpolationReport(arg1 models.InterpolationReport) {
+	fake.presentInterpolationReportMutex.Lock()
+	fake.presentInterpolationReportArgsForCall = append(fake.presentInterpolationReportArgsForCall, struct {
+		arg1 models.InterpolationReport
+	}{arg1})
+	stub := fake.PresentInterpolationReportStub
+	fake.recordInvocation("PresentInterpolationReport", []interface{}{arg1})
+	fake.presentInterpolationReportMutex.Unlock()
+	if stub != nil {
+		fake.PresentInterpolationReportStub(arg1)
+	}
+}
+
+func (fake *Presenter) PresentInterpolationReportCallCount() int {
+	fake.presentInterpolationReportMutex.RLock()
+	defer fake.presentInterpolationReportMutex.RUnlock()
+	return len(fake.presentInterpolationReportArgsForCall)
+}
+
+func (fake *Presenter) PresentInterpolationReportCalls(stub func(models.InterpolationReport)) {
+	fake.presentInterpolationReportMutex.Lock()
+	defer fake.presentInterpolationReportMutex.Unlock()
+	fake.PresentInterpolationReportStub = stub
+}
+
+func (fake *Presenter) PresentInterpolationReportArgsForCall(i int) models.InterpolationReport {
+	fake.presentInterpolationReportMutex.RLock()
+	defer fake.presentInterpolationReportMutex.RUnlock()
+	argsForCall := fake.presentInterpolationReportArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Presenter) PresentLocks(arg1 []models.Lock) {
+	var arg1Copy []models.Lock
+	if arg1 != nil {
+		arg1Copy = make([]models.Lock, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.presentLocksMutex.Lock()
+	fake.presentLocksArgsForCall = append(fake.presentLocksArgsForCall, struct {
+		arg1 []models.Lock
+	}{arg1Copy})
+	stub := fake.PresentLocksStub
+	fake.recordInvocation("PresentLocks", []interface{}{arg1Copy})
+	fake.presentLocksMutex.Unlock()
+	if stub != nil {
+		fake.PresentLocksStub(arg1)
+	}
+}
+
+func (fake *Presenter) PresentLocksCallCount() int {
+	fake.presentLocksMutex.RLock()
+	defer fake.presentLocksMutex.RUnlock()
+	return len(fake.presentLocksArgsForCall)
+}
+
+func (fake *Presenter) PresentLocksCalls(stub func([]models.Lock)) {
+	fake.presentLocksMutex.Lock()
+	defer fake.presentLocksMutex.Unlock()
+	fake.PresentLocksStub = stub
+}
+
+func (fake *Presenter) PresentLocksArgsForCall(i int) []models.Lock {
+	fake.presentLocksMutex.RLock()
+	defer fake.presentLocksMutex.RUnlock()
+	argsForCall := fake.presentLocksArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *Presenter) PresentPendingChanges(arg1 api.PendingChangesOutput) {
 	fake.presentPendingChangesMutex.Lock()
 	fake.presentPendingChangesArgsForCall = append(fake.presentPendingChangesArgsForCall, struct {
 		arg1 api.PendingChangesOutput
 	}{arg1})
+	stub := fake.PresentPendingChangesStub
 	fake.recordInvocation("PresentPendingChanges", []interface{}{arg1})
 	fake.presentPendingChangesMutex.Unlock()
-	if fake.PresentPendingChangesStub != nil {
+	if stub != nil {
 		fake.PresentPendingChangesStub(arg1)
 	}
 }
@@ -419,9 +597,10 @@ func (fake *Presenter) PresentSSLCertificate(arg1 api.SSLCertificate) {
 	fake.presentSSLCertificateArgsForCall = append(fake.presentSSLCertificateArgsForCall, struct {
 		arg1 api.SSLCertificate
 	}{arg1})
+	stub := fake.PresentSSLCertificateStub
 	fake.recordInvocation("PresentSSLCertificate", []interface{}{arg1})
 	fake.presentSSLCertificateMutex.Unlock()
-	if fake.PresentSSLCertificateStub != nil {
+	if stub != nil {
 		fake.PresentSSLCertificateStub(arg1)
 	}
 }
@@ -455,9 +634,10 @@ func (fake *Presenter) PresentStagedProducts(arg1 []api.DiagnosticProduct) {
 	fake.presentStagedProductsArgsForCall = append(fake.presentStagedProductsArgsForCall, struct {
 		arg1 []api.DiagnosticProduct
 	}{arg1Copy})
+	stub := fake.PresentStagedProductsStub
 	fake.recordInvocation("PresentStagedProducts", []interface{}{arg1Copy})
 	fake.presentStagedProductsMutex.Unlock()
-	if fake.PresentStagedProductsStub != nil {
+	if stub != nil {
 		fake.PresentStagedProductsStub(arg1)
 	}
 }
@@ -481,15 +661,51 @@ func (fake *Presenter) PresentStagedProductsArgsForCall(i int) []api.DiagnosticP
 	return argsForCall.arg1
 }
 
+func (fake *Presenter) PresentTileInfo(arg1 models.TileInfo) {
+	fake.presentTileInfoMutex.Lock()
+	fake.presentTileInfoArgsForCall = append(fake.presentTileInfoArgsForCall, struct {
+		arg1 models.TileInfo
+	}{arg1})
+	stub := fake.PresentTileInfoStub
+	fake.recordInvocation("PresentTileInfo", []interface{}{arg1})
+	fake.presentTileInfoMutex.Unlock()
+	if stub != nil {
+		fake.PresentTileInfoStub(arg1)
+	}
+}
+
+func (fake *Presenter) PresentTileInfoCallCount() int {
+	fake.presentTileInfoMutex.RLock()
+	defer fake.presentTileInfoMutex.RUnlock()
+	return len(fake.presentTileInfoArgsForCall)
+}
+
+func (fake *Presenter) PresentTileInfoCalls(stub func(models.TileInfo)) {
+	fake.presentTileInfoMutex.Lock()
+	defer fake.presentTileInfoMutex.Unlock()
+	fake.PresentTileInfoStub = stub
+}
+
+func (fake *Presenter) PresentTileInfoArgsForCall(i int) models.TileInfo {
+	fake.presentTileInfoMutex.RLock()
+	defer fake.presentTileInfoMutex.RUnlock()
+	argsForCall := fake.presentTileInfoArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *Presenter) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.presentAuditRecordsMutex.RLock()
+	defer fake.presentAuditRecordsMutex.RUnlock()
 	fake.presentAvailableProductsMutex.RLock()
 	defer fake.presentAvailableProductsMutex.RUnlock()
 	fake.presentCertificateAuthoritiesMutex.RLock()
 	defer fake.presentCertificateAuthoritiesMutex.RUnlock()
 	fake.presentCertificateAuthorityMutex.RLock()
 	defer fake.presentCertificateAuthorityMutex.RUnlock()
+	fake.presentCertificateInventoryMutex.RLock()
+	defer fake.presentCertificateInventoryMutex.RUnlock()
 	fake.presentCredentialReferencesMutex.RLock()
 	defer fake.presentCredentialReferencesMutex.RUnlock()
 	fake.presentCredentialsMutex.RLock()
@@ -502,12 +718,18 @@ func (fake *Presenter) Invocations() map[string][][]interface{} {
 	defer fake.presentErrandsMutex.RUnlock()
 	fake.presentInstallationsMutex.RLock()
 	defer fake.presentInstallationsMutex.RUnlock()
+	fake.presentInterpolationReportMutex.RLock()
+	defer fake.presentInterpolationReportMutex.RUnlock()
+	fake.presentLocksMutex.RLock()
+	defer fake.presentLocksMutex.RUnlock()
 	fake.presentPendingChangesMutex.RLock()
 	defer fake.presentPendingChangesMutex.RUnlock()
 	fake.presentSSLCertificateMutex.RLock()
 	defer fake.presentSSLCertificateMutex.RUnlock()
 	fake.presentStagedProductsMutex.RLock()
 	defer fake.presentStagedProductsMutex.RUnlock()
+	fake.presentTileInfoMutex.RLock()
+	defer fake.presentTileInfoMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -11,6 +11,10 @@ type TableWriter struct {
 	appendArgsForCall []struct {
 		arg1 []string
 	}
+	ClearRowsStub        func()
+	clearRowsMutex       sync.RWMutex
+	clearRowsArgsForCall []struct {
+	}
 	RenderStub        func()
 	renderMutex       sync.RWMutex
 	renderArgsForCall []struct {
@@ -49,9 +53,10 @@ func (fake *TableWriter) Append(arg1 []string) {
 	fake.appendArgsForCall = append(fake.appendArgsForCall, struct {
 		arg1 []string
 	}{arg1Copy})
+	stub := fake.AppendStub
 	fake.recordInvocation("Append", []interface{}{arg1Copy})
 	fake.appendMutex.Unlock()
-	if fake.AppendStub != nil {
+	if stub != nil {
 		fake.AppendStub(arg1)
 	}
 }
@@ -75,13 +80,38 @@ func (fake *TableWriter) AppendArgsForCall(i int) []string {
 	return argsForCall.arg1
 }
 
+func (fake *TableWriter) ClearRows() {
+	fake.clearRowsMutex.Lock()
+	fake.clearRowsArgsForCall = append(fake.clearRowsArgsForCall, struct {
+	}{})
+	stub := fake.ClearRowsStub
+	fake.recordInvocation("ClearRows", []interface{}{})
+	fake.clearRowsMutex.Unlock()
+	if stub != nil {
+		fake.ClearRowsStub()
+	}
+}
+
+func (fake *TableWriter) ClearRowsCallCount() int {
+	fake.clearRowsMutex.RLock()
+	defer fake.clearRowsMutex.RUnlock()
+	return len(fake.clearRowsArgsForCall)
+}
+
+func (fake *TableWriter) ClearRowsCalls(stub func()) {
+	fake.clearRowsMutex.Lock()
+	defer fake.clearRowsMutex.Unlock()
+	fake.ClearRowsStub = stub
+}
+
 func (fake *TableWriter) Render() {
 	fake.renderMutex.Lock()
 	fake.renderArgsForCall = append(fake.renderArgsForCall, struct {
 	}{})
+	stub := fake.RenderStub
 	fake.recordInvocation("Render", []interface{}{})
 	fake.renderMutex.Unlock()
-	if fake.RenderStub != nil {
+	if stub != nil {
 		fake.RenderStub()
 	}
 }
@@ -103,9 +133,10 @@ func (fake *TableWriter) SetAlignment(arg1 int) {
 	fake.setAlignmentArgsForCall = append(fake.setAlignmentArgsForCall, struct {
 		arg1 int
 	}{arg1})
+	stub := fake.SetAlignmentStub
 	fake.recordInvocation("SetAlignment", []interface{}{arg1})
 	fake.setAlignmentMutex.Unlock()
-	if fake.SetAlignmentStub != nil {
+	if stub != nil {
 		fake.SetAlignmentStub(arg1)
 	}
 }
@@ -134,9 +165,10 @@ func (fake *TableWriter) SetAutoFormatHeaders(arg1 bool) {
 	fake.setAutoFormatHeadersArgsForCall = append(fake.setAutoFormatHeadersArgsForCall, struct {
 		arg1 bool
 	}{arg1})
+	stub := fake.SetAutoFormatHeadersStub
 	fake.recordInvocation("SetAutoFormatHeaders", []interface{}{arg1})
 	fake.setAutoFormatHeadersMutex.Unlock()
-	if fake.SetAutoFormatHeadersStub != nil {
+	if stub != nil {
 		fake.SetAutoFormatHeadersStub(arg1)
 	}
 }
@@ -165,9 +197,10 @@ func (fake *TableWriter) SetAutoWrapText(arg1 bool) {
 	fake.setAutoWrapTextArgsForCall = append(fake.setAutoWrapTextArgsForCall, struct {
 		arg1 bool
 	}{arg1})
+	stub := fake.SetAutoWrapTextStub
 	fake.recordInvocation("SetAutoWrapText", []interface{}{arg1})
 	fake.setAutoWrapTextMutex.Unlock()
-	if fake.SetAutoWrapTextStub != nil {
+	if stub != nil {
 		fake.SetAutoWrapTextStub(arg1)
 	}
 }
@@ -201,9 +234,10 @@ func (fake *TableWriter) SetHeader(arg1 []string) {
 	fake.setHeaderArgsForCall = append(fake.setHeaderArgsForCall, struct {
 		arg1 []string
 	}{arg1Copy})
+	stub := fake.SetHeaderStub
 	fake.recordInvocation("SetHeader", []interface{}{arg1Copy})
 	fake.setHeaderMutex.Unlock()
-	if fake.SetHeaderStub != nil {
+	if stub != nil {
 		fake.SetHeaderStub(arg1)
 	}
 }
@@ -232,6 +266,8 @@ func (fake *TableWriter) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.appendMutex.RLock()
 	defer fake.appendMutex.RUnlock()
+	fake.clearRowsMutex.RLock()
+	defer fake.clearRowsMutex.RUnlock()
 	fake.renderMutex.RLock()
 	defer fake.renderMutex.RUnlock()
 	fake.setAlignmentMutex.RLock()
@@ -46,6 +46,14 @@ func (j JSONPresenter) PresentCertificateAuthority(certificateAuthority api.CA)
 	j.encodeJSON(certificateAuthority)
 }
 
+func (j JSONPresenter) PresentAuditRecords(records []models.AuditRecord) {
+	j.encodeJSON(records)
+}
+
+func (j JSONPresenter) PresentCertificateInventory(certificates []models.Certificate) {
+	j.encodeJSON(certificates)
+}
+
 func (j JSONPresenter) PresentSSLCertificate(certificate api.SSLCertificate) {
 	j.encodeJSON(certificate)
 }
@@ -54,6 +62,14 @@ func (j JSONPresenter) PresentInstallations(installations []models.Installation)
 	j.encodeJSON(installations)
 }
 
+func (j JSONPresenter) PresentInterpolationReport(report models.InterpolationReport) {
+	j.encodeJSON(report)
+}
+
+func (j JSONPresenter) PresentLocks(locks []models.Lock) {
+	j.encodeJSON(locks)
+}
+
 func (j JSONPresenter) PresentStagedProducts(stagedProducts []api.DiagnosticProduct) {
 	j.encodeJSON(stagedProducts)
 }
@@ -66,6 +82,10 @@ func (j JSONPresenter) PresentDiagnosticReport(report api.DiagnosticReport) {
 	_, _ = j.stdout.Write([]byte(report.FullReport))
 }
 
+func (j JSONPresenter) PresentTileInfo(info models.TileInfo) {
+	j.encodeJSON(info)
+}
+
 func (j JSONPresenter) encodeJSON(v interface{}) {
 	b, _ := json.MarshalIndent(&v, "", "  ")
 
@@ -1,6 +1,9 @@
 package presenters
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/pivotal-cf/om/api"
 	"github.com/pivotal-cf/om/models"
 )
@@ -8,18 +11,23 @@ import (
 //counterfeiter:generate -o fakes/presenter.go --fake-name Presenter . Presenter
 
 type Presenter interface {
+	PresentAuditRecords([]models.AuditRecord)
 	PresentAvailableProducts([]models.Product)
 	PresentCertificateAuthorities([]api.CA)
 	PresentCertificateAuthority(api.CA)
+	PresentCertificateInventory([]models.Certificate)
 	PresentSSLCertificate(api.SSLCertificate)
 	PresentCredentialReferences([]string)
 	PresentCredentials(map[string]string)
 	PresentDeployedProducts([]api.DiagnosticProduct)
 	PresentErrands([]models.Errand)
 	PresentInstallations([]models.Installation)
+	PresentInterpolationReport(models.InterpolationReport)
+	PresentLocks([]models.Lock)
 	PresentPendingChanges(api.PendingChangesOutput)
 	PresentStagedProducts([]api.DiagnosticProduct)
 	PresentDiagnosticReport(api.DiagnosticReport)
+	PresentTileInfo(models.TileInfo)
 }
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
@@ -30,16 +38,39 @@ type FormattedPresenter interface {
 	SetFormat(string)
 }
 
+// Formats are the formats to print as, the first one is the default.
+var Formats = []string{"table", "json", "yaml", "csv"}
+
+// ValidateFormat returns an error when the format is not one of the given formats,
+// which are all the Formats when none is given.
+func ValidateFormat(format string, formats ...string) error {
+	if len(formats) == 0 {
+		formats = Formats
+	}
+
+	for _, f := range formats {
+		if format == f {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unsupported format %q, expected one of %s", format, strings.Join(formats, ", "))
+}
+
 type MultiPresenter struct {
 	tablePresenter Presenter
 	jsonPresenter  Presenter
+	yamlPresenter  Presenter
+	csvPresenter   Presenter
 	format         string
 }
 
-func NewPresenter(tablePresenter Presenter, jsonPresenter Presenter) *MultiPresenter {
+func NewPresenter(tablePresenter Presenter, jsonPresenter Presenter, yamlPresenter Presenter, csvPresenter Presenter) *MultiPresenter {
 	return &MultiPresenter{
 		tablePresenter: tablePresenter,
 		jsonPresenter:  jsonPresenter,
+		yamlPresenter:  yamlPresenter,
+		csvPresenter:   csvPresenter,
 		format:         "table",
 	}
 }
@@ -48,109 +79,83 @@ func (p *MultiPresenter) SetFormat(format string) {
 	p.format = format
 }
 
-func (p *MultiPresenter) PresentAvailableProducts(products []models.Product) {
+func (p *MultiPresenter) presenter() Presenter {
 	switch p.format {
 	case "json":
-		p.jsonPresenter.PresentAvailableProducts(products)
+		return p.jsonPresenter
+	case "yaml":
+		return p.yamlPresenter
+	case "csv":
+		return p.csvPresenter
 	default:
-		p.tablePresenter.PresentAvailableProducts(products)
+		return p.tablePresenter
 	}
 }
 
+func (p *MultiPresenter) PresentAvailableProducts(products []models.Product) {
+	p.presenter().PresentAvailableProducts(products)
+}
+
 func (p *MultiPresenter) PresentCertificateAuthorities(cas []api.CA) {
-	switch p.format {
-	case "json":
-		p.jsonPresenter.PresentCertificateAuthorities(cas)
-	default:
-		p.tablePresenter.PresentCertificateAuthorities(cas)
-	}
+	p.presenter().PresentCertificateAuthorities(cas)
 }
 
 func (p *MultiPresenter) PresentCertificateAuthority(ca api.CA) {
-	switch p.format {
-	case "json":
-		p.jsonPresenter.PresentCertificateAuthority(ca)
-	default:
-		p.tablePresenter.PresentCertificateAuthority(ca)
-	}
+	p.presenter().PresentCertificateAuthority(ca)
+}
+
+func (p *MultiPresenter) PresentAuditRecords(records []models.AuditRecord) {
+	p.presenter().PresentAuditRecords(records)
+}
+
+func (p *MultiPresenter) PresentCertificateInventory(certificates []models.Certificate) {
+	p.presenter().PresentCertificateInventory(certificates)
 }
 
 func (p *MultiPresenter) PresentSSLCertificate(cert api.SSLCertificate) {
-	switch p.format {
-	case "json":
-		p.jsonPresenter.PresentSSLCertificate(cert)
-	default:
-		p.tablePresenter.PresentSSLCertificate(cert)
-	}
+	p.presenter().PresentSSLCertificate(cert)
 }
 
 func (p *MultiPresenter) PresentCredentialReferences(ref []string) {
-	switch p.format {
-	case "json":
-		p.jsonPresenter.PresentCredentialReferences(ref)
-	default:
-		p.tablePresenter.PresentCredentialReferences(ref)
-	}
+	p.presenter().PresentCredentialReferences(ref)
 }
 
 func (p *MultiPresenter) PresentCredentials(creds map[string]string) {
-	switch p.format {
-	case "json":
-		p.jsonPresenter.PresentCredentials(creds)
-	default:
-		p.tablePresenter.PresentCredentials(creds)
-	}
+	p.presenter().PresentCredentials(creds)
 }
 
 func (p *MultiPresenter) PresentDeployedProducts(products []api.DiagnosticProduct) {
-	switch p.format {
-	case "json":
-		p.jsonPresenter.PresentDeployedProducts(products)
-	default:
-		p.tablePresenter.PresentDeployedProducts(products)
-	}
+	p.presenter().PresentDeployedProducts(products)
 }
 
 func (p *MultiPresenter) PresentErrands(errands []models.Errand) {
-	switch p.format {
-	case "json":
-		p.jsonPresenter.PresentErrands(errands)
-	default:
-		p.tablePresenter.PresentErrands(errands)
-	}
+	p.presenter().PresentErrands(errands)
 }
 
 func (p *MultiPresenter) PresentInstallations(i []models.Installation) {
-	switch p.format {
-	case "json":
-		p.jsonPresenter.PresentInstallations(i)
-	default:
-		p.tablePresenter.PresentInstallations(i)
-	}
+	p.presenter().PresentInstallations(i)
+}
+
+func (p *MultiPresenter) PresentInterpolationReport(report models.InterpolationReport) {
+	p.presenter().PresentInterpolationReport(report)
 }
+
+func (p *MultiPresenter) PresentLocks(locks []models.Lock) {
+	p.presenter().PresentLocks(locks)
+}
+
 func (p *MultiPresenter) PresentPendingChanges(c api.PendingChangesOutput) {
-	switch p.format {
-	case "json":
-		p.jsonPresenter.PresentPendingChanges(c)
-	default:
-		p.tablePresenter.PresentPendingChanges(c)
-	}
+	p.presenter().PresentPendingChanges(c)
 }
 
 func (p *MultiPresenter) PresentStagedProducts(products []api.DiagnosticProduct) {
-	switch p.format {
-	case "json":
-		p.jsonPresenter.PresentStagedProducts(products)
-	default:
-		p.tablePresenter.PresentStagedProducts(products)
-	}
+	p.presenter().PresentStagedProducts(products)
 }
 
 func (p *MultiPresenter) PresentDiagnosticReport(report api.DiagnosticReport) {
-	switch p.format {
-	case "json":
-		p.jsonPresenter.PresentDiagnosticReport(report)
-	default:
-		p.tablePresenter.PresentDiagnosticReport(report)
-	}
+	p.presenter().PresentDiagnosticReport(report)
+}
+
+func (p *MultiPresenter) PresentTileInfo(info models.TileInfo) {
+	p.presenter().PresentTileInfo(info)
 }
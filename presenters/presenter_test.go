@@ -0,0 +1,73 @@
+package presenters_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/om/models"
+	"github.com/pivotal-cf/om/presenters"
+	"github.com/pivotal-cf/om/presenters/fakes"
+)
+
+var _ = Describe("MultiPresenter", func() {
+	var (
+		tablePresenter *fakes.Presenter
+		jsonPresenter  *fakes.Presenter
+		yamlPresenter  *fakes.Presenter
+		csvPresenter   *fakes.Presenter
+		multiPresenter *presenters.MultiPresenter
+		products       []models.Product
+	)
+
+	BeforeEach(func() {
+		tablePresenter = &fakes.Presenter{}
+		jsonPresenter = &fakes.Presenter{}
+		yamlPresenter = &fakes.Presenter{}
+		csvPresenter = &fakes.Presenter{}
+		multiPresenter = presenters.NewPresenter(tablePresenter, jsonPresenter, yamlPresenter, csvPresenter)
+		products = []models.Product{{Name: "some-name", Version: "some-version"}}
+	})
+
+	DescribeTable("presents with the presenter of the format",
+		func(format string, presenter **fakes.Presenter) {
+			if format != "" {
+				multiPresenter.SetFormat(format)
+			}
+			multiPresenter.PresentAvailableProducts(products)
+
+			for _, p := range []*fakes.Presenter{tablePresenter, jsonPresenter, yamlPresenter, csvPresenter} {
+				if p == *presenter {
+					Expect(p.PresentAvailableProductsCallCount()).To(Equal(1))
+					Expect(p.PresentAvailableProductsArgsForCall(0)).To(Equal(products))
+				} else {
+					Expect(p.PresentAvailableProductsCallCount()).To(Equal(0))
+				}
+			}
+		},
+		Entry("by default", "", &tablePresenter),
+		Entry("table", "table", &tablePresenter),
+		Entry("json", "json", &jsonPresenter),
+		Entry("yaml", "yaml", &yamlPresenter),
+		Entry("csv", "csv", &csvPresenter),
+	)
+
+	Describe("ValidateFormat", func() {
+		It("accepts every format", func() {
+			for _, format := range presenters.Formats {
+				Expect(presenters.ValidateFormat(format)).To(Succeed())
+			}
+		})
+
+		It("returns an error for other formats", func() {
+			err := presenters.ValidateFormat("xml")
+			Expect(err).To(MatchError(`unsupported format "xml", expected one of table, json, yaml, csv`))
+		})
+
+		It("only accepts the given formats", func() {
+			Expect(presenters.ValidateFormat("json", "table", "json")).To(Succeed())
+
+			err := presenters.ValidateFormat("csv", "table", "json")
+			Expect(err).To(MatchError(`unsupported format "csv", expected one of table, json`))
+		})
+	})
+})
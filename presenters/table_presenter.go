@@ -1,8 +1,10 @@
 package presenters
 
 import (
+	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/olekukonko/tablewriter"
@@ -19,6 +21,7 @@ type tableWriter interface {
 	Render()
 	SetAutoFormatHeaders(bool)
 	SetAutoWrapText(bool)
+	ClearRows()
 }
 
 type TablePresenter struct {
@@ -108,6 +111,61 @@ func (t TablePresenter) PresentCertificateAuthority(certificateAuthority api.CA)
 	t.tableWriter.Render()
 }
 
+func (t TablePresenter) PresentAuditRecords(records []models.AuditRecord) {
+	t.tableWriter.SetAutoWrapText(false)
+	t.tableWriter.SetHeader([]string{"Time", "Command", "Args", "Target", "User", "Method", "Endpoint", "Status"})
+
+	for _, record := range records {
+		status := strconv.Itoa(record.Status)
+		if record.Error != "" {
+			status = record.Error
+		}
+
+		t.tableWriter.Append([]string{
+			record.Time.Format(time.RFC3339),
+			record.Command,
+			strings.Join(record.Args, " "),
+			record.Target,
+			record.User,
+			record.Method,
+			record.Endpoint,
+			status,
+		})
+	}
+
+	t.tableWriter.Render()
+}
+
+func (t TablePresenter) PresentCertificateInventory(certificates []models.Certificate) {
+	t.tableWriter.SetAutoWrapText(false)
+	t.tableWriter.SetHeader([]string{"Source", "Name", "Product", "Subject", "SANs", "Key", "Signature Algorithm", "Issuer Chain", "Expires", "Days Left"})
+
+	for _, certificate := range certificates {
+		var key, expires string
+		if certificate.KeyType != "" {
+			key = fmt.Sprintf("%s %d", certificate.KeyType, certificate.KeySize)
+		}
+		if certificate.NotAfter != nil {
+			expires = certificate.NotAfter.Format(time.RFC3339)
+		}
+
+		t.tableWriter.Append([]string{
+			certificate.Source,
+			certificate.Name,
+			certificate.Product,
+			certificate.Subject,
+			strings.Join(certificate.SANs, ", "),
+			key,
+			certificate.SignatureAlgorithm,
+			strings.Join(certificate.IssuerChain, " -> "),
+			expires,
+			strconv.Itoa(certificate.DaysLeft),
+		})
+	}
+
+	t.tableWriter.Render()
+}
+
 func (t TablePresenter) PresentSSLCertificate(certificate api.SSLCertificate) {
 	t.tableWriter.SetAutoWrapText(false)
 	t.tableWriter.SetHeader([]string{"Certificate"})
@@ -140,6 +198,55 @@ func (t TablePresenter) PresentInstallations(installations []models.Installation
 	t.tableWriter.Render()
 }
 
+func (t TablePresenter) PresentInterpolationReport(report models.InterpolationReport) {
+	t.tableWriter.SetAlignment(tablewriter.ALIGN_LEFT)
+	t.tableWriter.SetAutoWrapText(false)
+	t.tableWriter.SetHeader([]string{"Variable", "Status", "Source", "Overridden"})
+
+	for _, name := range report.Missing {
+		t.tableWriter.Append([]string{name, "missing", "", ""})
+	}
+
+	for _, unused := range report.Unused {
+		for _, name := range unused.Names {
+			t.tableWriter.Append([]string{name, "unused", unused.Source, ""})
+		}
+	}
+
+	for _, overridden := range report.Overridden {
+		t.tableWriter.Append([]string{overridden.Name, "overridden", overridden.Source, strings.Join(overridden.Overridden, ", ")})
+	}
+
+	for _, name := range report.Generated {
+		t.tableWriter.Append([]string{name, "would be generated", "--vars-store", ""})
+	}
+
+	t.tableWriter.Render()
+}
+
+func (t TablePresenter) PresentLocks(locks []models.Lock) {
+	t.tableWriter.SetAutoWrapText(false)
+	t.tableWriter.SetHeader([]string{"Name", "Holder", "Command", "Acquired At", "Expires At", "Status"})
+
+	for _, lock := range locks {
+		status := "held"
+		if lock.Expired {
+			status = "expired"
+		}
+
+		t.tableWriter.Append([]string{
+			lock.Name,
+			lock.Holder,
+			lock.Command,
+			lock.AcquiredAt.Format(time.RFC3339),
+			lock.ExpiresAt.Format(time.RFC3339),
+			status,
+		})
+	}
+
+	t.tableWriter.Render()
+}
+
 func (t TablePresenter) PresentPendingChanges(output api.PendingChangesOutput) {
 	pendingChanges := output.ChangeList
 
@@ -171,6 +278,74 @@ func (t TablePresenter) PresentStagedProducts(stagedProducts []api.DiagnosticPro
 	t.tableWriter.Render()
 }
 
+func (t TablePresenter) PresentTileInfo(info models.TileInfo) {
+	t.tableWriter.SetAlignment(tablewriter.ALIGN_LEFT)
+	t.tableWriter.SetAutoWrapText(false)
+
+	t.renderTable([]string{"Name", "Version", "Label", "Minimum Version For Upgrade", "Ops Manager Versions"}, [][]string{{
+		info.Name,
+		info.Version,
+		info.Label,
+		info.MinimumVersionForUpgrade,
+		strings.Join(info.OpsManagerVersions, ", "),
+	}})
+
+	var rows [][]string
+	for _, release := range info.Releases {
+		rows = append(rows, []string{release.Name, release.Version, release.File})
+	}
+	t.renderTable([]string{"Release", "Version", "File"}, rows)
+
+	rows = nil
+	for _, criteria := range info.StemcellCriteria {
+		rows = append(rows, []string{criteria.OS, criteria.Version, strconv.FormatBool(criteria.EnablePatchSecurityUpdates)})
+	}
+	t.renderTable([]string{"Stemcell OS", "Stemcell Version", "Patch Security Updates"}, rows)
+
+	rows = nil
+	for _, product := range info.RequiredProducts {
+		rows = append(rows, []string{product.Name, product.Version})
+	}
+	t.renderTable([]string{"Required Product", "Version"}, rows)
+
+	rows = nil
+	for _, job := range info.JobTypes {
+		var resources []string
+		for _, resource := range job.Resources {
+			resources = append(resources, fmt.Sprintf("%s: %v", resource.Name, resource.Default))
+		}
+		rows = append(rows, []string{job.Name, strconv.Itoa(job.Instances), strconv.FormatBool(job.InstancesConfigurable), strings.Join(resources, ", ")})
+	}
+	t.renderTable([]string{"Job", "Instances", "Instances Configurable", "Resources"}, rows)
+
+	rows = nil
+	for _, errand := range info.Errands {
+		rows = append(rows, []string{errand.Name, errand.Type})
+	}
+	t.renderTable([]string{"Errand", "Type"}, rows)
+
+	rows = nil
+	for _, property := range info.Properties {
+		rows = append(rows, []string{property.Reference, property.Type, strconv.FormatBool(property.Required)})
+	}
+	t.renderTable([]string{"Property", "Type", "Required"}, rows)
+}
+
+// renderTable renders one of several tables sharing the same table writer,
+// skipping tables without any rows.
+func (t TablePresenter) renderTable(header []string, rows [][]string) {
+	if len(rows) == 0 {
+		return
+	}
+
+	t.tableWriter.SetHeader(header)
+	for _, row := range rows {
+		t.tableWriter.Append(row)
+	}
+	t.tableWriter.Render()
+	t.tableWriter.ClearRows()
+}
+
 func sortCredentialMap(cm map[string]string) ([]string, []string) {
 	var header []string
 	var credential []string
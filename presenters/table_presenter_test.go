@@ -167,6 +167,93 @@ var _ = Describe("TablePresenter", func() {
 		})
 	})
 
+	Describe("PresentAuditRecords", func() {
+		It("creates a table", func() {
+			tablePresenter.PresentAuditRecords([]models.AuditRecord{
+				{
+					Time:     time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC),
+					Command:  "apply-changes",
+					Args:     []string{"--product-name", "cf"},
+					Target:   "https://opsman.example.com",
+					User:     "admin",
+					Method:   "POST",
+					Endpoint: "/api/v0/installations",
+					Status:   200,
+				},
+				{
+					Time:     time.Date(2030, 1, 2, 3, 4, 6, 0, time.UTC),
+					Command:  "curl",
+					Args:     []string{},
+					Target:   "https://opsman.example.com",
+					Method:   "DELETE",
+					Endpoint: "/api/v0/staged/products/cf-guid",
+					Error:    "connection refused",
+				},
+			})
+			Expect(fakeTableWriter.SetAutoWrapTextCallCount()).To(Equal(1))
+			Expect(fakeTableWriter.SetAutoWrapTextArgsForCall(0)).To(BeFalse())
+
+			Expect(fakeTableWriter.SetHeaderArgsForCall(0)).To(Equal([]string{"Time", "Command", "Args", "Target", "User", "Method", "Endpoint", "Status"}))
+
+			Expect(fakeTableWriter.AppendCallCount()).To(Equal(2))
+			Expect(fakeTableWriter.AppendArgsForCall(0)).To(Equal([]string{
+				"2030-01-02T03:04:05Z", "apply-changes", "--product-name cf", "https://opsman.example.com", "admin",
+				"POST", "/api/v0/installations", "200",
+			}))
+			Expect(fakeTableWriter.AppendArgsForCall(1)).To(Equal([]string{
+				"2030-01-02T03:04:06Z", "curl", "", "https://opsman.example.com", "",
+				"DELETE", "/api/v0/staged/products/cf-guid", "connection refused",
+			}))
+
+			Expect(fakeTableWriter.RenderCallCount()).To(Equal(1))
+		})
+	})
+
+	Describe("PresentCertificateInventory", func() {
+		It("creates a table", func() {
+			notAfter := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+			tablePresenter.PresentCertificateInventory([]models.Certificate{
+				{
+					Source:             "ssl_certificate",
+					Name:               "ops_manager",
+					Subject:            "CN=opsman.example.com",
+					SANs:               []string{"opsman.example.com", "10.0.0.1"},
+					KeyType:            "RSA",
+					KeySize:            2048,
+					SignatureAlgorithm: "SHA256-RSA",
+					IssuerChain:        []string{"CN=intermediate", "CN=root"},
+					NotAfter:           &notAfter,
+					DaysLeft:           42,
+				},
+				{
+					Source:      "deployed",
+					Name:        ".properties.networking_poe_ssl_certs",
+					Product:     "cf-guid",
+					SANs:        []string{},
+					IssuerChain: []string{"CN=root"},
+					NotAfter:    &notAfter,
+					DaysLeft:    42,
+				},
+			})
+			Expect(fakeTableWriter.SetAutoWrapTextCallCount()).To(Equal(1))
+			Expect(fakeTableWriter.SetAutoWrapTextArgsForCall(0)).To(BeFalse())
+
+			Expect(fakeTableWriter.SetHeaderArgsForCall(0)).To(Equal([]string{"Source", "Name", "Product", "Subject", "SANs", "Key", "Signature Algorithm", "Issuer Chain", "Expires", "Days Left"}))
+
+			Expect(fakeTableWriter.AppendCallCount()).To(Equal(2))
+			Expect(fakeTableWriter.AppendArgsForCall(0)).To(Equal([]string{
+				"ssl_certificate", "ops_manager", "", "CN=opsman.example.com", "opsman.example.com, 10.0.0.1",
+				"RSA 2048", "SHA256-RSA", "CN=intermediate -> CN=root", "2030-01-02T03:04:05Z", "42",
+			}))
+			Expect(fakeTableWriter.AppendArgsForCall(1)).To(Equal([]string{
+				"deployed", ".properties.networking_poe_ssl_certs", "cf-guid", "", "",
+				"", "", "CN=root", "2030-01-02T03:04:05Z", "42",
+			}))
+
+			Expect(fakeTableWriter.RenderCallCount()).To(Equal(1))
+		})
+	})
+
 	Describe("PresentInstallations", func() {
 		var installations []models.Installation
 
@@ -224,6 +311,66 @@ var _ = Describe("TablePresenter", func() {
 		})
 	})
 
+	Describe("PresentInterpolationReport", func() {
+		It("creates a table with a row for every variable", func() {
+			tablePresenter.PresentInterpolationReport(models.InterpolationReport{
+				Missing: []string{"password"},
+				Unused: []models.UnusedVariables{
+					{Source: "--vars-file vars.yml", Names: []string{"pasword", "port"}},
+				},
+				Overridden: []models.OverriddenVariable{
+					{Name: "username", Source: "--var", Overridden: []string{"--vars-file b.yml", "--vars-file a.yml"}},
+				},
+				Generated: []string{"admin-password"},
+			})
+
+			Expect(fakeTableWriter.SetHeaderArgsForCall(0)).To(Equal([]string{"Variable", "Status", "Source", "Overridden"}))
+
+			Expect(fakeTableWriter.AppendCallCount()).To(Equal(5))
+			Expect(fakeTableWriter.AppendArgsForCall(0)).To(Equal([]string{"password", "missing", "", ""}))
+			Expect(fakeTableWriter.AppendArgsForCall(1)).To(Equal([]string{"pasword", "unused", "--vars-file vars.yml", ""}))
+			Expect(fakeTableWriter.AppendArgsForCall(2)).To(Equal([]string{"port", "unused", "--vars-file vars.yml", ""}))
+			Expect(fakeTableWriter.AppendArgsForCall(3)).To(Equal([]string{"username", "overridden", "--var", "--vars-file b.yml, --vars-file a.yml"}))
+			Expect(fakeTableWriter.AppendArgsForCall(4)).To(Equal([]string{"admin-password", "would be generated", "--vars-store", ""}))
+
+			Expect(fakeTableWriter.RenderCallCount()).To(Equal(1))
+		})
+	})
+
+	Describe("PresentLocks", func() {
+		It("creates a table", func() {
+			tablePresenter.PresentLocks([]models.Lock{
+				{
+					Name:       "opsman.example.com",
+					Holder:     "ci@worker (pid 42)",
+					Command:    "apply-changes",
+					AcquiredAt: time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC),
+					ExpiresAt:  time.Date(2030, 1, 2, 4, 4, 5, 0, time.UTC),
+				},
+				{
+					Name:       "other.example.com",
+					Holder:     "admin@laptop (pid 7)",
+					Command:    "configure-product",
+					AcquiredAt: time.Date(2030, 1, 1, 3, 4, 5, 0, time.UTC),
+					ExpiresAt:  time.Date(2030, 1, 1, 4, 4, 5, 0, time.UTC),
+					Expired:    true,
+				},
+			})
+			Expect(fakeTableWriter.SetAutoWrapTextArgsForCall(0)).To(BeFalse())
+			Expect(fakeTableWriter.SetHeaderArgsForCall(0)).To(Equal([]string{"Name", "Holder", "Command", "Acquired At", "Expires At", "Status"}))
+
+			Expect(fakeTableWriter.AppendCallCount()).To(Equal(2))
+			Expect(fakeTableWriter.AppendArgsForCall(0)).To(Equal([]string{
+				"opsman.example.com", "ci@worker (pid 42)", "apply-changes", "2030-01-02T03:04:05Z", "2030-01-02T04:04:05Z", "held",
+			}))
+			Expect(fakeTableWriter.AppendArgsForCall(1)).To(Equal([]string{
+				"other.example.com", "admin@laptop (pid 7)", "configure-product", "2030-01-01T03:04:05Z", "2030-01-01T04:04:05Z", "expired",
+			}))
+
+			Expect(fakeTableWriter.RenderCallCount()).To(Equal(1))
+		})
+	})
+
 	Describe("PresentPendingChanges", func() {
 		var pendingChanges api.PendingChangesOutput
 		BeforeEach(func() {
@@ -323,6 +470,39 @@ var _ = Describe("TablePresenter", func() {
 		})
 	})
 
+	Describe("PresentTileInfo", func() {
+		It("creates a table for every section with rows", func() {
+			tablePresenter.PresentTileInfo(models.TileInfo{
+				Name:               "some-product",
+				Version:            "1.2.3",
+				Label:              "Some Product",
+				OpsManagerVersions: []string{">= 2.6"},
+				Releases:           []models.TileRelease{{Name: "some-release", Version: "4.5.6", File: "some-release-4.5.6.tgz"}},
+				JobTypes: []models.TileJobType{{
+					Name:      "some-job",
+					Instances: 2,
+					Resources: []models.TileResource{{Name: "ram", Default: 1024}, {Name: "cpu", Default: 1}},
+				}},
+				Properties: []models.TileProperty{{Reference: ".properties.some-property", Type: "string", Required: true}},
+			})
+
+			Expect(fakeTableWriter.SetHeaderCallCount()).To(Equal(4))
+			Expect(fakeTableWriter.SetHeaderArgsForCall(0)).To(Equal([]string{"Name", "Version", "Label", "Minimum Version For Upgrade", "Ops Manager Versions"}))
+			Expect(fakeTableWriter.SetHeaderArgsForCall(1)).To(Equal([]string{"Release", "Version", "File"}))
+			Expect(fakeTableWriter.SetHeaderArgsForCall(2)).To(Equal([]string{"Job", "Instances", "Instances Configurable", "Resources"}))
+			Expect(fakeTableWriter.SetHeaderArgsForCall(3)).To(Equal([]string{"Property", "Type", "Required"}))
+
+			Expect(fakeTableWriter.AppendCallCount()).To(Equal(4))
+			Expect(fakeTableWriter.AppendArgsForCall(0)).To(Equal([]string{"some-product", "1.2.3", "Some Product", "", ">= 2.6"}))
+			Expect(fakeTableWriter.AppendArgsForCall(1)).To(Equal([]string{"some-release", "4.5.6", "some-release-4.5.6.tgz"}))
+			Expect(fakeTableWriter.AppendArgsForCall(2)).To(Equal([]string{"some-job", "2", "false", "ram: 1024, cpu: 1"}))
+			Expect(fakeTableWriter.AppendArgsForCall(3)).To(Equal([]string{".properties.some-property", "string", "true"}))
+
+			Expect(fakeTableWriter.RenderCallCount()).To(Equal(4))
+			Expect(fakeTableWriter.ClearRowsCallCount()).To(Equal(4))
+		})
+	})
+
 	Describe("PresentCertificateAuthorities", func() {
 		var certificateAuthorities []api.CA
 		BeforeEach(func() {
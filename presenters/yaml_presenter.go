@@ -0,0 +1,101 @@
+package presenters
+
+import (
+	"io"
+
+	yamlConverter "github.com/ghodss/yaml"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/models"
+)
+
+// YAMLPresenter prints the same documents as the JSONPresenter, as YAML.
+type YAMLPresenter struct {
+	stdout io.Writer
+}
+
+func NewYAMLPresenter(stdout io.Writer) YAMLPresenter {
+	return YAMLPresenter{
+		stdout: stdout,
+	}
+}
+
+func (y YAMLPresenter) PresentAvailableProducts(products []models.Product) {
+	y.encodeYAML(products)
+}
+
+func (y YAMLPresenter) PresentCertificateAuthorities(certificateAuthorities []api.CA) {
+	y.encodeYAML(certificateAuthorities)
+}
+
+func (y YAMLPresenter) PresentCredentialReferences(credentialReferences []string) {
+	y.encodeYAML(credentialReferences)
+}
+
+func (y YAMLPresenter) PresentCredentials(credentials map[string]string) {
+	y.encodeYAML(credentials)
+}
+
+func (y YAMLPresenter) PresentDeployedProducts(deployedProducts []api.DiagnosticProduct) {
+	y.encodeYAML(deployedProducts)
+}
+
+func (y YAMLPresenter) PresentErrands(errands []models.Errand) {
+	y.encodeYAML(errands)
+}
+
+func (y YAMLPresenter) PresentCertificateAuthority(certificateAuthority api.CA) {
+	y.encodeYAML(certificateAuthority)
+}
+
+func (y YAMLPresenter) PresentAuditRecords(records []models.AuditRecord) {
+	y.encodeYAML(records)
+}
+
+func (y YAMLPresenter) PresentCertificateInventory(certificates []models.Certificate) {
+	y.encodeYAML(certificates)
+}
+
+func (y YAMLPresenter) PresentSSLCertificate(certificate api.SSLCertificate) {
+	y.encodeYAML(certificate)
+}
+
+func (y YAMLPresenter) PresentInstallations(installations []models.Installation) {
+	y.encodeYAML(installations)
+}
+
+func (y YAMLPresenter) PresentInterpolationReport(report models.InterpolationReport) {
+	y.encodeYAML(report)
+}
+
+func (y YAMLPresenter) PresentLocks(locks []models.Lock) {
+	y.encodeYAML(locks)
+}
+
+func (y YAMLPresenter) PresentStagedProducts(stagedProducts []api.DiagnosticProduct) {
+	y.encodeYAML(stagedProducts)
+}
+
+func (y YAMLPresenter) PresentPendingChanges(pendingChangesOutput api.PendingChangesOutput) {
+	y.convertJSON(pendingChangesOutput.FullReport)
+}
+
+func (y YAMLPresenter) PresentDiagnosticReport(report api.DiagnosticReport) {
+	y.convertJSON(report.FullReport)
+}
+
+func (y YAMLPresenter) PresentTileInfo(info models.TileInfo) {
+	y.encodeYAML(info)
+}
+
+// encodeYAML marshals through JSON, so the keys are the same as with the JSONPresenter.
+func (y YAMLPresenter) encodeYAML(v interface{}) {
+	b, _ := yamlConverter.Marshal(v)
+
+	_, _ = y.stdout.Write(b)
+}
+
+func (y YAMLPresenter) convertJSON(document string) {
+	b, _ := yamlConverter.JSONToYAML([]byte(document))
+
+	_, _ = y.stdout.Write(b)
+}
@@ -0,0 +1,64 @@
+package presenters_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/models"
+	"github.com/pivotal-cf/om/presenters"
+)
+
+var _ = Describe("YAMLPresenter", func() {
+	var (
+		stdout        *gbytes.Buffer
+		yamlPresenter presenters.YAMLPresenter
+	)
+
+	BeforeEach(func() {
+		stdout = gbytes.NewBuffer()
+		yamlPresenter = presenters.NewYAMLPresenter(stdout)
+	})
+
+	It("uses the same keys as the JSON presenter", func() {
+		yamlPresenter.PresentCertificateAuthorities([]api.CA{{
+			GUID:      "some-guid",
+			Issuer:    "some-issuer",
+			CreatedOn: "2017-01-09",
+			ExpiresOn: "2021-01-09",
+			Active:    true,
+			CertPEM:   "-----BEGIN CERTIFICATE-----\nMIIC+zCCAeOgAwIBAgI....\n",
+		}})
+
+		Expect(string(stdout.Contents())).To(MatchYAML(`
+- guid: some-guid
+  issuer: some-issuer
+  created_on: "2017-01-09"
+  expires_on: "2021-01-09"
+  active: true
+  cert_pem: |
+    -----BEGIN CERTIFICATE-----
+    MIIC+zCCAeOgAwIBAgI....
+`))
+	})
+
+	It("presents lists of products", func() {
+		yamlPresenter.PresentAvailableProducts([]models.Product{
+			{Name: "some-name", Version: "some-version"},
+		})
+
+		Expect(string(stdout.Contents())).To(MatchYAML(`[{name: some-name, version: some-version}]`))
+	})
+
+	It("converts the JSON of the pending changes", func() {
+		yamlPresenter.PresentPendingChanges(api.PendingChangesOutput{
+			FullReport: `{"product_changes": [{"guid": "some-product", "action": "install"}]}`,
+		})
+
+		Expect(string(stdout.Contents())).To(MatchYAML(`
+product_changes:
+- guid: some-product
+  action: install
+`))
+	})
+})
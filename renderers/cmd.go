@@ -0,0 +1,36 @@
+package renderers
+
+import (
+	"fmt"
+	"strings"
+)
+
+type cmd struct {
+}
+
+// NewCmd creates a new cmd.exe renderer.
+// cmd.exe cannot set multi-line values,
+// so they have to be rendered as the path of a file.
+func NewCmd() Renderer {
+	return &cmd{}
+}
+
+// cmdEscaper escapes the characters that cmd.exe interprets in a command line,
+// so that values such as passwords are set as they are.
+var cmdEscaper = strings.NewReplacer(
+	"%", "%%",
+	"^", "^^",
+	`"`, `^"`,
+	"&", "^&",
+	"|", "^|",
+	"<", "^<",
+	">", "^>",
+)
+
+func (renderer *cmd) RenderEnvironmentVariable(variable string, value string) string {
+	return fmt.Sprintf("set %s=%s", variable, cmdEscaper.Replace(value))
+}
+
+func (renderer *cmd) Type() string {
+	return ShellTypeCmd
+}
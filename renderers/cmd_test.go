@@ -0,0 +1,36 @@
+package renderers_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/om/renderers"
+)
+
+var _ = Describe(renderers.ShellTypeCmd, func() {
+	var (
+		renderer renderers.Renderer
+	)
+
+	BeforeEach(func() {
+		renderer = renderers.NewCmd()
+	})
+
+	Describe("RenderEnvironmentVariable", func() {
+		It("prints set statement", func() {
+			result := renderer.RenderEnvironmentVariable("KEY", "some-value")
+			Expect(result).To(Equal(`set KEY=some-value`))
+		})
+
+		It("escapes the characters that cmd interprets", func() {
+			result := renderer.RenderEnvironmentVariable("KEY", `a%PATH%b^c"d&e|f<g>h`)
+			Expect(result).To(Equal(`set KEY=a%%PATH%%b^^c^"d^&e^|f^<g^>h`))
+		})
+	})
+
+	Describe("Type", func() {
+		It("is cmd", func() {
+			shellType := renderer.Type()
+			Expect(shellType).To(Equal(renderers.ShellTypeCmd))
+		})
+	})
+})
@@ -35,6 +35,10 @@ func (f *factory) createFromType(shellType string) (Renderer, error) {
 		return NewPowershell(), nil
 	case ShellTypePosix:
 		return NewPosix(), nil
+	case ShellTypeFish:
+		return NewFish(), nil
+	case ShellTypeCmd:
+		return NewCmd(), nil
 	default:
 		return nil, fmt.Errorf("unrecognized type '%s'", shellType)
 	}
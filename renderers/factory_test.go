@@ -38,5 +38,29 @@ var _ = Describe("Factory", func() {
 				Expect(renderer.Type()).To(Equal(renderers.ShellTypePosix))
 			})
 		})
+
+		Context("WhenShellTypeGiven", func() {
+			BeforeEach(func() {
+				envGetter = &fakes.EnvGetter{}
+				factory = renderers.NewFactory(envGetter)
+			})
+
+			It("creates fish renderer", func() {
+				renderer, err := factory.Create("fish")
+				Expect(err).To(BeNil())
+				Expect(renderer.Type()).To(Equal(renderers.ShellTypeFish))
+			})
+
+			It("creates cmd renderer", func() {
+				renderer, err := factory.Create("cmd")
+				Expect(err).To(BeNil())
+				Expect(renderer.Type()).To(Equal(renderers.ShellTypeCmd))
+			})
+
+			It("returns an error for an unknown shell type", func() {
+				_, err := factory.Create("tcsh")
+				Expect(err).To(MatchError("unrecognized type 'tcsh'"))
+			})
+		})
 	})
 })
@@ -0,0 +1,27 @@
+package renderers
+
+import (
+	"fmt"
+	"strings"
+)
+
+type fish struct {
+}
+
+// NewFish creates a new fish renderer
+func NewFish() Renderer {
+	return &fish{}
+}
+
+func (renderer *fish) RenderEnvironmentVariable(variable string, value string) string {
+	if strings.ContainsAny(value, "\n") && !strings.HasSuffix(value, "\n") {
+		value = value + "\n"
+	}
+
+	value = strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+	return fmt.Sprintf("set -gx %s '%s'", variable, value)
+}
+
+func (renderer *fish) Type() string {
+	return ShellTypeFish
+}
@@ -0,0 +1,56 @@
+package renderers_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/om/renderers"
+)
+
+var _ = Describe(renderers.ShellTypeFish, func() {
+	var (
+		renderer renderers.Renderer
+	)
+
+	BeforeEach(func() {
+		renderer = renderers.NewFish()
+	})
+
+	Describe("RenderEnvironmentVariable", func() {
+		Context("WhenSingleLine", func() {
+			It("prints env statement properly", func() {
+				key := "KEY"
+				value := "value"
+				result := renderer.RenderEnvironmentVariable(key, value)
+				Expect(result).To(Equal("set -gx KEY 'value'"))
+			})
+
+			It("escapes quotes and backslashes", func() {
+				key := "KEY"
+				value := `it's a \ value`
+				result := renderer.RenderEnvironmentVariable(key, value)
+				Expect(result).To(Equal(`set -gx KEY 'it\'s a \\ value'`))
+			})
+		})
+		Context("WhenMultiLine", func() {
+			It("prints env statement with enclosing quotes", func() {
+				key := "KEY"
+				value := "1\n2\n3\n4\n"
+				result := renderer.RenderEnvironmentVariable(key, value)
+				Expect(result).To(Equal("set -gx KEY '1\n2\n3\n4\n'"))
+			})
+			It("appends newline if not present", func() {
+				key := "KEY"
+				value := "1\n2\n3\n4"
+				result := renderer.RenderEnvironmentVariable(key, value)
+				Expect(result).To(Equal("set -gx KEY '1\n2\n3\n4\n'"))
+			})
+		})
+	})
+
+	Describe("Type", func() {
+		It("is fish", func() {
+			shellType := renderer.Type()
+			Expect(shellType).To(Equal(renderers.ShellTypeFish))
+		})
+	})
+})
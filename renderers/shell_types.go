@@ -3,4 +3,6 @@ package renderers
 const (
 	ShellTypePowershell = "powershell"
 	ShellTypePosix      = "posix"
+	ShellTypeFish       = "fish"
+	ShellTypeCmd        = "cmd"
 )
@@ -0,0 +1,109 @@
+// Package stowconfig builds the stow configurations of the blobstores
+// that om reads products from and keeps its locks in.
+package stowconfig
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/graymeta/stow"
+	"github.com/graymeta/stow/azure"
+	"github.com/graymeta/stow/google"
+	"github.com/graymeta/stow/s3"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+type S3Configuration struct {
+	Bucket          string `validate:"required"`
+	AccessKeyID     string
+	SecretAccessKey string
+	RegionName      string `validate:"required"`
+	Endpoint        string
+	DisableSSL      bool
+	EnableV2Signing bool
+	AuthType        string
+}
+
+type GCSConfiguration struct {
+	Bucket             string `validate:"required"`
+	ServiceAccountJSON string `validate:"required"`
+	ProjectID          string `validate:"required"`
+}
+
+type AzureConfiguration struct {
+	StorageAccount string `validate:"required"`
+	Key            string `validate:"required"`
+	Container      string `validate:"required"`
+}
+
+// S3 validates the configuration and returns the stow configuration of the "s3" kind.
+func S3(config S3Configuration) (stow.ConfigMap, error) {
+	validate := validator.New()
+	err := validate.Struct(config)
+	if err != nil {
+		return nil, err
+	}
+
+	disableSSL := strconv.FormatBool(config.DisableSSL)
+	enableV2Signing := strconv.FormatBool(config.EnableV2Signing)
+	if config.AuthType == "" {
+		config.AuthType = "accesskey"
+	}
+
+	err = validateAccessKeyAuthType(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return stow.ConfigMap{
+		s3.ConfigAccessKeyID: config.AccessKeyID,
+		s3.ConfigSecretKey:   config.SecretAccessKey,
+		s3.ConfigRegion:      config.RegionName,
+		s3.ConfigEndpoint:    config.Endpoint,
+		s3.ConfigDisableSSL:  disableSSL,
+		s3.ConfigV2Signing:   enableV2Signing,
+		s3.ConfigAuthType:    config.AuthType,
+	}, nil
+}
+
+func validateAccessKeyAuthType(config S3Configuration) error {
+	if config.AuthType == "iam" {
+		return nil
+	}
+
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return errors.New("the flags \"s3-access-key-id\" and \"s3-secret-access-key\" are required when the \"auth-type\" is \"accesskey\"")
+	}
+
+	return nil
+}
+
+// GCS validates the configuration and returns the stow configuration of the "google" kind,
+// with the scope of the storage API to use.
+func GCS(config GCSConfiguration, scope string) (stow.ConfigMap, error) {
+	validate := validator.New()
+	err := validate.Struct(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return stow.ConfigMap{
+		google.ConfigJSON:      config.ServiceAccountJSON,
+		google.ConfigProjectId: config.ProjectID,
+		google.ConfigScopes:    scope,
+	}, nil
+}
+
+// Azure validates the configuration and returns the stow configuration of the "azure" kind.
+func Azure(config AzureConfiguration) (stow.ConfigMap, error) {
+	validate := validator.New()
+	err := validate.Struct(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return stow.ConfigMap{
+		azure.ConfigAccount: config.StorageAccount,
+		azure.ConfigKey:     config.Key,
+	}, nil
+}
@@ -0,0 +1,13 @@
+package stowconfig_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestStowconfig(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Stowconfig Suite")
+}
@@ -0,0 +1,101 @@
+package stowconfig_test
+
+import (
+	"github.com/graymeta/stow"
+	"github.com/graymeta/stow/azure"
+	"github.com/graymeta/stow/google"
+	"github.com/graymeta/stow/s3"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/om/stowconfig"
+)
+
+var _ = Describe("stowconfig", func() {
+	Describe("S3", func() {
+		It("returns the s3 configuration with the access key auth type by default", func() {
+			config, err := stowconfig.S3(stowconfig.S3Configuration{
+				Bucket:          "some-bucket",
+				AccessKeyID:     "some-access-key-id",
+				SecretAccessKey: "some-secret-access-key",
+				RegionName:      "us-west-1",
+				Endpoint:        "https://s3.example.com",
+				EnableV2Signing: true,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config).To(Equal(stow.ConfigMap{
+				s3.ConfigAccessKeyID: "some-access-key-id",
+				s3.ConfigSecretKey:   "some-secret-access-key",
+				s3.ConfigRegion:      "us-west-1",
+				s3.ConfigEndpoint:    "https://s3.example.com",
+				s3.ConfigDisableSSL:  "false",
+				s3.ConfigV2Signing:   "true",
+				s3.ConfigAuthType:    "accesskey",
+			}))
+		})
+
+		It("does not require the access key with the iam auth type", func() {
+			config, err := stowconfig.S3(stowconfig.S3Configuration{
+				Bucket:     "some-bucket",
+				RegionName: "us-west-1",
+				AuthType:   "iam",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config).To(HaveKeyWithValue(s3.ConfigAuthType, "iam"))
+		})
+
+		It("requires the access key with the access key auth type", func() {
+			_, err := stowconfig.S3(stowconfig.S3Configuration{
+				Bucket:     "some-bucket",
+				RegionName: "us-west-1",
+			})
+			Expect(err).To(MatchError(`the flags "s3-access-key-id" and "s3-secret-access-key" are required when the "auth-type" is "accesskey"`))
+		})
+
+		It("requires the bucket and the region", func() {
+			_, err := stowconfig.S3(stowconfig.S3Configuration{})
+			Expect(err).To(MatchError(ContainSubstring("S3Configuration.Bucket")))
+			Expect(err).To(MatchError(ContainSubstring("S3Configuration.RegionName")))
+		})
+	})
+
+	Describe("GCS", func() {
+		It("returns the google configuration with the scope", func() {
+			config, err := stowconfig.GCS(stowconfig.GCSConfiguration{
+				Bucket:             "some-bucket",
+				ServiceAccountJSON: "{}",
+				ProjectID:          "some-project",
+			}, "some-scope")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config).To(Equal(stow.ConfigMap{
+				google.ConfigJSON:      "{}",
+				google.ConfigProjectId: "some-project",
+				google.ConfigScopes:    "some-scope",
+			}))
+		})
+
+		It("requires the bucket, the service account and the project", func() {
+			_, err := stowconfig.GCS(stowconfig.GCSConfiguration{}, "some-scope")
+			Expect(err).To(MatchError(ContainSubstring("GCSConfiguration.ServiceAccountJSON")))
+		})
+	})
+
+	Describe("Azure", func() {
+		It("returns the azure configuration", func() {
+			config, err := stowconfig.Azure(stowconfig.AzureConfiguration{
+				StorageAccount: "some-account",
+				Key:            "some-key",
+				Container:      "some-container",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config).To(Equal(stow.ConfigMap{
+				azure.ConfigAccount: "some-account",
+				azure.ConfigKey:     "some-key",
+			}))
+		})
+
+		It("requires the storage account, the key and the container", func() {
+			_, err := stowconfig.Azure(stowconfig.AzureConfiguration{StorageAccount: "some-account"})
+			Expect(err).To(MatchError(ContainSubstring("AzureConfiguration.Key")))
+		})
+	})
+})